@@ -34,6 +34,8 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	cnv "kubevirt.io/api/core/v1"
 	export "kubevirt.io/api/export/v1alpha1"
+	instancetype "kubevirt.io/api/instancetype/v1alpha2"
+	snapshot "kubevirt.io/api/snapshot/v1alpha1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -111,6 +113,14 @@ func main() {
 		log.Error(err, "unable to add kubevirt export APIs to scheme")
 		os.Exit(1)
 	}
+	if err := instancetype.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "unable to add kubevirt instancetype APIs to scheme")
+		os.Exit(1)
+	}
+	if err := snapshot.AddToScheme(mgr.GetScheme()); err != nil {
+		log.Error(err, "unable to add kubevirt snapshot APIs to scheme")
+		os.Exit(1)
+	}
 	if err := template.Install(mgr.GetScheme()); err != nil {
 		log.Error(err, "proceeding without optional OpenShift template APIs")
 	}
@@ -1,40 +1,54 @@
 package main
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
 	"flag"
-	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"io"
-	"k8s.io/klog/v2"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
+
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
+	libdelta "github.com/konveyor/forklift-controller/pkg/lib/delta"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
 )
 
 func main() {
 	var (
-		googleAuthPath string
-		bucketName     string
-		objectName     string
-		crNamespace    string
-		crName         string
+		bucketName  string
+		objectName  string
+		crNamespace string
+		crName      string
+		secretName  string
 
 		volumePath string
+		update     bool
 	)
-	flag.StringVar(&googleAuthPath, "google-auth-path", "", "Google Auth Path")
-	flag.StringVar(&bucketName, "bucket-name", "", "Bucket Name")
-	flag.StringVar(&objectName, "object-name", "", "Object Name")
+
+	klog.InitFlags(nil)
+
+	// Main arg
+	flag.StringVar(&secretName, "secret-name", "", "secret containing the GCP service account key")
+
+	flag.StringVar(&bucketName, "bucket-name", "", "Bucket the disk image has been exported to")
+	flag.StringVar(&objectName, "object-name", "", "Exported disk image object")
 	flag.StringVar(&volumePath, "volume-path", "", "Path to populate")
 	flag.StringVar(&crName, "cr-name", "", "Custom Resource instance name")
 	flag.StringVar(&crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.BoolVar(&update, "update", false, "Update a populated volume, the blocks that did not change are not written")
+
 	flag.Parse()
 
-	populate(googleAuthPath, volumePath, bucketName, objectName)
+	populate(volumePath, bucketName, objectName, crName, update)
 }
 
-func populate(googleAuthPath string, volumePath string, bucketName string, objectName string) {
+func populate(fileName, bucketName, objectName, imageName string, update bool) {
 	http.Handle("/metrics", promhttp.Handler())
 	go http.ListenAndServe(":2112", nil)
 	progressGague := prometheus.NewGaugeVec(
@@ -43,7 +57,7 @@ func populate(googleAuthPath string, volumePath string, bucketName string, objec
 			Name:      "gcp_volume_populator",
 			Help:      "Amount of data transferred",
 		},
-		[]string{"image_id"},
+		[]string{"image_name"},
 	)
 	if err := prometheus.Register(progressGague); err != nil {
 		klog.Error("Prometheus progress counter not registered:", err)
@@ -51,39 +65,85 @@ func populate(googleAuthPath string, volumePath string, bucketName string, objec
 		klog.Info("Prometheus progress counter registered.")
 	}
 
-	client := libclient.Client{
-		GoogleAuthPath: googleAuthPath,
+	options, err := readOptions()
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	client := &libclient.Client{
+		Options:    options,
+		BucketName: bucketName,
 	}
-	err := client.Connect()
+	err = client.Connect()
 	if err != nil {
-		klog.Error("Error connecting to GCP:", err)
-		return
+		klog.Fatal(err)
+	}
+	defer client.Close()
+
+	klog.Info("Downloading the image: ", bucketName, "/", objectName)
+	objectReader, err := client.DownloadImageFromBucket(bucketName, objectName)
+	if err != nil {
+		klog.Fatal(err)
 	}
-	klog.Info("Downloading image from bucket...", bucketName, objectName)
-	imageReader, err := client.DownloadImageFromBucket(bucketName, objectName)
+	defer objectReader.Close()
+
+	// The image export writes a gzip compressed tarball
+	// holding the raw disk.
+	imageReader, err := rawDisk(objectReader)
 	if err != nil {
 		klog.Fatal(err)
 	}
-	defer imageReader.Close()
 
 	flags := os.O_RDWR
-	if strings.HasSuffix(volumePath, "disk.img") {
+	if strings.HasSuffix(fileName, "disk.img") {
 		flags |= os.O_CREATE
 	}
 
-	klog.Info("Saving the image to: ", volumePath)
-	file, err := os.OpenFile(volumePath, flags, 0650)
+	klog.Info("Saving the image to: ", fileName)
+	file, err := os.OpenFile(fileName, flags, 0650)
 	if err != nil {
 		klog.Fatal(err)
 	}
 	defer file.Close()
 
-	err = writeData(imageReader, file, objectName, progressGague)
+	if update {
+		var total, changed int64
+		total, changed, err = libdelta.Write(imageReader, file, func(offset int64) {
+			progressGague.WithLabelValues(imageName).Set(float64(offset))
+		})
+		klog.Info("Total: ", total, " changed: ", changed)
+	} else {
+		err = writeData(imageReader, file, imageName, progressGague)
+	}
 	if err != nil {
 		klog.Fatal(err)
 	}
 }
 
+// Position the reader at the raw disk within the exported tarball.
+func rawDisk(reader io.Reader) (disk io.ReadCloser, err error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		var header *tar.Header
+		header, err = tarReader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errors.New("no disk found in the exported image")
+			}
+			return
+		}
+		if header.Typeflag == tar.TypeReg {
+			klog.Info("Found the disk: ", header.Name, " size: ", header.Size)
+			disk = io.NopCloser(tarReader)
+			return
+		}
+	}
+}
+
 type CountingReader struct {
 	reader io.ReadCloser
 	total  *int64
@@ -95,7 +155,7 @@ func (cr *CountingReader) Read(p []byte) (int, error) {
 	return n, err
 }
 
-func writeData(reader io.ReadCloser, file *os.File, imageID string, progress *prometheus.GaugeVec) error {
+func writeData(reader io.ReadCloser, file *os.File, imageName string, progress *prometheus.GaugeVec) error {
 	total := new(int64)
 	countingReader := CountingReader{reader, total}
 
@@ -108,7 +168,7 @@ func writeData(reader io.ReadCloser, file *os.File, imageID string, progress *pr
 				klog.Info("Finished!")
 				return
 			default:
-				progress.WithLabelValues(imageID).Set(float64(*total))
+				progress.WithLabelValues(imageName).Set(float64(*total))
 				klog.Info("Transferred: ", *total)
 				time.Sleep(3 * time.Second)
 			}
@@ -119,7 +179,38 @@ func writeData(reader io.ReadCloser, file *os.File, imageID string, progress *pr
 		klog.Fatal(err)
 	}
 	done <- true
-	progress.WithLabelValues(imageID).Set(float64(*total))
+	progress.WithLabelValues(imageName).Set(float64(*total))
 
 	return nil
 }
+
+func readOptions() (options map[string]string, err error) {
+	options = map[string]string{}
+	secretDirPath := "/etc/secret-volume"
+	dirEntries, err := os.ReadDir(secretDirPath)
+	if err != nil {
+		return
+	}
+	klog.Info("Options:")
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.Type().IsDir() {
+			option := dirEntry.Name()
+			if strings.HasPrefix(option, "..") {
+				continue
+			}
+			filePath := filepath.Join(secretDirPath, option)
+			var fileContent []byte
+			fileContent, err = os.ReadFile(filePath)
+			if err != nil {
+				return
+			}
+			value := string(fileContent)
+			options[option] = value
+			if option == libclient.ServiceAccount {
+				value = strings.Repeat("*", 8)
+			}
+			klog.Info(" - ", option, " = ", value)
+		}
+	}
+	return
+}
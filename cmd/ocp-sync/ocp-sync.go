@@ -0,0 +1,127 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	libdelta "github.com/konveyor/forklift-controller/pkg/lib/delta"
+	"k8s.io/klog/v2"
+)
+
+// The secret holding the export token and CA certificate.
+const (
+	secretDirPath = "/etc/secret-volume"
+	tokenKey      = "token"
+	caKey         = "ca.pem"
+	tokenHeader   = "x-kubevirt-export-token"
+)
+
+func main() {
+	var (
+		url        string
+		volumePath string
+		compressed bool
+	)
+
+	klog.InitFlags(nil)
+
+	flag.StringVar(&url, "url", "", "URL of the exported volume")
+	flag.StringVar(&volumePath, "volume-path", "", "Path to synchronize")
+	flag.BoolVar(&compressed, "gzip", false, "The exported volume is gzip compressed")
+
+	flag.Parse()
+
+	err := sync(url, volumePath, compressed)
+	if err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// Synchronize the volume with the exported volume.
+func sync(url, fileName string, compressed bool) (err error) {
+	client, token, err := exportClient()
+	if err != nil {
+		return
+	}
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set(tokenHeader, token)
+	klog.Info("Downloading the exported volume: ", url)
+	response, err := client.Do(request)
+	if err != nil {
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected export server response: %s", response.Status)
+		return
+	}
+	var reader io.Reader = response.Body
+	if compressed {
+		var gzipReader *gzip.Reader
+		gzipReader, err = gzip.NewReader(response.Body)
+		if err != nil {
+			return
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	flags := os.O_RDWR
+	if strings.HasSuffix(fileName, "disk.img") {
+		flags |= os.O_CREATE
+	}
+
+	klog.Info("Synchronizing: ", fileName)
+	file, err := os.OpenFile(fileName, flags, 0650)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	total, changed, err := libdelta.Write(reader, file, nil)
+	if err != nil {
+		return
+	}
+	klog.Info("Total: ", total, " changed: ", changed)
+	return
+}
+
+// Build the HTTP client of the export server.
+// Returns the client and the export token.
+func exportClient() (client *http.Client, token string, err error) {
+	content, err := os.ReadFile(filepath.Join(secretDirPath, tokenKey))
+	if err != nil {
+		return
+	}
+	token = strings.TrimSpace(string(content))
+	cert, err := os.ReadFile(filepath.Join(secretDirPath, caKey))
+	if err != nil {
+		return
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(cert) {
+		err = errors.New("failed to parse the export CA certificate")
+		return
+	}
+	client = &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				RootCAs: pool,
+			},
+		},
+	}
+	return
+}
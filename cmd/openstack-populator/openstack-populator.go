@@ -10,6 +10,7 @@ import (
 	"time"
 
 	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/openstack"
+	libdelta "github.com/konveyor/forklift-controller/pkg/lib/delta"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/klog/v2"
@@ -24,6 +25,7 @@ func main() {
 		secretName       string
 
 		volumePath string
+		delta      bool
 	)
 
 	klog.InitFlags(nil)
@@ -36,13 +38,14 @@ func main() {
 	flag.StringVar(&volumePath, "volume-path", "", "Path to populate")
 	flag.StringVar(&crName, "cr-name", "", "Custom Resource instance name")
 	flag.StringVar(&crNamespace, "cr-namespace", "", "Custom Resource instance namespace")
+	flag.BoolVar(&delta, "delta", false, "Write only the blocks that differ from the volume content")
 
 	flag.Parse()
 
-	populate(volumePath, identityEndpoint, secretName, imageID)
+	populate(volumePath, identityEndpoint, secretName, imageID, delta)
 }
 
-func populate(fileName, identityEndpoint, secretName, imageID string) {
+func populate(fileName, identityEndpoint, secretName, imageID string, delta bool) {
 	http.Handle("/metrics", promhttp.Handler())
 	go http.ListenAndServe(":2112", nil)
 	progressGague := prometheus.NewGaugeVec(
@@ -97,7 +100,15 @@ func populate(fileName, identityEndpoint, secretName, imageID string) {
 	}
 	defer file.Close()
 
-	err = writeData(imageReader, file, imageID, progressGague)
+	if delta {
+		var total, changed int64
+		total, changed, err = libdelta.Write(imageReader, file, func(offset int64) {
+			progressGague.WithLabelValues(imageID).Set(float64(offset))
+		})
+		klog.Info("Total: ", total, " changed: ", changed)
+	} else {
+		err = writeData(imageReader, file, imageID, progressGague)
+	}
 	if err != nil {
 		klog.Fatal(err)
 	}
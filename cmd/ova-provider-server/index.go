@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Object kinds.
+const (
+	KindVM      = "vm"
+	KindDisk    = "disk"
+	KindNetwork = "network"
+)
+
+// Change actions.
+const (
+	Added   = "added"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+const (
+	// Environment variable: index path.
+	EnvIndexPath = "INDEX_PATH"
+	// Default index path. Kept outside the catalog
+	// on the PVC owned by the provider.
+	DefaultIndexPath = "/var/lib/ova-provider-server/index.json"
+	// Max number of changes kept in the journal.
+	MaxJournal = 10000
+)
+
+// Change recorded in the journal.
+type Change struct {
+	// Revision (of the index) in which the change was made.
+	Revision int64 `json:"revision"`
+	// Object kind (vm|disk|network).
+	Kind string `json:"kind"`
+	// Action (added|updated|deleted).
+	Action string `json:"action"`
+	// Object ID.
+	ID string `json:"id"`
+	// The object. The last known object when deleted.
+	Object json.RawMessage `json:"object"`
+}
+
+// Changes since a revision.
+type ChangeList struct {
+	// Index epoch. Changed when the index is rebuilt.
+	Epoch string `json:"epoch"`
+	// Current revision.
+	Revision int64 `json:"revision"`
+	// The changes since the requested revision cannot be
+	// reported. The collections must be listed.
+	Reset bool `json:"reset"`
+	// Changes.
+	Changes []Change `json:"changes"`
+}
+
+// Indexed (OVA or OVF) file.
+type IndexedFile struct {
+	// Stamp (size and modification time) of the
+	// file and the files it references.
+	Stamp string
+	// Parse error.
+	Error string
+	// Parsed envelope.
+	Envelope Envelope
+}
+
+// Persistent index of the catalog.
+// Only new or changed files are parsed on rescan. The objects
+// built from the files are compared with the objects built on
+// the previous scan and the differences are recorded in the
+// journal so that clients may fetch the changes.
+type Index struct {
+	// Catalog root directory.
+	Root string `json:"-"`
+	// Path of the persisted index.
+	Path string `json:"-"`
+	// Epoch. Generated when the index is created.
+	Epoch string
+	// Current revision.
+	Revision int64
+	// Indexed files keyed by path.
+	Files map[string]*IndexedFile
+	// IDs by kind.
+	IDs map[string]map[string]string
+	// Verified integrity keyed by path.
+	Integrity map[string]IntegrityRecord
+	// Objects keyed by kind/ID.
+	Objects map[string]json.RawMessage
+	// Journal.
+	Journal []Change
+	// Current collections.
+	vms      []VM
+	disks    []VmDisk
+	networks []VmNetwork
+	// Mutex.
+	mutex sync.Mutex
+}
+
+// Load the index.
+// A new index (epoch) is created when not found or not readable.
+func (r *Index) Load() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	b, err := os.ReadFile(r.Path)
+	if err == nil {
+		err = json.Unmarshal(b, r)
+		if err != nil {
+			fmt.Println("Error loading index:", err)
+		}
+	}
+	if err != nil || r.Epoch == "" {
+		r.Epoch = uuid.New().String()
+		r.Revision = 0
+		r.Files = nil
+		r.Objects = nil
+		r.Journal = nil
+	}
+	if r.Files == nil {
+		r.Files = make(map[string]*IndexedFile)
+	}
+	if r.IDs == nil {
+		r.IDs = make(map[string]map[string]string)
+	}
+	for _, kind := range []string{KindVM, KindDisk, KindNetwork} {
+		if r.IDs[kind] == nil {
+			r.IDs[kind] = make(map[string]string)
+		}
+	}
+	if r.Objects == nil {
+		r.Objects = make(map[string]json.RawMessage)
+	}
+	vmIDMap = &UUIDMap{m: r.IDs[KindVM]}
+	diskIDMap = &UUIDMap{m: r.IDs[KindDisk]}
+	networkIDMap = &UUIDMap{m: r.IDs[KindNetwork]}
+	integrity.Restore(r.Integrity)
+}
+
+// Rescan the catalog.
+// Only new and changed files are parsed.
+func (r *Index) Rescan() (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ovaFiles, ovfFiles, err := findOVAFiles(r.Root)
+	if err != nil {
+		return
+	}
+	found := map[string]bool{}
+	dirty := false
+	for _, path := range append(ovaFiles, ovfFiles...) {
+		found[path] = true
+		indexed, cached := r.Files[path]
+		if cached && indexed.Stamp == fileStamp(path, &indexed.Envelope) {
+			continue
+		}
+		fmt.Println("Processing file:", path)
+		indexed = &IndexedFile{}
+		var envelope *Envelope
+		var pErr error
+		if strings.HasSuffix(strings.ToLower(path), ".ovf") {
+			envelope, pErr = readOVF(path)
+		} else {
+			envelope, pErr = readOVFFromOVA(path)
+		}
+		if pErr != nil {
+			fmt.Println("Error processing OVF:", path, pErr)
+			indexed.Error = pErr.Error()
+		} else {
+			indexed.Envelope = *envelope
+		}
+		indexed.Stamp = fileStamp(path, &indexed.Envelope)
+		r.Files[path] = indexed
+		dirty = true
+	}
+	for path := range r.Files {
+		if !found[path] {
+			delete(r.Files, path)
+			dirty = true
+		}
+	}
+	var envelopes []Envelope
+	var paths []string
+	for _, path := range r.sortedPaths() {
+		indexed := r.Files[path]
+		if indexed.Error != "" {
+			continue
+		}
+		envelopes = append(envelopes, indexed.Envelope)
+		paths = append(paths, path)
+	}
+	r.vms, err = convertToVmStruct(envelopes, paths)
+	if err != nil {
+		return
+	}
+	r.disks, err = convertToDiskStruct(envelopes, paths)
+	if err != nil {
+		return
+	}
+	r.networks, err = convertToNetworkStruct(envelopes)
+	if err != nil {
+		return
+	}
+	r.dedup()
+	changed, err := r.journal()
+	if err != nil {
+		return
+	}
+	if dirty || changed {
+		r.save()
+	}
+	return
+}
+
+// Remove objects with duplicate IDs.
+// Networks are referenced (by name) by many OVAs and the
+// same OVA (VM UUID) may be found in more than one location.
+// The first object found is kept.
+func (r *Index) dedup() {
+	seen := map[string]bool{}
+	vms := []VM{}
+	for _, vm := range r.vms {
+		if !seen[vm.UUID] {
+			seen[vm.UUID] = true
+			vms = append(vms, vm)
+		}
+	}
+	r.vms = vms
+	seen = map[string]bool{}
+	disks := []VmDisk{}
+	for _, disk := range r.disks {
+		if !seen[disk.ID] {
+			seen[disk.ID] = true
+			disks = append(disks, disk)
+		}
+	}
+	r.disks = disks
+	seen = map[string]bool{}
+	networks := []VmNetwork{}
+	for _, network := range r.networks {
+		if !seen[network.ID] {
+			seen[network.ID] = true
+			networks = append(networks, network)
+		}
+	}
+	r.networks = networks
+}
+
+// Record the changes made to the objects in the journal.
+func (r *Index) journal() (changed bool, err error) {
+	objects := make(map[string]json.RawMessage)
+	add := func(kind, id string, object interface{}) (err error) {
+		b, err := json.Marshal(object)
+		if err != nil {
+			return
+		}
+		objects[kind+"/"+id] = b
+		return
+	}
+	for _, vm := range r.vms {
+		err = add(KindVM, vm.UUID, vm)
+		if err != nil {
+			return
+		}
+	}
+	for _, disk := range r.disks {
+		err = add(KindDisk, disk.ID, disk)
+		if err != nil {
+			return
+		}
+	}
+	for _, network := range r.networks {
+		err = add(KindNetwork, network.ID, network)
+		if err != nil {
+			return
+		}
+	}
+	keys := []string{}
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	for key := range r.Objects {
+		if _, found := objects[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		kind, id, _ := strings.Cut(key, "/")
+		last, existed := r.Objects[key]
+		object, exists := objects[key]
+		change := Change{Kind: kind, ID: id, Object: object}
+		switch {
+		case !existed:
+			change.Action = Added
+		case !exists:
+			change.Action = Deleted
+			change.Object = last
+		case !bytes.Equal(last, object):
+			change.Action = Updated
+		default:
+			continue
+		}
+		r.Revision++
+		change.Revision = r.Revision
+		r.Journal = append(r.Journal, change)
+		changed = true
+	}
+	if len(r.Journal) > MaxJournal {
+		r.Journal = r.Journal[len(r.Journal)-MaxJournal:]
+	}
+	r.Objects = objects
+	return
+}
+
+// Changes since the revision.
+// Reset is reported when the epoch does not match or
+// the changes are no longer (or not yet) in the journal.
+func (r *Index) Changes(epoch string, since int64) (list ChangeList) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	list.Epoch = r.Epoch
+	list.Revision = r.Revision
+	list.Changes = []Change{}
+	oldest := r.Revision
+	if len(r.Journal) > 0 {
+		oldest = r.Journal[0].Revision - 1
+	}
+	if epoch != r.Epoch || since > r.Revision || since < oldest {
+		list.Reset = true
+		return
+	}
+	n := sort.Search(len(r.Journal), func(i int) bool {
+		return r.Journal[i].Revision > since
+	})
+	list.Changes = append(list.Changes, r.Journal[n:]...)
+	return
+}
+
+// Current VMs.
+func (r *Index) VMs() []VM {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.vms
+}
+
+// Current disks.
+func (r *Index) Disks() []VmDisk {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.disks
+}
+
+// Current networks.
+func (r *Index) Networks() []VmNetwork {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.networks
+}
+
+// Sorted file paths.
+func (r *Index) sortedPaths() (paths []string) {
+	for path := range r.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return
+}
+
+// Save the index.
+// Errors are logged. The index is kept in memory.
+func (r *Index) save() {
+	r.Integrity = integrity.Records()
+	b, err := json.Marshal(r)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(r.Path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(r.Path+PartialSuffix, b, 0644)
+	}
+	if err == nil {
+		err = os.Rename(r.Path+PartialSuffix, r.Path)
+	}
+	if err != nil {
+		fmt.Println("Error saving index:", err)
+	}
+}
+
+// Index path.
+func indexPath() string {
+	if p := os.Getenv(EnvIndexPath); p != "" {
+		return p
+	}
+	return DefaultIndexPath
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestIndexJournal(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	index := &Index{Epoch: "1"}
+	actions := func(changes []Change) (list []string) {
+		for _, change := range changes {
+			list = append(list, change.Action+" "+change.Kind+"/"+change.ID)
+		}
+		return
+	}
+	// Added.
+	index.vms = []VM{{UUID: "vm-1", Name: "one"}, {UUID: "vm-2", Name: "two"}}
+	index.disks = []VmDisk{{ID: "disk-1"}}
+	index.networks = []VmNetwork{{ID: "net-1"}}
+	changed, err := index.journal()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(changed).To(gomega.BeTrue())
+	g.Expect(index.Revision).To(gomega.Equal(int64(4)))
+	g.Expect(actions(index.Journal)).To(gomega.Equal(
+		[]string{
+			"added disk/disk-1",
+			"added network/net-1",
+			"added vm/vm-1",
+			"added vm/vm-2",
+		}))
+	// Unchanged.
+	changed, err = index.journal()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(changed).To(gomega.BeFalse())
+	g.Expect(index.Journal).To(gomega.HaveLen(4))
+	// Updated and deleted.
+	index.vms = []VM{{UUID: "vm-1", Name: "renamed"}}
+	changed, err = index.journal()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(changed).To(gomega.BeTrue())
+	g.Expect(index.Revision).To(gomega.Equal(int64(6)))
+	g.Expect(actions(index.Journal[4:])).To(gomega.Equal(
+		[]string{
+			"updated vm/vm-1",
+			"deleted vm/vm-2",
+		}))
+	// The last known object is reported when deleted.
+	deleted := index.Journal[5]
+	g.Expect(deleted.Revision).To(gomega.Equal(int64(6)))
+	g.Expect(string(deleted.Object)).To(gomega.ContainSubstring(`"Name":"two"`))
+}
+
+func TestIndexJournalTruncated(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	index := &Index{Epoch: "1"}
+	for i := 0; i < MaxJournal+1; i++ {
+		index.Journal = append(index.Journal, Change{Revision: int64(i + 1)})
+	}
+	index.Revision = MaxJournal + 1
+	index.networks = []VmNetwork{{ID: "net-1"}}
+	_, err := index.journal()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(index.Journal).To(gomega.HaveLen(MaxJournal))
+	g.Expect(index.Journal[0].Revision).To(gomega.Equal(int64(3)))
+	g.Expect(index.Journal[MaxJournal-1].Revision).To(gomega.Equal(int64(MaxJournal + 2)))
+}
+
+func TestIndexChanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	index := &Index{Epoch: "1", Revision: 5}
+	for i := 3; i <= 5; i++ {
+		index.Journal = append(index.Journal, Change{Revision: int64(i)})
+	}
+	revisions := func(list ChangeList) (revisions []int64) {
+		for _, change := range list.Changes {
+			revisions = append(revisions, change.Revision)
+		}
+		return
+	}
+	// Changes since the revision.
+	list := index.Changes("1", 3)
+	g.Expect(list.Reset).To(gomega.BeFalse())
+	g.Expect(list.Epoch).To(gomega.Equal("1"))
+	g.Expect(list.Revision).To(gomega.Equal(int64(5)))
+	g.Expect(revisions(list)).To(gomega.Equal([]int64{4, 5}))
+	// Oldest revision still in the journal.
+	list = index.Changes("1", 2)
+	g.Expect(list.Reset).To(gomega.BeFalse())
+	g.Expect(revisions(list)).To(gomega.Equal([]int64{3, 4, 5}))
+	// Current.
+	list = index.Changes("1", 5)
+	g.Expect(list.Reset).To(gomega.BeFalse())
+	g.Expect(list.Changes).To(gomega.BeEmpty())
+	// No longer in the journal.
+	list = index.Changes("1", 1)
+	g.Expect(list.Reset).To(gomega.BeTrue())
+	g.Expect(list.Changes).To(gomega.BeEmpty())
+	// Not yet in the journal.
+	list = index.Changes("1", 6)
+	g.Expect(list.Reset).To(gomega.BeTrue())
+	// Epoch changed.
+	list = index.Changes("2", 4)
+	g.Expect(list.Reset).To(gomega.BeTrue())
+	g.Expect(list.Epoch).To(gomega.Equal("1"))
+}
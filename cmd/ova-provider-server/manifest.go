@@ -0,0 +1,391 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Manifest entry. Example:
+//
+//	SHA256(disk1.vmdk)= 3b4c...
+var manifestEntry = regexp.MustCompile(`^(SHA1|SHA256|SHA512)\((.+)\)\s*=\s*([0-9a-fA-F]+)\s*$`)
+
+// Integrity of an OVA (or OVF) and the files it references.
+type Integrity struct {
+	// The manifest (.mf) has been found.
+	Manifest bool
+	// Referenced (or listed) files not found.
+	Missing []string
+	// Files not matching the manifest checksum.
+	Corrupt []string
+}
+
+// Checksum listed in the manifest.
+type Checksum struct {
+	Algorithm string
+	Digest    string
+}
+
+// Build the hash for the algorithm.
+func (r *Checksum) hash() hash.Hash {
+	switch r.Algorithm {
+	case "SHA1":
+		return sha1.New()
+	case "SHA512":
+		return sha512.New()
+	default:
+		return sha256.New()
+	}
+}
+
+// Parse the manifest.
+// Returns checksums keyed by file name.
+func parseManifest(reader io.Reader) (checksums map[string]Checksum, err error) {
+	checksums = make(map[string]Checksum)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		matched := manifestEntry.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if matched == nil {
+			continue
+		}
+		checksums[matched[2]] = Checksum{
+			Algorithm: matched[1],
+			Digest:    strings.ToLower(matched[3]),
+		}
+	}
+	err = scanner.Err()
+	return
+}
+
+// Names of the files referenced by the envelope.
+// Chunked files are referenced as: <href>.000000000, <href>.000000001, ...
+func referencedFiles(envelope *Envelope) (names []string) {
+	for _, file := range envelope.References.File {
+		if file.ChunkSize > 0 && file.Size > 0 {
+			chunks := (file.Size + file.ChunkSize - 1) / file.ChunkSize
+			for n := int64(0); n < chunks; n++ {
+				names = append(names, chunkName(file.Href, n))
+			}
+			continue
+		}
+		names = append(names, file.Href)
+	}
+	return
+}
+
+// Name of a file chunk.
+func chunkName(href string, n int64) string {
+	return fmt.Sprintf("%s.%09d", href, n)
+}
+
+// Open an OVA.
+// Gzip compressed OVAs are decompressed.
+func openOVA(path string) (reader io.Reader, closer io.Closer, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	buffered := bufio.NewReader(file)
+	magic, _ := buffered.Peek(2)
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(buffered)
+		if err != nil {
+			_ = file.Close()
+			return
+		}
+		reader = gz
+	} else {
+		reader = buffered
+	}
+	closer = file
+	return
+}
+
+// Verify the integrity of the OVA (tar).
+// The tar is read twice: the first pass finds the members
+// and the manifest, the second pass computes the checksums.
+func verifyOVA(path string, envelope *Envelope) (integrity Integrity, err error) {
+	members := map[string]bool{}
+	var checksums map[string]Checksum
+	err = walkOVA(path, func(name string, reader io.Reader) (err error) {
+		members[name] = true
+		if strings.HasSuffix(strings.ToLower(name), ".mf") {
+			checksums, err = parseManifest(reader)
+		}
+		return
+	})
+	if err != nil {
+		return
+	}
+	integrity.Manifest = checksums != nil
+	expected := expectedFiles(envelope, checksums)
+	for _, name := range expected {
+		if !members[name] {
+			integrity.Missing = append(integrity.Missing, name)
+		}
+	}
+	if len(checksums) == 0 {
+		return
+	}
+	err = walkOVA(path, func(name string, reader io.Reader) (err error) {
+		checksum, found := checksums[name]
+		if !found {
+			return
+		}
+		matched, err := verifyChecksum(reader, checksum)
+		if err != nil {
+			return
+		}
+		if !matched {
+			integrity.Corrupt = append(integrity.Corrupt, name)
+		}
+		return
+	})
+	return
+}
+
+// Walk the members of the OVA (tar).
+func walkOVA(path string, fn func(name string, reader io.Reader) error) (err error) {
+	reader, closer, err := openOVA(path)
+	if err != nil {
+		return
+	}
+	defer closer.Close()
+	tarReader := tar.NewReader(reader)
+	for {
+		var header *tar.Header
+		header, err = tarReader.Next()
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			return
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		err = fn(filepath.Base(header.Name), tarReader)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Verify the integrity of the OVF and the files
+// found in the same directory.
+func verifyOVF(path string, envelope *Envelope) (integrity Integrity, err error) {
+	dir := filepath.Dir(path)
+	manifest := strings.TrimSuffix(path, filepath.Ext(path)) + ".mf"
+	var checksums map[string]Checksum
+	file, err := os.Open(manifest)
+	if err == nil {
+		checksums, err = parseManifest(file)
+		_ = file.Close()
+		if err != nil {
+			return
+		}
+	} else if os.IsNotExist(err) {
+		err = nil
+	} else {
+		return
+	}
+	integrity.Manifest = checksums != nil
+	for _, name := range expectedFiles(envelope, checksums) {
+		file, err = os.Open(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+				integrity.Missing = append(integrity.Missing, name)
+				continue
+			}
+			return
+		}
+		checksum, found := checksums[name]
+		if found {
+			var matched bool
+			matched, err = verifyChecksum(file, checksum)
+			if err == nil && !matched {
+				integrity.Corrupt = append(integrity.Corrupt, name)
+			}
+		}
+		_ = file.Close()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Files expected to be found.
+// The files referenced by the envelope and listed in the manifest.
+func expectedFiles(envelope *Envelope, checksums map[string]Checksum) (names []string) {
+	set := map[string]bool{}
+	for _, name := range referencedFiles(envelope) {
+		set[name] = true
+	}
+	for name := range checksums {
+		set[name] = true
+	}
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Compute and compare the checksum.
+func verifyChecksum(reader io.Reader, checksum Checksum) (matched bool, err error) {
+	h := checksum.hash()
+	_, err = io.Copy(h, reader)
+	if err != nil {
+		return
+	}
+	matched = hex.EncodeToString(h.Sum(nil)) == checksum.Digest
+	return
+}
+
+// Integrity verified in the background and cached.
+// The checksums of large disks cannot be computed while
+// the inventory is being served. The (cached) result is
+// keyed by path and invalidated when the files change.
+type IntegrityCache struct {
+	mutex   sync.Mutex
+	entries map[string]*integrityEntry
+	queue   chan *integrityTask
+}
+
+// Cached entry.
+type integrityEntry struct {
+	stamp     string
+	integrity Integrity
+	pending   bool
+}
+
+// Verification task.
+type integrityTask struct {
+	path     string
+	stamp    string
+	envelope Envelope
+}
+
+// Start the verifier.
+func (r *IntegrityCache) Start() {
+	r.entries = make(map[string]*integrityEntry)
+	r.queue = make(chan *integrityTask, 1024)
+	go func() {
+		for task := range r.queue {
+			var integrity Integrity
+			var err error
+			if strings.HasSuffix(strings.ToLower(task.path), ".ovf") {
+				integrity, err = verifyOVF(task.path, &task.envelope)
+			} else {
+				integrity, err = verifyOVA(task.path, &task.envelope)
+			}
+			if err != nil {
+				fmt.Println("Error verifying integrity:", task.path, err)
+			}
+			r.mutex.Lock()
+			r.entries[task.path] = &integrityEntry{
+				stamp:     task.stamp,
+				integrity: integrity,
+			}
+			r.mutex.Unlock()
+		}
+	}()
+}
+
+// Verified integrity (record).
+type IntegrityRecord struct {
+	Stamp     string
+	Integrity Integrity
+}
+
+// Verified integrity records keyed by path.
+func (r *IntegrityCache) Records() (records map[string]IntegrityRecord) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	records = make(map[string]IntegrityRecord)
+	for path, entry := range r.entries {
+		if entry.pending {
+			continue
+		}
+		records[path] = IntegrityRecord{
+			Stamp:     entry.stamp,
+			Integrity: entry.integrity,
+		}
+	}
+	return
+}
+
+// Restore the verified integrity records.
+func (r *IntegrityCache) Restore(records map[string]IntegrityRecord) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for path, record := range records {
+		r.entries[path] = &integrityEntry{
+			stamp:     record.Stamp,
+			integrity: record.Integrity,
+		}
+	}
+}
+
+// Get the integrity of the OVA (or OVF).
+// Returns found=false when not (yet) verified.
+func (r *IntegrityCache) Get(path string, envelope *Envelope) (integrity Integrity, found bool) {
+	stamp := fileStamp(path, envelope)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry, cached := r.entries[path]
+	if cached && entry.stamp == stamp {
+		integrity = entry.integrity
+		found = !entry.pending
+		return
+	}
+	select {
+	case r.queue <- &integrityTask{path: path, stamp: stamp, envelope: *envelope}:
+		r.entries[path] = &integrityEntry{stamp: stamp, pending: true}
+	default:
+		// full.
+	}
+	return
+}
+
+// Stamp (size and modification time) of the OVA or the OVF
+// directory, used to detect changes.
+func fileStamp(path string, envelope *Envelope) string {
+	paths := []string{path}
+	if strings.HasSuffix(strings.ToLower(path), ".ovf") {
+		dir := filepath.Dir(path)
+		paths = append(paths, strings.TrimSuffix(path, filepath.Ext(path))+".mf")
+		for _, name := range referencedFiles(envelope) {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+	stamp := []string{}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			stamp = append(stamp, p+":-")
+			continue
+		}
+		stamp = append(stamp, fmt.Sprintf("%s:%d:%d", p, info.Size(), info.ModTime().UnixNano()))
+	}
+	return strings.Join(stamp, ",")
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestParseManifest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	checksums, err := parseManifest(
+		strings.NewReader(
+			strings.Join(
+				[]string{
+					"SHA256(vm.ovf)= 3B4C",
+					"  SHA1(disk1.vmdk) = abcd  ",
+					"SHA512(disk 2.vmdk)=ef01",
+					"MD5(disk3.vmdk)= 1234",
+					"garbage",
+				},
+				"\n")))
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(checksums).To(gomega.Equal(
+		map[string]Checksum{
+			"vm.ovf":      {Algorithm: "SHA256", Digest: "3b4c"},
+			"disk1.vmdk":  {Algorithm: "SHA1", Digest: "abcd"},
+			"disk 2.vmdk": {Algorithm: "SHA512", Digest: "ef01"},
+		}))
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	content := []byte("disk content")
+	sum1 := sha1.Sum(content)
+	sum256 := sha256.Sum256(content)
+	sum512 := sha512.Sum512(content)
+	cases := []struct {
+		name     string
+		checksum Checksum
+		matched  bool
+	}{
+		{
+			name:     "SHA1",
+			checksum: Checksum{Algorithm: "SHA1", Digest: hex.EncodeToString(sum1[:])},
+			matched:  true,
+		},
+		{
+			name:     "SHA256",
+			checksum: Checksum{Algorithm: "SHA256", Digest: hex.EncodeToString(sum256[:])},
+			matched:  true,
+		},
+		{
+			name:     "SHA512",
+			checksum: Checksum{Algorithm: "SHA512", Digest: hex.EncodeToString(sum512[:])},
+			matched:  true,
+		},
+		{
+			name:     "wrong algorithm",
+			checksum: Checksum{Algorithm: "SHA1", Digest: hex.EncodeToString(sum256[:])},
+		},
+		{
+			name:     "corrupted digest",
+			checksum: Checksum{Algorithm: "SHA256", Digest: "0" + hex.EncodeToString(sum256[1:])},
+		},
+		{
+			name:     "empty digest",
+			checksum: Checksum{Algorithm: "SHA256"},
+		},
+	}
+	for _, c := range cases {
+		matched, err := verifyChecksum(strings.NewReader(string(content)), c.checksum)
+		g.Expect(err).To(gomega.BeNil(), c.name)
+		g.Expect(matched).To(gomega.Equal(c.matched), c.name)
+	}
+}
+
+func TestVerifyOVA(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disk := "disk content"
+	sum := sha256.Sum256([]byte(disk))
+	digest := hex.EncodeToString(sum[:])
+	envelope := &Envelope{
+		References: References{
+			File: []File{
+				{Href: "disk1.vmdk"},
+			},
+		},
+	}
+	cases := []struct {
+		name     string
+		members  map[string]string
+		gzip     bool
+		expected Integrity
+	}{
+		{
+			name: "valid",
+			members: map[string]string{
+				"vm.ovf":     "",
+				"vm.mf":      "SHA256(disk1.vmdk)= " + digest,
+				"disk1.vmdk": disk,
+			},
+			expected: Integrity{Manifest: true},
+		},
+		{
+			name: "valid compressed",
+			members: map[string]string{
+				"vm.ovf":     "",
+				"vm.mf":      "SHA256(disk1.vmdk)= " + digest,
+				"disk1.vmdk": disk,
+			},
+			gzip:     true,
+			expected: Integrity{Manifest: true},
+		},
+		{
+			name: "no manifest",
+			members: map[string]string{
+				"vm.ovf":     "",
+				"disk1.vmdk": disk,
+			},
+			expected: Integrity{},
+		},
+		{
+			name: "corrupted disk",
+			members: map[string]string{
+				"vm.ovf":     "",
+				"vm.mf":      "SHA256(disk1.vmdk)= " + digest,
+				"disk1.vmdk": "corrupted",
+			},
+			expected: Integrity{Manifest: true, Corrupt: []string{"disk1.vmdk"}},
+		},
+		{
+			name: "corrupted manifest digest",
+			members: map[string]string{
+				"vm.ovf":     "",
+				"vm.mf":      "SHA256(disk1.vmdk)= " + strings.Repeat("0", len(digest)),
+				"disk1.vmdk": disk,
+			},
+			expected: Integrity{Manifest: true, Corrupt: []string{"disk1.vmdk"}},
+		},
+		{
+			name: "manifest lists a missing file",
+			members: map[string]string{
+				"vm.ovf":     "",
+				"vm.mf":      "SHA256(disk1.vmdk)= " + digest + "\nSHA256(disk2.vmdk)= " + digest,
+				"disk1.vmdk": disk,
+			},
+			expected: Integrity{Manifest: true, Missing: []string{"disk2.vmdk"}},
+		},
+		{
+			name: "referenced file missing",
+			members: map[string]string{
+				"vm.ovf": "",
+				"vm.mf":  "",
+			},
+			expected: Integrity{Manifest: true, Missing: []string{"disk1.vmdk"}},
+		},
+	}
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "vm.ova")
+		writeOVA(g, path, c.members, c.gzip)
+		integrity, err := verifyOVA(path, envelope)
+		g.Expect(err).To(gomega.BeNil(), c.name)
+		g.Expect(integrity).To(gomega.Equal(c.expected), c.name)
+	}
+}
+
+func TestVerifyOVF(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disk := "disk content"
+	sum := sha1.Sum([]byte(disk))
+	digest := hex.EncodeToString(sum[:])
+	envelope := &Envelope{
+		References: References{
+			File: []File{
+				{Href: "disk1.vmdk", Size: 12, ChunkSize: 8},
+			},
+		},
+	}
+	cases := []struct {
+		name     string
+		files    map[string]string
+		expected Integrity
+	}{
+		{
+			name: "valid chunked",
+			files: map[string]string{
+				"vm.ovf":               "",
+				"vm.mf":                "SHA1(disk1.vmdk.000000000)= " + digest,
+				"disk1.vmdk.000000000": disk,
+				"disk1.vmdk.000000001": "",
+			},
+			expected: Integrity{Manifest: true},
+		},
+		{
+			name: "corrupted chunk",
+			files: map[string]string{
+				"vm.ovf":               "",
+				"vm.mf":                "SHA1(disk1.vmdk.000000000)= " + digest,
+				"disk1.vmdk.000000000": "corrupted",
+				"disk1.vmdk.000000001": "",
+			},
+			expected: Integrity{Manifest: true, Corrupt: []string{"disk1.vmdk.000000000"}},
+		},
+		{
+			name: "missing chunk",
+			files: map[string]string{
+				"vm.ovf":               "",
+				"disk1.vmdk.000000000": disk,
+			},
+			expected: Integrity{Missing: []string{"disk1.vmdk.000000001"}},
+		},
+	}
+	for _, c := range cases {
+		dir := t.TempDir()
+		for name, content := range c.files {
+			err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+			g.Expect(err).To(gomega.BeNil(), c.name)
+		}
+		integrity, err := verifyOVF(filepath.Join(dir, "vm.ovf"), envelope)
+		g.Expect(err).To(gomega.BeNil(), c.name)
+		g.Expect(integrity).To(gomega.Equal(c.expected), c.name)
+	}
+}
+
+// Write an OVA (tar) with the specified members.
+func writeOVA(g *gomega.WithT, path string, members map[string]string, compressed bool) {
+	file, err := os.Create(path)
+	g.Expect(err).To(gomega.BeNil())
+	defer file.Close()
+	var writer io.Writer = file
+	if compressed {
+		gz := gzip.NewWriter(file)
+		defer gz.Close()
+		writer = gz
+	}
+	tw := tar.NewWriter(writer)
+	defer tw.Close()
+	for name, content := range members {
+		err = tw.WriteHeader(
+			&tar.Header{
+				Name:     name,
+				Mode:     0644,
+				Size:     int64(len(content)),
+				Typeflag: tar.TypeReg,
+			})
+		g.Expect(err).To(gomega.BeNil())
+		_, err = tw.Write([]byte(content))
+		g.Expect(err).To(gomega.BeNil())
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
@@ -56,9 +57,15 @@ type VirtualHardwareSection struct {
 }
 
 type References struct {
-	File []struct {
-		Href string `xml:"href,attr"`
-	} `xml:"File"`
+	File []File `xml:"File"`
+}
+
+type File struct {
+	ID          string `xml:"id,attr"`
+	Href        string `xml:"href,attr"`
+	Size        int64  `xml:"size,attr"`
+	Compression string `xml:"compression,attr"`
+	ChunkSize   int64  `xml:"chunkSize,attr"`
 }
 
 type DiskSection struct {
@@ -136,6 +143,9 @@ type VM struct {
 	NICs                  []NIC
 	Disks                 []VmDisk
 	Networks              []VmNetwork
+	IntegrityVerified     bool
+	MissingFiles          []string
+	CorruptFiles          []string
 }
 
 // Virtual Disk.
@@ -149,6 +159,9 @@ type VmDisk struct {
 	FileRef                 string
 	Format                  string
 	PopulatedSize           int64
+	Shared                  bool
+	Compression             string
+	ChunkSize               int64
 }
 
 // Virtual Device.
@@ -178,16 +191,33 @@ type VmNetwork struct {
 var vmIDMap *UUIDMap
 var diskIDMap *UUIDMap
 var networkIDMap *UUIDMap
+var integrity *IntegrityCache
+var index *Index
 
 func main() {
 
-	vmIDMap = NewUUIDMap()
-	diskIDMap = NewUUIDMap()
-	networkIDMap = NewUUIDMap()
+	integrity = &IntegrityCache{}
+	integrity.Start()
+	index = &Index{Root: CatalogRoot, Path: indexPath()}
+	index.Load()
+
+	source, err := sourceFromEnv()
+	if err != nil {
+		log.Fatal("Error building source: ", err)
+	}
+	if source != nil {
+		mirror := &Mirror{
+			Source:   source,
+			Root:     MirrorRoot,
+			Interval: syncInterval(),
+		}
+		mirror.Start()
+	}
 
 	http.HandleFunc("/vms", vmHandler)
 	http.HandleFunc("/disks", diskHandler)
 	http.HandleFunc("/networks", networkHandler)
+	http.HandleFunc("/changes", changesHandler)
 	http.HandleFunc("/watch", watchdHandler)
 	http.HandleFunc("/test_connection", connHandler)
 
@@ -206,14 +236,13 @@ func vmHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, invalidRequestMethodMsg, http.StatusMethodNotAllowed)
 		return
 	}
-	vmXML, ovaPath := scanOVAsOnNFS()
-	vmStruct, err := convertToVmStruct(vmXML, ovaPath)
+	err := index.Rescan()
 	if err != nil {
 		fmt.Println(errorProcessingOvfMsg, err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(vmStruct)
+	json.NewEncoder(w).Encode(index.VMs())
 	fmt.Println("VM handeler was called")
 }
 
@@ -222,15 +251,14 @@ func diskHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, invalidRequestMethodMsg, http.StatusMethodNotAllowed)
 		return
 	}
-	xmlStruct, ovaPath := scanOVAsOnNFS()
-	diskStruct, err := convertToDiskStruct(xmlStruct, ovaPath)
+	err := index.Rescan()
 	if err != nil {
 		fmt.Println(errorProcessingOvfMsg, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(diskStruct)
+	json.NewEncoder(w).Encode(index.Disks())
 	fmt.Println("Disk handeler was called")
 }
 
@@ -239,95 +267,78 @@ func networkHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, invalidRequestMethodMsg, http.StatusMethodNotAllowed)
 		return
 	}
-	xmlStruct, _ := scanOVAsOnNFS()
-	netStruct, err := convertToNetworkStruct(xmlStruct)
+	err := index.Rescan()
 	if err != nil {
 		fmt.Println(errorProcessingOvfMsg, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(netStruct)
+	json.NewEncoder(w).Encode(index.Networks())
 	fmt.Println("Network handeler was called")
 }
 
-func watchdHandler(w http.ResponseWriter, r *http.Request) {
-	fmt.Println(w, "This is the watch page!")
-	//TODO add watch
-}
-
-func scanOVAsOnNFS() (envelopes []Envelope, ovaPaths []string) {
-	ovaFiles, ovfFiles, err := findOVAFiles("/ova")
-	if err != nil {
-		fmt.Println("Error finding OVA anf OVF files:", err)
+// Changes since a revision.
+// Query: epoch=<epoch>&since=<revision>.
+func changesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, invalidRequestMethodMsg, http.StatusMethodNotAllowed)
 		return
 	}
-
-	var filesPath []string
-
-	for _, ovaFile := range ovaFiles {
-		fmt.Println("Processing OVA file:", ovaFile)
-
-		xmlStruct, err := readOVFFromOVA(ovaFile)
-		if err != nil {
-			fmt.Println("Error processing OVF from OVA:", err)
-			continue
-		}
-		envelopes = append(envelopes, *xmlStruct)
-		filesPath = append(filesPath, ovaFile)
+	since, err := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+	if err != nil {
+		since = -1
+	}
+	err = index.Rescan()
+	if err != nil {
+		fmt.Println(errorProcessingOvfMsg, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	for _, ovfFile := range ovfFiles {
-		fmt.Println("Processing OVF file:", ovfFile)
-
-		xmlStruct, err := readOVF(ovfFile)
-		if err != nil {
-			fmt.Println("Error processing OVF:", err)
-			continue
-		}
-		envelopes = append(envelopes, *xmlStruct)
-		filesPath = append(filesPath, ovfFile)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(index.Changes(r.URL.Query().Get("epoch"), since))
+}
 
-	}
-	return envelopes, filesPath
+func watchdHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Println(w, "This is the watch page!")
+	//TODO add watch
 }
 
+// Find the OVA and OVF files.
+// The directory tree is walked. Files in the root directory are ignored.
+// Compressed (gzip) OVAs are supported.
 func findOVAFiles(directory string) ([]string, []string, error) {
-	childs, err := os.ReadDir(directory)
-	if err != nil {
-		return nil, nil, err
-	}
-
 	var ovaFiles, ovfFiles []string
-	for _, child := range childs {
-		if !child.IsDir() {
-			continue
-		}
-		newDir := directory + "/" + child.Name()
-		files, err := os.ReadDir(newDir)
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
-		for _, file := range files {
-			path := filepath.Join(directory, child.Name(), file.Name())
-			switch {
-			case strings.HasSuffix(strings.ToLower(file.Name()), ".ova"):
-				ovaFiles = append(ovaFiles, path)
-			case strings.HasSuffix(strings.ToLower(file.Name()), ".ovf"):
-				ovfFiles = append(ovfFiles, path)
-			}
+		if entry.IsDir() || filepath.Dir(path) == filepath.Clean(directory) {
+			return nil
+		}
+		name := strings.ToLower(entry.Name())
+		switch {
+		case strings.HasSuffix(name, ".ova"), strings.HasSuffix(name, ".ova.gz"):
+			ovaFiles = append(ovaFiles, path)
+		case strings.HasSuffix(name, ".ovf"):
+			ovfFiles = append(ovfFiles, path)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 	return ovaFiles, ovfFiles, nil
 }
 
 func readOVFFromOVA(ovaFile string) (*Envelope, error) {
 	var envelope Envelope
-	file, err := os.Open(ovaFile)
+	file, closer, err := openOVA(ovaFile)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	defer closer.Close()
 
 	reader := tar.NewReader(file)
 	for {
@@ -428,6 +439,11 @@ func convertToVmStruct(envelope []Envelope, ovaPath []string) ([]VM, error) {
 					Name:                    name,
 				})
 				newVM.Disks[j].ID = diskIDMap.GetUUID(newVM.Disks[j], ovaPath[i]+"/"+name)
+				newVM.Disks[j].Shared = isSharedDisk(virtualSystem.HardwareSection.Items, disk.DiskId)
+				if file, found := findFile(vmXml.References, disk.FileRef); found {
+					newVM.Disks[j].Compression = file.Compression
+					newVM.Disks[j].ChunkSize = file.ChunkSize
+				}
 
 			}
 
@@ -459,6 +475,13 @@ func convertToVmStruct(envelope []Envelope, ovaPath []string) ([]VM, error) {
 			}
 			newVM.UUID = id
 
+			verified, found := integrity.Get(ovaPath[i], &vmXml)
+			if found {
+				newVM.IntegrityVerified = true
+				newVM.MissingFiles = verified.Missing
+				newVM.CorruptFiles = verified.Corrupt
+			}
+
 			vms = append(vms, newVM)
 		}
 	}
@@ -504,6 +527,33 @@ func convertToDiskStruct(envelope []Envelope, ovaPath []string) ([]VmDisk, error
 	return disks, nil
 }
 
+// Determine if the disk is attached with multi-writer sharing.
+// The disk item references the disk by: ovf:/disk/<diskId>.
+func isSharedDisk(items []Item, diskId string) bool {
+	for _, item := range items {
+		if !strings.HasSuffix(item.HostResource, "/"+diskId) {
+			continue
+		}
+		for _, conf := range item.Configs {
+			if conf.Key == "sharing" && conf.Value != "sharingNone" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Find the file referenced by ID.
+func findFile(references References, id string) (file File, found bool) {
+	for _, file = range references.File {
+		if file.ID == id {
+			found = true
+			return
+		}
+	}
+	return
+}
+
 func getDiskPath(path string) string {
 	if filepath.Ext(path) != ".ovf" {
 		return path
@@ -520,12 +570,6 @@ type UUIDMap struct {
 	m map[string]string
 }
 
-func NewUUIDMap() *UUIDMap {
-	return &UUIDMap{
-		m: make(map[string]string),
-	}
-}
-
 func (um *UUIDMap) GetUUID(object interface{}, key string) string {
 	var id string
 	id, ok := um.m[key]
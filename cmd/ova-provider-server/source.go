@@ -0,0 +1,594 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	liburl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source types.
+const (
+	SourceNFS  = "nfs"
+	SourceHTTP = "http"
+	SourceS3   = "s3"
+)
+
+// Environment variables.
+const (
+	EnvSource             = "SOURCE"
+	EnvSourceURL          = "SOURCE_URL"
+	EnvS3Region           = "S3_REGION"
+	EnvS3AccessKeyID      = "S3_ACCESS_KEY_ID"
+	EnvS3SecretAccessKey  = "S3_SECRET_ACCESS_KEY"
+	EnvUser               = "SOURCE_USER"
+	EnvPassword           = "SOURCE_PASSWORD"
+	EnvInsecureSkipVerify = "INSECURE_SKIP_VERIFY"
+	EnvSyncInterval       = "SYNC_INTERVAL"
+)
+
+const (
+	// Catalog root.
+	CatalogRoot = "/ova"
+	// Mirror (of a remote source) root.
+	MirrorRoot = CatalogRoot + "/remote"
+	// Mirror state.
+	MirrorState = ".sync.json"
+	// Suffix of files being downloaded.
+	PartialSuffix = ".partial"
+	// Default sync interval (seconds).
+	DefaultSyncInterval = 300
+	// Default S3 region.
+	DefaultS3Region = "us-east-1"
+)
+
+// Remote object.
+type Object struct {
+	// Path relative to the source root.
+	Path string
+	// Version (ETag, Last-Modified, size).
+	Version string
+}
+
+// Remote source of OVAs.
+type Source interface {
+	// List the (relevant) objects.
+	List() ([]Object, error)
+	// Open an object.
+	Open(path string) (io.ReadCloser, error)
+}
+
+// Build the source described by the environment.
+// Returns nil for NFS (mounted) sources.
+func sourceFromEnv() (source Source, err error) {
+	kind := os.Getenv(EnvSource)
+	if kind == "" || kind == SourceNFS {
+		return
+	}
+	url, err := liburl.Parse(os.Getenv(EnvSourceURL))
+	if err != nil {
+		return
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: os.Getenv(EnvInsecureSkipVerify) == "true",
+			},
+		},
+	}
+	switch kind {
+	case SourceHTTP:
+		source = &HttpSource{
+			URL:      url,
+			User:     os.Getenv(EnvUser),
+			Password: os.Getenv(EnvPassword),
+			client:   client,
+		}
+	case SourceS3:
+		region := os.Getenv(EnvS3Region)
+		if region == "" {
+			region = DefaultS3Region
+		}
+		source = &S3Source{
+			URL:       url,
+			Region:    region,
+			AccessKey: os.Getenv(EnvS3AccessKeyID),
+			SecretKey: os.Getenv(EnvS3SecretAccessKey),
+			client:    client,
+		}
+	default:
+		err = fmt.Errorf("source '%s' not supported", kind)
+	}
+	return
+}
+
+// Determine if the object is relevant to the catalog.
+func relevant(name string) bool {
+	name = strings.ToLower(name)
+	for _, suffix := range []string{".ova", ".ova.gz", ".ovf", ".mf", ".vmdk", ".iso", ".nvram"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return chunkSuffix.MatchString(name)
+}
+
+// Suffix of file chunks.
+var chunkSuffix = regexp.MustCompile(`\.[0-9]{9}$`)
+
+// HTTP (directory listing) source.
+// The directory listings (index pages) are crawled.
+type HttpSource struct {
+	URL      *liburl.URL
+	User     string
+	Password string
+	client   *http.Client
+}
+
+// Links found in an index page.
+var hrefPattern = regexp.MustCompile(`(?i)href\s*=\s*"([^"]+)"`)
+
+// List the objects.
+func (r *HttpSource) List() (objects []Object, err error) {
+	root := *r.URL
+	if !strings.HasSuffix(root.Path, "/") {
+		root.Path += "/"
+	}
+	visited := map[string]bool{}
+	objects, err = r.list(&root, &root, visited)
+	return
+}
+
+// List the objects found in the index page (recursively).
+func (r *HttpSource) list(root, dir *liburl.URL, visited map[string]bool) (objects []Object, err error) {
+	if visited[dir.Path] {
+		return
+	}
+	visited[dir.Path] = true
+	response, err := r.get(dir.String(), http.MethodGet)
+	if err != nil {
+		return
+	}
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return
+	}
+	for _, matched := range hrefPattern.FindAllStringSubmatch(string(body), -1) {
+		ref, pErr := liburl.Parse(matched[1])
+		if pErr != nil || ref.RawQuery != "" || ref.Fragment != "" {
+			continue
+		}
+		url := dir.ResolveReference(ref)
+		if url.Host != root.Host || !strings.HasPrefix(url.Path, root.Path) || url.Path == dir.Path {
+			continue
+		}
+		if strings.HasSuffix(url.Path, "/") {
+			var nested []Object
+			nested, err = r.list(root, url, visited)
+			if err != nil {
+				return
+			}
+			objects = append(objects, nested...)
+			continue
+		}
+		if !relevant(url.Path) {
+			continue
+		}
+		object := Object{Path: strings.TrimPrefix(url.Path, root.Path)}
+		object.Version, err = r.version(url)
+		if err != nil {
+			return
+		}
+		objects = append(objects, object)
+	}
+	return
+}
+
+// Object version.
+func (r *HttpSource) version(url *liburl.URL) (version string, err error) {
+	response, err := r.get(url.String(), http.MethodHead)
+	if err != nil {
+		return
+	}
+	_ = response.Body.Close()
+	version = strings.Join(
+		[]string{
+			response.Header.Get("ETag"),
+			response.Header.Get("Last-Modified"),
+			strconv.FormatInt(response.ContentLength, 10),
+		},
+		"|")
+	return
+}
+
+// Open an object.
+func (r *HttpSource) Open(p string) (reader io.ReadCloser, err error) {
+	url := *r.URL
+	url.Path = path.Join(url.Path, p)
+	response, err := r.get(url.String(), http.MethodGet)
+	if err != nil {
+		return
+	}
+	reader = response.Body
+	return
+}
+
+// Send the request.
+func (r *HttpSource) get(url, method string) (response *http.Response, err error) {
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return
+	}
+	if r.User != "" {
+		request.SetBasicAuth(r.User, r.Password)
+	}
+	response, err = r.client.Do(request)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		err = fmt.Errorf("%s %s: %s", method, url, response.Status)
+	}
+	return
+}
+
+// S3 (or compatible) source.
+// The URL is: <endpoint>/<bucket>[/<prefix>]. Example:
+//
+//	https://s3.us-east-1.amazonaws.com/bucket/ovas
+type S3Source struct {
+	URL       *liburl.URL
+	Region    string
+	AccessKey string
+	SecretKey string
+	client    *http.Client
+}
+
+// Bucket listing.
+type s3ListResult struct {
+	Contents []struct {
+		Key          string `xml:"Key"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size"`
+		LastModified string `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// Bucket and (key) prefix.
+func (r *S3Source) bucket() (bucket, prefix string) {
+	parts := strings.SplitN(strings.Trim(r.URL.Path, "/"), "/", 2)
+	bucket = parts[0]
+	if len(parts) > 1 && parts[1] != "" {
+		prefix = parts[1] + "/"
+	}
+	return
+}
+
+// List the objects.
+func (r *S3Source) List() (objects []Object, err error) {
+	bucket, prefix := r.bucket()
+	token := ""
+	for {
+		query := liburl.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		var response *http.Response
+		response, err = r.send(http.MethodGet, "/"+bucket, query)
+		if err != nil {
+			return
+		}
+		result := s3ListResult{}
+		err = xml.NewDecoder(response.Body).Decode(&result)
+		_ = response.Body.Close()
+		if err != nil {
+			return
+		}
+		for _, content := range result.Contents {
+			if !relevant(content.Key) {
+				continue
+			}
+			objects = append(
+				objects,
+				Object{
+					Path: strings.TrimPrefix(content.Key, prefix),
+					Version: strings.Join(
+						[]string{
+							content.ETag,
+							content.LastModified,
+							strconv.FormatInt(content.Size, 10),
+						},
+						"|"),
+				})
+		}
+		if !result.IsTruncated {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	return
+}
+
+// Open an object.
+func (r *S3Source) Open(p string) (reader io.ReadCloser, err error) {
+	bucket, prefix := r.bucket()
+	response, err := r.send(http.MethodGet, "/"+bucket+"/"+prefix+p, nil)
+	if err != nil {
+		return
+	}
+	reader = response.Body
+	return
+}
+
+// Send a (signed) request.
+func (r *S3Source) send(method, p string, query liburl.Values) (response *http.Response, err error) {
+	url := *r.URL
+	url.Path = p
+	url.RawPath = ""
+	if query != nil {
+		url.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	}
+	request, err := http.NewRequest(method, url.String(), nil)
+	if err != nil {
+		return
+	}
+	if r.AccessKey != "" {
+		r.sign(request, time.Now().UTC())
+	}
+	response, err = r.client.Do(request)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+		err = fmt.Errorf("%s %s: %s", method, p, response.Status)
+	}
+	return
+}
+
+// Sign the request (AWS signature version 4).
+func (r *S3Source) sign(request *http.Request, now time.Time) {
+	const algorithm = "AWS4-HMAC-SHA256"
+	payload := hashHex(nil)
+	date := now.Format("20060102")
+	stamp := now.Format("20060102T150405Z")
+	request.Header.Set("x-amz-date", stamp)
+	request.Header.Set("x-amz-content-sha256", payload)
+	headers := map[string]string{
+		"host":                 request.URL.Host,
+		"x-amz-content-sha256": payload,
+		"x-amz-date":           stamp,
+	}
+	names := []string{}
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonical := strings.Join(
+		[]string{
+			request.Method,
+			request.URL.EscapedPath(),
+			canonicalQuery(request.URL.Query()),
+			canonicalHeaders,
+			signedHeaders,
+			payload,
+		},
+		"\n")
+	scope := date + "/" + r.Region + "/s3/aws4_request"
+	toSign := strings.Join(
+		[]string{
+			algorithm,
+			stamp,
+			scope,
+			hashHex([]byte(canonical)),
+		},
+		"\n")
+	key := hmacSHA256([]byte("AWS4"+r.SecretKey), date)
+	key = hmacSHA256(key, r.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	request.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			algorithm,
+			r.AccessKey,
+			scope,
+			signedHeaders,
+			signature))
+}
+
+// Canonical (sorted and encoded) query.
+func canonicalQuery(query liburl.Values) string {
+	keys := []string{}
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(
+				pairs,
+				liburl.QueryEscape(key)+"="+strings.ReplaceAll(liburl.QueryEscape(value), "+", "%20"))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// SHA256 (hex).
+func hashHex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// HMAC-SHA256.
+func hmacSHA256(key []byte, s string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(s))
+	return h.Sum(nil)
+}
+
+// Mirror of a remote source.
+// The objects are downloaded into the mirror root (on the
+// catalog volume) and kept in sync. Objects are downloaded
+// into a partial file and renamed when complete so that
+// the catalog never contains incomplete objects.
+type Mirror struct {
+	Source   Source
+	Root     string
+	Interval time.Duration
+	// Object version keyed by path.
+	state map[string]string
+}
+
+// Start syncing.
+func (r *Mirror) Start() {
+	r.load()
+	go func() {
+		for {
+			err := r.Sync()
+			if err != nil {
+				fmt.Println("Error syncing source:", err)
+			}
+			time.Sleep(r.Interval)
+		}
+	}()
+}
+
+// Sync the mirror with the source.
+func (r *Mirror) Sync() (err error) {
+	objects, err := r.Source.List()
+	if err != nil {
+		return
+	}
+	wanted := map[string]bool{}
+	for _, object := range objects {
+		p := filepath.Clean("/" + object.Path)
+		wanted[p] = true
+		if version, found := r.state[p]; found && version == object.Version {
+			continue
+		}
+		dErr := r.download(object.Path, p)
+		if dErr != nil {
+			fmt.Println("Error downloading:", object.Path, dErr)
+			continue
+		}
+		r.state[p] = object.Version
+		err = r.save()
+		if err != nil {
+			return
+		}
+	}
+	for p := range r.state {
+		if wanted[p] {
+			continue
+		}
+		err = os.Remove(filepath.Join(r.Root, p))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		delete(r.state, p)
+		err = r.save()
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Download an object.
+func (r *Mirror) download(remote, local string) (err error) {
+	reader, err := r.Source.Open(remote)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	target := filepath.Join(r.Root, local)
+	err = os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return
+	}
+	partial := target + PartialSuffix
+	file, err := os.Create(partial)
+	if err != nil {
+		return
+	}
+	_, err = io.Copy(file, reader)
+	cErr := file.Close()
+	if err == nil {
+		err = cErr
+	}
+	if err != nil {
+		_ = os.Remove(partial)
+		return
+	}
+	err = os.Rename(partial, target)
+	return
+}
+
+// Load the state.
+func (r *Mirror) load() {
+	r.state = make(map[string]string)
+	b, err := os.ReadFile(filepath.Join(r.Root, MirrorState))
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(b, &r.state)
+}
+
+// Save the state.
+func (r *Mirror) save() (err error) {
+	b, err := json.Marshal(r.state)
+	if err != nil {
+		return
+	}
+	err = os.MkdirAll(r.Root, 0755)
+	if err != nil {
+		return
+	}
+	p := filepath.Join(r.Root, MirrorState)
+	err = os.WriteFile(p+PartialSuffix, b, 0644)
+	if err != nil {
+		return
+	}
+	err = os.Rename(p+PartialSuffix, p)
+	return
+}
+
+// Sync interval.
+func syncInterval() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv(EnvSyncInterval))
+	if err != nil || seconds < 1 {
+		seconds = DefaultSyncInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -22,12 +22,22 @@ const (
 	apiVersion = "v1beta1"
 )
 
+// Storage resource identifiers reported by the populators.
+const (
+	uuidRegex    = `[0-9a-fA-F]{8}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{12}`
+	gcpNameRegex = `[a-z](?:[-a-z0-9]*[a-z0-9])?`
+)
+
 type populator struct {
 	kind            string
 	resource        string
-	controllerFunc  func(bool, *unstructured.Unstructured) ([]string, error)
+	controllerFunc  populator_machinery.ArgsFunc
 	imageVar        string
 	metricsEndpoint string
+	metric          string
+	metricLabel     string
+	metricRegex     string
+	progressPath    []string
 }
 
 var populators = map[string]populator{
@@ -37,6 +47,10 @@ var populators = map[string]populator{
 		controllerFunc:  getOvirtPopulatorPodArgs,
 		imageVar:        "OVIRT_POPULATOR_IMAGE",
 		metricsEndpoint: ":8080",
+		metric:          "volume_populators_ovirt_volume_populator",
+		metricLabel:     "disk_id",
+		metricRegex:     uuidRegex,
+		progressPath:    []string{"status", "progress"},
 	},
 	"openstack": {
 		kind:            "OpenstackVolumePopulator",
@@ -44,6 +58,21 @@ var populators = map[string]populator{
 		controllerFunc:  getOpenstackPopulatorPodArgs,
 		imageVar:        "OPENSTACK_POPULATOR_IMAGE",
 		metricsEndpoint: ":8081",
+		metric:          "volume_populators_openstack_volume_populator",
+		metricLabel:     "image_id",
+		metricRegex:     uuidRegex,
+		progressPath:    []string{"status", "transferred"},
+	},
+	"gcp": {
+		kind:            "GcpVolumePopulator",
+		resource:        "gcpvolumepopulators",
+		controllerFunc:  getGcpPopulatorPodArgs,
+		imageVar:        "GCP_POPULATOR_IMAGE",
+		metricsEndpoint: ":8082",
+		metric:          "volume_populators_gcp_volume_populator",
+		metricLabel:     "image_name",
+		metricRegex:     gcpNameRegex,
+		progressPath:    []string{"status", "transferred"},
 	},
 }
 
@@ -70,23 +99,45 @@ func main() {
 		stop <- true
 	}()
 
+	err := register()
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	for _, registered := range populator_machinery.Registered() {
+		go func(registered *populator_machinery.Populator) {
+			populator_machinery.RunController(masterURL, kubeconfig, metricsPath,
+				prefix, mountPath, devicePath, registered)
+			<-stop
+		}(registered)
+	}
+	<-stop
+}
+
+// Register the populators with an image.
+func register() (err error) {
 	for _, populator := range populators {
 		imageName, ok := os.LookupEnv(populator.imageVar)
 		if !ok {
 			klog.Warning("Couldn't find", "imageVar", populator.imageVar)
 			continue
 		}
-		gk := schema.GroupKind{Group: groupName, Kind: populator.kind}
-		gvr := schema.GroupVersionResource{Group: groupName, Version: apiVersion, Resource: populator.resource}
-		controllerFunc := populator.controllerFunc
-		metricsEndpoint := populator.metricsEndpoint
-		go func() {
-			populator_machinery.RunController(masterURL, kubeconfig, imageName, metricsEndpoint, metricsPath,
-				prefix, gk, gvr, mountPath, devicePath, controllerFunc)
-			<-stop
-		}()
+		err = populator_machinery.Register(&populator_machinery.Populator{
+			GK:               schema.GroupKind{Group: groupName, Kind: populator.kind},
+			GVR:              schema.GroupVersionResource{Group: groupName, Version: apiVersion, Resource: populator.resource},
+			Image:            imageName,
+			Args:             populator.controllerFunc,
+			MetricsEndpoint:  populator.metricsEndpoint,
+			Metric:           populator.metric,
+			MetricLabel:      populator.metricLabel,
+			MetricLabelRegex: populator.metricRegex,
+			ProgressPath:     populator.progressPath,
+		})
+		if err != nil {
+			return
+		}
 	}
-	<-stop
+	return
 }
 
 func getOvirtPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) ([]string, error) {
@@ -133,3 +184,25 @@ func getOpenstackPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) (
 
 	return args, nil
 }
+
+func getGcpPopulatorPodArgs(rawBlock bool, u *unstructured.Unstructured) ([]string, error) {
+	var gcpPopulator v1beta1.GcpVolumePopulator
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), &gcpPopulator)
+	if nil != err {
+		return nil, err
+	}
+	args := []string{}
+	if rawBlock {
+		args = append(args, "--volume-path="+devicePath)
+	} else {
+		args = append(args, "--volume-path="+mountPath+"disk.img")
+	}
+
+	args = append(args, "--secret-name="+gcpPopulator.Spec.SecretName)
+	args = append(args, "--bucket-name="+gcpPopulator.Spec.BucketName)
+	args = append(args, "--object-name="+gcpPopulator.Spec.ObjectName)
+	args = append(args, "--cr-name="+gcpPopulator.Name)
+	args = append(args, "--cr-namespace="+gcpPopulator.Namespace)
+
+	return args, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	populator_machinery "github.com/konveyor/forklift-controller/pkg/lib-volume-populator/populator-machinery"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRegister(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Restored by the test.
+	for _, populator := range populators {
+		t.Setenv(populator.imageVar, "")
+		_ = os.Unsetenv(populator.imageVar)
+	}
+	t.Setenv("GCP_POPULATOR_IMAGE", "quay.io/kubev2v/gcp-populator:latest")
+
+	err := register()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	// Only the populators with an image are registered.
+	registered := populator_machinery.Registered()
+	g.Expect(registered).To(gomega.HaveLen(1))
+	gcp := registered[0]
+	g.Expect(gcp.GK.Group).To(gomega.Equal(groupName))
+	g.Expect(gcp.GK.Kind).To(gomega.Equal(v1beta1.GcpVolumePopulatorKind))
+	g.Expect(gcp.GVR.Resource).To(gomega.Equal("gcpvolumepopulators"))
+	g.Expect(gcp.Image).To(gomega.Equal("quay.io/kubev2v/gcp-populator:latest"))
+	g.Expect(gcp.MetricsEndpoint).To(gomega.Equal(":8082"))
+	g.Expect(gcp.Metric).To(gomega.Equal("volume_populators_gcp_volume_populator"))
+	g.Expect(gcp.MetricLabel).To(gomega.Equal("image_name"))
+	g.Expect(gcp.ProgressPath).To(gomega.Equal([]string{"status", "transferred"}))
+
+	// Registered once.
+	g.Expect(register()).To(gomega.HaveOccurred())
+}
+
+func TestGcpPopulatorMultiDisk(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gcp := populators["gcp"]
+	labelRegex := regexp.MustCompile(fmt.Sprintf(`^%s$`, gcp.metricRegex))
+
+	// Every disk of the VM is populated from its own image
+	// by its own pod, the args identify the disk of the PVC.
+	for _, disk := range []int{0, 1, 2} {
+		name := fmt.Sprintf("forklift-migration-vm-4711-disk-%d", disk)
+		g.Expect(labelRegex.MatchString(name)).To(gomega.BeTrue(), name)
+		cr := &v1beta1.GcpVolumePopulator{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: name},
+			Spec: v1beta1.GcpVolumePopulatorSpec{
+				SecretName: "secret",
+				BucketName: "bucket",
+				ObjectName: name + ".tar.gz",
+			},
+		}
+		object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(cr)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+
+		args, err := gcp.controllerFunc(false, &unstructured.Unstructured{Object: object})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(args).To(gomega.Equal([]string{
+			"--volume-path=" + mountPath + "disk.img",
+			"--secret-name=secret",
+			"--bucket-name=bucket",
+			"--object-name=" + name + ".tar.gz",
+			"--cr-name=" + name,
+			"--cr-namespace=test",
+		}))
+		args, err = gcp.controllerFunc(true, &unstructured.Unstructured{Object: object})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(args[0]).To(gomega.Equal("--volume-path=" + devicePath))
+	}
+}
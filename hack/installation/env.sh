@@ -41,6 +41,8 @@
 # export POPULATOR_CONTROLLER_IMAGE="${REGISTRY}/${REGISTRY_ORG}/populator-controller:${REGISTRY_TAG}"
 # export OVIRT_POPULATOR_IMAGE="${REGISTRY}/${REGISTRY_ORG}/ovirt-populator:${REGISTRY_TAG}"
 # export OPENSTACK_POPULATOR_IMAGE="${REGISTRY}/${REGISTRY_ORG}/openstack-populator:${REGISTRY_TAG}"
+# export GCP_POPULATOR_IMAGE="${REGISTRY}/${REGISTRY_ORG}/gcp-populator:${REGISTRY_TAG}"
+# export OCP_SYNC_IMAGE="${REGISTRY}/${REGISTRY_ORG}/ocp-sync:${REGISTRY_TAG}"
 #
 ### External images
 # export MUST_GATHER_IMAGE="quay.io/kubev2v/forklift-must-gather:latest"
@@ -0,0 +1,53 @@
+package v1beta1
+
+import (
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var GcpVolumePopulatorKind = "GcpVolumePopulator"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:shortName={gcpvp,gcpvps}
+type GcpVolumePopulator struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GcpVolumePopulatorSpec `json:"spec"`
+	// +optional
+	Status GcpVolumePopulatorStatus `json:"status"`
+}
+
+type GcpVolumePopulatorSpec struct {
+	SecretName string `json:"secretName"`
+	// The bucket the disk image has been exported to.
+	BucketName string `json:"bucketName"`
+	// The exported disk image object.
+	ObjectName string `json:"objectName"`
+	// The network attachment definition that should be used for disk transfer.
+	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
+}
+
+type GcpVolumePopulatorStatus struct {
+	// +optional
+	Transferred string `json:"transferred"`
+	// Number of times the population has been retried.
+	// +optional
+	Retries int `json:"retries,omitempty"`
+	// The reason the population failed.
+	// +optional
+	Failure string `json:"failure,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type GcpVolumePopulatorList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []GcpVolumePopulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GcpVolumePopulator{}, &GcpVolumePopulatorList{})
+}
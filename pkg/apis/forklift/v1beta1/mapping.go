@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
@@ -39,12 +40,25 @@ type DestinationNetwork struct {
 
 // Mapped network.
 type NetworkPair struct {
-	// Source network.
-	Source ref.Ref `json:"source"`
+	// Source network. Not required when the NICs
+	// are selected by the VLAN tag or the IP subnet.
+	Source ref.Ref `json:"source,omitempty"`
+	// Source VLAN tag. Maps the NICs connected to
+	// a source network with the VLAN tag.
+	VLAN string `json:"vlan,omitempty"`
+	// Source IP subnet (CIDR). Maps the NICs with
+	// a guest IP address within the subnet.
+	Subnet string `json:"subnet,omitempty"`
 	// Destination network.
 	Destination DestinationNetwork `json:"destination"`
 }
 
+// Whether the NICs are selected by the VLAN tag
+// or the IP subnet rather than the source network.
+func (r *NetworkPair) Selector() bool {
+	return r.VLAN != "" || r.Subnet != ""
+}
+
 // Mapped storage.
 type StoragePair struct {
 	// Source storage.
@@ -120,6 +134,40 @@ func (r *NetworkMap) FindNetwork(networkID string) (pair NetworkPair, found bool
 	return
 }
 
+// Find network map for a VLAN tag.
+func (r *NetworkMap) FindNetworkByVLAN(vlan string) (pair NetworkPair, found bool) {
+	for _, pair = range r.Spec.Map {
+		if pair.VLAN != "" && pair.VLAN == vlan {
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
+// Find network map for the subnet of any of the IP addresses.
+func (r *NetworkMap) FindNetworkBySubnet(ips []string) (pair NetworkPair, found bool) {
+	for _, pair = range r.Spec.Map {
+		if pair.Subnet == "" {
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(pair.Subnet)
+		if err != nil {
+			continue
+		}
+		for _, s := range ips {
+			ip := net.ParseIP(s)
+			if ip != nil && subnet.Contains(ip) {
+				found = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
 // Find network map for source type.
 func (r *NetworkMap) FindNetworkByType(networkType string) (pair NetworkPair, found bool) {
 	for _, pair = range r.Spec.Map {
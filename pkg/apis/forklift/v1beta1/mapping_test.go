@@ -0,0 +1,49 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestNetworkMapSelectors(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	mp := NetworkMap{
+		Spec: NetworkMapSpec{
+			Map: []NetworkPair{
+				{VLAN: "100", Destination: DestinationNetwork{Name: "vlan100"}},
+				{Subnet: "not-valid", Destination: DestinationNetwork{Name: "invalid"}},
+				{Subnet: "10.0.0.0/24", Destination: DestinationNetwork{Name: "subnet"}},
+			},
+		},
+	}
+
+	pair, found := mp.FindNetworkByVLAN("100")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(pair.Destination.Name).To(gomega.Equal("vlan100"))
+	_, found = mp.FindNetworkByVLAN("200")
+	g.Expect(found).To(gomega.BeFalse())
+	_, found = mp.FindNetworkByVLAN("")
+	g.Expect(found).To(gomega.BeFalse())
+
+	pair, found = mp.FindNetworkBySubnet([]string{"fe80::1", "10.0.0.7"})
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(pair.Destination.Name).To(gomega.Equal("subnet"))
+	_, found = mp.FindNetworkBySubnet([]string{"10.0.1.7"})
+	g.Expect(found).To(gomega.BeFalse())
+	_, found = mp.FindNetworkBySubnet(nil)
+	g.Expect(found).To(gomega.BeFalse())
+}
+
+func TestPreserveMAC(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	spec := PlanSpec{}
+	g.Expect(spec.PreserveMAC(true)).To(gomega.BeTrue())
+	spec.MACPolicy = MACRegenerate
+	g.Expect(spec.PreserveMAC(false)).To(gomega.BeFalse())
+	spec.MACPolicy = MACRegenerateConflicting
+	g.Expect(spec.PreserveMAC(false)).To(gomega.BeTrue())
+	g.Expect(spec.PreserveMAC(true)).To(gomega.BeFalse())
+}
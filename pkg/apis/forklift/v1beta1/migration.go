@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
@@ -33,6 +36,150 @@ type MigrationSpec struct {
 	// Date and time to finalize a warm migration.
 	// If present, this will override the value set on the Plan.
 	Cutover *meta.Time `json:"cutover,omitempty"`
+	// Date and time to start the migration.
+	// The VMs wait to be started until then.
+	Start *meta.Time `json:"start,omitempty"`
+	// Recurring windows during which the VMs may be cut over.
+	// Warm migrations are finalized and cold migrations are
+	// started only while a window is open. When not set, the
+	// VMs may be cut over at any time.
+	CutoverWindows []CutoverWindow `json:"cutoverWindows,omitempty"`
+	// List of VMs to be rolled back once their migration has ended.
+	// The target VM and its disks are deleted and the source
+	// VM is restored to its original power state.
+	Rollback []ref.Ref `json:"rollback,omitempty"`
+	// Roll back all the VMs once their migration has ended.
+	RollbackAll bool `json:"rollbackAll,omitempty"`
+	// List of failed VMs to be retried from the phase that failed
+	// rather than from the beginning. When set, only the listed
+	// VMs are retried and the other failed VMs are left as is.
+	Retry []ref.Ref `json:"retry,omitempty"`
+	// Render the objects that would be created for each VM rather
+	// than migrating. The source and destination are not modified,
+	// the manifests are stored in a ConfigMap per VM referenced by
+	// the status and the detected problems are reported on the VMs.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Recurring cutover window.
+type CutoverWindow struct {
+	// Days of the week on which the window opens (Monday, Tuesday, ...).
+	// The window opens every day when not set.
+	Days []string `json:"days,omitempty"`
+	// Time of day (HH:MM) at which the window opens.
+	Start string `json:"start"`
+	// Time of day (HH:MM) at which the window closes.
+	// A window closing before it opens spans midnight.
+	End string `json:"end"`
+	// IANA time zone of the window, UTC by default.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// Validate the window.
+func (r *CutoverWindow) Validate() (err error) {
+	_, _, _, err = r.parse()
+	return
+}
+
+// Determine whether the window is open at the specified time.
+// Windows that are not valid are never open.
+func (r *CutoverWindow) Open(t time.Time) (open bool) {
+	location, start, end, err := r.parse()
+	if err != nil {
+		return
+	}
+	t = t.In(location)
+	minute := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	day := t.Weekday()
+	switch {
+	case start < end:
+		open = minute >= start && minute < end && r.openOn(day)
+	case start > end:
+		// Spans midnight, the days are the days the window opens.
+		open = (minute >= start && r.openOn(day)) ||
+			(minute < end && r.openOn((day+6)%7))
+	}
+	return
+}
+
+// Determine whether the window opens on the day.
+func (r *CutoverWindow) openOn(day time.Weekday) bool {
+	if len(r.Days) == 0 {
+		return true
+	}
+	for _, d := range r.Days {
+		if d == day.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse the window.
+func (r *CutoverWindow) parse() (location *time.Location, start, end time.Duration, err error) {
+	location = time.UTC
+	if r.TimeZone != "" {
+		location, err = time.LoadLocation(r.TimeZone)
+		if err != nil {
+			err = fmt.Errorf("time zone '%s' not valid", r.TimeZone)
+			return
+		}
+	}
+	start, err = timeOfDay(r.Start)
+	if err != nil {
+		return
+	}
+	end, err = timeOfDay(r.End)
+	if err != nil {
+		return
+	}
+	if start == end {
+		err = fmt.Errorf("window opening and closing at %s", r.Start)
+		return
+	}
+	for _, d := range r.Days {
+		found := false
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if d == day.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err = fmt.Errorf("day '%s' not valid", d)
+			return
+		}
+	}
+	return
+}
+
+// Parse a time of day (HH:MM).
+func timeOfDay(s string) (d time.Duration, err error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		err = fmt.Errorf("time of day '%s' not valid, HH:MM expected", s)
+		return
+	}
+	d = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	return
+}
+
+// Determine whether the migration has reached its start time.
+func (r *MigrationSpec) Started(t time.Time) bool {
+	return r.Start == nil || !r.Start.After(t)
+}
+
+// Determine whether the VMs may be cut over at the specified time.
+func (r *MigrationSpec) CutoverWindowOpen(t time.Time) bool {
+	if len(r.CutoverWindows) == 0 {
+		return true
+	}
+	for i := range r.CutoverWindows {
+		if r.CutoverWindows[i].Open(t) {
+			return true
+		}
+	}
+	return false
 }
 
 // Canceled indicates whether a VM ref is present
@@ -58,6 +205,50 @@ func (r *MigrationSpec) Canceled(ref ref.Ref) (found bool) {
 	return
 }
 
+// RollbackRequested indicates whether a VM ref is present
+// in the list of VM refs to be rolled back.
+func (r *MigrationSpec) RollbackRequested(ref ref.Ref) (found bool) {
+	if ref.ID == "" {
+		return
+	}
+	if r.RollbackAll {
+		found = true
+		return
+	}
+
+	for _, vm := range r.Rollback {
+		if vm.ID == "" {
+			continue
+		}
+		if vm.ID == ref.ID {
+			found = true
+			return
+		}
+	}
+
+	return
+}
+
+// RetryRequested indicates whether a VM ref is present
+// in the list of VM refs to be retried.
+func (r *MigrationSpec) RetryRequested(ref ref.Ref) (found bool) {
+	if ref.ID == "" {
+		return
+	}
+
+	for _, vm := range r.Retry {
+		if vm.ID == "" {
+			continue
+		}
+		if vm.ID == ref.ID {
+			found = true
+			return
+		}
+	}
+
+	return
+}
+
 // MigrationStatus defines the observed state of Migration
 type MigrationStatus struct {
 	plan.Timed `json:",inline"`
@@ -68,6 +259,10 @@ type MigrationStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// VM status
 	VMs []*plan.VMStatus `json:"vms,omitempty"`
+	// ConfigMaps holding the manifests rendered by a dry run, one per VM.
+	Manifests []core.ObjectReference `json:"manifests,omitempty"`
+	// ConfigMap holding the report of the migration.
+	Report *core.ObjectReference `json:"report,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,47 @@
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestCutoverWindow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+	weekdays := []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+
+	tests := []struct {
+		name     string
+		window   CutoverWindow
+		time     time.Time
+		expected bool
+	}{
+		{"inside", CutoverWindow{Start: "01:00", End: "04:00"}, at(1, 2, 0), true},
+		{"opening", CutoverWindow{Start: "01:00", End: "04:00"}, at(1, 1, 0), true},
+		{"closing", CutoverWindow{Start: "01:00", End: "04:00"}, at(1, 4, 0), false},
+		{"outside", CutoverWindow{Start: "01:00", End: "04:00"}, at(1, 5, 0), false},
+		{"weekday", CutoverWindow{Days: weekdays, Start: "01:00", End: "04:00"}, at(5, 2, 0), true},
+		{"weekend", CutoverWindow{Days: weekdays, Start: "01:00", End: "04:00"}, at(6, 2, 0), false},
+		{"before midnight", CutoverWindow{Days: []string{"Monday"}, Start: "22:00", End: "02:00"}, at(1, 23, 0), true},
+		{"after midnight", CutoverWindow{Days: []string{"Monday"}, Start: "22:00", End: "02:00"}, at(2, 1, 0), true},
+		{"after midnight of another day", CutoverWindow{Days: []string{"Monday"}, Start: "22:00", End: "02:00"}, at(1, 1, 0), false},
+		{"time zone", CutoverWindow{Start: "01:00", End: "04:00", TimeZone: "America/New_York"}, at(1, 7, 0), true},
+		{"not valid", CutoverWindow{Start: "1am", End: "04:00"}, at(1, 2, 0), false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g.Expect(testCase.window.Open(testCase.time)).To(gomega.Equal(testCase.expected))
+		})
+	}
+
+	g.Expect((&CutoverWindow{Start: "01:00", End: "04:00", Days: []string{"Mon"}}).Validate()).ToNot(gomega.Succeed())
+	g.Expect((&CutoverWindow{Start: "01:00", End: "01:00"}).Validate()).ToNot(gomega.Succeed())
+	g.Expect((&CutoverWindow{Start: "01:00", End: "04:00", Days: weekdays}).Validate()).To(gomega.Succeed())
+}
@@ -31,6 +31,12 @@ type OvirtVolumePopulatorSpec struct {
 type OvirtVolumePopulatorStatus struct {
 	// +optional
 	Progress string `json:"progress"`
+	// Number of times the population has been retried.
+	// +optional
+	Retries int `json:"retries,omitempty"`
+	// The reason the population failed.
+	// +optional
+	Failure string `json:"failure,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
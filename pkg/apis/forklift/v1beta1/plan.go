@@ -26,6 +26,17 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// MAC address policies.
+const (
+	// The source MAC addresses are preserved.
+	MACPreserve = "Preserve"
+	// The MAC addresses are generated on the destination cluster.
+	MACRegenerate = "Regenerate"
+	// The source MAC addresses are preserved unless they
+	// conflict with VMs on the destination cluster.
+	MACRegenerateConflicting = "RegenerateConflicting"
+)
+
 // PlanSpec defines the desired state of Plan.
 type PlanSpec struct {
 	// Description
@@ -38,14 +49,40 @@ type PlanSpec struct {
 	Map plan.Map `json:"map"`
 	// List of VMs.
 	VMs []plan.VM `json:"vms"`
+	// Ordering groups of the VMs.
+	Groups []plan.Group `json:"groups,omitempty"`
 	// Whether this is a warm migration.
 	Warm bool `json:"warm,omitempty"`
+	// Whether the disks populated by a failed cold migration are
+	// preserved and delta synchronized by the next migration.
+	DeltaSync bool `json:"deltaSync,omitempty"`
+	// Whether the target VMs are verified once migrated.
+	// The VMs powered on after the migration are started and compared
+	// to the source VMs. Only the specification of the others is compared.
+	Verify bool `json:"verify,omitempty"`
+	// Whether the MAC addresses of the source NICs are preserved or
+	// regenerated on the target VMs. Defaults to Preserve.
+	// +kubebuilder:validation:Enum=Preserve;Regenerate;RegenerateConflicting
+	MACPolicy string `json:"macPolicy,omitempty"`
 	// The network attachment definition that should be used for disk transfer.
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
 	// Whether this plan should be archived.
 	Archived bool `json:"archived,omitempty"`
 }
 
+// Whether the MAC address of a source NIC is preserved on the target VM,
+// given whether the address conflicts with a VM on the destination cluster.
+func (r *PlanSpec) PreserveMAC(conflicting bool) bool {
+	switch r.MACPolicy {
+	case MACRegenerate:
+		return false
+	case MACRegenerateConflicting:
+		return !conflicting
+	default:
+		return true
+	}
+}
+
 // Find a planned VM.
 func (r *PlanSpec) FindVM(ref ref.Ref) (v *plan.VM, found bool) {
 	for _, vm := range r.VMs {
@@ -59,6 +96,19 @@ func (r *PlanSpec) FindVM(ref ref.Ref) (v *plan.VM, found bool) {
 	return
 }
 
+// Find an ordering group.
+func (r *PlanSpec) FindGroup(name string) (group *plan.Group, found bool) {
+	for i := range r.Groups {
+		if r.Groups[i].Name == name {
+			found = true
+			group = &r.Groups[i]
+			return
+		}
+	}
+
+	return
+}
+
 // PlanStatus defines the observed state of Plan.
 type PlanStatus struct {
 	// Conditions.
@@ -103,7 +153,7 @@ func (p *Plan) VSphereUsesEl9VirtV2v() (bool, error) {
 
 	switch source.Type() {
 	case VSphere:
-		return !p.Spec.Warm && destination.IsHost(), nil
+		return !p.Spec.Warm && !p.Spec.DeltaSync && destination.IsHost(), nil
 	case Ova:
 		return true, nil
 	default:
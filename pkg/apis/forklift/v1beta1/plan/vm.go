@@ -5,8 +5,10 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"path"
+	"strings"
 )
 
 // Plan hook.
@@ -29,6 +31,74 @@ type VM struct {
 	ref.Ref `json:",inline"`
 	// Enable hooks.
 	Hooks []HookRef `json:"hooks,omitempty"`
+	// Priority within the ordering group.
+	// VMs with higher priorities are started first.
+	Priority int `json:"priority,omitempty"`
+	// Ordering group.
+	Group string `json:"group,omitempty"`
+	// Network overrides of the NICs.
+	NICs []NIC `json:"nics,omitempty"`
+	// Storage overrides of the disks.
+	Disks []Disk `json:"disks,omitempty"`
+}
+
+// Network override of a NIC.
+// The NIC is mapped to the destination network
+// regardless of the network map.
+type NIC struct {
+	// MAC address of the source NIC.
+	MAC string `json:"mac"`
+	// The destination network type.
+	// +kubebuilder:validation:Enum=pod;multus
+	Type string `json:"type"`
+	// The destination network namespace (multus only).
+	Namespace string `json:"namespace,omitempty"`
+	// The destination network name.
+	Name string `json:"name,omitempty"`
+}
+
+// Disk provisioning.
+const (
+	// Sparse volumes.
+	ProvisioningThin = "Thin"
+	// Preallocated volumes.
+	ProvisioningThick = "Thick"
+)
+
+// Storage override of a disk.
+// The disk is migrated to the destination storage
+// regardless of the storage map.
+type Disk struct {
+	// Source disk identifier: the backing file (vSphere), the disk
+	// ID (oVirt), the volume or image ID (OpenStack), the disk
+	// path::name (OVA), the disk name (GCP) or the PVC namespace/name
+	// (OpenShift).
+	ID string `json:"id"`
+	// The destination storage class.
+	StorageClass string `json:"storageClass,omitempty"`
+	// Volume mode.
+	// +kubebuilder:validation:Enum=Filesystem;Block
+	VolumeMode core.PersistentVolumeMode `json:"volumeMode,omitempty"`
+	// Access mode.
+	// +kubebuilder:validation:Enum=ReadWriteOnce;ReadWriteMany;ReadOnlyMany
+	AccessMode core.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// Size of the destination disk. The disk is grown
+	// and may not be smaller than the source disk.
+	Size *resource.Quantity `json:"size,omitempty"`
+	// Whether the destination disk is sparse (Thin) or preallocated (Thick).
+	// Defaults to the storage profile of the storage class.
+	// +kubebuilder:validation:Enum=Thin;Thick
+	Provisioning string `json:"provisioning,omitempty"`
+}
+
+// Ordering group.
+// The VMs of a group are started once the VMs of the groups
+// it depends on have completed or reached cutover.
+type Group struct {
+	// Group name.
+	Name string `json:"name"`
+	// Groups that must be migrated first.
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // Find a Hook for the specified step.
@@ -44,6 +114,30 @@ func (r *VM) FindHook(step string) (ref HookRef, found bool) {
 	return
 }
 
+// Find the network override of a NIC by MAC address.
+func (r *VM) FindNIC(mac string) (nic NIC, found bool) {
+	for _, nic = range r.NICs {
+		if strings.EqualFold(nic.MAC, mac) {
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
+// Find the storage override of a disk.
+func (r *VM) FindDisk(id string) (disk Disk, found bool) {
+	for _, disk = range r.Disks {
+		if disk.ID == id {
+			found = true
+			break
+		}
+	}
+
+	return
+}
+
 // VM Status
 type VMStatus struct {
 	Timed `json:",inline"`
@@ -56,8 +150,14 @@ type VMStatus struct {
 	Error *Error `json:"error,omitempty"`
 	// Warm migration status
 	Warm *Warm `json:"warm,omitempty"`
+	// Delta sync status.
+	DeltaSync *DeltaSync `json:"deltaSync,omitempty"`
 	// Source VM power state before migration.
 	RestorePowerState string `json:"restorePowerState,omitempty"`
+	// Failed attempts recorded when the VM is resumed.
+	Attempts []Attempt `json:"attempts,omitempty"`
+	// Concerns reported by the inventory when the migration started.
+	Concerns []Concern `json:"concerns,omitempty"`
 
 	// Conditions.
 	libcnd.Conditions `json:",inline"`
@@ -72,6 +172,33 @@ type Warm struct {
 	Precopies           []Precopy  `json:"precopies,omitempty"`
 }
 
+// Delta sync status.
+// The disks populated by a failed cold migration are preserved and
+// only the blocks changed since the recorded snapshots are transferred
+// by the next migration.
+type DeltaSync struct {
+	// Snapshots from which the disks have been synchronized.
+	Precopies []Precopy `json:"precopies,omitempty"`
+	// Number of disks reused by the current migration.
+	Reused int `json:"reused,omitempty"`
+}
+
+// A failed attempt to migrate the VM.
+type Attempt struct {
+	Timed `json:",inline"`
+	// The phase that failed.
+	Phase string `json:"phase"`
+	// Errors.
+	Error *Error `json:"error,omitempty"`
+}
+
+// VM concern.
+type Concern struct {
+	Label      string `json:"label"`
+	Category   string `json:"category"`
+	Assessment string `json:"assessment"`
+}
+
 // Precopy durations
 type Precopy struct {
 	Start    *meta.Time `json:"start,omitempty"`
@@ -64,6 +64,35 @@ const (
 	Token = "token"
 )
 
+// OVA provider settings.
+const (
+	// Source of the OVA files: nfs (default), http or s3.
+	OvaSource = "source"
+	// Storage class of the store used to mirror http and s3 sources.
+	OvaStoreStorageClass = "storeStorageClass"
+	// Size of the store used to mirror http and s3 sources.
+	OvaStoreSize = "storeSize"
+	// S3 region.
+	OvaS3Region = "s3Region"
+)
+
+// OVA sources.
+const (
+	// NFS export. URL: <server>:<path>.
+	OvaSourceNFS = "nfs"
+	// HTTP(S) file server (directory listing). URL: http(s)://<host>/<path>/.
+	OvaSourceHTTP = "http"
+	// S3 compatible bucket. URL: http(s)://<endpoint>/<bucket>/<prefix>.
+	OvaSourceS3 = "s3"
+)
+
+// OVA sources.
+var OvaSources = []string{
+	OvaSourceNFS,
+	OvaSourceHTTP,
+	OvaSourceS3,
+}
+
 // Defines the desired state of Provider.
 type ProviderSpec struct {
 	// Provider type.
@@ -168,6 +197,35 @@ func (p *Provider) HasReconciled() bool {
 	return p.Generation == p.Status.ObservedGeneration
 }
 
+// The source of the OVA files.
+func (p *Provider) OvaSource() string {
+	if source, found := p.Spec.Settings[OvaSource]; found && source != "" {
+		return source
+	}
+	return OvaSourceNFS
+}
+
+// The OVA files are mirrored into the store.
+func (p *Provider) OvaMirrored() bool {
+	return p.Type() == Ova && p.OvaSource() != OvaSourceNFS
+}
+
+// Name of the PVC used to store the mirrored OVA files.
+func (p *Provider) OvaStoreName() string {
+	return "ova-store-" + p.Name
+}
+
+// Name of the PVC used to persist the index of the OVA files.
+func (p *Provider) OvaIndexName() string {
+	return "ova-index-" + p.Name
+}
+
+// The provider reports the VLAN tags of the source networks.
+// NICs can only be mapped by VLAN tag from such providers.
+func (p *Provider) SupportsVLAN() bool {
+	return p.Type() == VSphere || p.Type() == OVirt
+}
+
 // This provider requires VM guest conversion.
 func (p *Provider) RequiresConversion() bool {
 	return p.Type() == VSphere || p.Type() == Ova
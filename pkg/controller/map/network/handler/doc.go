@@ -2,6 +2,7 @@ package handler
 
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/map/network/handler/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/map/network/handler/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/map/network/handler/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/map/network/handler/ova"
@@ -53,6 +54,11 @@ func New(
 			client,
 			channel,
 			provider)
+	case api.GCP:
+		h, err = gcp.New(
+			client,
+			channel,
+			provider)
 	default:
 		err = liberr.New("provider not supported.")
 	}
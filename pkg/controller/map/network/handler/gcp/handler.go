@@ -21,56 +21,71 @@ type Handler struct {
 	*handler.Handler
 }
 
-// Ensure watch on networks.
+// Ensure watch on networks and subnetworks.
+// NICs may be mapped by subnetwork.
 func (r *Handler) Watch(watch *handler.WatchManager) (err error) {
-	w, err := watch.Ensure(
-		r.Provider(),
-		&gcp.Network{},
-		r)
-	if err != nil {
-		return
-	}
+	for _, kind := range []interface{}{&gcp.Network{}, &gcp.Subnetwork{}} {
+		w, err := watch.Ensure(
+			r.Provider(),
+			kind,
+			r)
+		if err != nil {
+			return err
+		}
 
-	log.Info(
-		"Inventory watch ensured.",
-		"provider",
-		path.Join(
-			r.Provider().Namespace,
-			r.Provider().Name),
-		"watch",
-		w.ID())
+		log.Info(
+			"Inventory watch ensured.",
+			"provider",
+			path.Join(
+				r.Provider().Namespace,
+				r.Provider().Name),
+			"watch",
+			w.ID())
+	}
 
 	return
 }
 
 // Resource created.
 func (r *Handler) Created(e libweb.Event) {
-	if network, cast := e.Resource.(*gcp.Network); cast {
-		r.changed(network)
+	switch resource := e.Resource.(type) {
+	case *gcp.Network:
+		r.changed(&resource.Resource)
+	case *gcp.Subnetwork:
+		r.changed(&resource.Resource)
 	}
 }
 
 // Resource created.
 func (r *Handler) Updated(e libweb.Event) {
-	if network, cast := e.Resource.(*gcp.Network); cast {
+	switch resource := e.Resource.(type) {
+	case *gcp.Network:
 		updated := e.Updated.(*gcp.Network)
-		if updated.Path != network.Path {
-			r.changed(network, updated)
+		if updated.Path != resource.Path {
+			r.changed(&resource.Resource, &updated.Resource)
+		}
+	case *gcp.Subnetwork:
+		updated := e.Updated.(*gcp.Subnetwork)
+		if updated.Name != resource.Name {
+			r.changed(&resource.Resource, &updated.Resource)
 		}
 	}
 }
 
 // Resource deleted.
 func (r *Handler) Deleted(e libweb.Event) {
-	if network, cast := e.Resource.(*gcp.Network); cast {
-		r.changed(network)
+	switch resource := e.Resource.(type) {
+	case *gcp.Network:
+		r.changed(&resource.Resource)
+	case *gcp.Subnetwork:
+		r.changed(&resource.Resource)
 	}
 }
 
 // Network changed.
 // Find all of the NetworkMap CRs the reference both the
 // provider and the changed network and enqueue reconcile events.
-func (r *Handler) changed(models ...*gcp.Network) {
+func (r *Handler) changed(models ...*gcp.Resource) {
 	log.V(3).Info(
 		"Network changed.",
 		"id",
@@ -91,7 +106,9 @@ func (r *Handler) changed(models ...*gcp.Network) {
 		for _, pair := range mp.Spec.Map {
 			ref := pair.Source
 			for _, network := range models {
-				if ref.ID == network.ID || strings.HasSuffix(network.Path, ref.Name) {
+				if ref.ID == network.ID ||
+					ref.Name == network.Name ||
+					strings.HasSuffix(network.Path, ref.Name) {
 					referenced = true
 					break
 				}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"errors"
+	"net"
 	"path"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -14,6 +15,8 @@ import (
 // Types
 const (
 	SourceNetworkNotValid      = "SourceNetworkNotValid"
+	SourceSubnetNotValid       = "SourceSubnetNotValid"
+	SourceVLANNotSupported     = "SourceVLANNotSupported"
 	DestinationNetworkNotValid = "DestinationNetworkNotValid"
 )
 
@@ -28,9 +31,11 @@ const (
 
 // Reasons
 const (
-	NotSet    = "NotSet"
-	NotFound  = "NotFound"
-	Ambiguous = "Ambiguous"
+	NotSet       = "NotSet"
+	NotFound     = "NotFound"
+	NotValid     = "NotValid"
+	NotSupported = "NotSupported"
+	Ambiguous    = "Ambiguous"
 )
 
 // Statuses
@@ -84,9 +89,24 @@ func (r *Reconciler) validateSource(mp *api.NetworkMap) (err error) {
 	}
 	notValid := []string{}
 	ambiguous := []string{}
+	subnets := []string{}
+	vlans := []string{}
 	references := refapi.Refs{}
 	list := mp.Spec.Map
 	for i := range list {
+		if list[i].Selector() {
+			// The NICs are selected by VLAN tag or
+			// IP subnet, the source network is ignored.
+			if subnet := list[i].Subnet; subnet != "" {
+				if _, _, pErr := net.ParseCIDR(subnet); pErr != nil {
+					subnets = append(subnets, subnet)
+				}
+			}
+			if vlan := list[i].VLAN; vlan != "" && !provider.SupportsVLAN() {
+				vlans = append(vlans, vlan)
+			}
+			continue
+		}
 		ref := &list[i].Source
 		if ref.NotSet() {
 			mp.Status.SetCondition(libcnd.Condition{
@@ -134,6 +154,26 @@ func (r *Reconciler) validateSource(mp *api.NetworkMap) (err error) {
 			Items:    ambiguous,
 		})
 	}
+	if len(subnets) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     SourceSubnetNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: Critical,
+			Message:  "Source subnet is not a valid CIDR.",
+			Items:    subnets,
+		})
+	}
+	if len(vlans) > 0 {
+		mp.Status.SetCondition(libcnd.Condition{
+			Type:     SourceVLANNotSupported,
+			Status:   True,
+			Reason:   NotSupported,
+			Category: Critical,
+			Message:  "Source VLAN tags are not reported by the source provider.",
+			Items:    vlans,
+		})
+	}
 
 	return
 }
@@ -2,6 +2,7 @@ package handler
 
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/map/storage/handler/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/map/storage/handler/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/map/storage/handler/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/map/storage/handler/ova"
@@ -53,6 +54,11 @@ func New(
 			client,
 			channel,
 			provider)
+	case api.GCP:
+		h, err = gcp.New(
+			client,
+			channel,
+			provider)
 	default:
 		err = liberr.New("provider not supported.")
 	}
@@ -22,17 +22,17 @@ type Handler struct {
 	*handler.Handler
 }
 
-// Ensure watch on VolumeType.
+// Ensure watch on DiskType.
 func (r *Handler) Watch(watch *handler.WatchManager) (err error) {
 	w, err := watch.Ensure(
 		r.Provider(),
-		&gcp.VolumeType{},
+		&gcp.DiskType{},
 		r)
 	if err != nil {
 		return
 	}
 	log.Info(
-		"VolumeType watch ensured.",
+		"DiskType watch ensured.",
 		"provider",
 		path.Join(
 			r.Provider().Namespace,
@@ -44,15 +44,15 @@ func (r *Handler) Watch(watch *handler.WatchManager) (err error) {
 
 // Resource created.
 func (r *Handler) Created(e libweb.Event) {
-	if model, cast := e.Resource.(*gcp.VolumeType); cast {
+	if model, cast := e.Resource.(*gcp.DiskType); cast {
 		r.changed(model)
 	}
 }
 
 // Resource updated.
 func (r *Handler) Updated(e libweb.Event) {
-	if model, cast := e.Resource.(*gcp.VolumeType); cast {
-		updated := e.Updated.(*gcp.VolumeType)
+	if model, cast := e.Resource.(*gcp.DiskType); cast {
+		updated := e.Updated.(*gcp.DiskType)
 		if updated.Path != model.Path {
 			r.changed(model, updated)
 		}
@@ -61,7 +61,7 @@ func (r *Handler) Updated(e libweb.Event) {
 
 // Resource deleted.
 func (r *Handler) Deleted(e libweb.Event) {
-	if model, cast := e.Resource.(*gcp.VolumeType); cast {
+	if model, cast := e.Resource.(*gcp.DiskType); cast {
 		r.changed(model)
 	}
 }
@@ -69,7 +69,7 @@ func (r *Handler) Deleted(e libweb.Event) {
 // Storage changed.
 // Find all StorageMap CRs that reference both the provider
 // and the changed volume type, and enqueue reconcile events.
-func (r *Handler) changed(models ...*gcp.VolumeType) {
+func (r *Handler) changed(models ...*gcp.DiskType) {
 	log.V(3).Info(
 		"Volume type changed.",
 		"id",
@@ -100,7 +100,7 @@ func (r *Handler) changed(models ...*gcp.VolumeType) {
 	}
 }
 
-func isReferenced(models []*gcp.VolumeType, storageMap *api.StorageMap) bool {
+func isReferenced(models []*gcp.DiskType, storageMap *api.StorageMap) bool {
 	for _, pair := range storageMap.Spec.Map {
 		ref := pair.Source
 		for _, model := range models {
@@ -143,6 +143,14 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	// Reflect plan.
 	r.reflectPlan(plan, migration)
 
+	// Report.
+	if migration.Status.MarkedCompleted() && migration.Status.Report == nil {
+		err = r.report(plan, migration)
+		if err != nil {
+			return
+		}
+	}
+
 	// Ready condition.
 	if !migration.Status.HasBlockerCondition() {
 		migration.Status.SetCondition(libcnd.Condition{
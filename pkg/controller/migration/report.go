@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"context"
+	"path"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/migration/report"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Persist the report of the completed migration.
+// The report is stored in a ConfigMap owned by the migration
+// so that it is kept when the plan is archived.
+func (r *Reconciler) report(plan *api.Plan, migration *api.Migration) (err error) {
+	found, snapshot := plan.Status.Migration.SnapshotWithMigration(migration.UID)
+	if !found {
+		return
+	}
+	rpt := report.New(plan, migration, snapshot)
+	data, err := rpt.Data()
+	if err != nil {
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: migration.Namespace,
+			Name:      report.Name(migration),
+		},
+		configMap)
+	if err == nil {
+		configMap.Data = data
+		err = r.Update(context.TODO(), configMap)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	} else {
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		configMap = &core.ConfigMap{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: migration.Namespace,
+				Name:      report.Name(migration),
+				Labels: map[string]string{
+					report.LabelReport:    "true",
+					report.LabelPlan:      string(plan.UID),
+					report.LabelMigration: string(migration.UID),
+				},
+			},
+			Data: data,
+		}
+		err = k8sutil.SetOwnerReference(migration, configMap, scheme.Scheme)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = r.Create(context.TODO(), configMap)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	migration.Status.Report = &core.ObjectReference{
+		Kind:      "ConfigMap",
+		Namespace: configMap.Namespace,
+		Name:      configMap.Name,
+	}
+	r.Log.Info(
+		"Report created.",
+		"report",
+		path.Join(
+			configMap.Namespace,
+			configMap.Name))
+
+	return
+}
@@ -0,0 +1,381 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigMap keys.
+const (
+	JSON = "report.json"
+	CSV  = "report.csv"
+)
+
+// Labels.
+const (
+	LabelReport    = "report"
+	LabelPlan      = "plan"
+	LabelMigration = "migration"
+)
+
+// Results.
+const (
+	Succeeded = "Succeeded"
+	Failed    = "Failed"
+	Canceled  = "Canceled"
+)
+
+// Pipeline steps not counted as transferred.
+const (
+	DiskAllocation = "DiskAllocation"
+)
+
+// Name of the ConfigMap holding the report of a migration.
+func Name(migration *api.Migration) string {
+	return migration.Name + "-report"
+}
+
+// Migration report.
+// Flattened view of the VMs migrated by a single
+// execution of a plan (migration history snapshot).
+type Report struct {
+	// Plan.
+	Plan Ref `json:"plan"`
+	// Migration.
+	Migration Ref `json:"migration"`
+	// Source provider.
+	Source Ref `json:"source"`
+	// Destination provider.
+	Destination Ref `json:"destination"`
+	// Target namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Warm migration.
+	Warm bool `json:"warm"`
+	// Result (Succeeded|Failed|Canceled).
+	Result string `json:"result"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Migrated VMs.
+	VMs []VM `json:"vms"`
+}
+
+// Object reference.
+type Ref struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// Migrated VM.
+type VM struct {
+	// Source VM ID.
+	ID string `json:"id"`
+	// Source VM name.
+	Name string `json:"name"`
+	// Target VM name.
+	TargetName string `json:"targetName"`
+	// Target VM namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Result (Succeeded|Failed|Canceled).
+	Result string `json:"result"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Data transferred (MB).
+	TransferredMB int64 `json:"transferredMB"`
+	// Number of warm precopies.
+	Precopies int `json:"precopies"`
+	// Phase the VM failed in.
+	ErrorPhase string `json:"errorPhase,omitempty"`
+	// Errors.
+	Errors []string `json:"errors,omitempty"`
+	// Concerns reported by the inventory when the migration started.
+	Concerns []Concern `json:"concerns,omitempty"`
+	// Pipeline steps.
+	Steps []Step `json:"steps"`
+}
+
+// VM concern.
+type Concern = plan.Concern
+
+// Pipeline step.
+type Step struct {
+	// Name.
+	Name string `json:"name"`
+	// Phase.
+	Phase string `json:"phase,omitempty"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Completed units.
+	Progress int64 `json:"progress"`
+	// Total units.
+	Total int64 `json:"total"`
+	// Progress unit.
+	Unit string `json:"unit,omitempty"`
+	// Errors.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Build the report of a completed migration.
+// The VMs are those of the plan history snapshot
+// of the migration, reflected on the migration status.
+func New(p *api.Plan, migration *api.Migration, snapshot *plan.Snapshot) (r *Report) {
+	r = &Report{
+		Plan: Ref{
+			Namespace: p.Namespace,
+			Name:      p.Name,
+			UID:       string(p.UID),
+		},
+		Migration: Ref{
+			Namespace: migration.Namespace,
+			Name:      migration.Name,
+			UID:       string(migration.UID),
+		},
+		Source: Ref{
+			Namespace: snapshot.Provider.Source.Namespace,
+			Name:      snapshot.Provider.Source.Name,
+			UID:       string(snapshot.Provider.Source.UID),
+		},
+		Destination: Ref{
+			Namespace: snapshot.Provider.Destination.Namespace,
+			Name:      snapshot.Provider.Destination.Name,
+			UID:       string(snapshot.Provider.Destination.UID),
+		},
+		TargetNamespace: p.Spec.TargetNamespace,
+		Warm:            p.Spec.Warm,
+		Result:          result(&migration.Status.Conditions),
+		Started:         migration.Status.Started,
+		Completed:       migration.Status.Completed,
+		VMs:             []VM{},
+	}
+	for _, vm := range migration.Status.VMs {
+		r.VMs = append(r.VMs, r.vm(p, vm))
+	}
+
+	return
+}
+
+// Build the VM entry.
+func (r *Report) vm(p *api.Plan, status *plan.VMStatus) (vm VM) {
+	vm = VM{
+		ID:              status.ID,
+		Name:            status.Name,
+		TargetName:      status.Name,
+		TargetNamespace: p.Spec.TargetNamespace,
+		Result:          result(&status.Conditions),
+		Started:         status.Started,
+		Completed:       status.Completed,
+		Concerns:        status.Concerns,
+		Steps:           []Step{},
+	}
+	// The name on the status is replaced with the
+	// target name when not DNS1123 compliant.
+	for _, planned := range p.Spec.VMs {
+		if planned.ID == status.ID && planned.Name != "" {
+			vm.Name = planned.Name
+			break
+		}
+	}
+	if status.Warm != nil {
+		vm.Precopies = len(status.Warm.Precopies)
+	}
+	if status.Error != nil {
+		vm.ErrorPhase = status.Error.Phase
+		vm.Errors = status.Error.Reasons
+	}
+	for _, step := range status.Pipeline {
+		entry := Step{
+			Name:      step.Name,
+			Phase:     step.Phase,
+			Started:   step.Started,
+			Completed: step.Completed,
+			Progress:  step.Progress.Completed,
+			Total:     step.Progress.Total,
+			Unit:      step.Annotations["unit"],
+		}
+		if step.Error != nil {
+			entry.Errors = step.Error.Reasons
+		}
+		if entry.Unit == "MB" && step.Name != DiskAllocation {
+			vm.TransferredMB += entry.Progress
+		}
+		vm.Steps = append(vm.Steps, entry)
+	}
+
+	return
+}
+
+// Result of the migration or the VM.
+func result(conditions *libcnd.Conditions) string {
+	switch {
+	case conditions.HasCondition(Canceled):
+		return Canceled
+	case conditions.HasCondition(Failed):
+		return Failed
+	case conditions.HasCondition(Succeeded):
+		return Succeeded
+	}
+
+	return ""
+}
+
+// Render the report as JSON.
+func (r *Report) JSON() (content []byte, err error) {
+	content, err = json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// CSV header.
+var header = []string{
+	"plan",
+	"migration",
+	"vmId",
+	"vmName",
+	"targetName",
+	"targetNamespace",
+	"result",
+	"started",
+	"completed",
+	"transferredMB",
+	"precopies",
+	"errors",
+	"concerns",
+	"step",
+	"stepPhase",
+	"stepStarted",
+	"stepCompleted",
+	"stepProgress",
+	"stepTotal",
+	"stepUnit",
+	"stepErrors",
+}
+
+// Render the report as CSV.
+// One row for each VM pipeline step.
+func (r *Report) CSV() (content []byte, err error) {
+	return List{r}.CSV()
+}
+
+// Report rows.
+func (r *Report) rows() (rows [][]string) {
+	for _, vm := range r.VMs {
+		concerns := []string{}
+		for _, concern := range vm.Concerns {
+			concerns = append(concerns, concern.Category+": "+concern.Label)
+		}
+		row := []string{
+			r.Plan.Namespace + "/" + r.Plan.Name,
+			r.Migration.Namespace + "/" + r.Migration.Name,
+			vm.ID,
+			vm.Name,
+			vm.TargetName,
+			vm.TargetNamespace,
+			vm.Result,
+			timestamp(vm.Started),
+			timestamp(vm.Completed),
+			strconv.FormatInt(vm.TransferredMB, 10),
+			strconv.Itoa(vm.Precopies),
+			strings.Join(vm.Errors, "; "),
+			strings.Join(concerns, "; "),
+		}
+		if len(vm.Steps) == 0 {
+			rows = append(rows, append(row, make([]string, len(header)-len(row))...))
+			continue
+		}
+		for _, step := range vm.Steps {
+			rows = append(
+				rows,
+				append(
+					append([]string{}, row...),
+					step.Name,
+					step.Phase,
+					timestamp(step.Started),
+					timestamp(step.Completed),
+					strconv.FormatInt(step.Progress, 10),
+					strconv.FormatInt(step.Total, 10),
+					step.Unit,
+					strings.Join(step.Errors, "; ")))
+		}
+	}
+
+	return
+}
+
+// List of reports.
+type List []*Report
+
+// Render the reports as a single CSV document.
+func (l List) CSV() (content []byte, err error) {
+	buf := &bytes.Buffer{}
+	writer := csv.NewWriter(buf)
+	rows := [][]string{header}
+	for _, r := range l {
+		rows = append(rows, r.rows()...)
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	content = buf.Bytes()
+
+	return
+}
+
+// Render the report as ConfigMap data.
+func (r *Report) Data() (data map[string]string, err error) {
+	j, err := r.JSON()
+	if err != nil {
+		return
+	}
+	c, err := r.CSV()
+	if err != nil {
+		return
+	}
+	data = map[string]string{
+		JSON: string(j),
+		CSV:  string(c),
+	}
+
+	return
+}
+
+// Load the report from a ConfigMap.
+func With(configMap *core.ConfigMap) (r *Report, err error) {
+	r = &Report{}
+	err = json.Unmarshal([]byte(configMap.Data[JSON]), r)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Format a timestamp.
+func timestamp(t *meta.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
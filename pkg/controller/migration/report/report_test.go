@@ -0,0 +1,121 @@
+package report
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReport(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns",
+			Name:      "plan",
+			UID:       "plan-uid",
+		},
+	}
+	p.Spec.TargetNamespace = "target"
+	p.Spec.Warm = true
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1", Name: "Web_Server"}},
+	}
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns",
+			Name:      "migration",
+			UID:       "migration-uid",
+		},
+	}
+	migration.Status.SetCondition(libcnd.Condition{Type: Failed, Status: libcnd.True})
+	succeeded := &plan.VMStatus{
+		VM: plan.VM{Ref: ref.Ref{ID: "vm-1", Name: "web-server"}},
+		Pipeline: []*plan.Step{
+			{
+				Task: plan.Task{
+					Name:        DiskAllocation,
+					Progress:    libitr.Progress{Completed: 100, Total: 100},
+					Annotations: map[string]string{"unit": "MB"},
+				},
+			},
+			{
+				Task: plan.Task{
+					Name:        "DiskTransfer",
+					Progress:    libitr.Progress{Completed: 100, Total: 100},
+					Annotations: map[string]string{"unit": "MB"},
+				},
+			},
+			{
+				Task: plan.Task{
+					Name:        "Cutover",
+					Progress:    libitr.Progress{Completed: 20, Total: 20},
+					Annotations: map[string]string{"unit": "MB"},
+				},
+			},
+		},
+		Warm: &plan.Warm{
+			Precopies: []plan.Precopy{{}, {}, {}},
+		},
+		Concerns: []plan.Concern{
+			{Category: "Warning", Label: "Changed Block Tracking (CBT) not enabled"},
+		},
+	}
+	succeeded.SetCondition(libcnd.Condition{Type: Succeeded, Status: libcnd.True})
+	failed := &plan.VMStatus{
+		VM: plan.VM{Ref: ref.Ref{ID: "vm-2", Name: "db"}},
+		Error: &plan.Error{
+			Phase:   "CopyDisks",
+			Reasons: []string{"disk, not found"},
+		},
+	}
+	failed.SetCondition(libcnd.Condition{Type: Failed, Status: libcnd.True})
+	migration.Status.VMs = []*plan.VMStatus{succeeded, failed}
+	snapshot := &plan.Snapshot{}
+	snapshot.Provider.Source.Name = "vsphere"
+
+	//Test build
+	r := New(p, migration, snapshot)
+	g.Expect(r.Result).To(gomega.Equal(Failed))
+	g.Expect(r.Source.Name).To(gomega.Equal("vsphere"))
+	g.Expect(r.VMs).To(gomega.HaveLen(2))
+	g.Expect(r.VMs[0].Name).To(gomega.Equal("Web_Server"))
+	g.Expect(r.VMs[0].TargetName).To(gomega.Equal("web-server"))
+	g.Expect(r.VMs[0].TargetNamespace).To(gomega.Equal("target"))
+	g.Expect(r.VMs[0].Result).To(gomega.Equal(Succeeded))
+	g.Expect(r.VMs[0].TransferredMB).To(gomega.Equal(int64(120)))
+	g.Expect(r.VMs[0].Precopies).To(gomega.Equal(3))
+	g.Expect(r.VMs[0].Concerns).To(gomega.Equal(succeeded.Concerns))
+	g.Expect(r.VMs[1].Concerns).To(gomega.BeEmpty())
+	g.Expect(r.VMs[1].Result).To(gomega.Equal(Failed))
+	g.Expect(r.VMs[1].ErrorPhase).To(gomega.Equal("CopyDisks"))
+
+	//Test CSV
+	content, err := r.CSV()
+	g.Expect(err).To(gomega.BeNil())
+	rows, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	g.Expect(err).To(gomega.BeNil())
+	// header, 3 steps and the VM without steps.
+	g.Expect(rows).To(gomega.HaveLen(5))
+	for _, row := range rows {
+		g.Expect(row).To(gomega.HaveLen(len(header)))
+	}
+	g.Expect(rows[4][11]).To(gomega.Equal("disk, not found"))
+	g.Expect(rows[1][12]).To(gomega.Equal("Warning: Changed Block Tracking (CBT) not enabled"))
+
+	//Test ConfigMap round trip
+	data, err := r.Data()
+	g.Expect(err).To(gomega.BeNil())
+	loaded, err := With(&core.ConfigMap{Data: data})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(loaded.VMs).To(gomega.HaveLen(2))
+	g.Expect(loaded.Migration.Name).To(gomega.Equal("migration"))
+}
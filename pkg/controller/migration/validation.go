@@ -3,7 +3,9 @@ package migration
 import (
 	"context"
 	"errors"
+	"fmt"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancnt "github.com/konveyor/forklift-controller/pkg/controller/plan"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
@@ -15,15 +17,16 @@ import (
 
 // Types
 const (
-	PlanNotValid = "PlanNotValid"
-	PlanNotReady = "PlanNotReady"
-	VMNotFound   = "VMNotFound"
-	VMNotUnique  = "VMNotUnique"
-	Running      = "Running"
-	Executing    = plancnt.Executing
-	Succeeded    = plancnt.Succeeded
-	Failed       = plancnt.Failed
-	Canceled     = plancnt.Canceled
+	PlanNotValid   = "PlanNotValid"
+	PlanNotReady   = "PlanNotReady"
+	WindowNotValid = "CutoverWindowNotValid"
+	VMNotFound     = "VMNotFound"
+	VMNotUnique    = "VMNotUnique"
+	Running        = "Running"
+	Executing      = plancnt.Executing
+	Succeeded      = plancnt.Succeeded
+	Failed         = plancnt.Failed
+	Canceled       = plancnt.Canceled
 )
 
 // Categories
@@ -40,6 +43,7 @@ const (
 	NotSet    = "NotSet"
 	NotFound  = "NotFound"
 	Ambiguous = "Ambiguous"
+	NotValid  = "NotValid"
 )
 
 // Statuses
@@ -50,6 +54,7 @@ const (
 
 // Validate the migration resource.
 func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err error) {
+	r.validateCutoverWindows(migration)
 	newCnd := libcnd.Condition{
 		Type:     PlanNotValid,
 		Status:   True,
@@ -90,7 +95,7 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 		return
 	}
 
-	// Validate the refs in the Cancel array
+	// Validate the refs in the Cancel, Rollback and Retry arrays
 	notFound := libcnd.Condition{
 		Type:     VMNotFound,
 		Status:   True,
@@ -123,7 +128,11 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 	if err != nil {
 		return
 	}
-	for _, ref := range migration.Spec.Cancel {
+	refs := []refapi.Ref{}
+	refs = append(refs, migration.Spec.Cancel...)
+	refs = append(refs, migration.Spec.Rollback...)
+	refs = append(refs, migration.Spec.Retry...)
+	for _, ref := range refs {
 		_, err = inventory.VM(&ref)
 		if err != nil {
 			if errors.As(err, &web.NotFoundError{}) {
@@ -149,3 +158,26 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 
 	return
 }
+
+// Validate the cutover windows.
+func (r *Reconciler) validateCutoverWindows(migration *api.Migration) {
+	notValid := libcnd.Condition{
+		Type:     WindowNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Cutover window not valid.",
+		Items:    []string{},
+	}
+	for i := range migration.Spec.CutoverWindows {
+		err := migration.Spec.CutoverWindows[i].Validate()
+		if err != nil {
+			notValid.Items = append(
+				notValid.Items,
+				fmt.Sprintf("[%d] %s", i, err.Error()))
+		}
+	}
+	if len(notValid.Items) > 0 {
+		migration.Status.SetCondition(notValid)
+	}
+}
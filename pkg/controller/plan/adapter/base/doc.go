@@ -20,6 +20,19 @@ const (
 
 var VolumePopulatorNotSupportedError = liberr.New("provider does not support volume populators")
 
+// Source VM specification.
+// Compared to the target VM by the post-migration verification.
+type VMSpec struct {
+	// Number of vCPUs.
+	CPUs int
+	// Memory in bytes.
+	Memory int64
+	// Disk sizes in bytes. A zero size is not verified.
+	Disks []int64
+	// NIC MAC addresses.
+	MACs []string
+}
+
 // Adapter API.
 // Constructs provider-specific implementations
 // of the Builder, Client, and Validator.
@@ -62,6 +75,11 @@ type Builder interface {
 	LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error)
 	// check whether the builder supports Volume Populators
 	SupportsVolumePopulators() bool
+	// Whether the disks may be delta synchronized between migrations.
+	SupportsDeltaSync(vmRef ref.Ref) (bool, error)
+	// Build the source VM specification verified on the target VM.
+	// Returns nil when the verification is not supported.
+	SourceSpec(vmRef ref.Ref) (*VMSpec, error)
 	// Build populator volumes
 	PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error)
 	// Transferred bytes
@@ -106,6 +124,8 @@ type Client interface {
 type Validator interface {
 	// Validate that a VM's disk backing storage has been mapped.
 	StorageMapped(vmRef ref.Ref) (bool, error)
+	// Validate that a VM's disk overrides reference its disks and are supported.
+	DiskOverrides(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's networks have been mapped.
 	NetworksMapped(vmRef ref.Ref) (bool, error)
 	// Validate that a VM's Host isn't in maintenance mode.
@@ -114,6 +134,9 @@ type Validator interface {
 	WarmMigration() bool
 	// Validate that no more than one of a VM's networks is mapped to the pod network.
 	PodNetwork(vmRef ref.Ref) (bool, error)
+	// Validate that the target cluster satisfies the VM's requirements.
+	// The target cluster is reached through the destination of the plan context.
+	TargetCompatible(ctx *plancontext.Context, vmRef ref.Ref) (bool, error)
 }
 
 // DestinationClient API.
@@ -0,0 +1,59 @@
+package base
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+)
+
+// Source NIC.
+// A NIC of the source VM to be mapped to a destination network.
+type NIC struct {
+	// MAC address.
+	MAC string
+	// Guest IP addresses.
+	IPs []string
+	// VLAN tag of the source network.
+	VLAN string
+	// Network map pair of the source network.
+	Mapped *api.NetworkPair
+}
+
+// Find the destination network of a source NIC.
+// The NIC is mapped, in order of precedence, by:
+//   - the NIC override of the plan VM.
+//   - the network map pair selecting a guest IP address.
+//   - the network map pair of the source network.
+//   - the network map pair selecting the VLAN tag.
+func DestinationNetwork(plan *api.Plan, vmRef ref.Ref, nic *NIC) (destination api.DestinationNetwork, found bool) {
+	if vm, vmFound := plan.Spec.FindVM(vmRef); vmFound {
+		if override, overridden := vm.FindNIC(nic.MAC); overridden {
+			destination = api.DestinationNetwork{
+				Type:      override.Type,
+				Namespace: override.Namespace,
+				Name:      override.Name,
+			}
+			found = true
+			return
+		}
+	}
+	networkMap := plan.Referenced.Map.Network
+	if networkMap == nil {
+		return
+	}
+	if pair, subnetFound := networkMap.FindNetworkBySubnet(nic.IPs); subnetFound {
+		destination = pair.Destination
+		found = true
+		return
+	}
+	if nic.Mapped != nil {
+		destination = nic.Mapped.Destination
+		found = true
+		return
+	}
+	if nic.VLAN != "" {
+		var pair api.NetworkPair
+		pair, found = networkMap.FindNetworkByVLAN(nic.VLAN)
+		destination = pair.Destination
+	}
+	return
+}
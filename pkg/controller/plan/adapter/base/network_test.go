@@ -0,0 +1,134 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func TestDestinationNetwork(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	pod := api.DestinationNetwork{Type: "pod"}
+	mapped := api.DestinationNetwork{Type: "multus", Namespace: "ns", Name: "mapped"}
+	bySubnet := api.DestinationNetwork{Type: "multus", Namespace: "ns", Name: "subnet"}
+	byVLAN := api.DestinationNetwork{Type: "multus", Namespace: "ns", Name: "vlan"}
+	overridden := api.DestinationNetwork{Type: "multus", Namespace: "ns", Name: "override"}
+
+	networkMap := &api.NetworkMap{}
+	networkMap.Spec.Map = []api.NetworkPair{
+		{Source: ref.Ref{ID: "net-1"}, Destination: mapped},
+		{Source: ref.Ref{ID: "net-2"}, Subnet: "10.0.0.0/24", Destination: bySubnet},
+		{Source: ref.Ref{ID: "net-2"}, Subnet: "not-a-cidr", Destination: pod},
+		{Source: ref.Ref{ID: "net-3"}, VLAN: "100", Destination: byVLAN},
+	}
+	pair := networkMap.Spec.Map[0]
+
+	plan := &api.Plan{}
+	plan.Spec.VMs = []planapi.VM{
+		{
+			Ref: ref.Ref{ID: "vm-1"},
+			NICs: []planapi.NIC{
+				{
+					MAC:       "00:00:00:00:00:01",
+					Type:      overridden.Type,
+					Namespace: overridden.Namespace,
+					Name:      overridden.Name,
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		vm          string
+		nic         NIC
+		unmapped    bool
+		found       bool
+		destination api.DestinationNetwork
+	}{
+		{
+			name:        "override wins over every mapping",
+			vm:          "vm-1",
+			nic:         NIC{MAC: "00:00:00:00:00:01", IPs: []string{"10.0.0.5"}, VLAN: "100", Mapped: &pair},
+			found:       true,
+			destination: overridden,
+		},
+		{
+			name:        "override without a network map",
+			vm:          "vm-1",
+			nic:         NIC{MAC: "00:00:00:00:00:01"},
+			unmapped:    true,
+			found:       true,
+			destination: overridden,
+		},
+		{
+			name:        "override of another VM is ignored",
+			vm:          "vm-2",
+			nic:         NIC{MAC: "00:00:00:00:00:01", Mapped: &pair},
+			found:       true,
+			destination: mapped,
+		},
+		{
+			name:     "no network map",
+			vm:       "vm-1",
+			nic:      NIC{MAC: "00:00:00:00:00:02", Mapped: &pair},
+			unmapped: true,
+		},
+		{
+			name:        "subnet wins over the source network",
+			vm:          "vm-1",
+			nic:         NIC{MAC: "00:00:00:00:00:02", IPs: []string{"192.168.0.1", "10.0.0.5"}, Mapped: &pair},
+			found:       true,
+			destination: bySubnet,
+		},
+		{
+			name:        "source network when no subnet matches",
+			vm:          "vm-1",
+			nic:         NIC{MAC: "00:00:00:00:00:02", IPs: []string{"192.168.0.1"}, VLAN: "100", Mapped: &pair},
+			found:       true,
+			destination: mapped,
+		},
+		{
+			name:        "source network wins over the VLAN",
+			vm:          "vm-1",
+			nic:         NIC{MAC: "00:00:00:00:00:02", VLAN: "100", Mapped: &pair},
+			found:       true,
+			destination: mapped,
+		},
+		{
+			name:        "VLAN when the source network is not mapped",
+			vm:          "vm-1",
+			nic:         NIC{MAC: "00:00:00:00:00:02", VLAN: "100"},
+			found:       true,
+			destination: byVLAN,
+		},
+		{
+			name: "VLAN not mapped",
+			vm:   "vm-1",
+			nic:  NIC{MAC: "00:00:00:00:00:02", VLAN: "200"},
+		},
+		{
+			name: "not mapped",
+			vm:   "vm-1",
+			nic:  NIC{MAC: "00:00:00:00:00:02", IPs: []string{"invalid"}},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			plan.Referenced.Map.Network = networkMap
+			if testCase.unmapped {
+				plan.Referenced.Map.Network = nil
+			}
+			destination, found := DestinationNetwork(plan, ref.Ref{ID: testCase.vm}, &testCase.nic)
+			g.Expect(found).To(gomega.Equal(testCase.found))
+			if testCase.found {
+				g.Expect(destination).To(gomega.Equal(testCase.destination))
+			}
+		})
+	}
+}
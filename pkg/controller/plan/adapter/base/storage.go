@@ -0,0 +1,119 @@
+package base
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Destination of a source disk.
+type Disk struct {
+	// Destination storage.
+	api.DestinationStorage
+	// Size in bytes.
+	Size int64
+	// Whether the volume is preallocated.
+	// Unset to let the storage profile decide.
+	Preallocation *bool
+}
+
+// Find the destination of a source disk.
+// The storage map destination of the disk backing and the size of the
+// source disk are overridden by the storage override of the plan VM.
+// The access and volume modes of the storage map do not apply to an
+// overriding storage class.
+func DestinationDisk(plan *api.Plan, vmRef ref.Ref, diskID string, mapped api.DestinationStorage, size int64) (disk Disk) {
+	disk = Disk{
+		DestinationStorage: mapped,
+		Size:               size,
+	}
+	vm, found := plan.Spec.FindVM(vmRef)
+	if !found {
+		return
+	}
+	override, found := vm.FindDisk(diskID)
+	if !found {
+		return
+	}
+	if override.StorageClass != "" {
+		disk.StorageClass = override.StorageClass
+		disk.VolumeMode = ""
+		disk.AccessMode = ""
+	}
+	if override.VolumeMode != "" {
+		disk.VolumeMode = override.VolumeMode
+	}
+	if override.AccessMode != "" {
+		disk.AccessMode = override.AccessMode
+	}
+	if override.Size != nil && override.Size.Value() > disk.Size {
+		disk.Size = override.Size.Value()
+	}
+	switch override.Provisioning {
+	case planapi.ProvisioningThin:
+		preallocation := false
+		disk.Preallocation = &preallocation
+	case planapi.ProvisioningThick:
+		preallocation := true
+		disk.Preallocation = &preallocation
+	}
+	return
+}
+
+// Build the DataVolume spec.
+// The access mode and volume mode are set when specified in the
+// storage map or the override. Otherwise, the storage profile
+// decides the default values.
+func (r *Disk) DataVolumeSpec(source *cdi.DataVolumeSource) (spec cdi.DataVolumeSpec) {
+	storageClass := r.StorageClass
+	spec = cdi.DataVolumeSpec{
+		Source: source,
+		Storage: &cdi.StorageSpec{
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: *resource.NewQuantity(r.Size, resource.BinarySI),
+				},
+			},
+			StorageClassName: &storageClass,
+		},
+		Preallocation: r.Preallocation,
+	}
+	if r.AccessMode != "" {
+		spec.Storage.AccessModes = []core.PersistentVolumeAccessMode{r.AccessMode}
+	}
+	if r.VolumeMode != "" {
+		volumeMode := r.VolumeMode
+		spec.Storage.VolumeMode = &volumeMode
+	}
+	return
+}
+
+// Validate the storage overrides of a plan VM.
+// The overrides must reference a source disk and may not shrink it.
+// Preallocation is only supported by the DataVolumes; the volume
+// populators do not preallocate the volumes.
+// The disks are the sizes of the source disks keyed by identifier.
+func DiskOverridesValid(plan *api.Plan, vmRef ref.Ref, disks map[string]int64, preallocation bool) (ok bool) {
+	vm, found := plan.Spec.FindVM(vmRef)
+	if !found {
+		ok = true
+		return
+	}
+	for _, override := range vm.Disks {
+		size, found := disks[override.ID]
+		if !found {
+			return
+		}
+		if override.Size != nil && override.Size.Value() < size {
+			return
+		}
+		if override.Provisioning == planapi.ProvisioningThick && !preallocation {
+			return
+		}
+	}
+	ok = true
+	return
+}
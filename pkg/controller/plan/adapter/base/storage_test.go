@@ -0,0 +1,71 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDestinationDisk(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	grown := resource.MustParse("20Gi")
+	shrunk := resource.MustParse("1Gi")
+	plan := &api.Plan{}
+	plan.Spec.VMs = []planapi.VM{
+		{
+			Ref: ref.Ref{ID: "vm-1"},
+			Disks: []planapi.Disk{
+				{ID: "log", StorageClass: "fast", Size: &grown, Provisioning: planapi.ProvisioningThick},
+				{ID: "data", Size: &shrunk, Provisioning: planapi.ProvisioningThin},
+			},
+		},
+	}
+	mapped := api.DestinationStorage{
+		StorageClass: "standard",
+		VolumeMode:   core.PersistentVolumeBlock,
+		AccessMode:   core.ReadWriteMany,
+	}
+	size := int64(10 * 1024 * 1024 * 1024)
+
+	//Test a disk without override
+	disk := DestinationDisk(plan, ref.Ref{ID: "vm-1"}, "os", mapped, size)
+	g.Expect(disk.DestinationStorage).To(gomega.Equal(mapped))
+	g.Expect(disk.Size).To(gomega.Equal(size))
+	g.Expect(disk.Preallocation).To(gomega.BeNil())
+
+	//Test the storage class, growth and preallocation overrides
+	disk = DestinationDisk(plan, ref.Ref{ID: "vm-1"}, "log", mapped, size)
+	g.Expect(disk.StorageClass).To(gomega.Equal("fast"))
+	g.Expect(disk.VolumeMode).To(gomega.BeEmpty())
+	g.Expect(disk.AccessMode).To(gomega.BeEmpty())
+	g.Expect(disk.Size).To(gomega.Equal(grown.Value()))
+	spec := disk.DataVolumeSpec(nil)
+	g.Expect(*spec.Storage.StorageClassName).To(gomega.Equal("fast"))
+	g.Expect(spec.Storage.Resources.Requests.Storage().Value()).To(gomega.Equal(grown.Value()))
+	g.Expect(*spec.Preallocation).To(gomega.BeTrue())
+
+	//Test the disk is never shrunk
+	disk = DestinationDisk(plan, ref.Ref{ID: "vm-1"}, "data", mapped, size)
+	g.Expect(disk.StorageClass).To(gomega.Equal("standard"))
+	g.Expect(disk.Size).To(gomega.Equal(size))
+	g.Expect(*disk.Preallocation).To(gomega.BeFalse())
+	spec = disk.DataVolumeSpec(nil)
+	g.Expect(spec.Storage.AccessModes).To(gomega.ConsistOf(core.ReadWriteMany))
+	g.Expect(*spec.Storage.VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+
+	//Test the validation of the overrides
+	disks := map[string]int64{"os": size, "log": size, "data": 0}
+	g.Expect(DiskOverridesValid(plan, ref.Ref{ID: "vm-1"}, disks, true)).To(gomega.BeTrue())
+	g.Expect(DiskOverridesValid(plan, ref.Ref{ID: "vm-1"}, disks, false)).To(gomega.BeFalse())
+	disks["data"] = size
+	g.Expect(DiskOverridesValid(plan, ref.Ref{ID: "vm-1"}, disks, true)).To(gomega.BeFalse())
+	delete(disks, "log")
+	g.Expect(DiskOverridesValid(plan, ref.Ref{ID: "vm-1"}, disks, true)).To(gomega.BeFalse())
+	g.Expect(DiskOverridesValid(plan, ref.Ref{ID: "vm-2"}, disks, true)).To(gomega.BeTrue())
+}
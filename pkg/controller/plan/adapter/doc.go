@@ -3,6 +3,7 @@ package adapter
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/ova"
@@ -31,6 +32,8 @@ func New(provider *api.Provider) (adapter Adapter, err error) {
 		adapter = &ocp.Adapter{}
 	case api.Ova:
 		adapter = &ova.Adapter{}
+	case api.GCP:
+		adapter = &gcp.Adapter{}
 	default:
 		err = liberr.New("provider not supported.")
 	}
@@ -1,20 +1,37 @@
 package gcp
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	utils "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
 	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	cnv "kubevirt.io/api/core/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// GCP builder.
 type Builder struct {
 	*plancontext.Context
-	// MAC addresses already in use on the destination cluster. k=mac, v=vmName
-	macConflictsMap map[string]string
 }
 
 // Template labels
@@ -36,53 +53,48 @@ const (
 	AnnImportDiskId = "cdi.kubevirt.io/storage.import.volumeId"
 )
 
-// OS types
+// Network types
+const (
+	Pod    = "pod"
+	Multus = "multus"
+)
+
+// Bus types
+const (
+	ScsiBus = "scsi"
+)
+
+// Machine types
+const (
+	Q35 = "q35"
+)
+
+// Interface models
 const (
-	Linux = "linux"
+	VifModelVirtio = "virtio"
 )
 
 // OS Distros
 const (
-	ArchLinux   = "arch"
-	CentOS      = "centos"
-	Debian      = "debian"
-	Fedora      = "fedora"
-	FreeBSD     = "freebsd"
-	Gentoo      = "gentoo"
-	Mandrake    = "mandrake"
-	Mandriva    = "mandriva"
-	MES         = "mes"
-	MSDOS       = "msdos"
-	NetBSD      = "netbsd"
-	Netware     = "netware"
-	OpenBSD     = "openbsd"
-	OpenSolaris = "opensolaris"
-	OpenSUSE    = "opensuse"
-	RHEL        = "rhel"
-	SLED        = "sled"
-	Ubuntu      = "ubuntu"
-	Windows     = "windows"
+	CentOS   = "centos"
+	Debian   = "debian"
+	Fedora   = "fedora"
+	OpenSUSE = "opensuse"
+	RHEL     = "rhel"
+	Rocky    = "rocky"
+	SLES     = "sles"
+	Ubuntu   = "ubuntu"
+	Windows  = "windows"
 )
 
 // Default Operating Systems
 const (
 	DefaultWindows = "win10"
-	DefaultLinux   = "rhel8.1"
 	UnknownOS      = "unknown"
 )
 
-func (r Builder) Secret(vmRef ref.Ref, in, object *core.Secret) error {
-	//TODO implement me
-	panic("implement me")
-}
-
-func (r Builder) ConfigMap(vmRef ref.Ref, secret *core.Secret, object *core.ConfigMap) error {
-	//TODO implement me
-	panic("implement me")
-}
-
 // Create the destination Kubevirt VM.
-func (r Builder) VirtualMachine(vmRef ref.Ref, vmSpec *cnv.VirtualMachineSpec, persistentVolumeClaims []core.PersistentVolumeClaim) (err error) {
+func (r *Builder) VirtualMachine(vmRef ref.Ref, vmSpec *cnv.VirtualMachineSpec, persistentVolumeClaims []core.PersistentVolumeClaim) (err error) {
 	vm := &model.Workload{}
 	err = r.Source.Inventory.Find(vm, vmRef)
 	if err != nil {
@@ -98,12 +110,9 @@ func (r Builder) VirtualMachine(vmRef ref.Ref, vmSpec *cnv.VirtualMachineSpec, p
 		vmSpec.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
 	}
 
-	//r.mapFirmware(vm, vmSpec)
-	//r.mapResources(vm, vmSpec)
-	//r.mapHardwareRng(vm, vmSpec)
-	//r.mapInput(vm, vmSpec)
-	//r.mapVideo(vm, vmSpec)
-	//r.mapDisks(vm, persistentVolumeClaims, vmSpec)
+	r.mapFirmware(vm, vmSpec)
+	r.mapResources(vm, vmSpec)
+	r.mapDisks(vm, persistentVolumeClaims, vmSpec)
 	err = r.mapNetworks(vm, vmSpec)
 	if err != nil {
 		err = liberr.Wrap(
@@ -113,75 +122,591 @@ func (r Builder) VirtualMachine(vmRef ref.Ref, vmSpec *cnv.VirtualMachineSpec, p
 			vmRef.String())
 		return
 	}
+
 	return
 }
 
+func (r *Builder) mapFirmware(vm *model.Workload, object *cnv.VirtualMachineSpec) {
+	var bootloader *cnv.Bootloader
+	if vm.UEFI() {
+		// We disable secure boot even if it was enabled on the source because the guest OS won't
+		// be able to boot without getting the NVRAM data. So we start the VM without secure boot
+		// to ease the procedure users need to do in order to make the guest OS to boot.
+		secureBootEnabled := false
+		bootloader = &cnv.Bootloader{
+			EFI: &cnv.EFI{
+				SecureBoot: &secureBootEnabled,
+			}}
+	} else {
+		bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
+	}
+	features := &cnv.Features{}
+	firmware := &cnv.Firmware{}
+	firmware.Bootloader = bootloader
+	object.Template.Spec.Domain.Features = features
+	object.Template.Spec.Domain.Firmware = firmware
+}
+
+func (r *Builder) mapResources(vm *model.Workload, object *cnv.VirtualMachineSpec) {
+	// KubeVirt supports Q35 or PC-Q35 machine types only.
+	object.Template.Spec.Domain.Machine = &cnv.Machine{Type: Q35}
+	object.Template.Spec.Domain.CPU = &cnv.CPU{
+		Sockets: uint32(vm.GuestCpus),
+		Cores:   1,
+		Threads: 1,
+	}
+	memory := resource.NewQuantity(int64(vm.MemoryMb)*1024*1024, resource.BinarySI)
+	resourceRequests := map[core.ResourceName]resource.Quantity{}
+	resourceRequests[core.ResourceMemory] = *memory
+	object.Template.Spec.Domain.Resources.Requests = resourceRequests
+}
+
+// Map the disks in the order they are attached to the source VM.
+// The guests ship the virtio-scsi drivers so the disks are
+// exposed on the SCSI bus and the boot disk is booted first.
+func (r *Builder) mapDisks(vm *model.Workload, persistentVolumeClaims []core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+	var kVolumes []cnv.Volume
+	var kDisks []cnv.Disk
+
+	pvcMap := make(map[string]*core.PersistentVolumeClaim)
+	for i := range persistentVolumeClaims {
+		pvc := &persistentVolumeClaims[i]
+		pvcMap[pvc.Annotations[AnnImportDiskId]] = pvc
+	}
+	disks := transferDisks(&vm.VM)
+	sort.Slice(disks, func(i, j int) bool {
+		return disks[i].Index < disks[j].Index
+	})
+	for _, disk := range disks {
+		pvc, found := pvcMap[disk.Name]
+		if !found {
+			r.Log.Info("PVC not found for disk", "disk", disk.Name)
+			continue
+		}
+		volumeName := fmt.Sprintf("vol-%v", disk.Index)
+		kVolume := cnv.Volume{
+			Name: volumeName,
+			VolumeSource: cnv.VolumeSource{
+				PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc.Name,
+					},
+				},
+			},
+		}
+		kDisk := cnv.Disk{
+			Name: volumeName,
+			DiskDevice: cnv.DiskDevice{
+				Disk: &cnv.DiskTarget{
+					Bus: ScsiBus,
+				},
+			},
+		}
+		if disk.Boot {
+			var bootOrder uint = 1
+			kDisk.BootOrder = &bootOrder
+		}
+		kVolumes = append(kVolumes, kVolume)
+		kDisks = append(kDisks, kDisk)
+	}
+
+	object.Template.Spec.Volumes = kVolumes
+	object.Template.Spec.Domain.Devices.Disks = kDisks
+}
+
 func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec) (err error) {
-	//TODO implement me
-	panic("implement me")
+	var kNetworks []cnv.Network
+	var kInterfaces []cnv.Interface
+
+	for i := range vm.NICs {
+		nic := &vm.NICs[i]
+		networkPair := findNetworkPair(r.Context.Map.Network.Spec.Map, vm, nic)
+		if networkPair == nil {
+			err = liberr.New(
+				"no network map for vm network",
+				"network",
+				nic.Network,
+				"subnetwork",
+				nic.Subnetwork)
+			return
+		}
+		networkName := fmt.Sprintf("net-%v", i)
+		kNetwork := cnv.Network{
+			Name: networkName,
+		}
+		kInterface := cnv.Interface{
+			Name:  networkName,
+			Model: VifModelVirtio,
+		}
+		switch networkPair.Destination.Type {
+		case Pod:
+			kNetwork.Pod = &cnv.PodNetwork{}
+			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+		case Multus:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(
+					networkPair.Destination.Namespace,
+					networkPair.Destination.Name),
+			}
+			kInterface.Bridge = &cnv.InterfaceBridge{}
+		}
+		kNetworks = append(kNetworks, kNetwork)
+		kInterfaces = append(kInterfaces, kInterface)
+	}
+
+	object.Template.Spec.Networks = kNetworks
+	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
+
+	return
 }
 
-func (r Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume) (dvs []cdi.DataVolume, err error) {
-	//TODO implement me
-	panic("implement me")
+// Build tasks.
+func (r *Builder) Tasks(vmRef ref.Ref) (tasks []*plan.Task, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, disk := range transferDisks(vm) {
+		task := &plan.Task{
+			Name: getDiskImageName(vm.ID, disk.Index),
+			Progress: libitr.Progress{
+				Total: disk.SizeGb * 1024,
+			},
+			Annotations: map[string]string{
+				"unit": "MB",
+			},
+		}
+		r.Log.Info("adding task to the plan", "task", task.Name)
+		tasks = append(tasks, task)
+	}
+
+	return
 }
 
-func (r Builder) Tasks(vmRef ref.Ref) ([]*plan.Task, error) {
-	//TODO implement me
-	panic("implement me")
+// Create DataVolume certificate configmap.
+func (r *Builder) ConfigMap(_ ref.Ref, in *core.Secret, object *core.ConfigMap) (err error) {
+	return
+}
+
+func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume) (dvs []cdi.DataVolume, err error) {
+	return
+}
+
+// Build template labels.
+// The OS is derived from the licenses of the boot disk.
+func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	os := UnknownOS
+	for _, disk := range vm.Disks {
+		if disk.Boot {
+			os = guestOS(disk.Licenses)
+			break
+		}
+	}
+
+	var flavor string
+	ram := vm.MemoryMb
+	switch {
+	case ram > 8192:
+		flavor = TemplateFlavorLarge
+	case ram > 4096 && ram <= 8192:
+		flavor = TemplateFlavorMedium
+	case ram > 2048 && ram <= 4096:
+		flavor = TemplateFlavorSmall
+	default:
+		flavor = TemplateFlavorTiny
+	}
+
+	labels = make(map[string]string)
+	labels[fmt.Sprintf(TemplateOSLabel, os)] = "true"
+	labels[fmt.Sprintf(TemplateWorkloadLabel, TemplateWorkloadServer)] = "true"
+	labels[fmt.Sprintf(TemplateFlavorLabel, flavor)] = "true"
+
+	return
 }
 
-func (r Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
-	//TODO implement me
-	panic("implement me")
+// Map the public image licenses to the template OS.
+// e.g: rhel-9-server, centos-stream-9, ubuntu-2204-lts,
+// windows-server-2019-dc, sles-15.
+func guestOS(licenses []string) (os string) {
+	os = UnknownOS
+	for _, license := range licenses {
+		parts := strings.Split(license, "-")
+		distro := parts[0]
+		version := ""
+		for _, part := range parts[1:] {
+			if part != "" && part[0] >= '0' && part[0] <= '9' {
+				version = part
+				break
+			}
+		}
+		switch distro {
+		case RHEL, Fedora:
+			os = distro + version
+		case CentOS:
+			if strings.Contains(license, "stream") {
+				os = fmt.Sprintf("%s-stream%s", distro, version)
+			} else {
+				os = distro + version
+			}
+		case Rocky:
+			os = RHEL + version
+		case Ubuntu:
+			if len(version) == 4 {
+				version = version[:2] + "." + version[2:]
+			}
+			os = distro + version
+		case SLES, OpenSUSE:
+			os = OpenSUSE
+		case Windows:
+			os = DefaultWindows
+			for _, year := range []string{"2012", "2016", "2019", "2022"} {
+				if strings.Contains(license, year) {
+					os = "win2k" + year[2:]
+				}
+			}
+		case Debian:
+			os = UnknownOS
+		default:
+			continue
+		}
+		return
+	}
+	return
 }
 
-func (r Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
-	//TODO implement me
-	panic("implement me")
+// Return a stable identifier for a DataVolume.
+func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
+	return ""
 }
 
-func (r Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
-	//TODO implement me
-	panic("implement me")
+// Return a stable identifier for a PersistentDataVolume
+func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	return pvc.Annotations[AnnImportDiskId]
 }
 
-func (r Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env []core.EnvVar, err error) {
-	//TODO implement me
-	panic("implement me")
+// Build credential secret.
+func (r *Builder) Secret(_ ref.Ref, in, secret *core.Secret) (err error) {
+	// no-op, we just need to clone the provider secret so there's no action to be made here
+	return
 }
 
-func (r Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
-	//TODO implement me
-	panic("implement me")
+func (r *Builder) PodEnvironment(_ ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+	return
 }
 
-func (r Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
-	//TODO implement me
-	panic("implement me")
+// Build LUN PVs.
+func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
+	// do nothing
+	return
 }
 
-func (r Builder) SupportsVolumePopulators() bool {
-	//TODO implement me
-	panic("implement me")
+// Build LUN PVCs.
+func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	// do nothing
+	return
 }
 
-func (r Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
-	//TODO implement me
-	panic("implement me")
+func (r *Builder) SupportsVolumePopulators() bool {
+	return true
 }
 
-func (r Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
-	//TODO implement me
-	panic("implement me")
+// Delta sync is not supported.
+func (r *Builder) SupportsDeltaSync(vmRef ref.Ref) (supported bool, err error) {
+	return
 }
 
-func (r Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []core.PersistentVolumeClaim) (err error) {
-	//TODO implement me
-	panic("implement me")
+// Verification is not supported.
+func (r *Builder) SourceSpec(vmRef ref.Ref) (spec *planbase.VMSpec, err error) {
+	return
 }
 
-func (r Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
-	//TODO implement me
-	panic("implement me")
+// Build the populator volumes.
+// The populator CR and the PVC are created once the disk
+// image has been exported to the bucket.
+func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
+	workload := &model.Workload{}
+	err = r.Source.Inventory.Find(workload, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	bucketName, err := r.bucketName()
+	if err != nil {
+		return
+	}
+	for _, disk := range transferDisks(&workload.VM) {
+		imageName := getDiskImageName(workload.ID, disk.Index)
+		image := &model.Image{}
+		err = r.Source.Inventory.Find(image, ref.Ref{Name: imageName})
+		if err != nil {
+			if errors.As(err, &model.NotFoundError{}) {
+				err = nil
+				r.Log.Info("the disk image has not been created yet", "image", imageName)
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		if image.Status != ImageStatusReady || image.Labels[forkliftLabelExported] != "true" {
+			r.Log.Info("the disk image has not been exported yet", "image", imageName)
+			continue
+		}
+
+		var populatorName string
+		populatorName, err = r.createVolumePopulatorCR(imageName, bucketName, secretName, workload.ID)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+
+		var mapped api.DestinationStorage
+		mapped, err = r.destinationStorage(disk.DiskType)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+
+		pvcAnnotations := make(map[string]string)
+		for k, v := range annotations {
+			pvcAnnotations[k] = v
+		}
+		pvcAnnotations[AnnImportDiskId] = disk.Name
+
+		destination := planbase.DestinationDisk(
+			r.Plan,
+			ref.Ref{ID: workload.ID},
+			disk.Name,
+			mapped,
+			disk.SizeGb*1024*1024*1024)
+
+		var pvc *core.PersistentVolumeClaim
+		pvc, err = r.persistentVolumeClaimWithSourceRef(imageName, destination, populatorName, pvcAnnotations)
+		if err != nil {
+			if !k8serr.IsAlreadyExists(err) {
+				err = liberr.Wrap(err, "couldn't build the PVC",
+					"image", imageName, "storageClassName", destination.StorageClass, "populatorName", populatorName)
+				return
+			}
+			err = nil
+			continue
+		}
+		pvcNames = append(pvcNames, pvc.Name)
+	}
+	return
+}
+
+// The bucket the images are exported to.
+func (r *Builder) bucketName() (name string, err error) {
+	c := libclient.Client{}
+	c.LoadOptionsFromSecret(r.Source.Secret)
+	err = c.Resolve()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	name = c.BucketName
+	return
+}
+
+func (r *Builder) createVolumePopulatorCR(imageName, bucketName, secretName, vmId string) (name string, err error) {
+	populatorCR := &api.GcpVolumePopulator{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      imageName,
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Labels:    map[string]string{"vmID": vmId, "migration": getMigrationID(r.Context)},
+		},
+		Spec: api.GcpVolumePopulatorSpec{
+			SecretName:      secretName,
+			BucketName:      bucketName,
+			ObjectName:      getDiskObjectName(imageName),
+			TransferNetwork: r.Plan.Spec.TransferNetwork,
+		},
+	}
+	err = r.Destination.Client.Create(context.TODO(), populatorCR, &client.CreateOptions{})
+	if err != nil {
+		if !k8serr.IsAlreadyExists(err) {
+			err = liberr.Wrap(err)
+			return
+		} else {
+			err = nil
+		}
+	}
+	name = populatorCR.Name
+	return
+}
+
+// Find the destination storage mapped to the disk type.
+func (r *Builder) destinationStorage(diskType string) (mapped api.DestinationStorage, err error) {
+	for _, storageMap := range r.Context.Map.Storage.Spec.Map {
+		if storageMap.Source.ID == diskType || storageMap.Source.Name == diskType {
+			mapped = storageMap.Destination
+			return
+		}
+	}
+	err = liberr.New("no storage class map found for disk type", "diskType", diskType)
+	return
+}
+
+// Using CDI logic to set the Volume mode and Access mode of the PVC - https://github.com/kubevirt/containerized-data-importer/blob/v1.56.0/pkg/controller/datavolume/util.go#L154
+func (r *Builder) getVolumeAndAccessMode(storageClassName string) ([]core.PersistentVolumeAccessMode, *core.PersistentVolumeMode, error) {
+	filesystemMode := core.PersistentVolumeFilesystem
+	storageProfile := &cdi.StorageProfile{}
+	err := r.Destination.Client.Get(context.TODO(), types.NamespacedName{Name: storageClassName}, storageProfile)
+	if err != nil {
+		return nil, nil, liberr.Wrap(err, "cannot get storage profile", "storageClassName", storageClassName)
+	}
+
+	if len(storageProfile.Status.ClaimPropertySets) > 0 &&
+		len(storageProfile.Status.ClaimPropertySets[0].AccessModes) > 0 {
+		accessModes := storageProfile.Status.ClaimPropertySets[0].AccessModes
+		volumeMode := storageProfile.Status.ClaimPropertySets[0].VolumeMode
+		if volumeMode == nil {
+			// volumeMode is an optional API parameter. Filesystem is the default mode used when volumeMode parameter is omitted.
+			volumeMode = &filesystemMode
+		}
+		return accessModes, volumeMode, nil
+	}
+
+	// no accessMode configured on storageProfile
+	return nil, nil, liberr.New("no accessMode defined on StorageProfile for StorageClass", "storageClassName", storageClassName)
+}
+
+// Get the GcpVolumePopulator CustomResource based on the image name.
+func (r *Builder) getVolumePopulator(name string) (populatorCr api.GcpVolumePopulator, err error) {
+	populatorCr = api.GcpVolumePopulator{}
+	err = r.Destination.Client.Get(context.TODO(), client.ObjectKey{Namespace: r.Plan.Spec.TargetNamespace, Name: name}, &populatorCr)
+	return
+}
+
+func (r *Builder) persistentVolumeClaimWithSourceRef(imageName string, destination planbase.Disk,
+	populatorName string, annotations map[string]string) (pvc *core.PersistentVolumeClaim, err error) {
+
+	apiGroup := "forklift.konveyor.io"
+	virtualSize := destination.Size
+	storageClassName := destination.StorageClass
+
+	var accessModes []core.PersistentVolumeAccessMode
+	var volumeMode *core.PersistentVolumeMode
+	// The storage profile is only needed for what the mapping leaves unset.
+	if destination.AccessMode == "" || destination.VolumeMode == "" {
+		accessModes, volumeMode, err = r.getVolumeAndAccessMode(storageClassName)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	if destination.AccessMode != "" {
+		accessModes = []core.PersistentVolumeAccessMode{destination.AccessMode}
+	}
+	if destination.VolumeMode != "" {
+		volumeMode = &destination.VolumeMode
+	}
+
+	if *volumeMode == core.PersistentVolumeFilesystem {
+		virtualSize = utils.CalculateSpaceWithOverhead(virtualSize, 0.1)
+	}
+
+	pvc = &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        imageName,
+			Namespace:   r.Plan.Spec.TargetNamespace,
+			Annotations: annotations,
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			AccessModes: accessModes,
+			Resources: core.ResourceRequirements{
+				Requests: map[core.ResourceName]resource.Quantity{
+					core.ResourceStorage: *resource.NewQuantity(virtualSize, resource.BinarySI)},
+			},
+			StorageClassName: &storageClassName,
+			VolumeMode:       volumeMode,
+			DataSourceRef: &core.TypedObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     api.GcpVolumePopulatorKind,
+				Name:     populatorName,
+			},
+		},
+	}
+
+	err = r.Destination.Client.Create(context.TODO(), pvc, &client.CreateOptions{})
+	return
+}
+
+func (r *Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
+	populatorCr, err := r.getVolumePopulator(persistentVolumeClaim.Name)
+	if err != nil {
+		return
+	}
+	transferredBytes, err = strconv.ParseInt(populatorCr.Status.Transferred, 10, 64)
+	if err != nil {
+		transferredBytes = 0
+		err = nil
+		return
+	}
+	return
+}
+
+func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []core.PersistentVolumeClaim) (err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		return
+	}
+	names := map[string]bool{}
+	for _, disk := range transferDisks(vm) {
+		names[getDiskImageName(vm.ID, disk.Index)] = true
+	}
+	// To be sure we have every disk based on what already migrated and what's not.
+	for _, pvc := range pvcs {
+		names[pvc.Name] = true
+	}
+	migrationID := string(r.Plan.Status.Migration.ActiveSnapshot().Migration.UID)
+	for name := range names {
+		populatorCr, err := r.getVolumePopulator(name)
+		if err != nil {
+			continue
+		}
+		err = r.setPopulatorLabels(populatorCr, vmRef.ID, migrationID)
+		if err != nil {
+			r.Log.Error(err, "Couldn't update the Populator Custom Resource labels.",
+				"vmRef", vmRef, "migration", migrationID, "GcpVolumePopulator", populatorCr.Name)
+			continue
+		}
+	}
+	return
+}
+
+func (r *Builder) setPopulatorLabels(populatorCr api.GcpVolumePopulator, vmId, migrationId string) (err error) {
+	populatorCrCopy := populatorCr.DeepCopy()
+	if populatorCr.Labels == nil {
+		populatorCr.Labels = make(map[string]string)
+	}
+	populatorCr.Labels["vmID"] = vmId
+	populatorCr.Labels["migration"] = migrationId
+	patch := client.MergeFrom(populatorCrCopy)
+	err = r.Destination.Client.Patch(context.TODO(), &populatorCr, patch)
+	return
+}
+
+// The task is named after the disk image, so is the PVC.
+func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	taskName = pvc.Name
+	return
 }
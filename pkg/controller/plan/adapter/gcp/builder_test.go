@@ -0,0 +1,202 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	utils "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	testNamespace = "test"
+	testImage     = "forklift-migration-vm-1-disk-0"
+)
+
+func builder(objects ...client.Object) (b *Builder) {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+
+	plan := &api.Plan{}
+	plan.Spec.TargetNamespace = testNamespace
+	plan.Spec.TransferNetwork = &core.ObjectReference{Namespace: testNamespace, Name: "transfer"}
+	plan.Spec.VMs = []planapi.VM{{Ref: ref.Ref{ID: "1"}}}
+	storageMap := &api.StorageMap{}
+	storageMap.Spec.Map = []api.StoragePair{
+		{
+			Source:      ref.Ref{Name: "pd-standard"},
+			Destination: api.DestinationStorage{StorageClass: "standard"},
+		},
+		{
+			Source: ref.Ref{ID: "pd-ssd"},
+			Destination: api.DestinationStorage{
+				StorageClass: "fast",
+				VolumeMode:   core.PersistentVolumeBlock,
+				AccessMode:   core.ReadWriteMany,
+			},
+		},
+	}
+	migration := &api.Migration{}
+	migration.UID = "migration"
+
+	ctx := &plancontext.Context{Plan: plan, Migration: migration}
+	ctx.Map.Storage = storageMap
+	// The cluster the controller runs in must not be used.
+	ctx.Client = fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx.Destination.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	b = &Builder{Context: ctx}
+	return
+}
+
+func storageProfile(name string, volumeMode core.PersistentVolumeMode) *cdi.StorageProfile {
+	return &cdi.StorageProfile{
+		ObjectMeta: meta.ObjectMeta{Name: name},
+		Status: cdi.StorageProfileStatus{
+			ClaimPropertySets: []cdi.ClaimPropertySet{
+				{
+					AccessModes: []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+					VolumeMode:  &volumeMode,
+				},
+			},
+		},
+	}
+}
+
+func TestDestinationStorage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	b := builder()
+
+	// Matched by name.
+	mapped, err := b.destinationStorage("pd-standard")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(mapped).To(gomega.Equal(api.DestinationStorage{StorageClass: "standard"}))
+
+	// Matched by ID, with the access and volume modes.
+	mapped, err = b.destinationStorage("pd-ssd")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(mapped.StorageClass).To(gomega.Equal("fast"))
+	g.Expect(mapped.VolumeMode).To(gomega.Equal(core.PersistentVolumeBlock))
+	g.Expect(mapped.AccessMode).To(gomega.Equal(core.ReadWriteMany))
+
+	// Not mapped.
+	_, err = b.destinationStorage("pd-balanced")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestVolumePopulatorCR(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	b := builder()
+
+	name, err := b.createVolumePopulatorCR(testImage, "bucket", "secret", "1")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(name).To(gomega.Equal(testImage))
+
+	populatorCr, err := b.getVolumePopulator(testImage)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(populatorCr.Labels).To(gomega.Equal(map[string]string{"vmID": "1", "migration": "migration"}))
+	g.Expect(populatorCr.Spec.SecretName).To(gomega.Equal("secret"))
+	g.Expect(populatorCr.Spec.BucketName).To(gomega.Equal("bucket"))
+	g.Expect(populatorCr.Spec.ObjectName).To(gomega.Equal(getDiskObjectName(testImage)))
+	g.Expect(populatorCr.Spec.TransferNetwork).To(gomega.Equal(b.Plan.Spec.TransferNetwork))
+
+	// Created again on a later reconcile.
+	name, err = b.createVolumePopulatorCR(testImage, "bucket", "secret", "1")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(name).To(gomega.Equal(testImage))
+
+	// Only on the destination cluster.
+	err = b.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testImage}, &api.GcpVolumePopulator{})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestPersistentVolumeClaim(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	const size = 10 * 1024 * 1024 * 1024
+
+	tests := []struct {
+		name       string
+		diskType   string
+		profile    *cdi.StorageProfile
+		accessMode core.PersistentVolumeAccessMode
+		volumeMode core.PersistentVolumeMode
+		size       int64
+	}{
+		{
+			name:       "filesystem from the storage profile",
+			diskType:   "pd-standard",
+			profile:    storageProfile("standard", core.PersistentVolumeFilesystem),
+			accessMode: core.ReadWriteOnce,
+			volumeMode: core.PersistentVolumeFilesystem,
+			size:       utils.CalculateSpaceWithOverhead(size, 0.1),
+		},
+		{
+			name:       "block from the storage profile",
+			diskType:   "pd-standard",
+			profile:    storageProfile("standard", core.PersistentVolumeBlock),
+			accessMode: core.ReadWriteOnce,
+			volumeMode: core.PersistentVolumeBlock,
+			size:       size,
+		},
+		{
+			name:       "modes from the storage map",
+			diskType:   "pd-ssd",
+			accessMode: core.ReadWriteMany,
+			volumeMode: core.PersistentVolumeBlock,
+			size:       size,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			var objects []client.Object
+			if testCase.profile != nil {
+				objects = append(objects, testCase.profile)
+			}
+			b := builder(objects...)
+			mapped, err := b.destinationStorage(testCase.diskType)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			destination := planbase.DestinationDisk(b.Plan, ref.Ref{ID: "1"}, "disk", mapped, size)
+			annotations := map[string]string{AnnImportDiskId: "disk"}
+
+			_, err = b.persistentVolumeClaimWithSourceRef(testImage, destination, testImage, annotations)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+
+			pvc := &core.PersistentVolumeClaim{}
+			err = b.Destination.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testImage}, pvc)
+			g.Expect(err).ToNot(gomega.HaveOccurred())
+			g.Expect(pvc.Annotations).To(gomega.Equal(annotations))
+			g.Expect(*pvc.Spec.StorageClassName).To(gomega.Equal(mapped.StorageClass))
+			g.Expect(pvc.Spec.AccessModes).To(gomega.Equal([]core.PersistentVolumeAccessMode{testCase.accessMode}))
+			g.Expect(*pvc.Spec.VolumeMode).To(gomega.Equal(testCase.volumeMode))
+			request := pvc.Spec.Resources.Requests[core.ResourceStorage]
+			g.Expect(request.Value()).To(gomega.Equal(testCase.size))
+			g.Expect(pvc.Spec.DataSourceRef.Kind).To(gomega.Equal(api.GcpVolumePopulatorKind))
+			g.Expect(pvc.Spec.DataSourceRef.Name).To(gomega.Equal(testImage))
+
+			// Only on the destination cluster.
+			err = b.Client.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testImage}, &core.PersistentVolumeClaim{})
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+	}
+
+	// No storage profile for the modes the mapping leaves unset.
+	b := builder()
+	mapped, _ := b.destinationStorage("pd-standard")
+	destination := planbase.DestinationDisk(b.Plan, ref.Ref{ID: "1"}, "disk", mapped, size)
+	_, err := b.persistentVolumeClaimWithSourceRef(testImage, destination, testImage, nil)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
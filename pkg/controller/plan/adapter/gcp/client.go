@@ -1,44 +1,79 @@
 package gcp
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+
 	"cloud.google.com/go/compute/apiv1/computepb"
-	"errors"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
-	gcpclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
-	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Power states.
 const (
-	ImageStatusReady = "READY"
+	powerOn      = "On"
+	powerOff     = "Off"
+	powerUnknown = "Unknown"
+)
+
+// Image status.
+var (
+	ImageStatusReady   = libclient.ImageStatusReady.String()
+	ImageStatusPending = libclient.ImageStatusPending.String()
+	ImageStatusFailed  = libclient.ImageStatusFailed.String()
 )
 
-var ResourceNotFoundError = errors.New("resource not found")
-var NameOrIDRequiredError = errors.New("id or name is required")
-var UnexpectedVolumeStatusError = errors.New("unexpected volume status")
+// Snapshot status.
+var (
+	SnapshotStatusReady  = libclient.SnapshotStatusReady.String()
+	SnapshotStatusFailed = libclient.SnapshotStatusFailed.String()
+)
 
+// Volume update pod.
+const (
+	// Annotation of the transfer network.
+	AnnDefaultNetwork = "v1.multus-cni.io/default-network"
+	// Populated volume paths.
+	updateVolumeName = "target"
+	updateMountPath  = "/mnt/"
+	updateDevicePath = "/dev/block"
+	// The qemu group.
+	qemuGroup = 107
+)
+
+// GCP VM Client
 type Client struct {
-	gcpclient.Client
-	Log     logging.LevelLogger
+	libclient.Client
 	Context *plancontext.Context
 }
 
-// Return the source VM's power state.
-func (r *Client) PowerState(vmRef ref.Ref) (state string, err error) {
-	state, err = r.VMStatus(vmRef.Name)
-	if err != nil {
-		err = liberr.Wrap(err)
-	}
+// Connect.
+func (r *Client) connect() (err error) {
+	r.URL = r.Context.Source.Provider.Spec.URL
+	r.LoadOptionsFromSecret(r.Context.Source.Secret)
+	err = r.Connect()
 	return
 }
 
 // Power on the source VM.
 func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
-	//vm, err := r.getVM(vmRef)
-	err = r.VMStart(vmRef.Name)
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	err = r.VMStart(vm.Zone, vm.Name)
 	if err != nil {
 		err = liberr.Wrap(err)
 	}
@@ -47,10 +82,43 @@ func (r *Client) PowerOn(vmRef ref.Ref) (err error) {
 
 // Power off the source VM.
 func (r *Client) PowerOff(vmRef ref.Ref) (err error) {
-	//vm, err := r.getVM(vmRef)
-	err = r.VMStop(vmRef.Name)
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	status, err := r.VMStatus(vm.Zone, vm.Name)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	switch status {
+	case libclient.VMStatusRunning.String(), libclient.VMStatusSuspended.String():
+		err = r.VMStop(vm.Zone, vm.Name)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	}
+	return
+}
+
+// Return the source VM's power state.
+func (r *Client) PowerState(vmRef ref.Ref) (state string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	status, err := r.VMStatus(vm.Zone, vm.Name)
 	if err != nil {
 		err = liberr.Wrap(err)
+		return
+	}
+	switch status {
+	case libclient.VMStatusTerminated.String(), libclient.VMStatusStopped.String():
+		state = powerOff
+	case libclient.VMStatusRunning.String():
+		state = powerOn
+	default:
+		state = powerUnknown
 	}
 	return
 }
@@ -62,22 +130,108 @@ func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
 		err = liberr.Wrap(err)
 		return
 	}
-	off = state == gcpclient.VMStatusStopped.String()
+	off = state == powerOff
 	return
 }
 
 // Create a snapshot of the source VM.
-func (r *Client) CreateSnapshot(vmRef ref.Ref) (imageID string, err error) {
+// Every transferred disk is snapshotted, the snapshots are
+// identified by the number of the precopy they belong to.
+func (r *Client) CreateSnapshot(vmRef ref.Ref) (snapshot string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	precopies, err := r.precopies(vmRef)
+	if err != nil {
+		return
+	}
+	snapshot = strconv.Itoa(len(precopies) + 1)
+	for _, disk := range transferDisks(vm) {
+		snapshotName := getDiskSnapshotName(vm.ID, disk.Index, snapshot)
+		err = r.Get(&computepb.Snapshot{}, snapshotName)
+		if err == nil {
+			continue
+		}
+		if !r.IsNotFound(err) {
+			return
+		}
+		labels := map[string]string{
+			forkliftLabelVM:   vm.ID,
+			forkliftLabelDisk: disk.Name,
+		}
+		err = r.SnapshotCreate(vm.Zone, disk.Name, snapshotName, labels)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"snapshot creation failed.",
+				"vm",
+				vmRef.String(),
+				"disk",
+				disk.Name)
+			return
+		}
+	}
 	return
 }
 
 // Remove all warm migration snapshots.
+// The images, the exported objects and the pods of the precopies
+// following the initial one are removed as well.
 func (r *Client) RemoveSnapshots(vmRef ref.Ref, precopies []planapi.Precopy) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	for i, precopy := range precopies {
+		for _, disk := range transferDisks(vm) {
+			if i > 0 {
+				imageName := getPrecopyImageName(vm.ID, disk.Index, precopy.Snapshot)
+				err = r.removeExportedImage(imageName)
+				if err != nil {
+					return
+				}
+				err = r.removeUpdatePod(imageName)
+				if err != nil {
+					return
+				}
+			}
+			err = r.SnapshotDelete(getDiskSnapshotName(vm.ID, disk.Index, precopy.Snapshot))
+			if err != nil {
+				if !r.IsNotFound(err) {
+					return
+				}
+				err = nil
+			}
+		}
+	}
 	return
 }
 
 // Check if a snapshot is ready to transfer.
-func (r *Client) CheckSnapshotReady(vmRef ref.Ref, imageID string) (ready bool, err error) {
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, snapshot string) (ready bool, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	for _, disk := range transferDisks(vm) {
+		snapshotName := getDiskSnapshotName(vm.ID, disk.Index, snapshot)
+		diskSnapshot := &computepb.Snapshot{}
+		err = r.Get(diskSnapshot, snapshotName)
+		if err != nil {
+			return
+		}
+		switch diskSnapshot.GetStatus() {
+		case SnapshotStatusReady:
+		case SnapshotStatusFailed:
+			err = liberr.New("snapshot creation failed.", "snapshot", snapshotName)
+			return
+		default:
+			r.Log.Info("Waiting for the snapshot to be ready.", "snapshot", snapshotName, "status", diskSnapshot.GetStatus())
+			return
+		}
+	}
+	ready = true
 	return
 }
 
@@ -88,18 +242,47 @@ func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, data
 
 // Close connections to the provider API.
 func (r *Client) Close() {
+	r.Client.Close()
 }
 
-func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
-	for _, vmStatus := range vms {
-		vmRef := ref.Ref{ID: vmStatus.Ref.ID, Name: vmStatus.Ref.Name}
+// Remove the images and the exported objects
+// created for the migrated VMs.
+func (r *Client) Finalize(vmStatuses []*planapi.VMStatus, migrationName string) {
+	for _, vmStatus := range vmStatuses {
+		vmRef := ref.Ref{ID: vmStatus.Ref.ID}
 		vm, err := r.getVM(vmRef)
 		if err != nil {
-			r.Log.Error(err, "failed to find vm", "vm", vm.Name)
+			r.Log.Error(err, "failed to find vm", "vm", vmRef.String())
+			continue
+		}
+		for _, disk := range transferDisks(vm) {
+			imageName := getDiskImageName(vm.ID, disk.Index)
+			err = r.removeExportedImage(imageName)
+			if err != nil {
+				r.Log.Error(err, "removing the exported image", "vm", vm.Name, "image", imageName)
+				continue
+			}
+		}
+	}
+}
+
+// Remove the exported object and the image.
+func (r *Client) removeExportedImage(imageName string) (err error) {
+	err = r.ObjectDelete(r.BucketName, getDiskObjectName(imageName))
+	if err != nil {
+		if !r.IsNotFound(err) {
 			return
 		}
-		// delete image
+		err = nil
 	}
+	err = r.ImageDelete(imageName)
+	if err != nil {
+		if !r.IsNotFound(err) {
+			return
+		}
+		err = nil
+	}
+	return
 }
 
 func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
@@ -107,81 +290,361 @@ func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
 	return
 }
 
-func (r *Client) getVM(vmRef ref.Ref) (vm *computepb.Instance, err error) {
-	if vmRef.Name == "" && vmRef.ID == "" {
-		err = NameOrIDRequiredError
+// Prepare the disks for the transfer.
+// The source VM must be stopped. For each disk an image is created
+// and exported to the bucket as a compressed raw disk that the
+// populator downloads. The image is labeled once exported so the
+// builder only creates the populator volumes for exported images.
+// On warm migrations the images are created from the snapshot of
+// the current precopy instead, see precopyTransfer().
+func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		return
+	}
+	if r.Context.Plan.Spec.Warm {
+		ready, err = r.precopyTransfer(vm)
 		return
 	}
-	err = r.Client.Get(vm, vmRef.Name)
+	poweredOff, err := r.PoweredOff(vmRef)
 	if err != nil {
-		err = liberr.Wrap(err)
 		return
 	}
+	if !poweredOff {
+		r.Log.Info("Waiting for the VM to be stopped.", "vm", vmRef.String())
+		return
+	}
+	ready = true
+	for _, disk := range transferDisks(vm) {
+		var exported bool
+		exported, err = r.exportImage(vm, disk, getDiskImageName(vm.ID, disk.Index), "")
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"disk export failed.",
+				"vm",
+				vmRef.String(),
+				"disk",
+				disk.Name)
+			return
+		}
+		if !exported {
+			ready = false
+		}
+	}
 	return
 }
 
-func (r *Client) createImage(vmRef ref.Ref) (imageName string, err error) {
-	if vmRef.Name == "" && vmRef.ID == "" {
-		err = NameOrIDRequiredError
+// Create the image from the disk, or from the disk snapshot when
+// set, and export it to the bucket.
+// Returns exported=true once done.
+func (r *Client) exportImage(vm *model.VM, disk model.AttachedDisk, imageName, snapshotName string) (exported bool, err error) {
+	image := &computepb.Image{}
+	err = r.Get(image, imageName)
+	if err != nil {
+		if !r.IsNotFound(err) {
+			return
+		}
+		labels := map[string]string{
+			forkliftLabelVM:   vm.ID,
+			forkliftLabelDisk: disk.Name,
+		}
+		if snapshotName != "" {
+			r.Log.Info("Creating the image from the snapshot.", "vm", vm.Name, "snapshot", snapshotName, "image", imageName)
+			err = r.ImageCreateFromSnapshot(snapshotName, imageName, labels)
+		} else {
+			r.Log.Info("Creating the image from the disk.", "vm", vm.Name, "disk", disk.Name, "image", imageName)
+			err = r.ImageCreateFromDisk(vm.Zone, disk.Name, imageName, labels, false)
+		}
 		return
 	}
-	// create image from boot disk when instance is stopped
-	imageName, err = r.Client.VMCreateImageFromDisk(vmRef.Name, false)
-	if err != nil {
-		err = liberr.Wrap(err)
+	switch image.GetStatus() {
+	case ImageStatusReady:
+	case ImageStatusFailed:
+		err = liberr.New("image creation failed.", "image", imageName)
+		return
+	default:
+		r.Log.Info("Waiting for the image to be ready.", "image", imageName, "status", image.GetStatus())
+		return
+	}
+	if image.Labels[forkliftLabelExported] == "true" {
+		exported = true
+		return
+	}
+	exported, err = r.ImageExport(imageName, getDiskObjectName(imageName))
+	if err != nil || !exported {
 		return
 	}
+	err = r.ImageSetLabels(imageName, map[string]string{forkliftLabelExported: "true"})
+	if err != nil {
+		exported = false
+	}
 	return
 }
 
-func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
-	ready = false
-	_, err = r.getVM(vmRef)
+// Transfer the snapshot of the current precopy.
+// The initial precopy is exported to the images the populators
+// download. The following ones are exported to images that a pod
+// writes onto the populated volumes. Compute Engine does not expose
+// the blocks changed between snapshots so every precopy transfers
+// the whole disk, the pod only skips writing the blocks that did
+// not change. Not reached while the validator rejects warm plans.
+// Returns ready=true once transferred.
+func (r *Client) precopyTransfer(vm *model.VM) (ready bool, err error) {
+	precopies, err := r.precopies(ref.Ref{ID: vm.ID})
 	if err != nil {
-		err = liberr.Wrap(
-			err,
-			"VM lookup failed.",
-			"vm",
-			vmRef.String())
 		return
 	}
-
-	// Turn off the VM. (make sure VM is off)
-	poweredOff, err := r.PoweredOff(vmRef)
-	if err != nil {
-		err = liberr.Wrap(err)
+	n := len(precopies)
+	if n == 0 {
 		return
 	}
-	if !poweredOff {
-		err = r.PowerOff(vmRef)
+	snapshot := precopies[n-1].Snapshot
+	ready = true
+	for _, disk := range transferDisks(vm) {
+		snapshotName := getDiskSnapshotName(vm.ID, disk.Index, snapshot)
+		imageName := getDiskImageName(vm.ID, disk.Index)
+		if n > 1 {
+			imageName = getPrecopyImageName(vm.ID, disk.Index, snapshot)
+		}
+		var exported bool
+		exported, err = r.exportImage(vm, disk, imageName, snapshotName)
 		if err != nil {
-			err = liberr.Wrap(err)
+			err = liberr.Wrap(
+				err,
+				"snapshot export failed.",
+				"vm",
+				vm.Name,
+				"snapshot",
+				snapshotName)
 			return
 		}
+		if !exported {
+			ready = false
+			continue
+		}
+		if n == 1 {
+			continue
+		}
+		var done bool
+		done, err = r.updateVolume(vm, disk, imageName)
+		if err != nil {
+			return
+		}
+		if !done {
+			ready = false
+		}
 	}
+	return
+}
 
-	// Create Image from VM's boot disk. (make sure image is ready)
-	imageName, err := r.createImage(vmRef)
+// Write the precopy image onto the populated volume.
+// The pod runs the populator in update mode with the settings of the
+// populator CR of the disk and mounts the populated PVC.
+// Returns done=true once the pod succeeded.
+func (r *Client) updateVolume(vm *model.VM, disk model.AttachedDisk, imageName string) (done bool, err error) {
+	namespace := r.Context.Plan.Spec.TargetNamespace
+	pod := &core.Pod{}
+	err = r.Context.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: imageName},
+		pod)
+	if err == nil {
+		switch pod.Status.Phase {
+		case core.PodSucceeded:
+			done = true
+		case core.PodFailed:
+			err = liberr.New("the volume update failed.", "pod", imageName)
+		}
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	volumeName := getDiskImageName(vm.ID, disk.Index)
+	populatorCr := &api.GcpVolumePopulator{}
+	err = r.Context.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: volumeName},
+		populatorCr)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
-	image := &computepb.Image{}
-	err = r.Client.Get(image, imageName)
+	pvc := &core.PersistentVolumeClaim{}
+	err = r.Context.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: volumeName},
+		pvc)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
-	if *image.Status != ImageStatusReady {
-		err = liberr.Wrap(ResourceNotFoundError)
+	pod = r.updatePod(vm, imageName, populatorCr, pvc)
+	err = r.Context.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
 		return
 	}
-	// Export Image to bucket (make sure image in bucket is ready)
-	err = r.VMExportImageToBucket(imageName, gcpclient.QCOW2, r.BucketName)
+	r.Log.Info("Created the volume update pod.", "vm", vm.Name, "pod", pod.Name, "pvc", pvc.Name)
+	return
+}
+
+// Build the volume update pod.
+func (r *Client) updatePod(vm *model.VM, imageName string, populatorCr *api.GcpVolumePopulator, pvc *core.PersistentVolumeClaim) (pod *core.Pod) {
+	nonRoot := true
+	allowPrivilegeEscalation := false
+	user := int64(qemuGroup)
+	args := []string{
+		"--update",
+		"--secret-name=" + populatorCr.Spec.SecretName,
+		"--bucket-name=" + populatorCr.Spec.BucketName,
+		"--object-name=" + getDiskObjectName(imageName),
+		"--cr-name=" + imageName,
+		"--cr-namespace=" + populatorCr.Namespace,
+	}
+	container := core.Container{
+		Name:  "populate",
+		Image: settings.Settings.Migration.GcpPopulatorImage,
+		SecurityContext: &core.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			RunAsNonRoot:             &nonRoot,
+			RunAsUser:                &user,
+			Capabilities: &core.Capabilities{
+				Drop: []core.Capability{"ALL"},
+			},
+		},
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      "secret-volume",
+				ReadOnly:  true,
+				MountPath: "/etc/secret-volume",
+			},
+		},
+	}
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == core.PersistentVolumeBlock {
+		args = append(args, "--volume-path="+updateDevicePath)
+		container.VolumeDevices = []core.VolumeDevice{
+			{
+				Name:       updateVolumeName,
+				DevicePath: updateDevicePath,
+			},
+		}
+	} else {
+		args = append(args, "--volume-path="+updateMountPath+"disk.img")
+		container.VolumeMounts = append(
+			container.VolumeMounts,
+			core.VolumeMount{
+				Name:      updateVolumeName,
+				MountPath: updateMountPath,
+			})
+	}
+	container.Args = args
+	annotations := map[string]string{}
+	if network := populatorCr.Spec.TransferNetwork; network != nil {
+		annotations[AnnDefaultNetwork] = fmt.Sprintf("%s/%s", network.Namespace, network.Name)
+	}
+	pod = &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        imageName,
+			Namespace:   populatorCr.Namespace,
+			Annotations: annotations,
+			Labels: map[string]string{
+				"vmID":      vm.ID,
+				"migration": getMigrationID(r.Context),
+			},
+		},
+		Spec: core.PodSpec{
+			Containers: []core.Container{container},
+			SecurityContext: &core.PodSecurityContext{
+				FSGroup: &user,
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+			RestartPolicy: core.RestartPolicyNever,
+			Volumes: []core.Volume{
+				{
+					Name: updateVolumeName,
+					VolumeSource: core.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				},
+				{
+					Name: "secret-volume",
+					VolumeSource: core.VolumeSource{
+						Secret: &core.SecretVolumeSource{
+							SecretName: populatorCr.Spec.SecretName,
+						},
+					},
+				},
+			},
+		},
+	}
+	return
+}
+
+// Remove the volume update pod.
+func (r *Client) removeUpdatePod(name string) (err error) {
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: r.Context.Plan.Spec.TargetNamespace,
+		},
+	}
+	err = r.Context.Destination.Client.Delete(context.TODO(), pod)
 	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			return
+		}
 		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// The warm migration precopies of the VM.
+func (r *Client) precopies(vmRef ref.Ref) (precopies []planapi.Precopy, err error) {
+	vmStatus, found := r.Context.Plan.Status.Migration.FindVM(vmRef)
+	if !found {
+		err = liberr.New("VM not found in the plan status.", "vm", vmRef.String())
 		return
 	}
-	ready = true
+	if vmStatus.Warm != nil {
+		precopies = vmStatus.Warm.Precopies
+	}
+	return
+}
+
+// Find the VM in the inventory.
+func (r *Client) getVM(vmRef ref.Ref) (vm *model.VM, err error) {
+	vm = &model.VM{}
+	err = r.Context.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+	}
+	return
+}
+
+// The VM disks to be transferred.
+// Every attached persistent disk is transferred, scratch
+// (local SSD) disks are not backed by a disk resource.
+func transferDisks(vm *model.VM) (disks []model.AttachedDisk) {
+	for _, disk := range vm.Disks {
+		if disk.Type == libclient.DiskTypePersistent {
+			disks = append(disks, disk)
+		}
+	}
 	return
 }
@@ -0,0 +1,64 @@
+package gcp
+
+import (
+	"fmt"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
+)
+
+// Image labels.
+const (
+	// The VM the image has been created for.
+	forkliftLabelVM = "forklift-vm"
+	// The source disk of the image.
+	forkliftLabelDisk = "forklift-disk"
+	// Set once the image has been exported to the bucket.
+	forkliftLabelExported = "forklift-exported"
+)
+
+func getMigrationID(ctx *plancontext.Context) string {
+	return string(ctx.Migration.GetUID())
+}
+
+// The name of the image created from a VM disk.
+// The name is also used for the populator CR, the PVC and the task.
+func getDiskImageName(vmID string, index int32) string {
+	const nameFormat = "forklift-migration-vm-%s-disk-%d"
+	return fmt.Sprintf(nameFormat, vmID, index)
+}
+
+// The name of the bucket object the image is exported to.
+func getDiskObjectName(imageName string) string {
+	return imageName + libclient.ExportSuffix
+}
+
+// The name of the snapshot taken of a VM disk at a precopy.
+func getDiskSnapshotName(vmID string, index int32, snapshot string) string {
+	const nameFormat = "forklift-snapshot-vm-%s-disk-%d-%s"
+	return fmt.Sprintf(nameFormat, vmID, index, snapshot)
+}
+
+// The name of the image created from the snapshot of a precopy
+// following the initial one. The name is also used for the pod
+// updating the populated volume.
+func getPrecopyImageName(vmID string, index int32, snapshot string) string {
+	return fmt.Sprintf("%s-%s", getDiskImageName(vmID, index), snapshot)
+}
+
+// Find the network map pair of a NIC.
+// A NIC is mapped either by its subnetwork or by its network,
+// the subnetwork pair is preferred.
+func findNetworkPair(mapping []api.NetworkPair, vm *model.Workload, nic *model.NetworkInterface) (pair *api.NetworkPair) {
+	for _, id := range vm.NICNetworkIDs(nic) {
+		for i := range mapping {
+			if mapping[i].Source.ID == id {
+				pair = &mapping[i]
+				return
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package gcp
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestGuestOS(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		licenses []string
+		expected string
+	}{
+		{"no licenses", nil, UnknownOS},
+		{"rhel", []string{"rhel-9-server"}, "rhel9"},
+		{"rocky", []string{"rocky-linux-8"}, "rhel8"},
+		{"centos", []string{"centos-7"}, "centos7"},
+		{"centos stream", []string{"centos-stream-9"}, "centos-stream9"},
+		{"ubuntu", []string{"ubuntu-2204-lts"}, "ubuntu22.04"},
+		{"fedora", []string{"fedora-38"}, "fedora38"},
+		{"windows server", []string{"windows-server-2019-dc"}, "win2k19"},
+		{"windows", []string{"windows-10"}, DefaultWindows},
+		{"sles", []string{"sles-15"}, OpenSUSE},
+		{"unknown license first", []string{"some-license", "rhel-8-server"}, "rhel8"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			g.Expect(guestOS(testCase.licenses)).To(gomega.Equal(testCase.expected))
+		})
+	}
+}
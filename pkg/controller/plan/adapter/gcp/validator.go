@@ -0,0 +1,143 @@
+package gcp
+
+import (
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/gcp"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Validator
+type Validator struct {
+	plan      *api.Plan
+	inventory web.Client
+}
+
+// Load.
+func (r *Validator) Load() (err error) {
+	r.inventory, err = web.NewClient(r.plan.Referenced.Provider.Source)
+	return
+}
+
+// Validate that a VM's disk types have been mapped.
+func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Storage == nil {
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, disk := range transferDisks(&vm.VM) {
+		if !r.plan.Referenced.Map.Storage.Status.Refs.Find(ref.Ref{ID: disk.DiskType}) {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Validate that a VM's disk overrides reference its disks and are supported.
+// The disks are transferred by the volume populator and not preallocated.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	disks := make(map[string]int64)
+	for _, disk := range transferDisks(&vm.VM) {
+		disks[disk.Name] = disk.SizeGb * 1024 * 1024 * 1024
+	}
+	ok = planbase.DiskOverridesValid(r.plan, ref.Ref{ID: vm.ID}, disks, false)
+	return
+}
+
+// Validate that a VM's networks have been mapped.
+func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for i := range vm.NICs {
+		if findNetworkPair(r.plan.Referenced.Map.Network.Spec.Map, vm, &vm.NICs[i]) == nil {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Validate that a VM's Host isn't in maintenance mode.
+func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// Validate whether warm migration is supported from this provider type.
+// Compute Engine does not expose the blocks changed between snapshots,
+// each precopy (including the one after the cutover) would export and
+// download the whole disks so the downtime would not be shortened.
+func (r *Validator) WarmMigration() (ok bool) {
+	ok = false
+	return
+}
+
+// Validate that the target cluster satisfies the VM's requirements.
+func (r *Validator) TargetCompatible(ctx *plancontext.Context, vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
+// Validate that no more than one of a VM's networks is mapped to the pod network.
+func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
+	if r.plan.Referenced.Map.Network == nil {
+		return
+	}
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	mapping := r.plan.Referenced.Map.Network.Spec.Map
+	podMapped := 0
+	for i := range vm.NICs {
+		mapped := findNetworkPair(mapping, vm, &vm.NICs[i])
+		if mapped != nil && mapped.Destination.Type == Pod {
+			podMapped++
+		}
+	}
+
+	ok = podMapped <= 1
+	return
+}
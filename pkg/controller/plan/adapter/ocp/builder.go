@@ -12,6 +12,8 @@ import (
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
 	export "kubevirt.io/api/export/v1alpha1"
+	instancetypeapi "kubevirt.io/api/instancetype"
+	instancetypev1 "kubevirt.io/api/instancetype/v1alpha2"
 
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
@@ -87,9 +89,14 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 
 	dataVolumes := []cdi.DataVolume{}
 	for _, volume := range vmExport.Status.Links.External.Volumes {
+		var claim string
+		claim, err = exportedClaim(r.sourceClient, vmRef, vmExport, volume.Name)
+		if err != nil {
+			return nil, err
+		}
 		// Get PVC
 		pvc := &core.PersistentVolumeClaim{}
-		err = r.sourceClient.Get(context.TODO(), client.ObjectKey{Namespace: vmRef.Namespace, Name: volume.Name}, pvc)
+		err = r.sourceClient.Get(context.TODO(), client.ObjectKey{Namespace: vmRef.Namespace, Name: claim}, pvc)
 		if err != nil {
 			return nil, liberr.Wrap(err)
 		}
@@ -102,8 +109,13 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 		if url == "" {
 			return nil, liberr.Wrap(fmt.Errorf("failed to get export URL, available formats: %v", volume.Formats))
 		}
-		storageClassName := storageMap[*pvc.Spec.StorageClassName].StorageClass
-		dataVolume.Spec = *createDataVolumeSpec(size, storageClassName, url, configMap.Name, secret.Name)
+		destination := planbase.DestinationDisk(
+			r.Plan,
+			vmRef,
+			pvcSourceName(pvc.Namespace, pvc.Name),
+			v1beta1.DestinationStorage{StorageClass: storageMap[*pvc.Spec.StorageClassName].StorageClass},
+			size.Value())
+		dataVolume.Spec = *createDataVolumeSpec(destination, url, configMap.Name, secret.Name)
 
 		err = r.Destination.Client.Create(context.TODO(), dataVolume, &client.CreateOptions{})
 		if err != nil {
@@ -129,6 +141,47 @@ func getExportURL(virtualMachineExportVolumeFormat []export.VirtualMachineExport
 	return ""
 }
 
+// Find the source PVC of an exported volume.
+// The volumes of a VM export are the PVCs of the VM. The volumes of a
+// snapshot export are restored into PVCs named after the export and
+// the VM volume or the PVC they are restored from.
+func exportedClaim(sourceClient client.Client, vmRef ref.Ref, vmExport *export.VirtualMachineExport, volume string) (claim string, err error) {
+	if vmExport.Spec.Source.Kind != snapshotKind {
+		claim = volume
+		return
+	}
+	vm := &cnv.VirtualMachine{}
+	err = sourceClient.Get(context.TODO(), client.ObjectKey{Namespace: vmRef.Namespace, Name: vmRef.Name}, vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, vol := range vm.Spec.Template.Spec.Volumes {
+		var name string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			name = vol.PersistentVolumeClaim.ClaimName
+		case vol.DataVolume != nil:
+			name = vol.DataVolume.Name
+		default:
+			continue
+		}
+		for _, restored := range []string{vol.Name, name} {
+			if volume == fmt.Sprintf("%s-%s", vmExport.Name, restored) {
+				claim = name
+				return
+			}
+		}
+	}
+	err = liberr.New(
+		"source PVC of the exported volume not found.",
+		"vm",
+		vmRef.String(),
+		"volume",
+		volume)
+	return
+}
+
 // PodEnvironment implements base.Builder
 func (*Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env []core.EnvVar, err error) {
 	return nil, nil
@@ -251,14 +304,170 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return liberr.Wrap(err)
 	}
 
+	// Only the template is carried over, the volumes of the source
+	// DataVolumeTemplates are attached as the imported PVCs.
 	targetVmSpec := sourceVm.Spec.DeepCopy()
 	object.Template = targetVmSpec.Template
+	err = r.mapInstancetype(vmRef, sourceVm, object)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	r.mapDisks(sourceVm, targetVmSpec, persistentVolumeClaims, vmRef)
+	err = r.mapCloudInitToTarget(sourceVm, targetVmSpec, vmRef)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
 	r.mapNetworks(sourceVm, targetVmSpec)
 
 	return nil
 }
 
+// Carry over the instance type and preference of the source VM.
+// The referenced objects are copied to the destination cluster, the
+// namespaced ones to the target namespace.
+func (r *Builder) mapInstancetype(vmRef ref.Ref, sourceVm *cnv.VirtualMachine, object *cnv.VirtualMachineSpec) (err error) {
+	if matcher := sourceVm.Spec.Instancetype; matcher != nil && matcher.Name != "" {
+		var instancetype client.Object
+		key := client.ObjectKey{Name: matcher.Name}
+		if isNamespacedKind(matcher.Kind, instancetypeapi.SingularResourceName) {
+			instancetype = &instancetypev1.VirtualMachineInstancetype{}
+			key.Namespace = vmRef.Namespace
+		} else {
+			instancetype = &instancetypev1.VirtualMachineClusterInstancetype{}
+		}
+		err = r.copyToTarget(key, instancetype)
+		if err != nil {
+			return
+		}
+		// The revision and the volume to infer from are specific to
+		// the source cluster.
+		object.Instancetype = &cnv.InstancetypeMatcher{
+			Name: matcher.Name,
+			Kind: matcher.Kind,
+		}
+	}
+	if matcher := sourceVm.Spec.Preference; matcher != nil && matcher.Name != "" {
+		var preference client.Object
+		key := client.ObjectKey{Name: matcher.Name}
+		if isNamespacedKind(matcher.Kind, instancetypeapi.SingularPreferenceResourceName) {
+			preference = &instancetypev1.VirtualMachinePreference{}
+			key.Namespace = vmRef.Namespace
+		} else {
+			preference = &instancetypev1.VirtualMachineClusterPreference{}
+		}
+		err = r.copyToTarget(key, preference)
+		if err != nil {
+			return
+		}
+		object.Preference = &cnv.PreferenceMatcher{
+			Name: matcher.Name,
+			Kind: matcher.Kind,
+		}
+	}
+	return
+}
+
+// Copy a source object to the destination cluster.
+// Namespaced objects are copied to the target namespace, existing
+// objects are left untouched.
+func (r *Builder) copyToTarget(key client.ObjectKey, object client.Object) (err error) {
+	err = r.sourceClient.Get(context.TODO(), key, object)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Source object not found.",
+			"object",
+			key.String())
+		return
+	}
+	object.SetResourceVersion("")
+	object.SetUID("")
+	object.SetGeneration(0)
+	object.SetCreationTimestamp(metav1.Time{})
+	object.SetManagedFields(nil)
+	object.SetOwnerReferences(nil)
+	if key.Namespace != "" {
+		object.SetNamespace(r.Plan.Spec.TargetNamespace)
+	}
+	err = r.Destination.Client.Create(context.TODO(), object)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Copied object to the destination cluster.",
+		"object",
+		key.String())
+	return
+}
+
+// Carry over the cloud-init and sysprep volumes along with the
+// secrets and config maps holding the user data.
+func (r *Builder) mapCloudInitToTarget(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.VirtualMachineSpec, vmRef ref.Ref) (err error) {
+	disks := make(map[string]*cnv.Disk)
+	for i := range sourceVm.Spec.Template.Spec.Domain.Devices.Disks {
+		disk := &sourceVm.Spec.Template.Spec.Domain.Devices.Disks[i]
+		disks[disk.Name] = disk
+	}
+	for _, vol := range sourceVm.Spec.Template.Spec.Volumes {
+		var secrets, configMaps []*core.LocalObjectReference
+		switch {
+		case vol.CloudInitNoCloud != nil:
+			secrets = append(
+				secrets,
+				vol.CloudInitNoCloud.UserDataSecretRef,
+				vol.CloudInitNoCloud.NetworkDataSecretRef)
+		case vol.CloudInitConfigDrive != nil:
+			secrets = append(
+				secrets,
+				vol.CloudInitConfigDrive.UserDataSecretRef,
+				vol.CloudInitConfigDrive.NetworkDataSecretRef)
+		case vol.Sysprep != nil:
+			secrets = append(secrets, vol.Sysprep.Secret)
+			configMaps = append(configMaps, vol.Sysprep.ConfigMap)
+		default:
+			continue
+		}
+		for _, secret := range secrets {
+			if secret == nil {
+				continue
+			}
+			err = r.copyToTarget(
+				client.ObjectKey{Namespace: vmRef.Namespace, Name: secret.Name},
+				&core.Secret{})
+			if err != nil {
+				return
+			}
+		}
+		for _, configMap := range configMaps {
+			if configMap == nil {
+				continue
+			}
+			err = r.copyToTarget(
+				client.ObjectKey{Namespace: vmRef.Namespace, Name: configMap.Name},
+				&core.ConfigMap{})
+			if err != nil {
+				return
+			}
+		}
+		targetVmSpec.Template.Spec.Volumes = append(targetVmSpec.Template.Spec.Volumes, *vol.DeepCopy())
+		if disk, found := disks[vol.Name]; found {
+			targetVmSpec.Template.Spec.Domain.Devices.Disks = append(targetVmSpec.Template.Spec.Domain.Devices.Disks, *disk.DeepCopy())
+		}
+	}
+	return
+}
+
+// Determine whether an instance type or preference kind is the
+// namespaced one. The cluster-scoped kinds are the default.
+func isNamespacedKind(kind, namespaced string) bool {
+	return strings.EqualFold(kind, namespaced)
+}
+
 func (r *Builder) mapDisks(sourceVm *cnv.VirtualMachine, targetVmSpec *cnv.VirtualMachineSpec, persistentVolumeClaims []core.PersistentVolumeClaim, vmRef ref.Ref) {
 	pvcMap := make(map[string]*core.PersistentVolumeClaim)
 	for i := range persistentVolumeClaims {
@@ -578,24 +787,16 @@ func (r *Builder) getSourceVmFromDefinition(vme *export.VirtualMachineExport) (*
 	return nil, liberr.New("failed to find vm in manifest")
 }
 
-func createDataVolumeSpec(size resource.Quantity, storageClassName, url, configMap, secret string) *cdi.DataVolumeSpec {
-	return &cdi.DataVolumeSpec{
-		Source: &cdi.DataVolumeSource{
+func createDataVolumeSpec(destination planbase.Disk, url, configMap, secret string) *cdi.DataVolumeSpec {
+	spec := destination.DataVolumeSpec(
+		&cdi.DataVolumeSource{
 			HTTP: &cdi.DataVolumeSourceHTTP{
 				URL:                url,
 				CertConfigMap:      configMap,
 				SecretExtraHeaders: []string{secret},
 			},
-		},
-		Storage: &cdi.StorageSpec{
-			Resources: core.ResourceRequirements{
-				Requests: core.ResourceList{
-					core.ResourceStorage: size,
-				},
-			},
-			StorageClassName: &storageClassName,
-		},
-	}
+		})
+	return &spec
 }
 
 func pvcSourceName(namespace, name string) string {
@@ -606,6 +807,16 @@ func (r *Builder) SupportsVolumePopulators() bool {
 	return false
 }
 
+// Delta sync is not supported.
+func (r *Builder) SupportsDeltaSync(vmRef ref.Ref) (supported bool, err error) {
+	return
+}
+
+// Verification is not supported.
+func (r *Builder) SourceSpec(vmRef ref.Ref) (spec *planbase.VMSpec, err error) {
+	return
+}
+
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
 	err = planbase.VolumePopulatorNotSupportedError
 	return
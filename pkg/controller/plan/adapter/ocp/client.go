@@ -2,30 +2,78 @@ package ocp
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strconv"
 	"time"
 
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	"github.com/konveyor/forklift-controller/pkg/settings"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	cnv "kubevirt.io/api/core/v1"
 	export "kubevirt.io/api/export/v1alpha1"
+	snapshotapi "kubevirt.io/api/snapshot/v1alpha1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// Warm migration.
+const (
+	// Kind of the snapshot export source.
+	snapshotKind = "VirtualMachineSnapshot"
+	// Annotation of the transfer network.
+	AnnDefaultNetwork = "v1.multus-cni.io/default-network"
+	// Delta transfer pod labels.
+	kApp       = "forklift.app"
+	kVM        = "vmID"
+	kMigration = "migration"
+	kPrecopy   = "precopy"
+	deltaApp   = "ocp-sync"
+	// Imported volume paths.
+	deltaVolumeName = "target"
+	deltaMountPath  = "/mnt/"
+	deltaDevicePath = "/dev/block"
+	// The qemu group.
+	qemuGroup = 107
+)
+
 type Client struct {
 	*plancontext.Context
 	sourceClient k8sclient.Client
 }
 
 // CheckSnapshotReady implements base.Client
-func (r *Client) CheckSnapshotReady(vmRef ref.Ref, snapshot string) (bool, error) {
-	return false, nil
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, snapshot string) (ready bool, err error) {
+	vmSnapshot := &snapshotapi.VirtualMachineSnapshot{}
+	err = r.sourceClient.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: snapshot},
+		vmSnapshot)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	status := vmSnapshot.Status
+	if status != nil && status.Phase == snapshotapi.Failed {
+		reason := ""
+		if status.Error != nil && status.Error.Message != nil {
+			reason = *status.Error.Message
+		}
+		err = liberr.New("snapshot creation failed.", "snapshot", snapshot, "reason", reason)
+		return
+	}
+	ready = status != nil && status.ReadyToUse != nil && *status.ReadyToUse
+	if !ready {
+		r.Log.Info("Waiting for the snapshot to be ready.", "vm", vmRef.String(), "snapshot", snapshot)
+	}
+	return
 }
 
 // Close implements base.Client
@@ -34,11 +82,67 @@ func (r *Client) Close() {
 }
 
 // CreateSnapshot implements base.Client
-func (r *Client) CreateSnapshot(vmRef ref.Ref) (string, error) {
-	return "", nil
+// The VirtualMachineSnapshot is named after the VM and the number of
+// the precopy it belongs to. A snapshot left by another migration is
+// not reused.
+func (r *Client) CreateSnapshot(vmRef ref.Ref) (snapshot string, err error) {
+	precopies, err := r.precopies(vmRef)
+	if err != nil {
+		return
+	}
+	snapshot = snapshotName(vmRef, len(precopies)+1)
+	migration := string(r.Migration.UID)
+	vmSnapshot := &snapshotapi.VirtualMachineSnapshot{}
+	err = r.sourceClient.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: snapshot},
+		vmSnapshot)
+	if err == nil {
+		if vmSnapshot.Labels[kMigration] != migration {
+			err = liberr.New(
+				"a snapshot of another migration exists.",
+				"vm",
+				vmRef.String(),
+				"snapshot",
+				snapshot)
+		}
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	apiGroup := cnv.GroupVersion.Group
+	vmSnapshot = &snapshotapi.VirtualMachineSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshot,
+			Namespace: vmRef.Namespace,
+			Labels: map[string]string{
+				kMigration: migration,
+			},
+		},
+		Spec: snapshotapi.VirtualMachineSnapshotSpec{
+			Source: core.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     "VirtualMachine",
+				Name:     vmRef.Name,
+			},
+		},
+	}
+	err = r.sourceClient.Create(context.TODO(), vmSnapshot)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"snapshot creation failed.",
+			"vm",
+			vmRef.String())
+	}
+	return
 }
 
 // Finalize implements base.Client
+// The snapshot of the initial precopy of warm migrations backs the
+// VM export and is removed with it.
 func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
 	for _, vm := range vms {
 		vmExport := &export.VirtualMachineExport{ObjectMeta: metav1.ObjectMeta{
@@ -49,8 +153,15 @@ func (r *Client) Finalize(vms []*planapi.VMStatus, planName string) {
 		err := r.sourceClient.Delete(context.TODO(), vmExport)
 		if err != nil {
 			r.Log.Info("Failed to delete VMExport", "VMExport", vmExport, "Error", err)
+		}
+		if vm.Warm == nil || len(vm.Warm.Precopies) == 0 {
 			continue
 		}
+		snapshot := vm.Warm.Precopies[0].Snapshot
+		err = r.removeSnapshot(vm.Namespace, snapshot)
+		if err != nil {
+			r.Log.Info("Failed to delete VMSnapshot", "VMSnapshot", snapshot, "Error", err)
+		}
 	}
 }
 
@@ -123,11 +234,33 @@ func (r *Client) PoweredOff(vmRef ref.Ref) (bool, error) {
 }
 
 // RemoveSnapshots implements base.Client
-func (r *Client) RemoveSnapshots(vmRef ref.Ref, precopies []planapi.Precopy) error {
-	return nil
+// The snapshots of the precopies following the initial one are removed
+// with their exports and the delta transfer pods. The snapshot of the
+// initial precopy is removed by Finalize().
+func (r *Client) RemoveSnapshots(vmRef ref.Ref, precopies []planapi.Precopy) (err error) {
+	err = r.removeDeltaPods(vmRef)
+	if err != nil {
+		return
+	}
+	for i, precopy := range precopies {
+		if i == 0 {
+			continue
+		}
+		err = r.removeExport(vmRef.Namespace, precopy.Snapshot)
+		if err != nil {
+			return
+		}
+		err = r.removeSnapshot(vmRef.Namespace, precopy.Snapshot)
+		if err != nil {
+			return
+		}
+	}
+	return
 }
 
 // SetCheckpoints implements base.Client
+// The HTTP imports do not support checkpoints, the precopies
+// following the initial one are transferred by PreTransferActions().
 func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool) (err error) {
 	return nil
 }
@@ -138,17 +271,38 @@ func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
 }
 
 // PreTransferActions implements base.Builder
+// The VM is exported for the builder to read the volumes and the VM
+// definition from. On warm migrations the snapshots of the precopies
+// are exported instead, see precopyTransfer().
 func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	if r.Plan.Spec.Warm {
+		ready, err = r.precopyTransfer(vmRef)
+		return
+	}
 	apiGroup := cnv.GroupVersion.Group
+	_, ready, err = r.ensureExport(
+		vmRef,
+		vmRef.Name,
+		core.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     "VirtualMachine",
+			Name:     vmRef.Name,
+		})
+	return
+}
 
+// Create the VM export unless it exists.
+// Returns ready=true once the export is ready.
+func (r *Client) ensureExport(vmRef ref.Ref, name string, source core.TypedLocalObjectReference) (vmExport *export.VirtualMachineExport, ready bool, err error) {
 	// Check if VM export exists
-	vmExport := &export.VirtualMachineExport{}
-	err = r.sourceClient.Get(context.Background(), k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: vmRef.Name}, vmExport)
+	vmExport = &export.VirtualMachineExport{}
+	err = r.sourceClient.Get(context.Background(), k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: name}, vmExport)
 
 	if err != nil {
 		if !k8serr.IsNotFound(err) {
-			r.Log.Error(err, "Failed to get VM-export.", "vm", vmRef.Name)
-			return true, liberr.Wrap(err)
+			r.Log.Error(err, "Failed to get VM-export.", "vm", vmRef.Name, "export", name)
+			err = liberr.Wrap(err)
+			return
 		}
 
 		var tokenTTLDuration *metav1.Duration
@@ -163,29 +317,448 @@ func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
 				APIVersion: "kubevirt.io/v1alpha3",
 			},
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      vmRef.Name,
+				Name:      name,
 				Namespace: vmRef.Namespace,
 			},
 			Spec: export.VirtualMachineExportSpec{
 				TTLDuration: tokenTTLDuration,
-				Source: core.TypedLocalObjectReference{
-					APIGroup: &apiGroup,
-					Kind:     "VirtualMachine",
-					Name:     vmRef.Name,
-				},
+				Source:      source,
 			},
 		}
 
 		err = r.sourceClient.Create(context.Background(), vmExport, &k8sclient.CreateOptions{})
 		if err != nil {
-			return true, liberr.Wrap(err)
+			err = liberr.Wrap(err)
+			return
 		}
 	}
 	if vmExport.Status != nil && vmExport.Status.Phase == export.Ready {
-		r.Log.Info("VM-export is ready.", "vm", vmRef.Name)
-		return true, nil
+		r.Log.Info("VM-export is ready.", "vm", vmRef.Name, "export", name)
+		ready = true
+		return
+	}
+
+	r.Log.Info("Waiting for VM-export to be ready...", "vm", vmRef.Name, "export", name)
+	return
+}
+
+// Transfer the snapshot of the current precopy.
+// The snapshot of the initial precopy is exported in place of the VM
+// and imported by the DataVolumes. The snapshots of the following
+// precopies are exported separately and a pod per volume writes the
+// changed blocks onto the imported volumes. The export of the initial
+// snapshot is kept until the VM is created from the definition it
+// provides. Not reached while the validator rejects warm plans.
+// Returns ready=true once transferred.
+func (r *Client) precopyTransfer(vmRef ref.Ref) (ready bool, err error) {
+	precopies, err := r.precopies(vmRef)
+	if err != nil {
+		return
+	}
+	n := len(precopies)
+	if n == 0 {
+		return
+	}
+	_, ready, err = r.ensureExport(vmRef, vmRef.Name, snapshotSource(precopies[0].Snapshot))
+	if err != nil || !ready || n == 1 {
+		return
+	}
+	snapshot := precopies[n-1].Snapshot
+	vmExport, ready, err := r.ensureExport(vmRef, snapshot, snapshotSource(snapshot))
+	if err != nil || !ready {
+		return
+	}
+	ready, err = r.deltaTransfer(vmRef, n, vmExport)
+	return
+}
+
+// Write the exported volumes of a precopy snapshot onto the volumes
+// imported by the DataVolumes.
+// Returns done=true once the pods of every volume succeeded.
+func (r *Client) deltaTransfer(vmRef ref.Ref, precopy int, vmExport *export.VirtualMachineExport) (done bool, err error) {
+	if vmExport.Status.Links == nil || vmExport.Status.Links.External == nil {
+		r.Log.Info("Waiting for the VM-export links.", "vm", vmRef.Name, "export", vmExport.Name)
+		return
+	}
+	dataVolumes, err := r.dataVolumes(vmRef)
+	if err != nil {
+		return
+	}
+	done = true
+	for _, volume := range vmExport.Status.Links.External.Volumes {
+		var claim string
+		claim, err = exportedClaim(r.sourceClient, vmRef, vmExport, volume.Name)
+		if err != nil {
+			return
+		}
+		dataVolume, found := dataVolumes[pvcSourceName(vmRef.Namespace, claim)]
+		if !found {
+			err = liberr.New(
+				"DataVolume of the exported volume not found.",
+				"vm",
+				vmRef.String(),
+				"volume",
+				volume.Name)
+			return
+		}
+		var synced bool
+		synced, err = r.deltaSync(vmRef, precopy, vmExport, volume, dataVolume)
+		if err != nil {
+			return
+		}
+		if !synced {
+			done = false
+		}
+	}
+	return
+}
+
+// Synchronize the imported volume with the exported volume.
+// The pod runs the sync tool that reads the exported volume and
+// writes only the blocks that differ. The export server does not
+// provide the changed blocks, so every precopy reads the whole
+// volume from the source cluster. The export token and the CA
+// certificate are passed in a secret named after the pod.
+// Returns done=true once the pod succeeded.
+func (r *Client) deltaSync(vmRef ref.Ref, precopy int, vmExport *export.VirtualMachineExport, volume export.VirtualMachineExportVolume, dataVolume *cdi.DataVolume) (done bool, err error) {
+	namespace := r.Plan.Spec.TargetNamespace
+	name := fmt.Sprintf("%s-precopy-%d", dataVolume.Name, precopy)
+	pod := &core.Pod{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: namespace, Name: name},
+		pod)
+	if err == nil {
+		switch pod.Status.Phase {
+		case core.PodSucceeded:
+			done = true
+		case core.PodFailed:
+			err = liberr.New("the delta transfer failed.", "pod", name)
+		}
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	url, compressed := getDeltaURL(volume.Formats)
+	if url == "" {
+		err = liberr.New(
+			"failed to get the export URL.",
+			"export",
+			vmExport.Name,
+			"formats",
+			volume.Formats)
+		return
+	}
+	pvc := &core.PersistentVolumeClaim{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: namespace, Name: dataVolume.Name},
+		pvc)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	podLabels := r.deltaLabels(vmRef)
+	podLabels[kPrecopy] = strconv.Itoa(precopy)
+	secret, err := r.deltaSecret(vmRef, name, vmExport, podLabels)
+	if err != nil {
+		return
+	}
+	pod = r.deltaPod(name, url, compressed, secret, pvc, podLabels)
+	err = r.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info("Created the delta transfer pod.", "vm", vmRef.String(), "pod", pod.Name, "pvc", pvc.Name)
+	return
+}
+
+// Create the secret holding the export token and the CA certificate
+// of the delta transfer pod.
+func (r *Client) deltaSecret(vmRef ref.Ref, name string, vmExport *export.VirtualMachineExport, podLabels map[string]string) (secret *core.Secret, err error) {
+	if vmExport.Status.TokenSecretRef == nil {
+		err = liberr.New("token secret ref is nil.", "export", vmExport.Name)
+		return
 	}
+	tokenSecret := &core.Secret{}
+	err = r.sourceClient.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: *vmExport.Status.TokenSecretRef},
+		tokenSecret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	secret = &core.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: r.Plan.Spec.TargetNamespace,
+			Labels:    podLabels,
+		},
+		Data: map[string][]byte{
+			"token":  tokenSecret.Data["token"],
+			"ca.pem": []byte(vmExport.Status.Links.External.Cert),
+		},
+	}
+	err = r.Destination.Client.Create(context.TODO(), secret)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Build the delta transfer pod.
+func (r *Client) deltaPod(name, url string, compressed bool, secret *core.Secret, pvc *core.PersistentVolumeClaim, podLabels map[string]string) (pod *core.Pod) {
+	nonRoot := true
+	allowPrivilegeEscalation := false
+	user := int64(qemuGroup)
+	args := []string{
+		"--url=" + url,
+	}
+	if compressed {
+		args = append(args, "--gzip")
+	}
+	container := core.Container{
+		Name:  "sync",
+		Image: settings.Settings.Migration.OcpSyncImage,
+		SecurityContext: &core.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			RunAsNonRoot:             &nonRoot,
+			RunAsUser:                &user,
+			Capabilities: &core.Capabilities{
+				Drop: []core.Capability{"ALL"},
+			},
+		},
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      "secret-volume",
+				ReadOnly:  true,
+				MountPath: "/etc/secret-volume",
+			},
+		},
+	}
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == core.PersistentVolumeBlock {
+		args = append(args, "--volume-path="+deltaDevicePath)
+		container.VolumeDevices = []core.VolumeDevice{
+			{
+				Name:       deltaVolumeName,
+				DevicePath: deltaDevicePath,
+			},
+		}
+	} else {
+		args = append(args, "--volume-path="+deltaMountPath+"disk.img")
+		container.VolumeMounts = append(
+			container.VolumeMounts,
+			core.VolumeMount{
+				Name:      deltaVolumeName,
+				MountPath: deltaMountPath,
+			})
+	}
+	container.Args = args
+	annotations := map[string]string{}
+	if network := r.Plan.Spec.TransferNetwork; network != nil {
+		annotations[AnnDefaultNetwork] = path.Join(network.Namespace, network.Name)
+	}
+	pod = &core.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   pvc.Namespace,
+			Annotations: annotations,
+			Labels:      podLabels,
+		},
+		Spec: core.PodSpec{
+			Containers: []core.Container{container},
+			SecurityContext: &core.PodSecurityContext{
+				FSGroup: &user,
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+			RestartPolicy: core.RestartPolicyNever,
+			Volumes: []core.Volume{
+				{
+					Name: deltaVolumeName,
+					VolumeSource: core.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				},
+				{
+					Name: "secret-volume",
+					VolumeSource: core.VolumeSource{
+						Secret: &core.SecretVolumeSource{
+							SecretName: secret.Name,
+						},
+					},
+				},
+			},
+		},
+	}
+	return
+}
+
+// Remove the delta transfer pods and their secrets.
+func (r *Client) removeDeltaPods(vmRef ref.Ref) (err error) {
+	options := &k8sclient.ListOptions{
+		Namespace:     r.Plan.Spec.TargetNamespace,
+		LabelSelector: labels.SelectorFromSet(r.deltaLabels(vmRef)),
+	}
+	pods := &core.PodList{}
+	err = r.Destination.Client.List(context.TODO(), pods, options)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range pods.Items {
+		err = r.Destination.Client.Delete(context.TODO(), &pods.Items[i])
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	secrets := &core.SecretList{}
+	err = r.Destination.Client.List(context.TODO(), secrets, options)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range secrets.Items {
+		err = r.Destination.Client.Delete(context.TODO(), &secrets.Items[i])
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+	err = nil
+	return
+}
 
-	r.Log.Info("Waiting for VM-export to be ready...", "vm", vmRef.Name)
-	return false, nil
+// Labels of the delta transfer pods and secrets.
+func (r *Client) deltaLabels(vmRef ref.Ref) map[string]string {
+	return map[string]string{
+		kApp: deltaApp,
+		kVM:  vmRef.ID,
+	}
+}
+
+// The DataVolumes of the VM keyed by the source PVC.
+func (r *Client) dataVolumes(vmRef ref.Ref) (dataVolumes map[string]*cdi.DataVolume, err error) {
+	list := &cdi.DataVolumeList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&k8sclient.ListOptions{
+			Namespace: r.Plan.Spec.TargetNamespace,
+			LabelSelector: labels.SelectorFromSet(map[string]string{
+				kVM:        vmRef.ID,
+				kMigration: string(r.Migration.UID),
+			}),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	dataVolumes = make(map[string]*cdi.DataVolume)
+	for i := range list.Items {
+		dataVolume := &list.Items[i]
+		dataVolumes[dataVolume.Annotations[planbase.AnnDiskSource]] = dataVolume
+	}
+	return
+}
+
+// Remove the VM export.
+func (r *Client) removeExport(namespace, name string) (err error) {
+	vmExport := &export.VirtualMachineExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	err = r.sourceClient.Delete(context.TODO(), vmExport)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Remove the VM snapshot.
+func (r *Client) removeSnapshot(namespace, name string) (err error) {
+	vmSnapshot := &snapshotapi.VirtualMachineSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	err = r.sourceClient.Delete(context.TODO(), vmSnapshot)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// The warm migration precopies of the VM.
+func (r *Client) precopies(vmRef ref.Ref) (precopies []planapi.Precopy, err error) {
+	vmStatus, found := r.Plan.Status.Migration.FindVM(vmRef)
+	if !found {
+		err = liberr.New("VM not found in the plan status.", "vm", vmRef.String())
+		return
+	}
+	if vmStatus.Warm != nil {
+		precopies = vmStatus.Warm.Precopies
+	}
+	return
+}
+
+// The export source of a VM snapshot.
+func snapshotSource(snapshot string) core.TypedLocalObjectReference {
+	apiGroup := snapshotapi.SchemeGroupVersion.Group
+	return core.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     snapshotKind,
+		Name:     snapshot,
+	}
+}
+
+// The name of the snapshot of a precopy.
+func snapshotName(vmRef ref.Ref, precopy int) string {
+	return fmt.Sprintf("%s-precopy-%d", vmRef.Name, precopy)
+}
+
+// The URL of the exported volume the delta transfer pod reads.
+// The raw format is preferred over the compressed one.
+func getDeltaURL(formats []export.VirtualMachineExportVolumeFormat) (url string, compressed bool) {
+	for _, format := range formats {
+		if format.Format == export.KubeVirtRaw {
+			url = format.Url
+			return
+		}
+	}
+	for _, format := range formats {
+		if format.Format == export.KubeVirtGz {
+			url = format.Url
+			compressed = true
+			return
+		}
+	}
+	return
 }
@@ -3,6 +3,7 @@ package ocp
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -10,9 +11,17 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
 
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	schedulinghelper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
 	cnv "kubevirt.io/api/core/v1"
+	instancetypeapi "kubevirt.io/api/instancetype"
+	instancetypev1 "kubevirt.io/api/instancetype/v1alpha2"
 	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -22,6 +31,16 @@ type Validator struct {
 	inventory    web.Client
 	sourceClient k8sclient.Client
 	log          logging.LevelLogger
+	// Target cluster facts.
+	target *target
+}
+
+// Target cluster facts shared by the VMs of a plan.
+type target struct {
+	// Schedulable nodes.
+	nodes []*core.Node
+	// Storage classes by name.
+	storageClasses map[string]bool
 }
 
 // MaintenanceMode implements base.Validator
@@ -59,6 +78,9 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 }
 
 // WarmMigration implements base.Validator
+// The VM export only serves the whole volumes, each precopy (including
+// the one after the cutover) would read them end to end so the downtime
+// would not be shortened.
 func (r *Validator) WarmMigration() bool {
 	return false
 }
@@ -131,6 +153,51 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return true, nil
 }
 
+// Validate that a VM's disk overrides reference its disks and are supported.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	vm := &cnv.VirtualMachine{}
+	err = r.sourceClient.Get(context.TODO(), k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: vmRef.Name}, vm)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	disks := make(map[string]int64)
+	for _, vol := range vm.Spec.Template.Spec.Volumes {
+		var pvcName string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			pvcName = vol.PersistentVolumeClaim.ClaimName
+		case vol.DataVolume != nil:
+			pvcName = vol.DataVolume.Name
+		default:
+			continue
+		}
+
+		pvc := &core.PersistentVolumeClaim{}
+		err = r.sourceClient.Get(context.TODO(), k8sclient.ObjectKey{
+			Namespace: vmRef.Namespace,
+			Name:      pvcName,
+		}, pvc)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"PVC not found.",
+				"pvc",
+				pvcName)
+			return
+		}
+		size := pvc.Spec.Resources.Requests[core.ResourceStorage]
+		disks[pvcSourceName(pvc.Namespace, pvc.Name)] = size.Value()
+	}
+	ok = planbase.DiskOverridesValid(r.plan, vmRef, disks, true)
+	return
+}
+
 // Validate that a VM's networks have been mapped.
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
@@ -178,3 +245,269 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 
 	return true, nil
 }
+
+// Validate that the target cluster satisfies the VM's requirements.
+// The VM must be schedulable on a target node, the storage classes it
+// requests must exist and the instance type and preference it references
+// must be available to be copied.
+func (r *Validator) TargetCompatible(ctx *plancontext.Context, vmRef ref.Ref) (ok bool, err error) {
+	if ctx == nil || ctx.Destination.Client == nil {
+		ok = true
+		return
+	}
+	vm := &cnv.VirtualMachine{}
+	err = r.sourceClient.Get(context.TODO(), k8sclient.ObjectKey{Namespace: vmRef.Namespace, Name: vmRef.Name}, vm)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	err = r.loadTarget(ctx.Destination.Client)
+	if err != nil {
+		return
+	}
+	ok, err = r.schedulable(vm)
+	if err != nil || !ok {
+		return
+	}
+	ok, err = r.instancetypeAvailable(vm, ctx.Destination.Client)
+	if err != nil || !ok {
+		return
+	}
+	ok, err = r.storageClassesFound(vm, vmRef)
+	return
+}
+
+// Load the target cluster facts shared by the VMs of the plan.
+// The nodes and the storage classes are only listed once.
+func (r *Validator) loadTarget(destinationClient k8sclient.Client) (err error) {
+	if r.target != nil {
+		return
+	}
+	nodeList := &core.NodeList{}
+	err = destinationClient.List(context.TODO(), nodeList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	storageClassList := &storage.StorageClassList{}
+	err = destinationClient.List(context.TODO(), storageClassList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	target := &target{
+		storageClasses: make(map[string]bool),
+	}
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !node.Spec.Unschedulable {
+			target.nodes = append(target.nodes, node)
+		}
+	}
+	for _, storageClass := range storageClassList.Items {
+		target.storageClasses[storageClass.Name] = true
+	}
+	r.target = target
+	return
+}
+
+// Determine whether a target node matches the node selector, the
+// required node affinity and the tolerations of the VM.
+func (r *Validator) schedulable(vm *cnv.VirtualMachine) (ok bool, err error) {
+	if vm.Spec.Template == nil {
+		ok = true
+		return
+	}
+	spec := vm.Spec.Template.Spec
+	affinity := nodeaffinity.GetRequiredNodeAffinity(
+		&core.Pod{
+			Spec: core.PodSpec{
+				NodeSelector: spec.NodeSelector,
+				Affinity:     spec.Affinity,
+			},
+		})
+	for _, node := range r.target.nodes {
+		matched, mErr := affinity.Match(node)
+		if mErr != nil {
+			err = liberr.Wrap(mErr)
+			return
+		}
+		if !matched {
+			continue
+		}
+		_, untolerated := schedulinghelper.FindMatchingUntoleratedTaint(
+			node.Spec.Taints,
+			spec.Tolerations,
+			func(taint *core.Taint) bool {
+				return taint.Effect == core.TaintEffectNoSchedule ||
+					taint.Effect == core.TaintEffectNoExecute
+			})
+		if !untolerated {
+			ok = true
+			return
+		}
+	}
+	r.log.Info(
+		"No target node matches the VM scheduling constraints.",
+		"vm",
+		path.Join(vm.Namespace, vm.Name),
+		"nodeSelector",
+		spec.NodeSelector)
+	return
+}
+
+// Determine whether the instance type and preference referenced by
+// the VM can be found on the source or the target cluster.
+func (r *Validator) instancetypeAvailable(vm *cnv.VirtualMachine, destinationClient k8sclient.Client) (ok bool, err error) {
+	if matcher := vm.Spec.Instancetype; matcher != nil && matcher.Name != "" {
+		var instancetype k8sclient.Object
+		key := k8sclient.ObjectKey{Name: matcher.Name}
+		if isNamespacedKind(matcher.Kind, instancetypeapi.SingularResourceName) {
+			instancetype = &instancetypev1.VirtualMachineInstancetype{}
+			key.Namespace = vm.Namespace
+		} else {
+			instancetype = &instancetypev1.VirtualMachineClusterInstancetype{}
+		}
+		ok, err = r.found(key, instancetype, destinationClient)
+		if err != nil || !ok {
+			return
+		}
+	}
+	if matcher := vm.Spec.Preference; matcher != nil && matcher.Name != "" {
+		var preference k8sclient.Object
+		key := k8sclient.ObjectKey{Name: matcher.Name}
+		if isNamespacedKind(matcher.Kind, instancetypeapi.SingularPreferenceResourceName) {
+			preference = &instancetypev1.VirtualMachinePreference{}
+			key.Namespace = vm.Namespace
+		} else {
+			preference = &instancetypev1.VirtualMachineClusterPreference{}
+		}
+		ok, err = r.found(key, preference, destinationClient)
+		if err != nil || !ok {
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Determine whether the storage classes of the VM disks and the
+// storage class preferred by the VM preference exist on the target
+// cluster. The disks are migrated to the storage class mapped for the
+// source storage class unless overridden by the plan VM.
+func (r *Validator) storageClassesFound(vm *cnv.VirtualMachine, vmRef ref.Ref) (ok bool, err error) {
+	storageClasses := []string{}
+	if vm.Spec.Template != nil {
+		for _, vol := range vm.Spec.Template.Spec.Volumes {
+			var pvcName string
+			switch {
+			case vol.PersistentVolumeClaim != nil:
+				pvcName = vol.PersistentVolumeClaim.ClaimName
+			case vol.DataVolume != nil:
+				pvcName = vol.DataVolume.Name
+			default:
+				continue
+			}
+			pvc := &core.PersistentVolumeClaim{}
+			err = r.sourceClient.Get(context.TODO(), k8sclient.ObjectKey{
+				Namespace: vm.Namespace,
+				Name:      pvcName,
+			}, pvc)
+			if err != nil {
+				err = liberr.Wrap(
+					err,
+					"PVC not found.",
+					"pvc",
+					pvcName)
+				return
+			}
+			mapped := api.DestinationStorage{}
+			if pvc.Spec.StorageClassName != nil && r.plan.Referenced.Map.Storage != nil {
+				if pair, found := r.plan.Referenced.Map.Storage.FindStorageByName(*pvc.Spec.StorageClassName); found {
+					mapped = pair.Destination
+				}
+			}
+			disk := planbase.DestinationDisk(
+				r.plan,
+				vmRef,
+				pvcSourceName(pvc.Namespace, pvc.Name),
+				mapped,
+				0)
+			if disk.StorageClass != "" {
+				storageClasses = append(storageClasses, disk.StorageClass)
+			}
+		}
+	}
+	if matcher := vm.Spec.Preference; matcher != nil && matcher.Name != "" {
+		var spec *instancetypev1.VirtualMachinePreferenceSpec
+		key := k8sclient.ObjectKey{Name: matcher.Name}
+		if isNamespacedKind(matcher.Kind, instancetypeapi.SingularPreferenceResourceName) {
+			key.Namespace = vm.Namespace
+			preference := &instancetypev1.VirtualMachinePreference{}
+			err = r.sourceClient.Get(context.TODO(), key, preference)
+			spec = &preference.Spec
+		} else {
+			preference := &instancetypev1.VirtualMachineClusterPreference{}
+			err = r.sourceClient.Get(context.TODO(), key, preference)
+			spec = &preference.Spec
+		}
+		if err != nil {
+			if !k8serr.IsNotFound(err) {
+				err = liberr.Wrap(err)
+				return
+			}
+			err = nil
+		} else if spec.Volumes != nil && spec.Volumes.PreferredStorageClassName != "" {
+			storageClasses = append(storageClasses, spec.Volumes.PreferredStorageClassName)
+		}
+	}
+	for _, name := range storageClasses {
+		if !r.target.storageClasses[name] {
+			r.log.Info(
+				"Storage class not found on the target cluster.",
+				"vm",
+				path.Join(vm.Namespace, vm.Name),
+				"storageClass",
+				name)
+			return
+		}
+	}
+	ok = true
+	return
+}
+
+// Determine whether an object exists on the source cluster or,
+// for cluster-scoped objects, on the target cluster.
+func (r *Validator) found(key k8sclient.ObjectKey, object k8sclient.Object, destinationClient k8sclient.Client) (ok bool, err error) {
+	err = r.sourceClient.Get(context.TODO(), key, object)
+	if err == nil {
+		ok = true
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = nil
+	if key.Namespace == "" {
+		err = destinationClient.Get(context.TODO(), key, object)
+		if err == nil {
+			ok = true
+			return
+		}
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+	}
+	r.log.Info(
+		"Object referenced by the VM not found.",
+		"object",
+		key.String())
+	return
+}
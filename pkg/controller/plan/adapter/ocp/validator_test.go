@@ -0,0 +1,115 @@
+package ocp
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	storage "k8s.io/api/storage/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestTargetCompatible(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = storage.AddToScheme(scheme)
+	_ = cnv.AddToScheme(scheme)
+
+	vm := &cnv.VirtualMachine{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"},
+		Spec: cnv.VirtualMachineSpec{
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					NodeSelector: map[string]string{"zone": "a"},
+					Volumes: []cnv.Volume{
+						{
+							Name: "disk",
+							VolumeSource: cnv.VolumeSource{
+								PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+									PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+										ClaimName: "disk",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	sourceClass := "source"
+	pvc := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "disk"},
+		Spec:       core.PersistentVolumeClaimSpec{StorageClassName: &sourceClass},
+	}
+	node := &core.Node{
+		ObjectMeta: meta.ObjectMeta{Name: "node", Labels: map[string]string{"zone": "a"}},
+	}
+	mapped := &storage.StorageClass{ObjectMeta: meta.ObjectMeta{Name: "mapped"}}
+
+	plan := &api.Plan{}
+	plan.Spec.VMs = []planapi.VM{{Ref: ref.Ref{ID: "vm-1"}}}
+	plan.Referenced.Map.Storage = &api.StorageMap{}
+	plan.Referenced.Map.Storage.Spec.Map = []api.StoragePair{
+		{Source: ref.Ref{Name: sourceClass}, Destination: api.DestinationStorage{StorageClass: "mapped"}},
+		// Not used by the VM disks.
+		{Source: ref.Ref{Name: "other"}, Destination: api.DestinationStorage{StorageClass: "missing"}},
+	}
+	vmRef := ref.Ref{ID: "vm-1", Namespace: "test", Name: "vm"}
+	validator := func() *Validator {
+		return &Validator{
+			plan:         plan,
+			sourceClient: fake.NewClientBuilder().WithScheme(scheme).WithObjects(vm, pvc).Build(),
+			log:          logging.WithName("test"),
+		}
+	}
+	ctx := &plancontext.Context{}
+	ctx.Destination.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, mapped).Build()
+
+	//Test only the storage classes of the VM disks are required
+	ok, err := validator().TargetCompatible(ctx, vmRef)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeTrue())
+
+	//Test the storage class override of a disk
+	plan.Spec.VMs[0].Disks = []planapi.Disk{{ID: "test/disk", StorageClass: "missing"}}
+	ok, err = validator().TargetCompatible(ctx, vmRef)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeFalse())
+	plan.Spec.VMs[0].Disks = nil
+
+	//Test the VM is not schedulable on the target nodes
+	node.Labels["zone"] = "b"
+	ctx.Destination.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, mapped).Build()
+	v := validator()
+	ok, err = v.TargetCompatible(ctx, vmRef)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeFalse())
+
+	//Test the target facts are only loaded once
+	node.Labels["zone"] = "a"
+	ctx.Destination.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(node, mapped).Build()
+	ok, err = v.TargetCompatible(ctx, vmRef)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeFalse())
+
+	//Test the VM is not found on the source cluster
+	ok, err = validator().TargetCompatible(ctx, ref.Ref{ID: "vm-2", Namespace: "test", Name: "vm-2"})
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeFalse())
+
+	//Test without the target cluster
+	ok, err = validator().TargetCompatible(nil, vmRef)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ok).To(gomega.BeTrue())
+}
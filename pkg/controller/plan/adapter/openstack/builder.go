@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	utils "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
@@ -278,7 +280,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, vmSpec *cnv.VirtualMachineSpec,
 	if err != nil {
 		return
 	}
-	if len(conflicts) > 0 {
+	if len(conflicts) > 0 && r.Plan.Spec.PreserveMAC(true) {
 		err = liberr.New(
 			fmt.Sprintf("Source VM has a mac address conflict with one or more destination VMs: %s", conflicts))
 		return
@@ -577,84 +579,56 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 	var kNetworks []cnv.Network
 	var kInterfaces []cnv.Interface
 
-	numNetworks := 0
-	for vmNetworkName, vmAddresses := range vm.Addresses {
-		if nics, ok := vmAddresses.([]interface{}); ok {
-			for _, nic := range nics {
-				networkName := fmt.Sprintf("net-%v", numNetworks)
-				kNetwork := cnv.Network{
-					Name: networkName,
-				}
-				kInterface := cnv.Interface{
-					Name: networkName,
-				}
-				var interfaceModel string
-				vifModel := DefaultProperties[VifModel]
-				if imageVIFModel, ok := vm.Image.Properties[VifModel]; ok {
-					vifModel = imageVIFModel.(string)
-				}
-				switch vifModel {
-				case VifModelVirtualE1000:
-					interfaceModel = VifModelE1000
-				case VifModelVirtualE1000e:
-					interfaceModel = VifModelE1000e
-				case VifModelVirtualPcnet32:
-					interfaceModel = VifModelPcnet
-				case VifModelE1000, VifModelE1000e, VifModelNe2kpci, VifModelPcnet, VifModelRtl8139, VifModelVirtio:
-					interfaceModel = vifModel
-				default:
-					interfaceModel = DefaultProperties[VifModel]
-				}
-				kInterface.Model = interfaceModel
-				if m := nic.(map[string]interface{}); ok {
-					if macAddress, ok := m["OS-EXT-IPS-MAC:mac_addr"]; ok {
-						kInterface.MacAddress = macAddress.(string)
-					}
-					if ipType, ok := m["OS-EXT-IPS:type"]; ok {
-						if ipType.(string) == "floating" {
-							continue
-						}
-					}
-				}
+	var interfaceModel string
+	vifModel := DefaultProperties[VifModel]
+	if imageVIFModel, ok := vm.Image.Properties[VifModel]; ok {
+		vifModel = imageVIFModel.(string)
+	}
+	switch vifModel {
+	case VifModelVirtualE1000:
+		interfaceModel = VifModelE1000
+	case VifModelVirtualE1000e:
+		interfaceModel = VifModelE1000e
+	case VifModelVirtualPcnet32:
+		interfaceModel = VifModelPcnet
+	case VifModelE1000, VifModelE1000e, VifModelNe2kpci, VifModelPcnet, VifModelRtl8139, VifModelVirtio:
+		interfaceModel = vifModel
+	default:
+		interfaceModel = DefaultProperties[VifModel]
+	}
 
-				var vmNetworkID string
-				for _, vmNetwork := range vm.Networks {
-					if vmNetwork.Name == vmNetworkName {
-						vmNetworkID = vmNetwork.ID
-						break
-					}
-				}
-				var networkPair *api.NetworkPair
-				networkMaps := r.Context.Map.Network.Spec.Map
-				found := false
-				for i := range networkMaps {
-					networkPair = &networkMaps[i]
-					if networkPair.Source.ID == vmNetworkID {
-						found = true
-						break
-					}
-				}
-				if !found {
-					err = liberr.New("no network map for vm network", "network", vmNetworkID)
-					return
-				}
-				switch networkPair.Destination.Type {
-				case Pod:
-					kNetwork.Pod = &cnv.PodNetwork{}
-					kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-				case Multus:
-					kNetwork.Multus = &cnv.MultusNetwork{
-						NetworkName: path.Join(
-							networkPair.Destination.Namespace,
-							networkPair.Destination.Name),
-					}
-					kInterface.Bridge = &cnv.InterfaceBridge{}
-				}
-				kNetworks = append(kNetworks, kNetwork)
-				kInterfaces = append(kInterfaces, kInterface)
-				numNetworks++
+	for i, nic := range sourceNICs(r.Context.Map.Network, vm) {
+		destination, found := planbase.DestinationNetwork(r.Plan, ref.Ref{ID: vm.ID}, &nic)
+		if !found {
+			err = liberr.New("no network map for vm nic", "mac", nic.MAC)
+			return
+		}
+		networkName := fmt.Sprintf("net-%v", i)
+		kNetwork := cnv.Network{
+			Name: networkName,
+		}
+		kInterface := cnv.Interface{
+			Name:  networkName,
+			Model: interfaceModel,
+		}
+		_, conflicting := r.macConflictsMap[nic.MAC]
+		if r.Plan.Spec.PreserveMAC(conflicting) {
+			kInterface.MacAddress = nic.MAC
+		}
+		switch destination.Type {
+		case Pod:
+			kNetwork.Pod = &cnv.PodNetwork{}
+			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+		case Multus:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(
+					destination.Namespace,
+					destination.Name),
 			}
+			kInterface.Bridge = &cnv.InterfaceBridge{}
 		}
+		kNetworks = append(kNetworks, kNetwork)
+		kInterfaces = append(kInterfaces, kInterface)
 	}
 
 	object.Template.Spec.Networks = kNetworks
@@ -685,6 +659,66 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 	return
 }
 
+// Build the source NICs of the VM to be mapped.
+// The NICs are found in the addresses of the VM networks, the fixed
+// addresses of a NIC are grouped by MAC address. A NIC is described by
+// the network map pair of its network and its fixed IP addresses.
+func sourceNICs(networkMap *api.NetworkMap, vm *model.Workload) (nics []planbase.NIC) {
+	vmNetworkNames := []string{}
+	for vmNetworkName := range vm.Addresses {
+		vmNetworkNames = append(vmNetworkNames, vmNetworkName)
+	}
+	sort.Strings(vmNetworkNames)
+	for _, vmNetworkName := range vmNetworkNames {
+		var mapped *api.NetworkPair
+		for _, vmNetwork := range vm.Networks {
+			if vmNetwork.Name != vmNetworkName {
+				continue
+			}
+			if networkMap != nil {
+				for i := range networkMap.Spec.Map {
+					pair := &networkMap.Spec.Map[i]
+					if !pair.Selector() && pair.Source.ID == vmNetwork.ID {
+						mapped = pair
+						break
+					}
+				}
+			}
+			break
+		}
+		addresses, ok := vm.Addresses[vmNetworkName].([]interface{})
+		if !ok {
+			continue
+		}
+		index := make(map[string]int)
+		for _, address := range addresses {
+			m, ok := address.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if ipType, ok := m["OS-EXT-IPS:type"].(string); ok && ipType == "floating" {
+				continue
+			}
+			macAddress, _ := m["OS-EXT-IPS-MAC:mac_addr"].(string)
+			i, found := index[macAddress]
+			if !found {
+				i = len(nics)
+				index[macAddress] = i
+				nics = append(
+					nics,
+					planbase.NIC{
+						MAC:    macAddress,
+						Mapped: mapped,
+					})
+			}
+			if ip, ok := m["addr"].(string); ok {
+				nics[i].IPs = append(nics[i].IPs, ip)
+			}
+		}
+	}
+	return
+}
+
 // Build tasks.
 func (r *Builder) Tasks(vmRef ref.Ref) (tasks []*plan.Task, err error) {
 	workload := &model.Workload{}
@@ -870,6 +904,48 @@ func (r *Builder) SupportsVolumePopulators() bool {
 	return true
 }
 
+// Delta sync is not supported.
+func (r *Builder) SupportsDeltaSync(vmRef ref.Ref) (supported bool, err error) {
+	return
+}
+
+// Build the source VM specification verified on the target VM.
+// The size of a disk created from an image is not verified.
+func (r *Builder) SourceSpec(vmRef ref.Ref) (spec *planbase.VMSpec, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	spec = &planbase.VMSpec{
+		CPUs:   vm.Flavor.VCPUs,
+		Memory: int64(vm.Flavor.RAM) * 1024 * 1024,
+	}
+	if vm.ImageID != "" {
+		spec.Disks = append(spec.Disks, 0)
+	}
+	for _, volume := range vm.Volumes {
+		spec.Disks = append(spec.Disks, int64(volume.Size)*1024*1024*1024)
+	}
+	for _, vmAddresses := range vm.Addresses {
+		if nics, ok := vmAddresses.([]interface{}); ok {
+			for _, nic := range nics {
+				if m, ok := nic.(map[string]interface{}); ok {
+					if macAddress, ok := m["OS-EXT-IPS-MAC:mac_addr"].(string); ok {
+						spec.MACs = append(spec.MACs, macAddress)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
 	workload := &model.Workload{}
 	err = r.Source.Inventory.Find(workload, vmRef)
@@ -931,12 +1007,28 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 			}
 		}
 
+		diskID := workload.ImageID
+		if _, ok := image.Properties[forkliftPropertyOriginalVolumeID]; ok {
+			diskID = originalVolumeDiskId
+		}
+		virtualSize := image.VirtualSize
+		// virtual_size may not always be available
+		if virtualSize == 0 {
+			virtualSize = image.SizeBytes
+		}
+		destination := planbase.DestinationDisk(
+			r.Plan,
+			ref.Ref{ID: workload.ID},
+			diskID,
+			api.DestinationStorage{StorageClass: storageClassName},
+			virtualSize)
+
 		var pvc *core.PersistentVolumeClaim
-		pvc, err = r.persistentVolumeClaimWithSourceRef(image, storageClassName, populatorName, annotations)
+		pvc, err = r.persistentVolumeClaimWithSourceRef(image, destination, populatorName, annotations)
 		if err != nil {
 			if !k8serr.IsAlreadyExists(err) {
 				err = liberr.Wrap(err, "couldn't build the PVC",
-					"image", image.Name, "storageClassName", storageClassName, "populatorName", populatorName)
+					"image", image.Name, "storageClassName", destination.StorageClass, "populatorName", populatorName)
 				return
 			}
 			err = nil
@@ -1081,15 +1173,12 @@ func (r *Builder) getVolumePopulator(name string) (populatorCr api.OpenstackVolu
 	return
 }
 
-func (r *Builder) persistentVolumeClaimWithSourceRef(image model.Image, storageClassName string,
+func (r *Builder) persistentVolumeClaimWithSourceRef(image model.Image, destination planbase.Disk,
 	populatorName string, annotations map[string]string) (pvc *core.PersistentVolumeClaim, err error) {
 
 	apiGroup := "forklift.konveyor.io"
-	virtualSize := image.VirtualSize
-	// virtual_size may not always be available
-	if virtualSize == 0 {
-		virtualSize = image.SizeBytes
-	}
+	virtualSize := destination.Size
+	storageClassName := destination.StorageClass
 
 	var accessModes []core.PersistentVolumeAccessMode
 	var volumeMode *core.PersistentVolumeMode
@@ -1098,6 +1187,12 @@ func (r *Builder) persistentVolumeClaimWithSourceRef(image model.Image, storageC
 		err = liberr.Wrap(err)
 		return
 	}
+	if destination.AccessMode != "" {
+		accessModes = []core.PersistentVolumeAccessMode{destination.AccessMode}
+	}
+	if destination.VolumeMode != "" {
+		volumeMode = &destination.VolumeMode
+	}
 
 	if *volumeMode == core.PersistentVolumeFilesystem {
 		virtualSize = utils.CalculateSpaceWithOverhead(virtualSize, 0.1)
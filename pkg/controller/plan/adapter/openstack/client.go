@@ -1,16 +1,25 @@
 package openstack
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	planapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/openstack"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
@@ -24,6 +33,7 @@ const (
 	SnapshotStatusCreating  = libclient.SnapshotStatusCreating
 	SnapshotStatusDeleting  = libclient.SnapshotStatusDeleting
 	SnapshotStatusDeleted   = libclient.SnapshotStatusDeleted
+	SnapshotStatusError     = libclient.SnapshotStatusError
 
 	VolumeStatusAvailable = libclient.VolumeStatusAvailable
 	VolumeStatusInUse     = libclient.VolumeStatusInUse
@@ -32,6 +42,18 @@ const (
 	VolumeStatusUploading = libclient.VolumeStatusUploading
 )
 
+// Delta transfer pod.
+const (
+	// Annotation of the transfer network.
+	AnnDefaultNetwork = "v1.multus-cni.io/default-network"
+	// Populated volume paths.
+	deltaVolumeName = "target"
+	deltaMountPath  = "/mnt/"
+	deltaDevicePath = "/dev/block"
+	// The qemu group.
+	qemuGroup = 107
+)
+
 var ResourceNotFoundError = errors.New("resource not found")
 var NameOrIDRequiredError = errors.New("id or name is required")
 var UnexpectedVolumeStatusError = errors.New("unexpected volume status")
@@ -96,21 +118,146 @@ func (r *Client) PoweredOff(vmRef ref.Ref) (off bool, err error) {
 }
 
 // Create a snapshot of the source VM.
-func (r *Client) CreateSnapshot(vmRef ref.Ref) (imageID string, err error) {
+// Every attached volume is snapshotted, the snapshots are identified
+// by the number of the precopy they belong to. Cinder does not expose
+// the blocks changed between snapshots, every snapshot is exported
+// as the whole volume, see exportPrecopy().
+func (r *Client) CreateSnapshot(vmRef ref.Ref) (snapshot string, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	if _, found := vm.Image["id"]; found {
+		err = liberr.New(
+			"warm migration is not supported for image based VMs.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	precopies, err := r.precopies(vmRef)
+	if err != nil {
+		return
+	}
+	snapshot = strconv.Itoa(len(precopies) + 1)
+	for _, attachedVolume := range vm.AttachedVolumes {
+		_, err = r.getPrecopySnapshot(vm, attachedVolume.ID, snapshot)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, ResourceNotFoundError) {
+			return
+		}
+		opts := &libclient.SnapshotCreateOpts{}
+		opts.Name = getPrecopySnapshotName(r.Context, vm.ID, snapshot)
+		opts.VolumeID = attachedVolume.ID
+		opts.Force = true
+		opts.Metadata = map[string]string{
+			forkliftPropertyOriginalVolumeID: attachedVolume.ID,
+			forkliftPropertyPrecopy:          snapshot,
+		}
+		err = r.Create(&libclient.Snapshot{}, opts)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"snapshot creation failed.",
+				"vm",
+				vmRef.String(),
+				"volumeID",
+				attachedVolume.ID)
+			return
+		}
+		r.Log.Info("creating the precopy snapshot",
+			"vm", vm.Name, "volumeID", attachedVolume.ID, "precopy", snapshot)
+	}
 	return
 }
 
 // Remove all warm migration snapshots.
+// The volumes, the images and the pods of the precopies following
+// the initial one are removed as well. The images of the initial
+// precopy back the populated volumes and are removed by Finalize().
 func (r *Client) RemoveSnapshots(vmRef ref.Ref, precopies []planapi.Precopy) (err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for i, precopy := range precopies {
+		for _, attachedVolume := range vm.AttachedVolumes {
+			if i > 0 {
+				imageName := getDeltaImageName(r.Context, vm.ID, attachedVolume.ID, precopy.Snapshot)
+				err = r.removeDeltaPod(imageName)
+				if err != nil {
+					return
+				}
+				err = r.removeImage(imageName)
+				if err != nil {
+					return
+				}
+			}
+			err = r.removePrecopyVolume(vm, attachedVolume.ID, precopy.Snapshot)
+			if err != nil {
+				return
+			}
+			err = r.removePrecopySnapshot(vm, attachedVolume.ID, precopy.Snapshot)
+			if err != nil {
+				return
+			}
+		}
+	}
 	return
 }
 
 // Check if a snapshot is ready to transfer.
-func (r *Client) CheckSnapshotReady(vmRef ref.Ref, imageID string) (ready bool, err error) {
+func (r *Client) CheckSnapshotReady(vmRef ref.Ref, snapshot string) (ready bool, err error) {
+	vm, err := r.getVM(vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	for _, attachedVolume := range vm.AttachedVolumes {
+		var volumeSnapshot *libclient.Snapshot
+		volumeSnapshot, err = r.getPrecopySnapshot(vm, attachedVolume.ID, snapshot)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		switch volumeSnapshot.Status {
+		case SnapshotStatusAvailable:
+		case SnapshotStatusCreating:
+			r.Log.Info("the precopy snapshot is still being created",
+				"vm", vm.Name, "snapshot", volumeSnapshot.Name, "volumeID", attachedVolume.ID)
+			return
+		default:
+			err = liberr.New(
+				"unexpected snapshot status.",
+				"snapshot",
+				volumeSnapshot.ID,
+				"status",
+				volumeSnapshot.Status)
+			return
+		}
+	}
+	ready = true
 	return
 }
 
 // Set DataVolume checkpoints.
+// The precopies are transferred by the populators and the delta
+// transfer pods, no DataVolume is involved.
 func (r *Client) SetCheckpoints(vmRef ref.Ref, precopies []planapi.Precopy, datavolumes []cdi.DataVolume, final bool) error {
 	return nil
 }
@@ -169,6 +316,9 @@ func (r *Client) DetachDisks(vmRef ref.Ref) (err error) {
 	return
 }
 
+// Create the images of the VM volumes the populators transfer.
+// On warm migrations the images are created from the snapshots of
+// the current precopy instead, see precopyTransfer().
 func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
 	vm, err := r.getVM(vmRef)
 	if err != nil {
@@ -179,6 +329,10 @@ func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
 			vmRef.String())
 		return
 	}
+	if r.Context.Plan.Spec.Warm {
+		ready, err = r.precopyTransfer(vm)
+		return
+	}
 	// VM Snapshot
 	vmSnapshotImage, err := r.getVmSnapshotImage(vm)
 	if err != nil {
@@ -615,26 +769,35 @@ func (r *Client) createImageFromVolume(vm *libclient.VM, volumeID string) (image
 		err = liberr.Wrap(err)
 		return
 	}
-	// Workaround for https://bugs.launchpad.net/cinder/+bug/1945500
+	err = r.unsetGlanceMetadata(vm, volume)
+	if err != nil {
+		return
+	}
+	imageName := getImageFromVolumeName(r.Context, vm.ID, volume.Metadata[forkliftPropertyOriginalVolumeID])
+	image, err = r.UploadImage(imageName, volume.ID)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	return
+}
+
+// Remove the reserved glance metadata the volume inherited
+// so it can be uploaded to an image.
+// Workaround for https://bugs.launchpad.net/cinder/+bug/1945500
+func (r *Client) unsetGlanceMetadata(vm *libclient.VM, volume *libclient.Volume) (err error) {
 	for key := range volume.VolumeImageMetadata {
 		if strings.HasPrefix(key, "os_glance") {
-			err = r.UnsetImageMetadata(volumeID, key)
+			err = r.UnsetImageMetadata(volume.ID, key)
 			if err != nil {
 				err = liberr.Wrap(
 					err,
 					"failed to remove reserved glance metadata from volume.",
-					"vm", vm.Name, "volumeID", volumeID, "key", key)
+					"vm", vm.Name, "volumeID", volume.ID, "key", key)
 				return
 			}
 		}
 	}
-	// end Workaround
-	imageName := getImageFromVolumeName(r.Context, vm.ID, volume.Metadata[forkliftPropertyOriginalVolumeID])
-	image, err = r.UploadImage(imageName, volume.ID)
-	if err != nil {
-		err = liberr.Wrap(err)
-		return
-	}
 	return
 }
 
@@ -922,3 +1085,473 @@ func (r *Client) getImagesFromVolumes(vm *libclient.VM) (images []libclient.Imag
 	}
 	return
 }
+
+// Transfer the snapshots of the current precopy.
+// The initial precopy is exported to the images the populators
+// download. The following ones are exported to images that a pod
+// writes onto the populated volumes. Every precopy uploads and
+// downloads the whole volumes, the pod only skips writing the
+// blocks that did not change. Not reached while the validator
+// rejects warm plans. Returns ready=true once transferred.
+func (r *Client) precopyTransfer(vm *libclient.VM) (ready bool, err error) {
+	precopies, err := r.precopies(ref.Ref{ID: vm.ID})
+	if err != nil {
+		return
+	}
+	n := len(precopies)
+	if n == 0 {
+		return
+	}
+	snapshot := precopies[n-1].Snapshot
+	ready = true
+	for _, attachedVolume := range vm.AttachedVolumes {
+		imageName := getImageFromVolumeName(r.Context, vm.ID, attachedVolume.ID)
+		if n > 1 {
+			imageName = getDeltaImageName(r.Context, vm.ID, attachedVolume.ID, snapshot)
+		}
+		var image *libclient.Image
+		image, err = r.exportPrecopy(vm, attachedVolume.ID, snapshot, imageName)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"snapshot export failed.",
+				"vm",
+				vm.Name,
+				"volumeID",
+				attachedVolume.ID)
+			return
+		}
+		if image == nil {
+			ready = false
+			continue
+		}
+		if n == 1 {
+			// The populator volumes are built from the inventory.
+			inventoryImage := &model.Image{}
+			err = r.Context.Source.Inventory.Find(inventoryImage, ref.Ref{ID: image.ID})
+			if err != nil {
+				if !errors.As(err, &model.NotFoundError{}) {
+					return
+				}
+				err = nil
+				ready = false
+				continue
+			}
+			if _, found := inventoryImage.Properties[forkliftPropertyOriginalVolumeID]; !found {
+				ready = false
+			}
+			continue
+		}
+		var done bool
+		done, err = r.deltaTransfer(vm, attachedVolume.ID, image)
+		if err != nil {
+			return
+		}
+		if !done {
+			ready = false
+		}
+	}
+	return
+}
+
+// Export the precopy snapshot of a volume to an image.
+// The snapshot is cloned to a volume that is uploaded to the image,
+// the volume is removed once the image is active. The image holds
+// the whole volume.
+// Returns the image once active.
+func (r *Client) exportPrecopy(vm *libclient.VM, volumeID, snapshot, imageName string) (image *libclient.Image, err error) {
+	found, err := r.getImage(ref.Ref{Name: imageName})
+	if err == nil {
+		switch found.Status {
+		case ImageStatusActive:
+			if _, set := found.Properties[forkliftPropertyOriginalVolumeID]; !set {
+				opts := &libclient.ImageUpdateOpts{}
+				opts.AddImageProperty(forkliftPropertyOriginalVolumeID, volumeID)
+				err = r.Update(found, opts)
+				if err != nil {
+					err = liberr.Wrap(err)
+					return
+				}
+			}
+			err = r.removePrecopyVolume(vm, volumeID, snapshot)
+			if err != nil {
+				return
+			}
+			image = found
+		case ImageStatusImporting, ImageStatusQueued, ImageStatusUploading, ImageStatusSaving:
+			r.Log.Info("the precopy image is not ready yet",
+				"vm", vm.Name, "image", found.Name, "status", found.Status)
+		default:
+			err = liberr.New(
+				"unexpected image status.",
+				"image",
+				found.Name,
+				"status",
+				found.Status)
+		}
+		return
+	}
+	if !errors.Is(err, ResourceNotFoundError) {
+		err = liberr.Wrap(err)
+		return
+	}
+	volume, err := r.getPrecopyVolume(vm, volumeID, snapshot)
+	if err != nil {
+		if !errors.Is(err, ResourceNotFoundError) {
+			err = liberr.Wrap(err)
+			return
+		}
+		var volumeSnapshot *libclient.Snapshot
+		volumeSnapshot, err = r.getPrecopySnapshot(vm, volumeID, snapshot)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		opts := &libclient.VolumeCreateOpts{}
+		opts.Name = getPrecopyVolumeName(r.Context, vm.ID, snapshot)
+		opts.SnapshotID = volumeSnapshot.ID
+		opts.Metadata = map[string]string{
+			forkliftPropertyOriginalVolumeID: volumeID,
+			forkliftPropertyPrecopy:          snapshot,
+		}
+		err = r.Create(&libclient.Volume{}, opts)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info("creating the volume from the precopy snapshot",
+			"vm", vm.Name, "snapshot", volumeSnapshot.Name, "volumeID", volumeID)
+		return
+	}
+	switch volume.Status {
+	case VolumeStatusAvailable:
+		err = r.unsetGlanceMetadata(vm, volume)
+		if err != nil {
+			return
+		}
+		_, err = r.UploadImage(imageName, volume.ID)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info("creating the image from the precopy volume",
+			"vm", vm.Name, "volume", volume.Name, "image", imageName)
+	case VolumeStatusCreating, VolumeStatusUploading:
+		r.Log.Info("the precopy volume is not ready yet",
+			"vm", vm.Name, "volume", volume.Name, "status", volume.Status)
+	default:
+		err = UnexpectedVolumeStatusError
+		r.Log.Error(err, "checking the precopy volume",
+			"vm", vm.Name, "volume", volume.Name, "status", volume.Status)
+	}
+	return
+}
+
+// Write the precopy image onto the populated volume.
+// The pod runs the populator in delta mode with the settings of the
+// populator CR of the volume and mounts the populated PVC. The whole
+// image is downloaded, only the blocks that differ are written.
+// Returns done=true once the pod succeeded.
+func (r *Client) deltaTransfer(vm *libclient.VM, volumeID string, image *libclient.Image) (done bool, err error) {
+	namespace := r.Context.Plan.Spec.TargetNamespace
+	pod := &core.Pod{}
+	err = r.Context.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: image.Name},
+		pod)
+	if err == nil {
+		switch pod.Status.Phase {
+		case core.PodSucceeded:
+			done = true
+		case core.PodFailed:
+			err = liberr.New("the delta transfer failed.", "pod", pod.Name)
+		}
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	// The populator CR is named after the initial image and the
+	// populated volume after its ID.
+	initialImageName := getImageFromVolumeName(r.Context, vm.ID, volumeID)
+	initialImage, err := r.getImage(ref.Ref{Name: initialImageName})
+	if err != nil {
+		err = liberr.Wrap(err, "image", initialImageName)
+		return
+	}
+	populatorCr := &api.OpenstackVolumePopulator{}
+	err = r.Context.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: initialImageName},
+		populatorCr)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pvc := &core.PersistentVolumeClaim{}
+	err = r.Context.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: namespace, Name: initialImage.ID},
+		pvc)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pod = r.deltaPod(vm, image, populatorCr, pvc)
+	err = r.Context.Destination.Client.Create(context.TODO(), pod)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info("created the delta transfer pod",
+		"vm", vm.Name, "pod", pod.Name, "pvc", pvc.Name)
+	return
+}
+
+// Build the delta transfer pod.
+func (r *Client) deltaPod(vm *libclient.VM, image *libclient.Image, populatorCr *api.OpenstackVolumePopulator, pvc *core.PersistentVolumeClaim) (pod *core.Pod) {
+	nonRoot := true
+	allowPrivilegeEscalation := false
+	user := int64(qemuGroup)
+	args := []string{
+		"--delta",
+		"--endpoint=" + populatorCr.Spec.IdentityURL,
+		"--secret-name=" + populatorCr.Spec.SecretName,
+		"--image-id=" + image.ID,
+		"--cr-name=" + image.Name,
+		"--cr-namespace=" + populatorCr.Namespace,
+	}
+	container := core.Container{
+		Name:  "populate",
+		Image: settings.Settings.Migration.OpenstackPopulatorImage,
+		SecurityContext: &core.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			RunAsNonRoot:             &nonRoot,
+			RunAsUser:                &user,
+			Capabilities: &core.Capabilities{
+				Drop: []core.Capability{"ALL"},
+			},
+		},
+		VolumeMounts: []core.VolumeMount{
+			{
+				Name:      "secret-volume",
+				ReadOnly:  true,
+				MountPath: "/etc/secret-volume",
+			},
+		},
+	}
+	if pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == core.PersistentVolumeBlock {
+		args = append(args, "--volume-path="+deltaDevicePath)
+		container.VolumeDevices = []core.VolumeDevice{
+			{
+				Name:       deltaVolumeName,
+				DevicePath: deltaDevicePath,
+			},
+		}
+	} else {
+		args = append(args, "--volume-path="+deltaMountPath+"disk.img")
+		container.VolumeMounts = append(
+			container.VolumeMounts,
+			core.VolumeMount{
+				Name:      deltaVolumeName,
+				MountPath: deltaMountPath,
+			})
+	}
+	container.Args = args
+	annotations := map[string]string{}
+	if network := populatorCr.Spec.TransferNetwork; network != nil {
+		annotations[AnnDefaultNetwork] = fmt.Sprintf("%s/%s", network.Namespace, network.Name)
+	}
+	pod = &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        image.Name,
+			Namespace:   populatorCr.Namespace,
+			Annotations: annotations,
+			Labels: map[string]string{
+				"vmID":      vm.ID,
+				"migration": getMigrationID(r.Context),
+			},
+		},
+		Spec: core.PodSpec{
+			Containers: []core.Container{container},
+			SecurityContext: &core.PodSecurityContext{
+				FSGroup: &user,
+				SeccompProfile: &core.SeccompProfile{
+					Type: core.SeccompProfileTypeRuntimeDefault,
+				},
+			},
+			RestartPolicy: core.RestartPolicyNever,
+			Volumes: []core.Volume{
+				{
+					Name: deltaVolumeName,
+					VolumeSource: core.VolumeSource{
+						PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				},
+				{
+					Name: "secret-volume",
+					VolumeSource: core.VolumeSource{
+						Secret: &core.SecretVolumeSource{
+							SecretName: populatorCr.Spec.SecretName,
+						},
+					},
+				},
+			},
+		},
+	}
+	return
+}
+
+// Remove the delta transfer pod.
+func (r *Client) removeDeltaPod(name string) (err error) {
+	pod := &core.Pod{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: r.Context.Plan.Spec.TargetNamespace,
+		},
+	}
+	err = r.Context.Destination.Client.Delete(context.TODO(), pod)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Remove an image by name.
+func (r *Client) removeImage(name string) (err error) {
+	image, err := r.getImage(ref.Ref{Name: name})
+	if err != nil {
+		if errors.Is(err, ResourceNotFoundError) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	switch image.Status {
+	case libclient.ImageStatusDeleted, libclient.ImageStatusPendingDelete:
+		return
+	default:
+		err = r.Delete(image)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	}
+	return
+}
+
+// Get the precopy snapshot of a volume.
+func (r *Client) getPrecopySnapshot(vm *libclient.VM, volumeID, snapshot string) (volumeSnapshot *libclient.Snapshot, err error) {
+	snapshots := []libclient.Snapshot{}
+	opts := libclient.SnapshotListOpts{}
+	opts.Name = getPrecopySnapshotName(r.Context, vm.ID, snapshot)
+	opts.VolumeID = volumeID
+	opts.Limit = 1
+	err = r.List(&snapshots, &opts)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(snapshots) == 0 {
+		err = ResourceNotFoundError
+		return
+	}
+	volumeSnapshot = &snapshots[0]
+	return
+}
+
+// Get the volume created from the precopy snapshot of a volume.
+func (r *Client) getPrecopyVolume(vm *libclient.VM, volumeID, snapshot string) (volume *libclient.Volume, err error) {
+	volumes := []libclient.Volume{}
+	opts := libclient.VolumeListOpts{}
+	opts.Name = getPrecopyVolumeName(r.Context, vm.ID, snapshot)
+	opts.Metadata = map[string]string{
+		forkliftPropertyOriginalVolumeID: volumeID,
+	}
+	opts.Limit = 1
+	err = r.List(&volumes, &opts)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(volumes) == 0 {
+		err = ResourceNotFoundError
+		return
+	}
+	volume = &volumes[0]
+	return
+}
+
+// Remove the volume created from the precopy snapshot of a volume.
+func (r *Client) removePrecopyVolume(vm *libclient.VM, volumeID, snapshot string) (err error) {
+	volume, err := r.getPrecopyVolume(vm, volumeID, snapshot)
+	if err != nil {
+		if errors.Is(err, ResourceNotFoundError) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	switch volume.Status {
+	case VolumeStatusAvailable:
+		err = r.Delete(volume)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	case VolumeStatusDeleting:
+	default:
+		r.Log.Info("the precopy volume cannot be removed yet",
+			"vm", vm.Name, "volume", volume.Name, "status", volume.Status)
+	}
+	return
+}
+
+// Remove the precopy snapshot of a volume.
+func (r *Client) removePrecopySnapshot(vm *libclient.VM, volumeID, snapshot string) (err error) {
+	volumeSnapshot, err := r.getPrecopySnapshot(vm, volumeID, snapshot)
+	if err != nil {
+		if errors.Is(err, ResourceNotFoundError) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	switch volumeSnapshot.Status {
+	case SnapshotStatusAvailable, SnapshotStatusError:
+		err = r.Delete(volumeSnapshot)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+	case SnapshotStatusDeleted, SnapshotStatusDeleting:
+	default:
+		r.Log.Info("the precopy snapshot cannot be removed yet",
+			"vm", vm.Name, "snapshot", volumeSnapshot.Name, "status", volumeSnapshot.Status)
+	}
+	return
+}
+
+// The warm migration precopies of the VM.
+func (r *Client) precopies(vmRef ref.Ref) (precopies []planapi.Precopy, err error) {
+	vmStatus, found := r.Context.Plan.Status.Migration.FindVM(vmRef)
+	if !found {
+		err = liberr.New("VM not found in the plan status.", "vm", vmRef.String())
+		return
+	}
+	if vmStatus.Warm != nil {
+		precopies = vmStatus.Warm.Precopies
+	}
+	return
+}
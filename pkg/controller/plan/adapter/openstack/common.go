@@ -7,6 +7,7 @@ import (
 
 const (
 	forkliftPropertyOriginalVolumeID = "forklift_original_volume_id"
+	forkliftPropertyPrecopy          = "forklift_precopy"
 )
 
 func getMigrationName(ctx *plancontext.Context) string {
@@ -35,3 +36,23 @@ func getImageFromVolumeName(ctx *plancontext.Context, vmID, volumeID string) str
 	const nameFormat = "%s-volume-%s"
 	return fmt.Sprintf(nameFormat, getVmSnapshotName(ctx, vmID), volumeID)
 }
+
+// The name of the snapshot taken of the VM volumes at a precopy.
+func getPrecopySnapshotName(ctx *plancontext.Context, vmID, snapshot string) string {
+	const nameFormat = "%s precopy %s"
+	return fmt.Sprintf(nameFormat, getSnapshotFromVolumeName(ctx, vmID), snapshot)
+}
+
+// The name of the volume created from a precopy snapshot.
+func getPrecopyVolumeName(ctx *plancontext.Context, vmID, snapshot string) string {
+	const nameFormat = "%s precopy %s"
+	return fmt.Sprintf(nameFormat, getVolumeFromSnapshotName(ctx, vmID, ""), snapshot)
+}
+
+// The name of the image created from the volume of a precopy
+// following the initial one. The name is also used for the pod
+// transferring the changes into the populated volume.
+func getDeltaImageName(ctx *plancontext.Context, vmID, volumeID, snapshot string) string {
+	const nameFormat = "%s-%s"
+	return fmt.Sprintf(nameFormat, getImageFromVolumeName(ctx, vmID, volumeID), snapshot)
+}
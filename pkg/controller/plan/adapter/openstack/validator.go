@@ -3,6 +3,8 @@ package openstack
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -43,6 +45,31 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that a VM's disk overrides reference its disks and are supported.
+// The disks are transferred by the volume populator and not preallocated.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	disks := make(map[string]int64)
+	if vm.ImageID != "" {
+		disks[vm.ImageID] = 0
+	}
+	for _, volume := range vm.Volumes {
+		disks[volume.ID] = int64(volume.Size) * 1024 * 1024 * 1024
+	}
+	ok = planbase.DiskOverridesValid(r.plan, ref.Ref{ID: vm.ID}, disks, false)
+	return
+}
+
 // Validate that a VM's networks have been mapped.
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
@@ -58,8 +85,9 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 			vmRef.String())
 		return
 	}
-	for _, network := range vm.Networks {
-		if !r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: network.ID}) {
+	nics := sourceNICs(r.plan.Referenced.Map.Network, vm)
+	for i := range nics {
+		if _, found := planbase.DestinationNetwork(r.plan, ref.Ref{ID: vm.ID}, &nics[i]); !found {
 			return
 		}
 	}
@@ -74,11 +102,20 @@ func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
 }
 
 // Validate whether warm migration is supported from this provider type.
+// Cinder does not expose the blocks changed between snapshots, each
+// precopy (including the one after the cutover) would clone, upload
+// and download the whole volumes so the downtime would not be shortened.
 func (r *Validator) WarmMigration() (ok bool) {
 	ok = false
 	return
 }
 
+// Validate that the target cluster satisfies the VM's requirements.
+func (r *Validator) TargetCompatible(ctx *plancontext.Context, vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
 // Validate that no more than one of a VM's networks is mapped to the pod network.
 func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
@@ -95,15 +132,12 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
+	nics := sourceNICs(r.plan.Referenced.Map.Network, vm)
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		for _, network := range vm.Networks {
-			if ref.ID == network.ID && mapped.Destination.Type == "Pod" {
-				podMapped++
-			}
+	for i := range nics {
+		destination, found := planbase.DestinationNetwork(r.plan, ref.Ref{ID: vm.ID}, &nics[i])
+		if found && destination.Type == Pod {
+			podMapped++
 		}
 	}
 
@@ -10,11 +10,13 @@ import (
 	"strconv"
 	"strings"
 
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/ova"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ova"
@@ -173,33 +175,19 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 			if err != nil {
 				return nil, err
 			}
-			storageClass := mapped.Destination.StorageClass
 			var dvSource cdi.DataVolumeSource
 			dvSource = cdi.DataVolumeSource{
 				Blank: &cdi.DataVolumeBlankImage{},
 			}
-			dvSpec := cdi.DataVolumeSpec{
-				Source: &dvSource,
-				Storage: &cdi.StorageSpec{
-					Resources: core.ResourceRequirements{
-						Requests: core.ResourceList{
-							core.ResourceStorage: *resource.NewQuantity(diskSize, resource.BinarySI),
-						},
-					},
-					StorageClassName: &storageClass,
-				},
-			}
-			// set the access mode and volume mode if they were specified in the storage map.
-			// otherwise, let the storage profile decide the default values.
-			if mapped.Destination.AccessMode != "" {
-				dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
-			}
-			if mapped.Destination.VolumeMode != "" {
-				dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
-			}
+			destination := planbase.DestinationDisk(
+				r.Plan,
+				ref.Ref{ID: vm.ID},
+				getDiskFullPath(disk),
+				mapped.Destination,
+				diskSize)
 
 			dv := dvTemplate.DeepCopy()
-			dv.Spec = dvSpec
+			dv.Spec = destination.DataVolumeSpec(&dvSource)
 			if dv.ObjectMeta.Annotations == nil {
 				dv.ObjectMeta.Annotations = make(map[string]string)
 			}
@@ -229,7 +217,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	if err != nil {
 		return
 	}
-	if len(conflicts) > 0 {
+	if len(conflicts) > 0 && r.Plan.Spec.PreserveMAC(true) {
 		err = liberr.New(
 			fmt.Sprintf("Source VM has a mac address conflict with one or more destination VMs: %s", conflicts))
 		return
@@ -259,57 +247,78 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	var kNetworks []cnv.Network
 	var kInterfaces []cnv.Interface
 
+	nics, err := sourceNICs(r.Source.Inventory, r.Context.Map.Network, vm)
+	if err != nil {
+		return
+	}
 	numNetworks := 0
-	netMapIn := r.Context.Map.Network.Spec.Map
-	for i := range netMapIn {
-		mapped := &netMapIn[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
+	for i := range nics {
+		nic := &nics[i]
+		destination, found := planbase.DestinationNetwork(r.Plan, ref.Ref{ID: vm.ID}, nic)
+		if !found {
+			continue
 		}
-
-		needed := []ova.NIC{}
-		for _, nic := range vm.NICs {
-			if nic.Network == network.Name {
-				needed = append(needed, nic)
-			}
+		networkName := fmt.Sprintf("net-%v", numNetworks)
+		numNetworks++
+		kNetwork := cnv.Network{
+			Name: networkName,
 		}
-		if len(needed) == 0 {
-			continue
+		kInterface := cnv.Interface{
+			Name:  networkName,
+			Model: Virtio,
 		}
-		for _, nic := range needed {
-			networkName := fmt.Sprintf("net-%v", numNetworks)
-			numNetworks++
-			kNetwork := cnv.Network{
-				Name: networkName,
-			}
-			kInterface := cnv.Interface{
-				Name:       networkName,
-				Model:      Virtio,
-				MacAddress: nic.MAC,
-			}
-			switch mapped.Destination.Type {
-			case Pod:
-				kNetwork.Pod = &cnv.PodNetwork{}
-				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
-				kNetwork.Multus = &cnv.MultusNetwork{
-					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
-				}
-				kInterface.Bridge = &cnv.InterfaceBridge{}
+		_, conflicting := r.macConflictsMap[nic.MAC]
+		if r.Plan.Spec.PreserveMAC(conflicting) {
+			kInterface.MacAddress = nic.MAC
+		}
+		switch destination.Type {
+		case Pod:
+			kNetwork.Pod = &cnv.PodNetwork{}
+			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+		case Multus:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(destination.Namespace, destination.Name),
 			}
-			kNetworks = append(kNetworks, kNetwork)
-			kInterfaces = append(kInterfaces, kInterface)
+			kInterface.Bridge = &cnv.InterfaceBridge{}
 		}
+		kNetworks = append(kNetworks, kNetwork)
+		kInterfaces = append(kInterfaces, kInterface)
 	}
 	object.Template.Spec.Networks = kNetworks
 	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
 	return
 }
 
+// Build the source NICs of the VM to be mapped.
+// The OVF describes neither the VLAN tags nor the guest IP addresses,
+// a NIC is only described by the network map pair of its network.
+func sourceNICs(inventory web.Client, networkMap *api.NetworkMap, vm *model.VM) (nics []planbase.NIC, err error) {
+	mapped := make(map[string]*api.NetworkPair)
+	if networkMap != nil {
+		for i := range networkMap.Spec.Map {
+			pair := &networkMap.Spec.Map[i]
+			if pair.Selector() {
+				continue
+			}
+			network := &model.Network{}
+			err = inventory.Find(network, pair.Source)
+			if err != nil {
+				return
+			}
+			mapped[network.Name] = pair
+		}
+	}
+	for _, nic := range vm.NICs {
+		nics = append(
+			nics,
+			planbase.NIC{
+				MAC:    nic.MAC,
+				Mapped: mapped[nic.Network],
+			})
+	}
+	return
+}
+
 func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
 	tablet := cnv.Input{
 		Type: Tablet,
@@ -497,6 +506,11 @@ func getDiskSourcePath(filePath string) string {
 	return filepath.Dir(filePath)
 }
 
+// Size of a disk in bytes.
+func DiskSize(disk *ova.Disk) (int64, error) {
+	return getResourceCapacity(disk.Capacity, disk.CapacityAllocationUnits)
+}
+
 func getResourceCapacity(capacity int64, units string) (int64, error) {
 	items := strings.Split(units, "*")
 	for i := range items {
@@ -545,6 +559,47 @@ func (r *Builder) SupportsVolumePopulators() bool {
 	return false
 }
 
+// Delta sync is not supported.
+func (r *Builder) SupportsDeltaSync(vmRef ref.Ref) (supported bool, err error) {
+	return
+}
+
+// Build the source VM specification verified on the target VM.
+func (r *Builder) SourceSpec(vmRef ref.Ref) (spec *planbase.VMSpec, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	memory, err := getResourceCapacity(int64(vm.MemoryMB), vm.MemoryUnits)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	spec = &planbase.VMSpec{
+		CPUs:   int(vm.CpuCount),
+		Memory: memory,
+	}
+	for _, disk := range vm.Disks {
+		var size int64
+		size, err = getResourceCapacity(disk.Capacity, disk.CapacityAllocationUnits)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		spec.Disks = append(spec.Disks, size)
+	}
+	for _, nic := range vm.NICs {
+		spec.MACs = append(spec.MACs, nic.MAC)
+	}
+	return
+}
+
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
 	err = planbase.VolumePopulatorNotSupportedError
 	return
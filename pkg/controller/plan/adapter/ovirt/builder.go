@@ -17,11 +17,13 @@ import (
 	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	utils "github.com/konveyor/forklift-controller/pkg/controller/plan/util"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	"github.com/konveyor/forklift-controller/pkg/settings"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -196,49 +198,35 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 	dsMapIn := r.Context.Map.Storage.Spec.Map
 	for i := range dsMapIn {
 		mapped := &dsMapIn[i]
-		ref := mapped.Source
 		sd := &model.StorageDomain{}
-		fErr := r.Source.Inventory.Find(sd, ref)
+		fErr := r.Source.Inventory.Find(sd, mapped.Source)
 		if fErr != nil {
 			err = fErr
 			return
 		}
 		for _, da := range vm.DiskAttachments {
 			if da.Disk.StorageType == "image" && da.Disk.StorageDomain == sd.ID {
-				storageClass := mapped.Destination.StorageClass
 				size := da.Disk.ProvisionedSize
 				if da.Disk.ActualSize > size {
 					size = da.Disk.ActualSize
 				}
-				dvSpec := cdi.DataVolumeSpec{
-					Source: &cdi.DataVolumeSource{
+				destination := planbase.DestinationDisk(
+					r.Plan,
+					ref.Ref{ID: vm.ID},
+					da.Disk.ID,
+					mapped.Destination,
+					size)
+
+				dv := dvTemplate.DeepCopy()
+				dv.Spec = destination.DataVolumeSpec(
+					&cdi.DataVolumeSource{
 						Imageio: &cdi.DataVolumeSourceImageIO{
 							URL:           url,
 							DiskID:        da.Disk.ID,
 							SecretRef:     secret.Name,
 							CertConfigMap: configMap.Name,
 						},
-					},
-					Storage: &cdi.StorageSpec{
-						Resources: core.ResourceRequirements{
-							Requests: core.ResourceList{
-								core.ResourceStorage: *resource.NewQuantity(size, resource.BinarySI),
-							},
-						},
-						StorageClassName: &storageClass,
-					},
-				}
-				// set the access mode and volume mode if they were specified in the storage map.
-				// otherwise, let the storage profile decide the default values.
-				if mapped.Destination.AccessMode != "" {
-					dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
-				}
-				if mapped.Destination.VolumeMode != "" {
-					dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
-				}
-
-				dv := dvTemplate.DeepCopy()
-				dv.Spec = dvSpec
+					})
 				if dv.ObjectMeta.Annotations == nil {
 					dv.ObjectMeta.Annotations = make(map[string]string)
 				}
@@ -269,7 +257,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	if err != nil {
 		return
 	}
-	if len(conflicts) > 0 {
+	if len(conflicts) > 0 && r.Plan.Spec.PreserveMAC(true) {
 		err = liberr.New(
 			fmt.Sprintf("Source VM has a mac address conflict with one or more destination VMs: %s", conflicts))
 		return
@@ -296,60 +284,103 @@ func (r *Builder) mapNetworks(vm *model.Workload, object *cnv.VirtualMachineSpec
 	var kNetworks []cnv.Network
 	var kInterfaces []cnv.Interface
 
+	nics, err := sourceNICs(r.Source.Inventory, r.Context.Map.Network, vm)
+	if err != nil {
+		return
+	}
 	numNetworks := 0
-	netMapIn := r.Context.Map.Network.Spec.Map
-	for i := range netMapIn {
-		mapped := &netMapIn[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
+	for i := range nics {
+		nic := &vm.NICs[i]
+		destination, found := planbase.DestinationNetwork(r.Plan, ref.Ref{ID: vm.ID}, &nics[i])
+		if !found {
+			continue
 		}
-		needed := []model.XNIC{}
-		for _, nic := range vm.NICs {
-			if nic.Profile.Network == network.ID {
-				needed = append(needed, nic)
-			}
+		networkName := fmt.Sprintf("net-%v", numNetworks)
+		numNetworks++
+		kNetwork := cnv.Network{
+			Name: networkName,
 		}
-		if len(needed) == 0 {
-			continue
+		kInterface := cnv.Interface{
+			Name:  networkName,
+			Model: nic.Interface,
 		}
-		for _, nic := range needed {
-			networkName := fmt.Sprintf("net-%v", numNetworks)
-			numNetworks++
-			kNetwork := cnv.Network{
-				Name: networkName,
-			}
-			kInterface := cnv.Interface{
-				Name:       networkName,
-				Model:      nic.Interface,
-				MacAddress: nic.MAC,
+		_, conflicting := r.macConflictsMap[nic.MAC]
+		if r.Plan.Spec.PreserveMAC(conflicting) {
+			kInterface.MacAddress = nic.MAC
+		}
+		switch destination.Type {
+		case Pod:
+			kNetwork.Pod = &cnv.PodNetwork{}
+			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+		case Multus:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(destination.Namespace, destination.Name),
 			}
-			switch mapped.Destination.Type {
-			case Pod:
-				kNetwork.Pod = &cnv.PodNetwork{}
-				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
-				kNetwork.Multus = &cnv.MultusNetwork{
-					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
-				}
-				if nic.Profile.PassThrough {
-					kInterface.SRIOV = &cnv.InterfaceSRIOV{}
-				} else {
-					kInterface.Bridge = &cnv.InterfaceBridge{}
-				}
+			if nic.Profile.PassThrough {
+				kInterface.SRIOV = &cnv.InterfaceSRIOV{}
+			} else {
+				kInterface.Bridge = &cnv.InterfaceBridge{}
 			}
-			kNetworks = append(kNetworks, kNetwork)
-			kInterfaces = append(kInterfaces, kInterface)
 		}
+		kNetworks = append(kNetworks, kNetwork)
+		kInterfaces = append(kInterfaces, kInterface)
 	}
 	object.Template.Spec.Networks = kNetworks
 	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
 	return
 }
 
+// Build the source NICs of the VM to be mapped, ordered as the VM NICs.
+// A NIC is described by the network map pair of its network, the VLAN
+// tag of its network and the IP addresses reported by the guest agent.
+func sourceNICs(inventory web.Client, networkMap *api.NetworkMap, vm *model.Workload) (nics []planbase.NIC, err error) {
+	mapped := make(map[string]*api.NetworkPair)
+	if networkMap != nil {
+		for i := range networkMap.Spec.Map {
+			pair := &networkMap.Spec.Map[i]
+			if pair.Selector() {
+				continue
+			}
+			network := &model.Network{}
+			err = inventory.Find(network, pair.Source)
+			if err != nil {
+				return
+			}
+			mapped[network.ID] = pair
+		}
+	}
+	vlans := make(map[string]string)
+	for _, nic := range vm.NICs {
+		sourceNIC := planbase.NIC{
+			MAC:    nic.MAC,
+			Mapped: mapped[nic.Profile.Network],
+		}
+		if nic.Profile.Network != "" {
+			vlan, found := vlans[nic.Profile.Network]
+			if !found {
+				network := &model.Network{}
+				err = inventory.Get(network, nic.Profile.Network)
+				if err != nil {
+					err = liberr.Wrap(
+						err,
+						"Network lookup failed.",
+						"network",
+						nic.Profile.Network)
+					return
+				}
+				vlan = network.VLan
+				vlans[nic.Profile.Network] = vlan
+			}
+			sourceNIC.VLAN = vlan
+		}
+		for _, ip := range nic.IpAddress {
+			sourceNIC.IPs = append(sourceNIC.IPs, ip.Address)
+		}
+		nics = append(nics, sourceNIC)
+	}
+	return
+}
+
 func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
 	tablet := cnv.Input{
 		Type: Tablet,
@@ -667,7 +698,47 @@ func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.Persiste
 }
 
 func (r *Builder) SupportsVolumePopulators() bool {
-	return !r.Context.Plan.Spec.Warm && r.Context.Plan.Provider.Destination.IsHost()
+	return !r.Context.Plan.Spec.Warm && !r.Context.Plan.Spec.DeltaSync && r.Context.Plan.Provider.Destination.IsHost()
+}
+
+// Whether the disks may be delta synchronized between migrations.
+// The changed blocks are transferred from the disk snapshots using
+// the multistage imports also used by warm migration.
+func (r *Builder) SupportsDeltaSync(vmRef ref.Ref) (supported bool, err error) {
+	supported = settings.Settings.Features.OvirtWarmMigration
+	return
+}
+
+// Build the source VM specification verified on the target VM.
+func (r *Builder) SourceSpec(vmRef ref.Ref) (spec *planbase.VMSpec, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	spec = &planbase.VMSpec{
+		CPUs:   int(vm.CpuSockets * vm.CpuCores * vm.CpuThreads),
+		Memory: vm.Memory,
+	}
+	for _, da := range vm.DiskAttachments {
+		size := da.Disk.ProvisionedSize
+		if da.Disk.StorageType == "lun" {
+			size = 0
+			for _, lu := range da.Disk.Lun.LogicalUnits.LogicalUnit {
+				size += lu.Size
+			}
+		}
+		spec.Disks = append(spec.Disks, size)
+	}
+	for _, nic := range vm.NICs {
+		spec.MACs = append(spec.MACs, nic.MAC)
+	}
+	return
 }
 
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
@@ -695,12 +766,12 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 				return
 			}
 			var pvc *core.PersistentVolumeClaim
-			storageClassName := r.Context.Map.Storage.Spec.Map[0].Destination.StorageClass
-			pvc, err = r.persistentVolumeClaimWithSourceRef(diskAttachment, storageClassName, populatorName, annotations)
+			destination := r.populatorDisk(workload, diskAttachment)
+			pvc, err = r.persistentVolumeClaimWithSourceRef(diskAttachment, destination, populatorName, annotations)
 			if err != nil {
 				if !k8serr.IsAlreadyExists(err) {
 					err = liberr.New("couldn't build the PVC", "diskAttachmentID", diskAttachment.DiskAttachment.ID,
-						"storageClassName", storageClassName, "populatorName", populatorName)
+						"storageClassName", destination.StorageClass, "populatorName", populatorName)
 					return
 				}
 				err = nil
@@ -712,6 +783,27 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 	return
 }
 
+// Find the destination of a disk transferred by the volume populator.
+// The storage class is mapped by the storage domain, defaulting to the
+// first storage map entry. The access and volume modes default to the
+// storage profile.
+func (r *Builder) populatorDisk(workload *model.Workload, diskAttachment model.XDiskAttachment) planbase.Disk {
+	dsMapIn := r.Context.Map.Storage.Spec.Map
+	storageClass := dsMapIn[0].Destination.StorageClass
+	for _, pair := range dsMapIn {
+		if pair.Source.ID == diskAttachment.Disk.StorageDomain {
+			storageClass = pair.Destination.StorageClass
+			break
+		}
+	}
+	return planbase.DestinationDisk(
+		r.Plan,
+		ref.Ref{ID: workload.ID},
+		diskAttachment.Disk.ID,
+		api.DestinationStorage{StorageClass: storageClass},
+		diskAttachment.Disk.ProvisionedSize)
+}
+
 // Get the OvirtVolumePopulator CustomResource based on the PVC name.
 func (r *Builder) getVolumePopulator(name string) (populatorCr api.OvirtVolumePopulator, err error) {
 	populatorCr = api.OvirtVolumePopulator{}
@@ -779,11 +871,12 @@ func (r *Builder) getDefaultVolumeAndAccessMode(storageClassName string) ([]core
 }
 
 // Build a PersistentVolumeClaim with DataSourceRef for VolumePopulator
-func (r *Builder) persistentVolumeClaimWithSourceRef(diskAttachment model.XDiskAttachment, storageClassName string, populatorName string,
+func (r *Builder) persistentVolumeClaimWithSourceRef(diskAttachment model.XDiskAttachment, destination planbase.Disk, populatorName string,
 	annotations map[string]string) (pvc *core.PersistentVolumeClaim, err error) {
 
 	// We add 10% overhead because of the fsOverhead in CDI, around 5% to ext4 and 5% for root partition.
-	diskSize := diskAttachment.Disk.ProvisionedSize
+	diskSize := destination.Size
+	storageClassName := destination.StorageClass
 
 	var accessModes []core.PersistentVolumeAccessMode
 	var volumeMode *core.PersistentVolumeMode
@@ -792,6 +885,12 @@ func (r *Builder) persistentVolumeClaimWithSourceRef(diskAttachment model.XDiskA
 		err = liberr.Wrap(err)
 		return
 	}
+	if destination.AccessMode != "" {
+		accessModes = []core.PersistentVolumeAccessMode{destination.AccessMode}
+	}
+	if destination.VolumeMode != "" {
+		volumeMode = &destination.VolumeMode
+	}
 
 	// Accounting for fsOverhead is only required for `volumeMode: Filesystem`, as we may not have enough space
 	// after creating a filesystem on an underlying block device
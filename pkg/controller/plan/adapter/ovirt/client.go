@@ -444,7 +444,7 @@ func (r Client) Finalize(vms []*planapi.VMStatus, planName string) {
 		}
 	}()
 
-	if !r.Plan.Spec.Warm {
+	if !r.Plan.Spec.Warm && !r.Plan.Spec.DeltaSync {
 		r.Log.Info("Skipping precopy removal for cold migration")
 		return
 	}
@@ -452,21 +452,39 @@ func (r Client) Finalize(vms []*planapi.VMStatus, planName string) {
 	r.connect()
 	defer r.Close()
 	var wg sync.WaitGroup
-	wg.Add(len(vms))
 	for _, vm := range vms {
+		precopies := r.finalizedPrecopies(vm)
+		if len(precopies) == 0 {
+			continue
+		}
 		_, vmService, err := r.getVM(vm.Ref)
 		if err != nil {
 			r.Log.Error(err, "Failed to get VM", "vm", vm.Ref.String())
 			continue
 		}
 
-		go r.removePrecopies(vm.Warm.Precopies, vmService, &wg)
+		wg.Add(1)
+		go r.removePrecopies(precopies, vmService, &wg)
 	}
 
 	wg.Wait()
 	r.Log.Info("Finished removing precopies")
 }
 
+// Precopies to be removed when the migration of the VM has ended.
+// The snapshots of a delta synchronized VM are kept when the migration
+// has failed so that the next migration only transfers the changed blocks.
+func (r Client) finalizedPrecopies(vm *planapi.VMStatus) (precopies []planapi.Precopy) {
+	if vm.Warm != nil {
+		precopies = vm.Warm.Precopies
+		return
+	}
+	if vm.DeltaSync != nil && vm.Error == nil {
+		precopies = vm.DeltaSync.Precopies
+	}
+	return
+}
+
 func (r Client) removePrecopies(precopies []planapi.Precopy, vmService *ovirtsdk.VmService, wg *sync.WaitGroup) {
 	if len(precopies) == 0 {
 		return
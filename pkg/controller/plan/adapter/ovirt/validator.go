@@ -3,6 +3,8 @@ package ovirt
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -27,6 +29,12 @@ func (r *Validator) WarmMigration() (ok bool) {
 	return
 }
 
+// Validate that the target cluster satisfies the VM's requirements.
+func (r *Validator) TargetCompatible(ctx *plancontext.Context, vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
 // Validate that a VM's networks have been mapped.
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
@@ -43,8 +51,12 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	for _, nic := range vm.NICs {
-		if !r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: nic.Profile.Network}) {
+	nics, err := sourceNICs(r.inventory, r.plan.Referenced.Map.Network, vm)
+	if err != nil {
+		return
+	}
+	for i := range nics {
+		if _, found := planbase.DestinationNetwork(r.plan, ref.Ref{ID: vm.ID}, &nics[i]); !found {
 			return
 		}
 	}
@@ -68,21 +80,15 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
+	nics, err := sourceNICs(r.inventory, r.plan.Referenced.Map.Network, vm)
+	if err != nil {
+		return
+	}
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
-		}
-		for _, nic := range vm.NICs {
-			if nic.Profile.Network == network.ID && mapped.Destination.Type == Pod {
-				podMapped++
-			}
+	for i := range nics {
+		destination, found := planbase.DestinationNetwork(r.plan, ref.Ref{ID: vm.ID}, &nics[i])
+		if found && destination.Type == Pod {
+			podMapped++
 		}
 	}
 
@@ -119,6 +125,32 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that a VM's disk overrides reference its disks and are supported.
+// The disks are preallocated unless transferred by the volume populator.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.Workload{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	disks := make(map[string]int64)
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.StorageType != "lun" {
+			disks[da.Disk.ID] = da.Disk.ProvisionedSize
+		}
+	}
+	destination := r.plan.Referenced.Provider.Destination
+	populator := !r.plan.Spec.Warm && !r.plan.Spec.DeltaSync && destination != nil && destination.IsHost()
+	ok = planbase.DiskOverridesValid(r.plan, ref.Ref{ID: vm.ID}, disks, !populator)
+	return
+}
+
 // Validate that a VM's Host isn't in maintenance mode. No-op for oVirt.
 func (r *Validator) MaintenanceMode(_ ref.Ref) (ok bool, err error) {
 	ok = true
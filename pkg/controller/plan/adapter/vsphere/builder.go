@@ -8,6 +8,7 @@ import (
 	"path"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -334,16 +335,14 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 	dsMapIn := r.Context.Map.Storage.Spec.Map
 	for i := range dsMapIn {
 		mapped := &dsMapIn[i]
-		ref := mapped.Source
 		ds := &model.Datastore{}
-		fErr := r.Source.Inventory.Find(ds, ref)
+		fErr := r.Source.Inventory.Find(ds, mapped.Source)
 		if fErr != nil {
 			err = fErr
 			return
 		}
 		for _, disk := range vm.Disks {
 			if disk.Datastore.ID == ds.ID {
-				storageClass := mapped.Destination.StorageClass
 				var dvSource cdi.DataVolumeSource
 				el9, el9Err := r.Context.Plan.VSphereUsesEl9VirtV2v()
 				if el9Err != nil {
@@ -368,28 +367,15 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 						},
 					}
 				}
-				dvSpec := cdi.DataVolumeSpec{
-					Source: &dvSource,
-					Storage: &cdi.StorageSpec{
-						Resources: core.ResourceRequirements{
-							Requests: core.ResourceList{
-								core.ResourceStorage: *resource.NewQuantity(disk.Capacity, resource.BinarySI),
-							},
-						},
-						StorageClassName: &storageClass,
-					},
-				}
-				// set the access mode and volume mode if they were specified in the storage map.
-				// otherwise, let the storage profile decide the default values.
-				if mapped.Destination.AccessMode != "" {
-					dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{mapped.Destination.AccessMode}
-				}
-				if mapped.Destination.VolumeMode != "" {
-					dvSpec.Storage.VolumeMode = &mapped.Destination.VolumeMode
-				}
+				destination := planbase.DestinationDisk(
+					r.Plan,
+					ref.Ref{ID: vm.ID},
+					trimBackingFileName(disk.File),
+					mapped.Destination,
+					disk.Capacity)
 
 				dv := dvTemplate.DeepCopy()
-				dv.Spec = dvSpec
+				dv.Spec = destination.DataVolumeSpec(&dvSource)
 				if dv.ObjectMeta.Annotations == nil {
 					dv.ObjectMeta.Annotations = make(map[string]string)
 				}
@@ -442,7 +428,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	if err != nil {
 		return
 	}
-	if len(conflicts) > 0 {
+	if len(conflicts) > 0 && r.Plan.Spec.PreserveMAC(true) {
 		err = liberr.New(
 			fmt.Sprintf("Source VM has a mac address conflict with one or more destination VMs: %s", conflicts))
 		return
@@ -474,64 +460,121 @@ func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err
 	var kNetworks []cnv.Network
 	var kInterfaces []cnv.Interface
 
+	nics, err := sourceNICs(r.Source.Inventory, r.Context.Map.Network, vm)
+	if err != nil {
+		return
+	}
 	numNetworks := 0
-	netMapIn := r.Context.Map.Network.Spec.Map
-	for i := range netMapIn {
-		mapped := &netMapIn[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.Source.Inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
+	for i := range nics {
+		nic := &nics[i]
+		destination, found := planbase.DestinationNetwork(r.Plan, ref.Ref{ID: vm.ID}, nic)
+		if !found {
+			continue
+		}
+		networkName := fmt.Sprintf("net-%v", numNetworks)
+		numNetworks++
+		kNetwork := cnv.Network{
+			Name: networkName,
+		}
+		kInterface := cnv.Interface{
+			Name:  networkName,
+			Model: Virtio,
 		}
+		_, conflicting := r.macConflictsMap[nic.MAC]
+		if r.Plan.Spec.PreserveMAC(conflicting) {
+			kInterface.MacAddress = nic.MAC
+		}
+		switch destination.Type {
+		case Pod:
+			kNetwork.Pod = &cnv.PodNetwork{}
+			kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+		case Multus:
+			kNetwork.Multus = &cnv.MultusNetwork{
+				NetworkName: path.Join(destination.Namespace, destination.Name),
+			}
+			kInterface.Bridge = &cnv.InterfaceBridge{}
+		}
+		kNetworks = append(kNetworks, kNetwork)
+		kInterfaces = append(kInterfaces, kInterface)
+	}
+	object.Template.Spec.Networks = kNetworks
+	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
+	return
+}
 
-		needed := []vsphere.NIC{}
-		for _, nic := range vm.NICs {
-			switch network.Variant {
-			case vsphere.NetDvPortGroup:
-				if nic.Network.ID == network.Key {
-					needed = append(needed, nic)
-				}
-			default:
-				if nic.Network.ID == network.ID {
-					needed = append(needed, nic)
-				}
+// Build the source NICs of the VM to be mapped.
+// A NIC is described by the network map pair of its network, the VLAN
+// tag of its network and the guest IP addresses reported on the NIC.
+func sourceNICs(inventory web.Client, networkMap *api.NetworkMap, vm *model.VM) (nics []planbase.NIC, err error) {
+	mapped := make(map[string]*api.NetworkPair)
+	if networkMap != nil {
+		for i := range networkMap.Spec.Map {
+			pair := &networkMap.Spec.Map[i]
+			if pair.Selector() {
+				continue
 			}
+			network := &model.Network{}
+			err = inventory.Find(network, pair.Source)
+			if err != nil {
+				return
+			}
+			mapped[networkKey(network)] = pair
 		}
-		if len(needed) == 0 {
-			continue
+	}
+	host := &model.Host{}
+	err = inventory.Get(host, vm.Host)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"Host lookup failed.",
+			"host",
+			vm.Host)
+		return
+	}
+	vlans := make(map[string]string)
+	for _, ref := range vm.Networks {
+		network := &model.Network{}
+		err = inventory.Get(network, ref.ID)
+		if err != nil {
+			err = liberr.Wrap(
+				err,
+				"Network lookup failed.",
+				"network",
+				ref.ID)
+			return
 		}
-		for _, nic := range needed {
-			networkName := fmt.Sprintf("net-%v", numNetworks)
-			numNetworks++
-			kNetwork := cnv.Network{
-				Name: networkName,
-			}
-			kInterface := cnv.Interface{
-				Name:       networkName,
-				Model:      Virtio,
-				MacAddress: nic.MAC,
+		vlan := network.Tag
+		if network.Variant == vsphere.NetStandard {
+			if portGroup, found := host.Network.PortGroup(network.Name); found && portGroup.VlanId > 0 {
+				vlan = strconv.Itoa(int(portGroup.VlanId))
 			}
-			switch mapped.Destination.Type {
-			case Pod:
-				kNetwork.Pod = &cnv.PodNetwork{}
-				kInterface.Masquerade = &cnv.InterfaceMasquerade{}
-			case Multus:
-				kNetwork.Multus = &cnv.MultusNetwork{
-					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
-				}
-				kInterface.Bridge = &cnv.InterfaceBridge{}
+		}
+		vlans[networkKey(network)] = vlan
+	}
+	for _, nic := range vm.NICs {
+		sourceNIC := planbase.NIC{
+			MAC:    nic.MAC,
+			VLAN:   vlans[nic.Network.ID],
+			Mapped: mapped[nic.Network.ID],
+		}
+		for _, guestNetwork := range vm.GuestNetworks {
+			if strings.EqualFold(guestNetwork.MAC, nic.MAC) {
+				sourceNIC.IPs = append(sourceNIC.IPs, guestNetwork.IP)
 			}
-			kNetworks = append(kNetworks, kNetwork)
-			kInterfaces = append(kInterfaces, kInterface)
 		}
+		nics = append(nics, sourceNIC)
 	}
-	object.Template.Spec.Networks = kNetworks
-	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
 	return
 }
 
+// The key referenced by the NICs connected to the network.
+func networkKey(network *model.Network) string {
+	if network.Variant == vsphere.NetDvPortGroup {
+		return network.Key
+	}
+	return network.ID
+}
+
 func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
 	tablet := cnv.Input{
 		Type: Tablet,
@@ -836,6 +879,44 @@ func (r *Builder) SupportsVolumePopulators() bool {
 	return false
 }
 
+// Whether the disks may be delta synchronized between migrations.
+// The changed blocks are found using changed block tracking (CBT).
+func (r *Builder) SupportsDeltaSync(vmRef ref.Ref) (supported bool, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	supported = vm.ChangeTrackingEnabled
+	return
+}
+
+// Build the source VM specification verified on the target VM.
+func (r *Builder) SourceSpec(vmRef ref.Ref) (spec *planbase.VMSpec, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM lookup failed.",
+			"vm",
+			vmRef.String())
+		return
+	}
+	spec = &planbase.VMSpec{
+		CPUs:   int(vm.CpuCount),
+		Memory: int64(vm.MemoryMB) * 0x100000,
+	}
+	for _, disk := range vm.Disks {
+		spec.Disks = append(spec.Disks, disk.Capacity)
+	}
+	for _, nic := range vm.NICs {
+		spec.MACs = append(spec.MACs, nic.MAC)
+	}
+	return
+}
+
 func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcNames []string, err error) {
 	err = planbase.VolumePopulatorNotSupportedError
 	return
@@ -3,6 +3,8 @@ package vsphere
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -26,6 +28,12 @@ func (r *Validator) WarmMigration() (ok bool) {
 	return
 }
 
+// Validate that the target cluster satisfies the VM's requirements.
+func (r *Validator) TargetCompatible(ctx *plancontext.Context, vmRef ref.Ref) (ok bool, err error) {
+	ok = true
+	return
+}
+
 // Validate that a VM's networks have been mapped.
 func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
@@ -42,8 +50,12 @@ func (r *Validator) NetworksMapped(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	for _, net := range vm.Networks {
-		if !r.plan.Referenced.Map.Network.Status.Refs.Find(ref.Ref{ID: net.ID}) {
+	nics, err := sourceNICs(r.inventory, r.plan.Referenced.Map.Network, vm)
+	if err != nil {
+		return
+	}
+	for i := range nics {
+		if _, found := planbase.DestinationNetwork(r.plan, ref.Ref{ID: vm.ID}, &nics[i]); !found {
 			return
 		}
 	}
@@ -56,7 +68,7 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 	if r.plan.Referenced.Map.Network == nil {
 		return
 	}
-	vm := &model.Workload{}
+	vm := &model.VM{}
 	err = r.inventory.Find(vm, vmRef)
 	if err != nil {
 		err = liberr.Wrap(
@@ -67,21 +79,15 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 		return
 	}
 
-	mapping := r.plan.Referenced.Map.Network.Spec.Map
+	nics, err := sourceNICs(r.inventory, r.plan.Referenced.Map.Network, vm)
+	if err != nil {
+		return
+	}
 	podMapped := 0
-	for i := range mapping {
-		mapped := &mapping[i]
-		ref := mapped.Source
-		network := &model.Network{}
-		fErr := r.inventory.Find(network, ref)
-		if fErr != nil {
-			err = fErr
-			return
-		}
-		for _, nic := range vm.NICs {
-			if nic.Network.ID == network.ID && mapped.Destination.Type == Pod {
-				podMapped++
-			}
+	for i := range nics {
+		destination, found := planbase.DestinationNetwork(r.plan, ref.Ref{ID: vm.ID}, &nics[i])
+		if found && destination.Type == Pod {
+			podMapped++
 		}
 	}
 
@@ -114,6 +120,27 @@ func (r *Validator) StorageMapped(vmRef ref.Ref) (ok bool, err error) {
 	return
 }
 
+// Validate that a VM's disk overrides reference its disks and are supported.
+func (r *Validator) DiskOverrides(vmRef ref.Ref) (ok bool, err error) {
+	vm := &model.VM{}
+	err = r.inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(
+			err,
+			"VM not found in inventory.",
+			"vm",
+			vmRef.String())
+		return
+	}
+
+	disks := make(map[string]int64)
+	for _, disk := range vm.Disks {
+		disks[trimBackingFileName(disk.File)] = disk.Capacity
+	}
+	ok = planbase.DiskOverridesValid(r.plan, ref.Ref{ID: vm.ID}, disks, true)
+	return
+}
+
 // Validate that a VM's Host isn't in maintenance mode.
 func (r *Validator) MaintenanceMode(vmRef ref.Ref) (ok bool, err error) {
 	vm := &model.VM{}
@@ -250,6 +250,14 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		return
 	}
 
+	//
+	// Dry runs.
+	// Run on their own so that blocked plans are
+	// dry run and the failures do not abort the reconcile.
+	if !plan.Status.HasCondition(Archived) {
+		r.dryRun(plan)
+	}
+
 	//
 	// Execute.
 	// The plan is updated as needed to reflect status.
@@ -313,7 +321,7 @@ func (r *Reconciler) archive(plan *api.Plan) {
 // Execute the plan.
 //  1. Find active (current) migration.
 //  2. If found, update the context and match the snapshot.
-//  3. Cancel as needed.
+//  3. Cancel and roll back as needed.
 //  4. If not, find the next pending migration.
 //  5. If a new migration is being started, update the context and snapshot.
 //  6. Run the migration.
@@ -382,6 +390,12 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 		return
 	}
 	//
+	// Rollback.
+	err = r.rollback(ctx, migration)
+	if err != nil {
+		return
+	}
+	//
 	// Find pending migrations.
 	pending := []*api.Migration{}
 	pending, err = r.pendingMigrations(plan)
@@ -424,7 +438,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	snapshot.EndStagingConditions()
 
 	// Reflect the active snapshot status on the plan.
-	for _, t := range []string{Executing, Succeeded, Failed, Canceled} {
+	for _, t := range []string{Executing, Succeeded, Failed, Canceled, WaitingForWindow} {
 		if cnd := snapshot.FindCondition(t); cnd != nil {
 			r.Log.V(2).Info(
 				"Snapshot condition copied to plan.",
@@ -516,6 +530,46 @@ func (r *Reconciler) matchSnapshot(ctx *plancontext.Context) (matched bool) {
 	return true
 }
 
+// Roll back the VMs as requested on the migration
+// referenced in the active snapshot. The migration
+// may have ended.
+func (r *Reconciler) rollback(ctx *plancontext.Context, active *api.Migration) (err error) {
+	migration := active
+	if migration == nil {
+		snapshot := ctx.Plan.Status.Migration.ActiveSnapshot()
+		if snapshot.Migration.UID == "" {
+			return
+		}
+		migration = &api.Migration{}
+		err = r.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: snapshot.Migration.Namespace,
+				Name:      snapshot.Migration.Name,
+			},
+			migration)
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+			} else {
+				err = liberr.Wrap(err)
+			}
+			return
+		}
+		if migration.UID != snapshot.Migration.UID {
+			return
+		}
+	}
+	if len(migration.Spec.Rollback) == 0 && !migration.Spec.RollbackAll {
+		return
+	}
+	ctx.SetMigration(migration)
+	runner := Migration{Context: ctx}
+	err = runner.Rollback()
+
+	return
+}
+
 // Get the current (active) migration referenced in the snapshot.
 // The active snapshot will be marked canceled.
 // Returns: nil when not-found.
@@ -578,7 +632,7 @@ func (r *Reconciler) pendingMigrations(plan *api.Plan) (list []*api.Migration, e
 	list = []*api.Migration{}
 	for i := range all.Items {
 		migration := &all.Items[i]
-		if !migration.Match(plan) {
+		if !migration.Match(plan) || migration.Spec.DryRun {
 			continue
 		}
 		if found, snapshot := plan.Status.Migration.SnapshotWithMigration(migration.UID); found {
@@ -0,0 +1,316 @@
+package plan
+
+import (
+	"context"
+	"path"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Annotations
+const (
+	// The (source VM) snapshot the DataVolume has last been synchronized from.
+	AnnSyncSnapshot = "forklift.konveyor.io/sync-snapshot"
+	// Prefix of the PVC annotations set by CDI when a checkpoint has been copied.
+	AnnCheckpointsCopied = "cdi.kubevirt.io/storage.checkpoint.copied"
+)
+
+// Delta sync.
+// The disks of a cold migration are populated using multistage imports
+// from a snapshot of the (powered off) source VM. The DataVolumes are
+// finalized only once the guest has been converted so that, when the
+// disk transfer or the conversion fails, the next migration reuses the
+// populated disks: a checkpoint from the previous to a new snapshot is
+// added and only the changed blocks are transferred. The conversion is
+// run again on the reused disks.
+
+// Whether the disks of the VM are delta synchronized.
+func (r *Predicate) deltaSync() (allowed bool, err error) {
+	spec := r.context.Plan.Spec
+	if !spec.DeltaSync || spec.Warm || r.builder == nil {
+		return
+	}
+	if r.builder.SupportsVolumePopulators() {
+		return
+	}
+	allowed, err = r.builder.SupportsDeltaSync(r.vm.Ref)
+	return
+}
+
+// Whether the disks of the VM are delta synchronized.
+func (r *Migration) deltaSync(vm *plan.VMStatus) (sync bool, err error) {
+	predicate := &Predicate{vm: &vm.VM, context: r.Context, builder: r.builder}
+	sync, err = predicate.Evaluate(DeltaSync)
+	if err != nil {
+		return
+	}
+	sync = sync && vm.DeltaSync != nil && len(vm.DeltaSync.Precopies) > 0
+	return
+}
+
+// Ensure the delta synchronized DataVolumes.
+// The DataVolumes populated by the previous migrations are reused when
+// synchronized from a snapshot that is still known. The others are deleted
+// and replaced by DataVolumes populated from the current snapshot.
+func (r *Migration) syncDataVolumes(vm *plan.VMStatus, dataVolumes []cdi.DataVolume) (err error) {
+	precopies := vm.DeltaSync.Precopies
+	current := precopies[len(precopies)-1]
+	known := make(map[string]bool)
+	for _, precopy := range precopies[:len(precopies)-1] {
+		known[precopy.Snapshot] = true
+	}
+	existing, err := r.kubevirt.getDVs(vm)
+	if err != nil {
+		return
+	}
+	ensured := make(map[string]bool)
+	for _, dv := range existing {
+		ensured[r.builder.ResolveDataVolumeIdentifier(dv.DataVolume)] = true
+	}
+	previous, err := r.kubevirt.previousDataVolumes(vm)
+	if err != nil {
+		return
+	}
+	reusable := make(map[string]*cdi.DataVolume)
+	for i := range previous {
+		dv := &previous[i]
+		id := r.builder.ResolveDataVolumeIdentifier(dv)
+		if ensured[id] || reusable[id] != nil || !r.reusable(dv, known) {
+			err = r.kubevirt.deleteDataVolume(vm, dv)
+			if err != nil {
+				return
+			}
+			continue
+		}
+		reusable[id] = dv
+	}
+	// Group the reused DataVolumes by the snapshot they
+	// have been synchronized from.
+	reused := make(map[string][]cdi.DataVolume)
+	populated := []cdi.DataVolume{}
+	for _, dv := range dataVolumes {
+		id := r.builder.ResolveDataVolumeIdentifier(&dv)
+		if ensured[id] {
+			continue
+		}
+		if previous, found := reusable[id]; found {
+			snapshot := previous.Annotations[AnnSyncSnapshot]
+			reused[snapshot] = append(reused[snapshot], *previous)
+			delete(reusable, id)
+			continue
+		}
+		populated = append(populated, dv)
+	}
+	// The disks no longer found on the source VM.
+	for _, dv := range reusable {
+		err = r.kubevirt.deleteDataVolume(vm, dv)
+		if err != nil {
+			return
+		}
+	}
+	for snapshot, dvs := range reused {
+		err = r.provider.SetCheckpoints(vm.Ref, []plan.Precopy{{Snapshot: snapshot}, current}, dvs, false)
+		if err != nil {
+			return
+		}
+		for i := range dvs {
+			dv := &dvs[i]
+			dv.Labels[kMigration] = string(r.Migration.UID)
+			dv.Annotations[kMigration] = string(r.Migration.UID)
+			dv.Annotations[AnnSyncSnapshot] = current.Snapshot
+			err = r.Destination.Client.Update(context.TODO(), dv)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			vm.DeltaSync.Reused++
+			r.Log.Info(
+				"Reused DataVolume.",
+				"dv",
+				path.Join(
+					dv.Namespace,
+					dv.Name),
+				"snapshot",
+				snapshot,
+				"vm",
+				vm.String())
+		}
+	}
+	if len(populated) == 0 {
+		return
+	}
+	err = r.provider.SetCheckpoints(vm.Ref, []plan.Precopy{current}, populated, false)
+	if err != nil {
+		return
+	}
+	for i := range populated {
+		populated[i].Annotations[AnnSyncSnapshot] = current.Snapshot
+	}
+	err = r.kubevirt.EnsureDataVolumes(vm, populated)
+	return
+}
+
+// Finalize the delta synchronized DataVolumes.
+// The last checkpoint has been copied from the snapshot of the powered
+// off VM and the guest converted, the checkpoint is marked final so
+// that the imports complete.
+func (r *Migration) finalizeDataVolumes(vm *plan.VMStatus) (err error) {
+	disks, err := r.kubevirt.getDVs(vm)
+	if err != nil {
+		return
+	}
+	for _, disk := range disks {
+		dv := disk.DataVolume
+		if dv.Spec.FinalCheckpoint {
+			continue
+		}
+		dv.Spec.FinalCheckpoint = true
+		err = r.Destination.Client.Update(context.TODO(), dv)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Finalized DataVolume.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name),
+			"vm",
+			vm.String())
+	}
+	return
+}
+
+// Whether a DataVolume populated by a previous migration may be reused.
+// The last checkpoint must have been copied from a known snapshot.
+func (r *Migration) reusable(dv *cdi.DataVolume, known map[string]bool) bool {
+	if dv.Spec.FinalCheckpoint || dv.Status.Phase != cdi.Paused {
+		return false
+	}
+	if !known[dv.Annotations[AnnSyncSnapshot]] {
+		return false
+	}
+	copied, err := r.kubevirt.checkpointCopied(dv)
+	if err != nil {
+		r.Log.Error(
+			err,
+			"Could not determine whether the checkpoint has been copied.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name))
+		return false
+	}
+	return copied
+}
+
+// Drop the delta sync of a VM no longer delta synchronized.
+// The snapshots are removed and the preserved disks deleted.
+func (r *Migration) dropDeltaSync(vm *plan.VMStatus) (err error) {
+	err = r.kubevirt.DeletePreviousDataVolumes(vm)
+	if err != nil {
+		return
+	}
+	err = r.provider.RemoveSnapshots(vm.Ref, vm.DeltaSync.Precopies)
+	if err != nil {
+		return
+	}
+	vm.DeltaSync = nil
+	return
+}
+
+// DataVolumes populated by the previous migrations of the VM.
+func (r *KubeVirt) previousDataVolumes(vm *plan.VMStatus) (dvs []cdi.DataVolume, err error) {
+	list := &cdi.DataVolumeList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: labels.SelectorFromSet(r.vmAllButMigrationLabels(vm.Ref)),
+			Namespace:     r.Plan.Spec.TargetNamespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	migration := string(r.Migration.UID)
+	for i := range list.Items {
+		dv := &list.Items[i]
+		if dv.Labels[kMigration] != migration {
+			dvs = append(dvs, *dv)
+		}
+	}
+	return
+}
+
+// Delete the DataVolumes populated by the previous migrations of the VM.
+func (r *KubeVirt) DeletePreviousDataVolumes(vm *plan.VMStatus) (err error) {
+	dvs, err := r.previousDataVolumes(vm)
+	if err != nil {
+		return
+	}
+	for i := range dvs {
+		err = r.deleteDataVolume(vm, &dvs[i])
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Delete a DataVolume.
+func (r *KubeVirt) deleteDataVolume(vm *plan.VMStatus, dv *cdi.DataVolume) (err error) {
+	err = r.Destination.Client.Delete(context.TODO(), dv)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	r.Log.Info(
+		"Deleted DataVolume.",
+		"dv",
+		path.Join(
+			dv.Namespace,
+			dv.Name),
+		"vm",
+		vm.String())
+	return
+}
+
+// Whether the last checkpoint of the DataVolume has been copied.
+func (r *KubeVirt) checkpointCopied(dv *cdi.DataVolume) (copied bool, err error) {
+	n := len(dv.Spec.Checkpoints)
+	if n == 0 {
+		copied = true
+		return
+	}
+	pvc := &core.PersistentVolumeClaim{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Namespace: dv.Namespace,
+			Name:      dv.Name,
+		},
+		pvc)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	_, copied = pvc.Annotations[AnnCheckpointsCopied+"."+dv.Spec.Checkpoints[n-1].Current]
+	return
+}
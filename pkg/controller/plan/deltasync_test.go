@@ -0,0 +1,344 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Builder resolving the DataVolumes by disk.
+type deltaSyncBuilder struct {
+	adapter.Builder
+}
+
+func (r *deltaSyncBuilder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
+	return dv.Annotations[planbase.AnnDiskSource]
+}
+
+// Provider client recording the checkpoints.
+type deltaSyncClient struct {
+	adapter.Client
+	// Checkpointed DataVolumes keyed by the previous snapshot.
+	checkpoints map[string][]string
+}
+
+func (r *deltaSyncClient) SetCheckpoints(
+	vmRef ref.Ref, precopies []plan.Precopy, dvs []cdi.DataVolume, final bool) (err error) {
+	//
+	previous := ""
+	current := precopies[len(precopies)-1].Snapshot
+	if len(precopies) > 1 {
+		previous = precopies[len(precopies)-2].Snapshot
+	}
+	for i := range dvs {
+		dv := &dvs[i]
+		dv.Spec.Checkpoints = append(
+			dv.Spec.Checkpoints,
+			cdi.DataVolumeCheckpoint{Previous: previous, Current: current})
+		dv.Spec.FinalCheckpoint = final
+		r.checkpoints[previous] = append(r.checkpoints[previous], dv.Name)
+	}
+	return
+}
+
+func deltaSyncMigration(objects ...client.Object) (migration *Migration, provider *deltaSyncClient) {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
+	ovirt := api.OVirt
+	host := api.OpenShift
+	ctx := &plancontext.Context{
+		Plan: &api.Plan{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "test",
+				UID:  "plan",
+			},
+			Spec: api.PlanSpec{
+				TargetNamespace: "test",
+				DeltaSync:       true,
+			},
+		},
+		Migration: &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "test",
+				UID:  "m2",
+			},
+		},
+		Log: logging.WithName("test"),
+	}
+	ctx.Plan.Referenced.Provider.Source = &api.Provider{
+		Spec: api.ProviderSpec{Type: &ovirt},
+	}
+	ctx.Plan.Referenced.Provider.Destination = &api.Provider{
+		Spec: api.ProviderSpec{Type: &host},
+	}
+	ctx.Destination.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		Build()
+	builder := &deltaSyncBuilder{}
+	provider = &deltaSyncClient{checkpoints: make(map[string][]string)}
+	migration = &Migration{
+		Context:  ctx,
+		builder:  builder,
+		provider: provider,
+		kubevirt: KubeVirt{
+			Context: ctx,
+			Builder: builder,
+		},
+	}
+	return
+}
+
+func deltaSyncDataVolume(name, migration, disk, snapshot string, phase cdi.DataVolumePhase) *cdi.DataVolume {
+	return &cdi.DataVolume{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Labels: map[string]string{
+				kMigration: migration,
+				kPlan:      "plan",
+				kVM:        "vm-1",
+			},
+			Annotations: map[string]string{
+				planbase.AnnDiskSource: disk,
+				AnnSyncSnapshot:        snapshot,
+			},
+		},
+		Status: cdi.DataVolumeStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func deltaSyncVM(snapshots ...string) (vm *plan.VMStatus) {
+	vm = &plan.VMStatus{
+		VM: plan.VM{
+			Ref: ref.Ref{ID: "vm-1"},
+		},
+		DeltaSync: &plan.DeltaSync{},
+	}
+	for _, snapshot := range snapshots {
+		vm.DeltaSync.Precopies = append(
+			vm.DeltaSync.Precopies,
+			plan.Precopy{Snapshot: snapshot})
+	}
+	return
+}
+
+func TestDeltaSyncReusable(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	known := map[string]bool{"s1": true}
+	// Checkpoint copied.
+	copied := deltaSyncDataVolume("copied", "m1", "a", "s1", cdi.Paused)
+	copied.Spec.Checkpoints = []cdi.DataVolumeCheckpoint{{Current: "s1"}}
+	copiedPVC := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "copied",
+			Namespace: "test",
+			Annotations: map[string]string{
+				AnnCheckpointsCopied + ".s1": "s1",
+			},
+		},
+	}
+	// Checkpoint not copied.
+	pending := deltaSyncDataVolume("pending", "m1", "b", "s1", cdi.Paused)
+	pending.Spec.Checkpoints = []cdi.DataVolumeCheckpoint{{Current: "s1"}}
+	pendingPVC := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "pending",
+			Namespace: "test",
+		},
+	}
+	migration, _ := deltaSyncMigration(copiedPVC, pendingPVC)
+	g.Expect(migration.reusable(copied, known)).To(gomega.BeTrue())
+	g.Expect(migration.reusable(pending, known)).To(gomega.BeFalse())
+	// No checkpoint.
+	dv := deltaSyncDataVolume("dv", "m1", "c", "s1", cdi.Paused)
+	g.Expect(migration.reusable(dv, known)).To(gomega.BeTrue())
+	// Unknown snapshot.
+	dv = deltaSyncDataVolume("dv", "m1", "c", "s0", cdi.Paused)
+	g.Expect(migration.reusable(dv, known)).To(gomega.BeFalse())
+	// Finalized.
+	dv = deltaSyncDataVolume("dv", "m1", "c", "s1", cdi.Paused)
+	dv.Spec.FinalCheckpoint = true
+	g.Expect(migration.reusable(dv, known)).To(gomega.BeFalse())
+	// Not paused.
+	dv = deltaSyncDataVolume("dv", "m1", "c", "s1", cdi.Succeeded)
+	g.Expect(migration.reusable(dv, known)).To(gomega.BeFalse())
+	dv = deltaSyncDataVolume("dv", "m1", "c", "s1", cdi.Failed)
+	g.Expect(migration.reusable(dv, known)).To(gomega.BeFalse())
+}
+
+func TestSyncDataVolumes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, provider := deltaSyncMigration(
+		// Reused: synchronized from s1.
+		deltaSyncDataVolume("a", "m1", "disk-a", "s1", cdi.Paused),
+		deltaSyncDataVolume("a-duplicate", "m1", "disk-a", "s1", cdi.Paused),
+		// Reused: synchronized from s2.
+		deltaSyncDataVolume("b", "m0", "disk-b", "s2", cdi.Paused),
+		// Stale: synchronized from an unknown snapshot.
+		deltaSyncDataVolume("c", "m1", "disk-c", "s0", cdi.Paused),
+		// Stale: the disk is no longer found on the source VM.
+		deltaSyncDataVolume("d", "m1", "disk-d", "s2", cdi.Paused),
+		// Another VM.
+		&cdi.DataVolume{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      "other",
+				Namespace: "test",
+				Labels: map[string]string{
+					kMigration: "m1",
+					kPlan:      "plan",
+					kVM:        "vm-2",
+				},
+			},
+		})
+	vm := deltaSyncVM("s1", "s2", "s3")
+	dataVolumes := []cdi.DataVolume{
+		*deltaSyncDataVolume("new-a", "m2", "disk-a", "", ""),
+		*deltaSyncDataVolume("new-b", "m2", "disk-b", "", ""),
+		*deltaSyncDataVolume("new-c", "m2", "disk-c", "", ""),
+	}
+	err := migration.syncDataVolumes(vm, dataVolumes)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.DeltaSync.Reused).To(gomega.Equal(2))
+	// Checkpoints grouped by the snapshot synchronized from.
+	g.Expect(provider.checkpoints).To(gomega.HaveLen(3))
+	g.Expect(provider.checkpoints["s1"]).To(gomega.HaveLen(1))
+	g.Expect(provider.checkpoints["s2"]).To(gomega.ConsistOf("b"))
+	g.Expect(provider.checkpoints[""]).To(gomega.ConsistOf("new-c"))
+	// The stale and duplicate DataVolumes are deleted.
+	list := &cdi.DataVolumeList{}
+	err = migration.Destination.Client.List(context.TODO(), list)
+	g.Expect(err).To(gomega.BeNil())
+	names := []string{}
+	disks := map[string]string{}
+	for _, dv := range list.Items {
+		names = append(names, dv.Name)
+		if dv.Labels[kVM] != "vm-1" {
+			continue
+		}
+		disks[dv.Annotations[planbase.AnnDiskSource]] = dv.Name
+		g.Expect(dv.Labels[kMigration]).To(gomega.Equal("m2"))
+		g.Expect(dv.Annotations[AnnSyncSnapshot]).To(gomega.Equal("s3"))
+		g.Expect(dv.Spec.FinalCheckpoint).To(gomega.BeFalse())
+		g.Expect(dv.Spec.Checkpoints).ToNot(gomega.BeEmpty())
+	}
+	g.Expect(names).To(gomega.HaveLen(4))
+	g.Expect(names).To(gomega.ContainElements("b", "new-c", "other"))
+	g.Expect(names).ToNot(gomega.ContainElements("c", "d", "new-a", "new-b"))
+	g.Expect(disks).To(gomega.HaveLen(3))
+	g.Expect(disks["disk-a"]).To(gomega.Equal(provider.checkpoints["s1"][0]))
+}
+
+func TestSyncDataVolumesEnsured(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Already ensured by the current migration.
+	migration, provider := deltaSyncMigration(
+		deltaSyncDataVolume("a", "m2", "disk-a", "s2", cdi.ImportInProgress),
+		deltaSyncDataVolume("a-previous", "m1", "disk-a", "s1", cdi.Paused))
+	vm := deltaSyncVM("s1", "s2")
+	dataVolumes := []cdi.DataVolume{
+		*deltaSyncDataVolume("new-a", "m2", "disk-a", "", ""),
+	}
+	err := migration.syncDataVolumes(vm, dataVolumes)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.DeltaSync.Reused).To(gomega.Equal(0))
+	g.Expect(provider.checkpoints).To(gomega.BeEmpty())
+	list := &cdi.DataVolumeList{}
+	err = migration.Destination.Client.List(context.TODO(), list)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list.Items).To(gomega.HaveLen(1))
+	g.Expect(list.Items[0].Name).To(gomega.Equal("a"))
+}
+
+func TestFinalizeDataVolumes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, _ := deltaSyncMigration(
+		deltaSyncDataVolume("a", "m2", "disk-a", "s2", cdi.Paused),
+		deltaSyncDataVolume("b", "m2", "disk-b", "s2", cdi.Paused),
+		deltaSyncDataVolume("previous", "m1", "disk-c", "s1", cdi.Paused))
+	vm := deltaSyncVM("s1", "s2")
+	err := migration.finalizeDataVolumes(vm)
+	g.Expect(err).To(gomega.BeNil())
+	for _, name := range []string{"a", "b"} {
+		dv := &cdi.DataVolume{}
+		err = migration.Destination.Client.Get(
+			context.TODO(),
+			types.NamespacedName{Namespace: "test", Name: name},
+			dv)
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(dv.Spec.FinalCheckpoint).To(gomega.BeTrue())
+	}
+	// Not finalized: populated by a previous migration.
+	dv := &cdi.DataVolume{}
+	err = migration.Destination.Client.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: "test", Name: "previous"},
+		dv)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(dv.Spec.FinalCheckpoint).To(gomega.BeFalse())
+}
+
+func TestSyncDataVolumesAfterConversionFailure(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// The DataVolumes are finalized after the guest conversion.
+	index := map[string]int{}
+	for i, step := range coldItinerary.Pipeline {
+		index[step.Name] = i
+	}
+	g.Expect(index[AddFinalCheckpoint]).To(gomega.BeNumerically(">", index[ConvertGuest]))
+	g.Expect(index[Finalize]).To(gomega.BeNumerically(">", index[ConvertGuest]))
+	// The DataVolumes left by a migration failed converting the guest.
+	objects := []client.Object{}
+	for _, disk := range []string{"disk-a", "disk-b"} {
+		dv := deltaSyncDataVolume(disk, "m1", disk, "s1", cdi.Paused)
+		dv.Spec.Checkpoints = []cdi.DataVolumeCheckpoint{{Current: "s1"}}
+		pvc := &core.PersistentVolumeClaim{
+			ObjectMeta: meta.ObjectMeta{
+				Name:      disk,
+				Namespace: "test",
+				Annotations: map[string]string{
+					AnnCheckpointsCopied + ".s1": "s1",
+				},
+			},
+		}
+		objects = append(objects, dv, pvc)
+	}
+	migration, provider := deltaSyncMigration(objects...)
+	vm := deltaSyncVM("s1", "s2")
+	dataVolumes := []cdi.DataVolume{
+		*deltaSyncDataVolume("new-a", "m2", "disk-a", "", ""),
+		*deltaSyncDataVolume("new-b", "m2", "disk-b", "", ""),
+	}
+	err := migration.syncDataVolumes(vm, dataVolumes)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.DeltaSync.Reused).To(gomega.Equal(2))
+	g.Expect(provider.checkpoints).To(gomega.HaveLen(1))
+	g.Expect(provider.checkpoints["s1"]).To(gomega.ConsistOf("disk-a", "disk-b"))
+	list := &cdi.DataVolumeList{}
+	err = migration.Destination.Client.List(context.TODO(), list)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list.Items).To(gomega.HaveLen(2))
+	for _, dv := range list.Items {
+		g.Expect(dv.Labels[kMigration]).To(gomega.Equal("m2"))
+		g.Expect(dv.Annotations[AnnSyncSnapshot]).To(gomega.Equal("s2"))
+		g.Expect(dv.Spec.FinalCheckpoint).To(gomega.BeFalse())
+	}
+}
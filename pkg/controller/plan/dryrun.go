@@ -0,0 +1,701 @@
+package plan
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apiserver/pkg/storage/names"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	k8sutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+// Dry run conditions.
+const (
+	MacConflicts         = "MacConflicts"
+	StorageClassNotFound = "StorageClassNotFound"
+	TargetNameConflict   = "TargetNameConflict"
+	DisksNotRendered     = "DisksNotRendered"
+)
+
+// Replaces the secret data in the rendered manifests.
+const Redacted = "<redacted>"
+
+// Max size of the manifest of a VM.
+// The size limit of a ConfigMap.
+const MaxManifestSize = 1024 * 1024
+
+// Run the pending dry runs of the plan.
+// The dry runs are run on their own, regardless of whether the plan
+// is blocked, and a dry run that cannot be run is marked failed on
+// the migration status. The objects that would be created for each
+// VM are rendered into a ConfigMap per VM referenced by the migration
+// status and the problems detected are reported as VM conditions.
+func (r *Reconciler) dryRun(plan *api.Plan) {
+	list := &api.MigrationList{}
+	err := r.List(context.TODO(), list)
+	if err != nil {
+		r.Log.Error(err, "Listing the dry runs failed.")
+		return
+	}
+	for i := range list.Items {
+		migration := &list.Items[i]
+		if !migration.Spec.DryRun || !migration.Match(plan) {
+			continue
+		}
+		if migration.Status.MarkedCompleted() {
+			continue
+		}
+		err = r.runDryRun(plan, migration)
+		if err != nil {
+			r.Log.Error(
+				err,
+				"Dry run failed.",
+				"migration",
+				path.Join(
+					migration.Namespace,
+					migration.Name))
+		}
+	}
+}
+
+// Run a dry run and update the migration status.
+func (r *Reconciler) runDryRun(plan *api.Plan, migration *api.Migration) (err error) {
+	r.Log.Info(
+		"Dry run [STARTED]",
+		"migration",
+		path.Join(
+			migration.Namespace,
+			migration.Name))
+	migration.Status.MarkStarted()
+	rErr := r.renderDryRun(plan, migration)
+	if rErr != nil {
+		migration.Status.SetCondition(libcnd.Condition{
+			Type:     Failed,
+			Status:   True,
+			Category: Advisory,
+			Message:  "The dry run has FAILED.",
+			Items:    []string{rErr.Error()},
+			Durable:  true,
+		})
+	} else {
+		failed := false
+		for _, vm := range migration.Status.VMs {
+			if vm.Error != nil {
+				failed = true
+			}
+		}
+		if failed {
+			migration.Status.SetCondition(libcnd.Condition{
+				Type:     Failed,
+				Status:   True,
+				Category: Advisory,
+				Message:  "The dry run has FAILED.",
+				Durable:  true,
+			})
+		} else {
+			migration.Status.SetCondition(libcnd.Condition{
+				Type:     Succeeded,
+				Status:   True,
+				Category: Advisory,
+				Message:  "The dry run has SUCCEEDED.",
+				Durable:  true,
+			})
+		}
+	}
+	migration.Status.MarkCompleted()
+	err = r.Status().Update(context.TODO(), migration)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Dry run [COMPLETED]",
+		"migration",
+		path.Join(
+			migration.Namespace,
+			migration.Name),
+		"error",
+		rErr)
+
+	return
+}
+
+// Render the manifests of the dry run VMs into
+// a ConfigMap per VM.
+func (r *Reconciler) renderDryRun(plan *api.Plan, migration *api.Migration) (err error) {
+	ctx, err := plancontext.New(r, plan, r.Log)
+	if err != nil {
+		return
+	}
+	ctx.SetMigration(migration)
+	runner := DryRun{Context: ctx}
+	manifests, err := runner.Run()
+	if err != nil {
+		return
+	}
+	migration.Status.Manifests = []core.ObjectReference{}
+	for i, vm := range runner.VMs {
+		manifest, found := manifests[vm.ID]
+		if !found {
+			continue
+		}
+		var configMap *core.ConfigMap
+		configMap, err = r.ensureManifest(
+			migration,
+			r.manifestName(migration, i),
+			map[string]string{runner.key(vm): manifest})
+		if err != nil {
+			return
+		}
+		migration.Status.Manifests = append(
+			migration.Status.Manifests,
+			core.ObjectReference{
+				Kind:      "ConfigMap",
+				Namespace: configMap.Namespace,
+				Name:      configMap.Name,
+			})
+	}
+	migration.Status.VMs = runner.VMs
+
+	return
+}
+
+// Name of the ConfigMap holding the manifest of the VM
+// at the specified index.
+func (r *Reconciler) manifestName(migration *api.Migration, index int) (name string) {
+	suffix := fmt.Sprintf("-dry-run-%d", index)
+	name = migration.Name
+	if len(name)+len(suffix) > validation.DNS1123SubdomainMaxLength {
+		name = strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.")
+	}
+	name += suffix
+	return
+}
+
+// Ensure the ConfigMap holding a rendered manifest.
+func (r *Reconciler) ensureManifest(migration *api.Migration, name string, data map[string]string) (configMap *core.ConfigMap, err error) {
+	configMap = &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: migration.Namespace,
+			Name:      name,
+		},
+		configMap)
+	if err == nil {
+		configMap.Data = data
+		err = r.Update(context.TODO(), configMap)
+		if err != nil {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	configMap = &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: migration.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				kMigration: string(migration.UID),
+			},
+		},
+		Data: data,
+	}
+	err = k8sutil.SetOwnerReference(migration, configMap, r.Scheme())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.Create(context.TODO(), configMap)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+// Dry run.
+// Renders the objects that would be created on the destination
+// for each VM without migrating. The clients are wrapped so that
+// nothing the builders may create is persisted.
+type DryRun struct {
+	*plancontext.Context
+	// VM status.
+	VMs []*plan.VMStatus
+	// Builder
+	builder adapter.Builder
+	// kubevirt.
+	kubevirt KubeVirt
+	// Storage classes found on the destination.
+	storageClasses map[string]bool
+	// MAC addresses in use on the destination. k=mac, v=vm.
+	macs map[string]string
+	// Target VM names. k=name, v=source VM.
+	names map[string]string
+}
+
+// Run the dry run.
+// Returns the manifests keyed by VM ID.
+func (r *DryRun) Run() (manifests map[string]string, err error) {
+	err = r.init()
+	if err != nil {
+		return
+	}
+	manifests = make(map[string]string)
+	r.VMs = []*plan.VMStatus{}
+	for _, vm := range r.Plan.Spec.VMs {
+		status := &plan.VMStatus{VM: vm}
+		status.MarkStarted()
+		manifest, rErr := r.render(status)
+		if rErr == nil && len(manifest) > MaxManifestSize {
+			rErr = liberr.New(
+				fmt.Sprintf(
+					"The manifest exceeds the max size of %d bytes.",
+					MaxManifestSize))
+		}
+		if rErr != nil {
+			status.AddError(rErr.Error())
+			r.Log.Error(
+				rErr,
+				"Dry run failed.",
+				"vm",
+				status.String())
+		} else {
+			manifests[status.ID] = manifest
+		}
+		status.MarkCompleted()
+		r.VMs = append(r.VMs, status)
+	}
+
+	return
+}
+
+// Initialize.
+func (r *DryRun) init() (err error) {
+	r.Client = client.NewDryRunClient(r.Client)
+	r.Destination.Client = client.NewDryRunClient(r.Destination.Client)
+	if r.builder == nil {
+		var pAdapter adapter.Adapter
+		pAdapter, err = adapter.New(r.Source.Provider)
+		if err != nil {
+			return
+		}
+		r.builder, err = pAdapter.Builder(r.Context)
+		if err != nil {
+			return
+		}
+	}
+	r.kubevirt = KubeVirt{
+		Context: r.Context,
+		Builder: r.builder,
+	}
+	r.names = make(map[string]string)
+
+	return
+}
+
+// Render the manifest of a VM.
+func (r *DryRun) render(vm *plan.VMStatus) (manifest string, err error) {
+	_, err = r.Source.Inventory.VM(&vm.Ref)
+	if err != nil {
+		return
+	}
+	var secret *core.Secret
+	var configMap *core.ConfigMap
+	dataVolumes := []cdi.DataVolume{}
+	pvcs := []core.PersistentVolumeClaim{}
+	// The transfer objects of an OpenShift source are
+	// built from the VM export created by the migration.
+	exported := r.Plan.IsSourceProviderOCP()
+	if !exported {
+		secret, err = r.kubevirt.secret(vm.Ref, r.kubevirt.secretDataSetterForCDI(vm.Ref))
+		if err != nil {
+			return
+		}
+		r.setName(&secret.ObjectMeta)
+		configMap, err = r.kubevirt.configMap(vm.Ref)
+		if err != nil {
+			return
+		}
+		r.setName(&configMap.ObjectMeta)
+	}
+	switch {
+	case exported:
+		vm.SetCondition(libcnd.Condition{
+			Type:     DisksNotRendered,
+			Status:   True,
+			Category: Advisory,
+			Reason:   NotSupported,
+			Message:  "The disks are exported from the source cluster by the migration and are not rendered.",
+		})
+	case r.builder.SupportsVolumePopulators():
+		vm.SetCondition(libcnd.Condition{
+			Type:     DisksNotRendered,
+			Status:   True,
+			Category: Advisory,
+			Reason:   NotSupported,
+			Message:  "The disks are populated by volume populators and are not rendered.",
+		})
+	default:
+		dataVolumes, err = r.kubevirt.dataVolumes(vm, secret, configMap)
+		if err != nil {
+			return
+		}
+		for i := range dataVolumes {
+			dv := &dataVolumes[i]
+			r.setName(&dv.ObjectMeta)
+			pvcs = append(pvcs, r.claim(dv))
+		}
+		err = r.validateStorageClasses(vm, dataVolumes)
+		if err != nil {
+			return
+		}
+	}
+	lunPVs, err := r.builder.LunPersistentVolumes(vm.Ref)
+	if err != nil {
+		return
+	}
+	lunPVCs, err := r.builder.LunPersistentVolumeClaims(vm.Ref)
+	if err != nil {
+		return
+	}
+	pvcs = append(pvcs, lunPVCs...)
+	virtualMachine, err := r.kubevirt.buildVirtualMachine(vm, pvcs)
+	if err != nil {
+		return
+	}
+	err = r.validateName(vm, virtualMachine)
+	if err != nil {
+		return
+	}
+	err = r.validateMacs(vm, virtualMachine)
+	if err != nil {
+		return
+	}
+	var pod *core.Pod
+	if r.Source.Provider.RequiresConversion() {
+		domain := r.kubevirt.libvirtDomain(&VirtualMachine{VirtualMachine: virtualMachine}, &pvcs)
+		var domainXML []byte
+		domainXML, err = xml.Marshal(domain)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		configMap.BinaryData["input.xml"] = domainXML
+		pod, err = r.kubevirt.guestConversionPod(
+			vm,
+			virtualMachine.Spec.Template.Spec.Volumes,
+			configMap,
+			&pvcs,
+			secret)
+		if err != nil {
+			return
+		}
+		r.setName(&pod.ObjectMeta)
+	}
+	//
+	// Manifest.
+	documents := []string{}
+	add := func(object runtime.Object, gvk schema.GroupVersionKind) (err error) {
+		object.GetObjectKind().SetGroupVersionKind(gvk)
+		b, err := yaml.Marshal(object)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		documents = append(documents, string(b))
+		return
+	}
+	if secret != nil {
+		r.redact(secret)
+		err = add(secret, core.SchemeGroupVersion.WithKind("Secret"))
+		if err != nil {
+			return
+		}
+	}
+	if configMap != nil {
+		err = add(configMap, core.SchemeGroupVersion.WithKind("ConfigMap"))
+		if err != nil {
+			return
+		}
+	}
+	for i := range dataVolumes {
+		err = add(&dataVolumes[i], cdi.SchemeGroupVersion.WithKind("DataVolume"))
+		if err != nil {
+			return
+		}
+	}
+	for i := range lunPVs {
+		err = add(&lunPVs[i], core.SchemeGroupVersion.WithKind("PersistentVolume"))
+		if err != nil {
+			return
+		}
+	}
+	for i := range lunPVCs {
+		err = add(&lunPVCs[i], core.SchemeGroupVersion.WithKind("PersistentVolumeClaim"))
+		if err != nil {
+			return
+		}
+	}
+	err = add(virtualMachine, cnv.SchemeGroupVersion.WithKind("VirtualMachine"))
+	if err != nil {
+		return
+	}
+	if pod != nil {
+		err = add(pod, core.SchemeGroupVersion.WithKind("Pod"))
+		if err != nil {
+			return
+		}
+	}
+	for _, ref := range vm.Hooks {
+		hook, found := r.FindHook(ref.Hook)
+		if !found {
+			continue
+		}
+		runner := HookRunner{
+			Context: r.Context,
+			vm:      &plan.VMStatus{VM: vm.VM, Phase: ref.Step},
+			hook:    hook,
+		}
+		var mp *core.ConfigMap
+		mp, err = runner.configMap()
+		if err != nil {
+			return
+		}
+		r.setName(&mp.ObjectMeta)
+		var job *batch.Job
+		job, err = runner.job(mp)
+		if err != nil {
+			return
+		}
+		r.setName(&job.ObjectMeta)
+		err = add(mp, core.SchemeGroupVersion.WithKind("ConfigMap"))
+		if err != nil {
+			return
+		}
+		err = add(job, batch.SchemeGroupVersion.WithKind("Job"))
+		if err != nil {
+			return
+		}
+	}
+	manifest = strings.Join(documents, "---\n")
+
+	return
+}
+
+// Validate the storage classes of the DataVolumes
+// exist on the destination.
+func (r *DryRun) validateStorageClasses(vm *plan.VMStatus, dataVolumes []cdi.DataVolume) (err error) {
+	if r.storageClasses == nil {
+		list := []ocp.StorageClass{}
+		err = r.Destination.Inventory.List(&list)
+		if err != nil {
+			return
+		}
+		r.storageClasses = make(map[string]bool)
+		for _, storageClass := range list {
+			r.storageClasses[storageClass.Name] = true
+		}
+	}
+	missing := []string{}
+	for i := range dataVolumes {
+		dv := &dataVolumes[i]
+		var name *string
+		if dv.Spec.Storage != nil {
+			name = dv.Spec.Storage.StorageClassName
+		} else if dv.Spec.PVC != nil {
+			name = dv.Spec.PVC.StorageClassName
+		}
+		if name == nil || *name == "" || r.storageClasses[*name] {
+			continue
+		}
+		missing = append(missing, *name)
+	}
+	if len(missing) > 0 {
+		vm.SetCondition(libcnd.Condition{
+			Type:     StorageClassNotFound,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotFound,
+			Message:  "Storage classes not found on the destination.",
+			Items:    missing,
+		})
+	}
+
+	return
+}
+
+// Validate the target VM name is not already in use
+// on the destination or by another VM of the plan.
+func (r *DryRun) validateName(vm *plan.VMStatus, virtualMachine *cnv.VirtualMachine) (err error) {
+	if other, found := r.names[virtualMachine.Name]; found {
+		vm.SetCondition(libcnd.Condition{
+			Type:     TargetNameConflict,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotUnique,
+			Message:  "The target name is already used by another VM of the plan.",
+			Items:    []string{other},
+		})
+	} else {
+		r.names[virtualMachine.Name] = vm.String()
+	}
+	existing := &cnv.VirtualMachine{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: virtualMachine.Namespace,
+			Name:      virtualMachine.Name,
+		},
+		existing)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	if existing.Labels[kPlan] != string(r.Plan.UID) {
+		vm.SetCondition(libcnd.Condition{
+			Type:     VMAlreadyExists,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotUnique,
+			Message:  "A VM with the target name already exists on the destination.",
+			Items:    []string{path.Join(existing.Namespace, existing.Name)},
+		})
+	}
+
+	return
+}
+
+// Validate the MAC addresses of the target VM
+// are not in use on the destination.
+func (r *DryRun) validateMacs(vm *plan.VMStatus, virtualMachine *cnv.VirtualMachine) (err error) {
+	if virtualMachine.Spec.Template == nil {
+		return
+	}
+	if r.macs == nil {
+		list := []ocp.VM{}
+		err = r.Destination.Inventory.List(&list, base.Param{
+			Key:   base.DetailParam,
+			Value: "all",
+		})
+		if err != nil {
+			return
+		}
+		r.macs = make(map[string]string)
+		for _, kVM := range list {
+			for _, iface := range kVM.Object.Spec.Template.Spec.Domain.Devices.Interfaces {
+				if iface.MacAddress != "" {
+					r.macs[iface.MacAddress] = path.Join(kVM.Namespace, kVM.Name)
+				}
+			}
+		}
+	}
+	target := path.Join(virtualMachine.Namespace, virtualMachine.Name)
+	conflicts := []string{}
+	for _, iface := range virtualMachine.Spec.Template.Spec.Domain.Devices.Interfaces {
+		if other, found := r.macs[iface.MacAddress]; found && other != target {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", iface.MacAddress, other))
+		}
+	}
+	if len(conflicts) > 0 {
+		vm.SetCondition(libcnd.Condition{
+			Type:     MacConflicts,
+			Status:   True,
+			Category: Critical,
+			Reason:   NotUnique,
+			Message:  "MAC addresses already in use on the destination.",
+			Items:    conflicts,
+		})
+	}
+
+	return
+}
+
+// Build the PVC a DataVolume would populate.
+func (r *DryRun) claim(dv *cdi.DataVolume) (pvc core.PersistentVolumeClaim) {
+	pvc = core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:   dv.Namespace,
+			Name:        dv.Name,
+			Labels:      dv.Labels,
+			Annotations: dv.Annotations,
+		},
+	}
+	if dv.Spec.PVC != nil {
+		pvc.Spec = *dv.Spec.PVC.DeepCopy()
+	} else if dv.Spec.Storage != nil {
+		storage := dv.Spec.Storage
+		pvc.Spec.AccessModes = storage.AccessModes
+		pvc.Spec.Resources = storage.Resources
+		pvc.Spec.StorageClassName = storage.StorageClassName
+		pvc.Spec.VolumeMode = storage.VolumeMode
+	}
+
+	return
+}
+
+// Name an object the way the API server would.
+func (r *DryRun) setName(object *meta.ObjectMeta) {
+	if object.Name == "" && object.GenerateName != "" {
+		object.Name = names.SimpleNameGenerator.GenerateName(object.GenerateName)
+	}
+}
+
+// Redact the secret data.
+func (r *DryRun) redact(secret *core.Secret) {
+	redacted := make(map[string]string)
+	for key := range secret.Data {
+		redacted[key] = Redacted
+	}
+	for key := range secret.StringData {
+		redacted[key] = Redacted
+	}
+	secret.Data = nil
+	secret.StringData = redacted
+}
+
+// ConfigMap key of a VM manifest.
+func (r *DryRun) key(vm *plan.VMStatus) string {
+	key := strings.Map(
+		func(c rune) rune {
+			switch {
+			case c >= 'a' && c <= 'z',
+				c >= 'A' && c <= 'Z',
+				c >= '0' && c <= '9',
+				c == '-', c == '_', c == '.':
+				return c
+			}
+			return '-'
+		},
+		vm.ID)
+	return key + ".yaml"
+}
@@ -0,0 +1,328 @@
+package plan
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	webbase "github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Builder creating the DataVolumes on the destination
+// the way the provider builders may.
+type dryRunBuilder struct {
+	adapter.Builder
+	ctx *plancontext.Context
+}
+
+func (r *dryRunBuilder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
+	object.StringData = map[string]string{"password": "secret"}
+	return
+}
+
+func (r *dryRunBuilder) ConfigMap(vmRef ref.Ref, secret *core.Secret, object *core.ConfigMap) (err error) {
+	return
+}
+
+func (r *dryRunBuilder) DataVolumes(
+	vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume) (dvs []cdi.DataVolume, err error) {
+	//
+	storageClass := "fast"
+	dv := dvTemplate.DeepCopy()
+	dv.Spec.Storage = &cdi.StorageSpec{
+		AccessModes:      []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+		StorageClassName: &storageClass,
+	}
+	err = r.ctx.Destination.Client.Create(context.TODO(), dv)
+	if err != nil {
+		return
+	}
+	dvs = append(dvs, *dv)
+	return
+}
+
+func (r *dryRunBuilder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
+	err = liberr.New("no template")
+	return
+}
+
+func (r *dryRunBuilder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec, pvcs []core.PersistentVolumeClaim) (err error) {
+	object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
+	for _, pvc := range pvcs {
+		object.Template.Spec.Volumes = append(
+			object.Template.Spec.Volumes,
+			cnv.Volume{
+				Name: pvc.Name,
+				VolumeSource: cnv.VolumeSource{
+					PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+						PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+							ClaimName: pvc.Name,
+						},
+					},
+				},
+			})
+	}
+	return
+}
+
+func (r *dryRunBuilder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
+	return
+}
+
+func (r *dryRunBuilder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	return
+}
+
+func (r *dryRunBuilder) SupportsVolumePopulators() bool {
+	return false
+}
+
+// Inventory of the source and destination providers.
+type dryRunInventory struct {
+	web.Client
+}
+
+func (r *dryRunInventory) VM(ref *webbase.Ref) (object interface{}, err error) {
+	return
+}
+
+func (r *dryRunInventory) List(list interface{}, param ...webbase.Param) (err error) {
+	switch list := list.(type) {
+	case *[]ocp.StorageClass:
+		*list = []ocp.StorageClass{{Resource: ocp.Resource{Name: "fast"}}}
+	}
+	return
+}
+
+func dryRunContext(objects ...client.Object) (ctx *plancontext.Context) {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
+	_ = cnv.AddToScheme(scheme)
+	ovirt := api.OVirt
+	host := api.OpenShift
+	ctx = &plancontext.Context{
+		Plan: &api.Plan{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "test",
+				UID:  "plan",
+			},
+			Spec: api.PlanSpec{
+				TargetNamespace: "target",
+				VMs: []plan.VM{
+					{Ref: ref.Ref{ID: "vm-1", Name: "vm-1"}},
+				},
+			},
+		},
+		Migration: &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "test",
+				UID:  "migration",
+			},
+		},
+		Log: logging.WithName("test"),
+	}
+	ctx.Plan.Referenced.Provider.Source = &api.Provider{
+		Spec: api.ProviderSpec{Type: &ovirt},
+	}
+	ctx.Plan.Referenced.Provider.Destination = &api.Provider{
+		Spec: api.ProviderSpec{Type: &host},
+	}
+	ctx.Source.Provider = ctx.Plan.Referenced.Provider.Source
+	ctx.Source.Inventory = &dryRunInventory{}
+	ctx.Source.Secret = &core.Secret{}
+	ctx.Destination.Provider = ctx.Plan.Referenced.Provider.Destination
+	ctx.Destination.Inventory = &dryRunInventory{}
+	ctx.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+	ctx.Destination.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		Build()
+	return
+}
+
+func TestDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	dryRun := DryRun{}
+
+	//Test the manifest key of a VM
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "namespace/vm-1"}}}
+	g.Expect(dryRun.key(vm)).To(gomega.Equal("namespace-vm-1.yaml"))
+
+	//Test the secret data is redacted
+	secret := &core.Secret{
+		Data:       map[string][]byte{"password": []byte("secret")},
+		StringData: map[string]string{"user": "admin"},
+	}
+	dryRun.redact(secret)
+	g.Expect(secret.Data).To(gomega.BeNil())
+	g.Expect(secret.StringData).To(gomega.Equal(map[string]string{
+		"password": Redacted,
+		"user":     Redacted,
+	}))
+
+	//Test the generated name
+	object := &meta.ObjectMeta{GenerateName: "plan-vm-1-"}
+	dryRun.setName(object)
+	g.Expect(object.Name).To(gomega.HavePrefix("plan-vm-1-"))
+	g.Expect(len(object.Name)).To(gomega.BeNumerically(">", len(object.GenerateName)))
+
+	//Test the claim of a DataVolume
+	storageClass := "fast"
+	dv := &cdi.DataVolume{
+		ObjectMeta: meta.ObjectMeta{Name: "plan-vm-1-abcde", Namespace: "target"},
+		Spec: cdi.DataVolumeSpec{
+			Storage: &cdi.StorageSpec{
+				AccessModes:      []core.PersistentVolumeAccessMode{core.ReadWriteOnce},
+				StorageClassName: &storageClass,
+			},
+		},
+	}
+	pvc := dryRun.claim(dv)
+	g.Expect(pvc.Name).To(gomega.Equal(dv.Name))
+	g.Expect(*pvc.Spec.StorageClassName).To(gomega.Equal(storageClass))
+	g.Expect(pvc.Spec.AccessModes).To(gomega.ConsistOf(core.ReadWriteOnce))
+}
+
+func TestDryRunRender(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	existing := &cnv.VirtualMachine{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "other",
+			Namespace: "target",
+		},
+	}
+	ctx := dryRunContext(existing)
+	destination := ctx.Destination.Client
+	dryRun := DryRun{
+		Context: ctx,
+		builder: &dryRunBuilder{ctx: ctx},
+	}
+	manifests, err := dryRun.Run()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(dryRun.VMs).To(gomega.HaveLen(1))
+	g.Expect(dryRun.VMs[0].Error).To(gomega.BeNil())
+	g.Expect(dryRun.VMs[0].HasAnyCondition(StorageClassNotFound, TargetNameConflict, VMAlreadyExists)).To(gomega.BeFalse())
+
+	//Test the manifest of the VM is rendered
+	manifest, found := manifests["vm-1"]
+	g.Expect(found).To(gomega.BeTrue())
+	for _, kind := range []string{"Secret", "ConfigMap", "DataVolume", "VirtualMachine"} {
+		g.Expect(manifest).To(gomega.ContainSubstring("kind: " + kind + "\n"))
+	}
+	g.Expect(manifest).To(gomega.ContainSubstring(Redacted))
+	g.Expect(manifest).ToNot(gomega.ContainSubstring("password: secret"))
+	g.Expect(strings.Count(manifest, "---\n")).To(gomega.Equal(3))
+
+	//Test nothing has been created on the destination
+	dvs := &cdi.DataVolumeList{}
+	g.Expect(destination.List(context.TODO(), dvs)).To(gomega.Succeed())
+	g.Expect(dvs.Items).To(gomega.BeEmpty())
+	secrets := &core.SecretList{}
+	g.Expect(destination.List(context.TODO(), secrets)).To(gomega.Succeed())
+	g.Expect(secrets.Items).To(gomega.BeEmpty())
+	configMaps := &core.ConfigMapList{}
+	g.Expect(destination.List(context.TODO(), configMaps)).To(gomega.Succeed())
+	g.Expect(configMaps.Items).To(gomega.BeEmpty())
+	pvcs := &core.PersistentVolumeClaimList{}
+	g.Expect(destination.List(context.TODO(), pvcs)).To(gomega.Succeed())
+	g.Expect(pvcs.Items).To(gomega.BeEmpty())
+	vms := &cnv.VirtualMachineList{}
+	g.Expect(destination.List(context.TODO(), vms)).To(gomega.Succeed())
+	g.Expect(vms.Items).To(gomega.HaveLen(1))
+	g.Expect(vms.Items[0].Name).To(gomega.Equal("other"))
+	g.Expect(vms.Items[0].ResourceVersion).To(gomega.Equal(existing.ResourceVersion))
+}
+
+func TestDryRunRenderOCP(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ctx := dryRunContext()
+	ocpType := api.OpenShift
+	ctx.Plan.Referenced.Provider.Source.Spec.Type = &ocpType
+	dryRun := DryRun{
+		Context: ctx,
+		builder: &dryRunBuilder{ctx: ctx},
+	}
+	manifests, err := dryRun.Run()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(dryRun.VMs[0].Error).To(gomega.BeNil())
+	g.Expect(dryRun.VMs[0].HasCondition(DisksNotRendered)).To(gomega.BeTrue())
+	manifest := manifests["vm-1"]
+	g.Expect(manifest).To(gomega.ContainSubstring("kind: VirtualMachine\n"))
+	g.Expect(manifest).ToNot(gomega.ContainSubstring("kind: DataVolume\n"))
+	g.Expect(manifest).ToNot(gomega.ContainSubstring("kind: Secret\n"))
+}
+
+func TestDryRunManifests(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "test",
+			Namespace: "konveyor-forklift",
+			UID:       "migration",
+		},
+		Spec: api.MigrationSpec{DryRun: true},
+	}
+	r := Reconciler{}
+	r.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(migration).
+		WithStatusSubresource(migration).
+		Build()
+	r.Log = logging.WithName("test")
+
+	//Test the ConfigMap names
+	g.Expect(r.manifestName(migration, 1)).To(gomega.Equal("test-dry-run-1"))
+	long := &api.Migration{ObjectMeta: meta.ObjectMeta{Name: strings.Repeat("a", 253)}}
+	g.Expect(r.manifestName(long, 12)).To(gomega.HaveLen(253))
+	g.Expect(r.manifestName(long, 12)).To(gomega.HaveSuffix("-dry-run-12"))
+
+	//Test a ConfigMap per VM
+	for i, key := range []string{"vm-1.yaml", "vm-2.yaml"} {
+		_, err := r.ensureManifest(migration, r.manifestName(migration, i), map[string]string{key: "kind: VirtualMachine"})
+		g.Expect(err).To(gomega.BeNil())
+	}
+	configMap, err := r.ensureManifest(migration, r.manifestName(migration, 0), map[string]string{"vm-1.yaml": "updated"})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(configMap.Data).To(gomega.Equal(map[string]string{"vm-1.yaml": "updated"}))
+	configMaps := &core.ConfigMapList{}
+	g.Expect(r.List(context.TODO(), configMaps)).To(gomega.Succeed())
+	g.Expect(configMaps.Items).To(gomega.HaveLen(2))
+	for _, configMap := range configMaps.Items {
+		g.Expect(configMap.Labels[kMigration]).To(gomega.Equal("migration"))
+		g.Expect(configMap.OwnerReferences).To(gomega.HaveLen(1))
+	}
+
+	//Test the failure of a dry run is recorded on the migration
+	err = r.runDryRun(&api.Plan{}, migration)
+	g.Expect(err).To(gomega.BeNil())
+	latest := &api.Migration{}
+	g.Expect(r.Get(context.TODO(), client.ObjectKeyFromObject(migration), latest)).To(gomega.Succeed())
+	g.Expect(latest.Status.MarkedCompleted()).To(gomega.BeTrue())
+	condition := latest.Status.FindCondition(Failed)
+	g.Expect(condition).ToNot(gomega.BeNil())
+	g.Expect(condition.Items).To(gomega.HaveLen(1))
+	g.Expect(latest.Status.HasCondition(Succeeded)).To(gomega.BeFalse())
+}
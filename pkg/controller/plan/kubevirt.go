@@ -29,11 +29,14 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	ovacontainer "github.com/konveyor/forklift-controller/pkg/controller/provider/container/ova"
+	populator_machinery "github.com/konveyor/forklift-controller/pkg/lib-volume-populator/populator-machinery"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	core "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -59,6 +62,12 @@ const (
 	AnnDeleteAfterCompletion = "cdi.kubevirt.io/storage.deleteAfterCompletion"
 	// DV immediate bind to WaitForFirstConsumer storage class
 	AnnBindImmediate = "cdi.kubevirt.io/storage.bind.immediate.requested"
+	// UIDs of the plans using the clone of the OVA store (value=comma separated UIDs).
+	AnnOvaStoreUsers = "forklift.konveyor.io/ova-store-users"
+	// Epoch of the OVA server index when the OVA store was cloned.
+	AnnOvaStoreEpoch = "forklift.konveyor.io/ova-store-epoch"
+	// Revision of the OVA server index when the OVA store was cloned.
+	AnnOvaStoreRevision = "forklift.konveyor.io/ova-store-revision"
 	// Max Length for vm name
 	NameMaxLength  = 63
 	VddkVolumeName = "vddk-vol-mount"
@@ -74,6 +83,8 @@ const (
 	kVM = "vmID"
 	// App label
 	kApp = "forklift.app"
+	// OVA store clone label (value=provider UID)
+	kOvaStore = "ova-store"
 )
 
 // User
@@ -87,11 +98,19 @@ const (
 // Map of VirtualMachines keyed by vmID.
 type VirtualMachineMap map[string]VirtualMachine
 
+// Index of the OVA files mirrored by the OVA server.
+type OvaIndex interface {
+	// Changes made in the index since the revision of the epoch.
+	Changes(epoch string, since int64) (*ovacontainer.ChangeList, error)
+}
+
 // Represents kubevirt.
 type KubeVirt struct {
 	*plancontext.Context
 	// Builder
 	Builder adapter.Builder
+	// Index of the mirrored OVA files.
+	OvaIndex OvaIndex
 }
 
 // Build a VirtualMachineMap.
@@ -235,6 +254,21 @@ func (r *KubeVirt) DeleteDataVolumes(vm *plan.VMStatus) (err error) {
 	return
 }
 
+// Delete the PersistentVolumeClaims associated with the VM.
+func (r *KubeVirt) DeletePVCs(vm *plan.VMStatus) (err error) {
+	pvcs, err := r.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	for i := range pvcs {
+		err = r.DeleteObject(&pvcs[i], vm, "Deleted PVC.", "pvc")
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
 // Delete the importer pod for a PersistentVolumeClaim.
 func (r *KubeVirt) DeleteImporterPod(pvc core.PersistentVolumeClaim) (err error) {
 	var pod *core.Pod
@@ -562,6 +596,77 @@ func (r *KubeVirt) getDVs(vm *plan.VMStatus) (edvs []ExtendedDataVolume, err err
 	return
 }
 
+// Get the failure reported by the volume populator (CR) of the PVC.
+// The populator controller records the failure once the
+// population retries are exhausted.
+func (r *KubeVirt) getPopulatorFailure(pvc *core.PersistentVolumeClaim) (failure string, err error) {
+	populator, err := r.getPopulator(pvc)
+	if err != nil || populator == nil {
+		return
+	}
+	failure = populator_machinery.Failure(populator)
+	return
+}
+
+// Reset the failures reported by the volume populators (CRs)
+// of the VM PVCs so that the populations are retried.
+func (r *KubeVirt) ResetPopulatorFailures(vm *plan.VMStatus) (err error) {
+	pvcs, err := r.getPVCs(vm.Ref)
+	if err != nil {
+		return
+	}
+	for i := range pvcs {
+		pvc := &pvcs[i]
+		var populator *unstructured.Unstructured
+		populator, err = r.getPopulator(pvc)
+		if err != nil {
+			return
+		}
+		if populator == nil || populator_machinery.Failure(populator) == "" {
+			continue
+		}
+		populator_machinery.ResetFailure(populator)
+		err = r.Destination.Client.Update(context.TODO(), populator)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Reset the volume populator failure.",
+			"populator",
+			path.Join(
+				populator.GetNamespace(),
+				populator.GetName()),
+			"vm",
+			vm.String())
+	}
+	return
+}
+
+// Get the volume populator (CR) of the PVC.
+// Returns nil when the PVC is not populated by a forklift populator.
+func (r *KubeVirt) getPopulator(pvc *core.PersistentVolumeClaim) (populator *unstructured.Unstructured, err error) {
+	dsRef := pvc.Spec.DataSourceRef
+	if dsRef == nil || dsRef.APIGroup == nil || *dsRef.APIGroup != api.SchemeGroupVersion.Group {
+		return
+	}
+	populator = &unstructured.Unstructured{}
+	populator.SetGroupVersionKind(api.SchemeGroupVersion.WithKind(dsRef.Kind))
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: pvc.Namespace, Name: dsRef.Name},
+		populator)
+	if err != nil {
+		populator = nil
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+	return
+}
+
 // Return PersistentVolumeClaims associated with a VM.
 func (r *KubeVirt) getPVCs(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
 	pvcsList := &core.PersistentVolumeClaimList{}
@@ -742,6 +847,357 @@ func (r *KubeVirt) EnsureGuestConversionPod(vm *plan.VMStatus, vmCr *VirtualMach
 	return
 }
 
+// Ensure the store of the OVA files mirrored from an http or s3
+// source is available in the target namespace. The store of the
+// provider cannot be mounted across namespaces so it is cloned into
+// the target namespace. The revision of the OVA server index is noted
+// on the clone when it is created. A clone is shared by the plans
+// migrating from the provider as long as the VMs of the plan have not
+// changed in the index since that revision, otherwise a new clone is
+// created. The plans are recorded as users of the clone, which is
+// deleted when the last user releases it.
+func (r *KubeVirt) EnsureOvaStore() (ready bool, err error) {
+	provider := r.Source.Provider
+	if !provider.OvaMirrored() || provider.Namespace == r.Plan.Spec.TargetNamespace {
+		ready = true
+		return
+	}
+	dv, err := r.ovaStore()
+	if err != nil {
+		return
+	}
+	if dv == nil {
+		dv, err = r.joinOvaStore()
+		if err != nil || dv == nil {
+			return
+		}
+	}
+	switch dv.Status.Phase {
+	case cdi.Succeeded:
+		ready = true
+	case cdi.Failed:
+		err = liberr.New(
+			"OVA store clone failed.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name))
+	}
+
+	return
+}
+
+// Find the clone of the OVA store used by the plan.
+// Returns nil when the plan does not use a clone.
+func (r *KubeVirt) ovaStore() (dv *cdi.DataVolume, err error) {
+	list, err := r.ovaStores()
+	if err != nil {
+		return
+	}
+	for i := range list {
+		if getOvaStoreUsers(&list[i]).has(string(r.Plan.UID)) {
+			dv = &list[i]
+			return
+		}
+	}
+	return
+}
+
+// List the clones of the OVA store in the target namespace.
+func (r *KubeVirt) ovaStores() (list []cdi.DataVolume, err error) {
+	dvList := &cdi.DataVolumeList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		dvList,
+		&client.ListOptions{
+			LabelSelector: labels.SelectorFromSet(
+				map[string]string{
+					kOvaStore: string(r.Source.Provider.UID),
+				}),
+			Namespace: r.Plan.Spec.TargetNamespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	list = dvList.Items
+	return
+}
+
+// Join a clone of the OVA store that holds the current OVA
+// files of the VMs. A new clone is created when none does.
+// Returns the joined clone or nil when created.
+func (r *KubeVirt) joinOvaStore() (dv *cdi.DataVolume, err error) {
+	index, err := r.ovaIndex()
+	if err != nil {
+		return
+	}
+	list, err := r.ovaStores()
+	if err != nil {
+		return
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return ovaStoreRevision(&list[i]) > ovaStoreRevision(&list[j])
+	})
+	for i := range list {
+		current := false
+		current, err = r.ovaStoreCurrent(index, &list[i])
+		if err != nil {
+			return
+		}
+		if !current {
+			continue
+		}
+		dv = &list[i]
+		users := append(getOvaStoreUsers(dv), string(r.Plan.UID))
+		users.set(dv)
+		err = r.Destination.Client.Update(context.TODO(), dv)
+		if err != nil {
+			err = liberr.Wrap(err)
+			dv = nil
+			return
+		}
+		r.Log.Info(
+			"Joined OVA store clone.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name))
+		return
+	}
+	err = r.createOvaStore(index)
+	return
+}
+
+// The VMs have not changed in the index since the clone was created.
+func (r *KubeVirt) ovaStoreCurrent(index OvaIndex, dv *cdi.DataVolume) (current bool, err error) {
+	changes, err := index.Changes(dv.Annotations[AnnOvaStoreEpoch], ovaStoreRevision(dv))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if changes.Reset {
+		return
+	}
+	vms := make(map[string]bool)
+	for _, vm := range r.Plan.Spec.VMs {
+		vms[vm.ID] = true
+	}
+	for _, change := range changes.Changes {
+		if change.Kind == ovacontainer.VMKind && vms[change.ID] {
+			return
+		}
+	}
+	current = true
+	return
+}
+
+// Create a clone of the OVA store sized as the store.
+// The revision of the index is noted before the store is
+// cloned so the clone holds at least the noted revision.
+func (r *KubeVirt) createOvaStore(index OvaIndex) (err error) {
+	provider := r.Source.Provider
+	changes, err := index.Changes("", 0)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	store := &core.PersistentVolumeClaim{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: provider.Namespace,
+			Name:      provider.OvaStoreName(),
+		},
+		store)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	dv := &cdi.DataVolume{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:    r.Plan.Spec.TargetNamespace,
+			GenerateName: provider.OvaStoreName() + "-",
+			Labels: map[string]string{
+				kOvaStore: string(provider.UID),
+			},
+			Annotations: map[string]string{
+				AnnOvaStoreEpoch:    changes.Epoch,
+				AnnOvaStoreRevision: strconv.FormatInt(changes.Revision, 10),
+			},
+		},
+		Spec: cdi.DataVolumeSpec{
+			Source: &cdi.DataVolumeSource{
+				PVC: &cdi.DataVolumeSourcePVC{
+					Namespace: provider.Namespace,
+					Name:      provider.OvaStoreName(),
+				},
+			},
+			Storage: &cdi.StorageSpec{
+				AccessModes: []core.PersistentVolumeAccessMode{
+					core.ReadWriteMany,
+				},
+				Resources: core.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceStorage: store.Spec.Resources.Requests[core.ResourceStorage],
+					},
+				},
+			},
+		},
+	}
+	ovaStoreUsers{string(r.Plan.UID)}.set(dv)
+	err = r.Destination.Client.Create(context.TODO(), dv)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.Log.Info(
+		"Created OVA store clone.",
+		"dv",
+		path.Join(
+			dv.Namespace,
+			dv.Name),
+		"revision",
+		changes.Revision)
+
+	return
+}
+
+// Release the clones of the OVA store used by the plan.
+// The plan is removed from the users of the clones and
+// the clones are deleted once they have no users.
+func (r *KubeVirt) ReleaseOvaStore() (err error) {
+	provider := r.Source.Provider
+	if !provider.OvaMirrored() || provider.Namespace == r.Plan.Spec.TargetNamespace {
+		return
+	}
+	list, err := r.ovaStores()
+	if err != nil {
+		return
+	}
+	for i := range list {
+		dv := &list[i]
+		users := ovaStoreUsers{}
+		found := false
+		for _, uid := range getOvaStoreUsers(dv) {
+			if uid != string(r.Plan.UID) {
+				users = append(users, uid)
+			} else {
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		if len(users) > 0 {
+			users.set(dv)
+			err = r.Destination.Client.Update(context.TODO(), dv)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			continue
+		}
+		err = r.Destination.Client.Delete(
+			context.TODO(),
+			dv,
+			&client.DeleteOptions{
+				Preconditions: &meta.Preconditions{
+					ResourceVersion: &dv.ResourceVersion,
+				},
+			})
+		if err != nil {
+			if k8serr.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		r.Log.Info(
+			"Deleted OVA store clone.",
+			"dv",
+			path.Join(
+				dv.Namespace,
+				dv.Name))
+	}
+
+	return
+}
+
+// Name of the OVA store mounted by the conversion pod.
+func (r *KubeVirt) ovaStoreName() (name string, err error) {
+	provider := r.Source.Provider
+	if provider.Namespace == r.Plan.Spec.TargetNamespace {
+		name = provider.OvaStoreName()
+		return
+	}
+	dv, err := r.ovaStore()
+	if err != nil {
+		return
+	}
+	if dv == nil {
+		err = liberr.New(
+			"OVA store clone not found.",
+			"namespace",
+			r.Plan.Spec.TargetNamespace)
+		return
+	}
+	name = dv.Name
+	return
+}
+
+// Index of the mirrored OVA files.
+// The OVA server of the provider is used by default.
+func (r *KubeVirt) ovaIndex() (index OvaIndex, err error) {
+	if r.OvaIndex == nil {
+		client := &ovacontainer.Client{}
+		err = client.Connect(r.Source.Provider)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.OvaIndex = client
+	}
+	index = r.OvaIndex
+	return
+}
+
+// Revision of the index noted on the clone.
+func ovaStoreRevision(dv *cdi.DataVolume) (revision int64) {
+	revision, _ = strconv.ParseInt(dv.Annotations[AnnOvaStoreRevision], 10, 64)
+	return
+}
+
+// UIDs of the plans using the clone of the OVA store.
+type ovaStoreUsers []string
+
+// Get the users recorded on the clone.
+func getOvaStoreUsers(dv *cdi.DataVolume) (users ovaStoreUsers) {
+	if value, found := dv.Annotations[AnnOvaStoreUsers]; found && value != "" {
+		users = strings.Split(value, ",")
+	}
+	return
+}
+
+// Record the users on the clone.
+func (r ovaStoreUsers) set(dv *cdi.DataVolume) {
+	if dv.Annotations == nil {
+		dv.Annotations = make(map[string]string)
+	}
+	dv.Annotations[AnnOvaStoreUsers] = strings.Join(r, ",")
+}
+
+// The plan is a user of the clone.
+func (r ovaStoreUsers) has(uid string) bool {
+	for _, user := range r {
+		if user == uid {
+			return true
+		}
+	}
+	return false
+}
+
 // Get the guest conversion pod for the VM.
 func (r *KubeVirt) GetGuestConversionPod(vm *plan.VMStatus) (pod *core.Pod, err error) {
 	list := &core.PodList{}
@@ -972,7 +1428,12 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus) (object *cnv.VirtualMachine
 		err = liberr.Wrap(err)
 		return
 	}
+	object, err = r.buildVirtualMachine(vm, pvcs)
+	return
+}
 
+// Build the Kubevirt VM CR with the specified PVCs.
+func (r *KubeVirt) buildVirtualMachine(vm *plan.VMStatus, pvcs []core.PersistentVolumeClaim) (object *cnv.VirtualMachine, err error) {
 	//If the VM name is not valid according to DNS1123 labeling
 	//convention it will be automatically changed.
 	var originalName string
@@ -1184,7 +1645,10 @@ func (r *KubeVirt) findTemplate(vm *plan.VMStatus) (tmpl *template.Template, err
 }
 
 func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume, configMap *core.ConfigMap, pvcs *[]core.PersistentVolumeClaim, v2vSecret *core.Secret) (pod *core.Pod, err error) {
-	volumes, volumeMounts, volumeDevices := r.podVolumeMounts(vmVolumes, configMap, pvcs)
+	volumes, volumeMounts, volumeDevices, err := r.podVolumeMounts(vmVolumes, configMap, pvcs)
+	if err != nil {
+		return
+	}
 
 	// qemu group
 	fsGroup := qemuGroup
@@ -1290,7 +1754,7 @@ func (r *KubeVirt) guestConversionPod(vm *plan.VMStatus, vmVolumes []cnv.Volume,
 	return
 }
 
-func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, configMap *core.ConfigMap, pvcs *[]core.PersistentVolumeClaim) (volumes []core.Volume, mounts []core.VolumeMount, devices []core.VolumeDevice) {
+func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, configMap *core.ConfigMap, pvcs *[]core.PersistentVolumeClaim) (volumes []core.Volume, mounts []core.VolumeMount, devices []core.VolumeDevice, err error) {
 	pvcsByName := make(map[string]core.PersistentVolumeClaim)
 	for _, pvc := range *pvcs {
 		pvcsByName[pvc.Name] = pvc
@@ -1336,26 +1800,46 @@ func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, configMap *core.Confi
 
 	switch r.Source.Provider.Type() {
 	case api.Ova:
-		server := r.Source.Provider.Spec.URL
-		splitted := strings.Split(server, ":")
-
-		if len(splitted) != 2 {
-			r.Log.Info("The NFS server path format is wrong")
-			return
-		}
-		nfsServer := splitted[0]
-		nfsPath := splitted[1]
+		if r.Source.Provider.OvaMirrored() {
+			// The OVA files mirrored (by the OVA server) from
+			// an http or s3 source. The store (PVC) is shared
+			// or cloned into the target namespace.
+			var claimName string
+			claimName, err = r.ovaStoreName()
+			if err != nil {
+				return
+			}
+			volumes = append(volumes, core.Volume{
+				Name: "nfs",
+				VolumeSource: core.VolumeSource{
+					PersistentVolumeClaim: &core.PersistentVolumeClaimVolumeSource{
+						ClaimName: claimName,
+						ReadOnly:  true,
+					},
+				},
+			})
+		} else {
+			server := r.Source.Provider.Spec.URL
+			splitted := strings.Split(server, ":")
 
-		//path from disk
-		volumes = append(volumes, core.Volume{
-			Name: "nfs",
-			VolumeSource: core.VolumeSource{
-				NFS: &core.NFSVolumeSource{
-					Server: nfsServer,
-					Path:   nfsPath,
+			if len(splitted) != 2 {
+				r.Log.Info("The NFS server path format is wrong")
+				return
+			}
+			nfsServer := splitted[0]
+			nfsPath := splitted[1]
+
+			//path from disk
+			volumes = append(volumes, core.Volume{
+				Name: "nfs",
+				VolumeSource: core.VolumeSource{
+					NFS: &core.NFSVolumeSource{
+						Server: nfsServer,
+						Path:   nfsPath,
+					},
 				},
-			},
-		})
+			})
+		}
 		mounts = append(mounts,
 			core.VolumeMount{
 				Name:      "libvirt-domain-xml",
@@ -0,0 +1,176 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	ovacontainer "github.com/konveyor/forklift-controller/pkg/controller/provider/container/ova"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// OVA server index.
+type ovaIndex struct {
+	epoch   string
+	journal []ovacontainer.Change
+}
+
+func (r *ovaIndex) Changes(epoch string, since int64) (list *ovacontainer.ChangeList, err error) {
+	list = &ovacontainer.ChangeList{
+		Epoch:    r.epoch,
+		Revision: int64(len(r.journal)),
+	}
+	if epoch != r.epoch {
+		list.Reset = true
+		return
+	}
+	list.Changes = r.journal[since:]
+	return
+}
+
+// Index a VM.
+func (r *ovaIndex) add(id string) {
+	r.journal = append(
+		r.journal,
+		ovacontainer.Change{
+			Revision: int64(len(r.journal) + 1),
+			Kind:     ovacontainer.VMKind,
+			Action:   "added",
+			ID:       id,
+		})
+}
+
+func ovaStoreKubeVirt(client client.Client, index OvaIndex, provider *api.Provider, uid types.UID, vms ...string) *KubeVirt {
+	ctx := &plancontext.Context{
+		Plan: &api.Plan{
+			ObjectMeta: meta.ObjectMeta{
+				Name: string(uid),
+				UID:  uid,
+			},
+			Spec: api.PlanSpec{
+				TargetNamespace: "test",
+			},
+		},
+		Migration: &api.Migration{},
+		Log:       logging.WithName("test"),
+	}
+	for _, id := range vms {
+		ctx.Plan.Spec.VMs = append(ctx.Plan.Spec.VMs, plan.VM{Ref: ref.Ref{ID: id}})
+	}
+	ctx.Source.Provider = provider
+	ctx.Destination.Client = client
+	return &KubeVirt{Context: ctx, OvaIndex: index}
+}
+
+func TestOvaStore(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ova := api.Ova
+	provider := &api.Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "ova",
+			Namespace: "forklift",
+			UID:       "provider",
+		},
+		Spec: api.ProviderSpec{
+			Type: &ova,
+			Settings: map[string]string{
+				api.OvaSource: api.OvaSourceHTTP,
+			},
+		},
+	}
+	store := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      provider.OvaStoreName(),
+			Namespace: provider.Namespace,
+		},
+		Spec: core.PersistentVolumeClaimSpec{
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: resource.MustParse("50Gi"),
+				},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(store).
+		Build()
+	index := &ovaIndex{epoch: "1"}
+	index.add("vm-1")
+	index.add("vm-2")
+	a := ovaStoreKubeVirt(client, index, provider, "a", "vm-1")
+	b := ovaStoreKubeVirt(client, index, provider, "b", "vm-2")
+	c := ovaStoreKubeVirt(client, index, provider, "c", "vm-1", "vm-3")
+	succeeded := func(name string) {
+		dv := &cdi.DataVolume{}
+		err := client.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: name}, dv)
+		g.Expect(err).To(gomega.BeNil())
+		dv.Status.Phase = cdi.Succeeded
+		err = client.Update(context.TODO(), dv)
+		g.Expect(err).To(gomega.BeNil())
+	}
+	// A clone sized as the store noting the index revision.
+	ready, err := a.EnsureOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ready).To(gomega.BeFalse())
+	dv, err := a.ovaStore()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(dv).ToNot(gomega.BeNil())
+	g.Expect(dv.Spec.Source.PVC.Name).To(gomega.Equal(provider.OvaStoreName()))
+	g.Expect(dv.Spec.Storage.Resources.Requests.Storage().String()).To(gomega.Equal("50Gi"))
+	g.Expect(dv.Annotations[AnnOvaStoreEpoch]).To(gomega.Equal("1"))
+	g.Expect(dv.Annotations[AnnOvaStoreRevision]).To(gomega.Equal("2"))
+	first := dv.Name
+	succeeded(first)
+	ready, err = a.EnsureOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ready).To(gomega.BeTrue())
+	// Shared while the VMs did not change since the revision.
+	index.add("vm-3")
+	ready, err = b.EnsureOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ready).To(gomega.BeTrue())
+	name, err := b.ovaStoreName()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(name).To(gomega.Equal(first))
+	// Cloned again for a VM indexed after the revision.
+	ready, err = c.EnsureOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(ready).To(gomega.BeFalse())
+	name, err = c.ovaStoreName()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(name).ToNot(gomega.Equal(first))
+	dv, _ = c.ovaStore()
+	g.Expect(dv.Annotations[AnnOvaStoreRevision]).To(gomega.Equal("3"))
+	g.Expect(getOvaStoreUsers(dv)).To(gomega.Equal(ovaStoreUsers{"c"}))
+	// Deleted when released by the last plan.
+	err = a.ReleaseOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	list, err := a.ovaStores()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list).To(gomega.HaveLen(2))
+	err = b.ReleaseOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	err = c.ReleaseOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+	list, err = a.ovaStores()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(list).To(gomega.BeEmpty())
+	err = c.ReleaseOvaStore()
+	g.Expect(err).To(gomega.BeNil())
+}
@@ -2,6 +2,7 @@ package plan
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -40,6 +41,8 @@ var (
 	RequiresConversion libitr.Flag = 0x04
 	CDIDiskCopy        libitr.Flag = 0x08
 	VirtV2vDiskCopy    libitr.Flag = 0x10
+	DeltaSync          libitr.Flag = 0x20
+	Verify             libitr.Flag = 0x40
 )
 
 // Phases.
@@ -68,6 +71,9 @@ const (
 	WaitForSnapshot          = "WaitForSnapshot"
 	WaitForInitialSnapshot   = "WaitForInitialSnapshot"
 	WaitForFinalSnapshot     = "WaitForFinalSnapshot"
+	CreateSyncSnapshot       = "CreateSyncSnapshot"
+	WaitForSyncSnapshot      = "WaitForSyncSnapshot"
+	VerifyVM                 = "VerifyVM"
 )
 
 // Steps.
@@ -79,9 +85,21 @@ const (
 	ImageConversion = "ImageConversion"
 	DiskTransferV2v = "DiskTransferV2v"
 	VMCreation      = "VirtualMachineCreation"
+	Verification    = "Verification"
+	Rollback        = "Rollback"
 	Unknown         = "Unknown"
 )
 
+// Rollback tasks.
+const (
+	DeleteTargetVM    = "DeleteVM"
+	DeleteDisks       = "DeleteDisks"
+	DeletePods        = "DeletePods"
+	DeletePopulators  = "DeletePopulators"
+	RemoveSnapshots   = "RemoveSnapshots"
+	RestorePowerState = "RestorePowerState"
+)
+
 // Power states.
 const (
 	On = "On"
@@ -100,14 +118,19 @@ var (
 			{Name: StorePowerState},
 			{Name: PowerOffSource},
 			{Name: WaitForPowerOff},
+			{Name: CreateSyncSnapshot, All: DeltaSync},
+			{Name: WaitForSyncSnapshot, All: DeltaSync},
 			{Name: CreateDataVolumes},
 			{Name: CopyDisks, All: CDIDiskCopy},
 			{Name: AllocateDisks, All: VirtV2vDiskCopy},
 			{Name: CreateGuestConversionPod, All: RequiresConversion},
 			{Name: ConvertGuest, All: RequiresConversion},
 			{Name: CopyDisksVirtV2V, All: RequiresConversion},
+			{Name: AddFinalCheckpoint, All: DeltaSync},
+			{Name: Finalize, All: DeltaSync},
 			{Name: CreateVM},
 			{Name: PostHook, All: HasPostHook},
+			{Name: VerifyVM, All: Verify},
 			{Name: Completed},
 		},
 	}
@@ -135,6 +158,7 @@ var (
 			{Name: ConvertGuest, All: RequiresConversion},
 			{Name: CreateVM},
 			{Name: PostHook, All: HasPostHook},
+			{Name: VerifyVM, All: Verify},
 			{Name: Completed},
 		},
 	}
@@ -190,16 +214,20 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 		}
 	}
 
-	vm, hasNext, err := r.scheduler.Next()
-	if err != nil {
-		return
-	}
-	if hasNext {
-		err = r.execute(vm)
-		if err != nil {
+	if r.startable() {
+		vm, hasNext, nErr := r.scheduler.Next()
+		if nErr != nil {
+			err = nErr
 			return
 		}
+		if hasNext {
+			err = r.execute(vm)
+			if err != nil {
+				return
+			}
+		}
 	}
+	r.reflectWaiting()
 
 	completed, err := r.end()
 	if completed {
@@ -280,12 +308,15 @@ func (r *Migration) begin() (err error) {
 	r.Plan.Status.Migration.VMs = kept
 	//
 	// Add/Update.
+	r.resolveRetryRefs()
+	retrying := len(r.Migration.Spec.Retry) > 0
 	list := []*plan.VMStatus{}
 	for _, vm := range r.Plan.Spec.VMs {
 		var status *plan.VMStatus
-		r.itinerary().Predicate = &Predicate{vm: &vm, context: r.Context}
+		r.itinerary().Predicate = &Predicate{vm: &vm, context: r.Context, builder: r.builder}
 		step, _ := r.itinerary().First()
-		if current, found := r.Plan.Status.Migration.FindVM(vm.Ref); !found {
+		current, found := r.Plan.Status.Migration.FindVM(vm.Ref)
+		if !found {
 			status = &plan.VMStatus{VM: vm}
 			if r.Plan.Spec.Warm {
 				status.Warm = &plan.Warm{}
@@ -293,13 +324,37 @@ func (r *Migration) begin() (err error) {
 		} else {
 			status = current
 		}
-		if status.Phase != Completed || status.HasAnyCondition(Canceled, Failed) {
+		if found && retrying {
+			if r.Migration.Spec.RetryRequested(status.Ref) && status.HasCondition(Failed) {
+				var resumed bool
+				resumed, err = r.resume(status)
+				if err != nil {
+					return
+				}
+				if resumed {
+					err = r.snapshotConcerns(status)
+					if err != nil {
+						return
+					}
+					list = append(list, status)
+					continue
+				}
+			} else {
+				log.Info(
+					"Pipeline preserved.",
+					"vm",
+					vm.String())
+				list = append(list, status)
+				continue
+			}
+		}
+		if status.Phase != Completed || status.HasAnyCondition(Canceled, Failed, RolledBack) {
 			pipeline, pErr := r.buildPipeline(&vm)
 			if pErr != nil {
 				err = liberr.Wrap(pErr)
 				return
 			}
-			status.DeleteCondition(Canceled, Failed)
+			status.DeleteCondition(Canceled, Failed, RolledBack)
 			status.MarkReset()
 			status.Pipeline = pipeline
 			status.Phase = step.Name
@@ -307,6 +362,10 @@ func (r *Migration) begin() (err error) {
 			if r.Plan.Spec.Warm {
 				status.Warm = &plan.Warm{}
 			}
+			err = r.snapshotConcerns(status)
+			if err != nil {
+				return
+			}
 			log.Info(
 				"Pipeline reset.",
 				"vm",
@@ -327,6 +386,160 @@ func (r *Migration) begin() (err error) {
 	return
 }
 
+// Snapshot the concerns reported by the inventory for the VM
+// so that the report reflects the VM as it was when migrated.
+func (r *Migration) snapshotConcerns(vm *plan.VMStatus) (err error) {
+	object, err := r.Source.Inventory.VM(&vm.Ref)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	// The VM resources of all the providers
+	// render the concerns the same way.
+	content, err := json.Marshal(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	resource := struct {
+		Concerns []plan.Concern `json:"concerns"`
+	}{}
+	err = json.Unmarshal(content, &resource)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	vm.Concerns = resource.Concerns
+
+	return
+}
+
+// Resume a failed VM from the phase that failed.
+// The pipeline history is preserved, the failed attempt is
+// recorded and only the failed steps are reset so that the
+// populated disks are reused. The guest conversion and the
+// hooks are re-run from the beginning.
+// Returns false when the VM cannot be resumed.
+func (r *Migration) resume(vm *plan.VMStatus) (resumed bool, err error) {
+	if vm.Error == nil {
+		return
+	}
+	r.itinerary().Predicate = &Predicate{vm: &vm.VM, context: r.Context, builder: r.builder}
+	phase := vm.Error.Phase
+	if _, gErr := r.itinerary().Get(phase); gErr != nil || phase == Completed {
+		return
+	}
+	switch phase {
+	case ConvertGuest, CopyDisksVirtV2V:
+		err = r.kubevirt.DeleteGuestConversionPod(vm)
+		if err != nil {
+			return
+		}
+		phase = CreateGuestConversionPod
+	case PreHook, PostHook:
+		err = r.kubevirt.DeleteHookJobs(vm)
+		if err != nil {
+			return
+		}
+	}
+	vm.Attempts = append(
+		vm.Attempts,
+		plan.Attempt{
+			Timed: plan.Timed{
+				Started:   vm.Started,
+				Completed: vm.Completed,
+			},
+			Phase: vm.Error.Phase,
+			Error: vm.Error,
+		})
+	for _, step := range vm.Pipeline {
+		if step.Error == nil {
+			continue
+		}
+		step.Error = nil
+		step.Completed = nil
+		step.Phase = Running
+		for _, task := range step.Tasks {
+			if task.Error != nil {
+				task.Error = nil
+				task.Completed = nil
+			}
+		}
+	}
+	vm.DeleteCondition(Failed)
+	vm.Error = nil
+	vm.Completed = nil
+	vm.Phase = phase
+	resumed = true
+	log.Info(
+		"Pipeline resumed.",
+		"vm",
+		vm.String(),
+		"phase",
+		phase)
+
+	return
+}
+
+// Determine whether VMs may be started.
+// The VMs are held at Started until the start time of the migration.
+// Cold migrations cut the VMs over when started so they are also
+// held until a cutover window opens. The waiting VMs are flagged.
+func (r *Migration) startable() (startable bool) {
+	now := time.Now()
+	waiting := libcnd.Condition{
+		Type:     WaitingForWindow,
+		Status:   True,
+		Category: Advisory,
+	}
+	switch {
+	case !r.Migration.Spec.Started(now):
+		waiting.Reason = NotStarted
+		waiting.Message = fmt.Sprintf(
+			"The VM is waiting for the migration to start at %s.",
+			r.Migration.Spec.Start.UTC().Format(time.RFC3339))
+	case !r.Plan.Spec.Warm && !r.Migration.Spec.CutoverWindowOpen(now):
+		waiting.Reason = WindowClosed
+		waiting.Message = "The VM is waiting for a cutover window to open."
+	default:
+		startable = true
+	}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.MarkedStarted() || vm.MarkedCompleted() {
+			continue
+		}
+		if startable {
+			vm.DeleteCondition(WaitingForWindow)
+		} else {
+			vm.SetCondition(waiting)
+		}
+	}
+	return
+}
+
+// Reflect the VMs waiting for a window on the snapshot.
+func (r *Migration) reflectWaiting() {
+	waiting := libcnd.Condition{
+		Type:     WaitingForWindow,
+		Status:   True,
+		Category: Advisory,
+		Message:  "VMs are waiting for the migration to start or for a cutover window to open.",
+		Items:    []string{},
+	}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.MarkedCompleted() {
+			continue
+		}
+		if vm.HasCondition(WaitingForWindow) {
+			waiting.Items = append(waiting.Items, vm.String())
+		}
+	}
+	if len(waiting.Items) > 0 {
+		snapshot := r.Plan.Status.Migration.ActiveSnapshot()
+		snapshot.SetCondition(waiting)
+	}
+}
+
 // Archive the plan.
 // Best effort to remove any retained migration resources.
 func (r *Migration) Archive() {
@@ -345,6 +558,10 @@ func (r *Migration) Archive() {
 				vm.String())
 		}
 	}
+	err = r.kubevirt.ReleaseOvaStore()
+	if err != nil {
+		r.Log.Error(err, "Couldn't release the OVA store clone while archiving plan.")
+	}
 	return
 }
 
@@ -410,6 +627,209 @@ func (r *Migration) Cancel() (err error) {
 			}
 		}
 	}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if !vm.MarkedCompleted() {
+			return
+		}
+	}
+	err = r.kubevirt.ReleaseOvaStore()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Roll back the VMs that have been marked for rollback.
+// A VM is rolled back once its migration has ended. The
+// rollback is recorded as a step of the VM pipeline and
+// the failed tasks are retried until all have completed.
+func (r *Migration) Rollback() (err error) {
+	if !r.rollbackPending() {
+		return
+	}
+	defer func() {
+		if r.provider != nil {
+			r.provider.Close()
+		}
+	}()
+	err = r.init()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	r.resolveRollbackRefs()
+
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if !r.Migration.Spec.RollbackRequested(vm.Ref) {
+			continue
+		}
+		if !vm.MarkedCompleted() || vm.HasCondition(RolledBack) {
+			continue
+		}
+		r.rollback(vm)
+	}
+
+	return
+}
+
+// Determine whether any VM is pending rollback.
+// The refs not yet resolved are matched by name so that
+// the provider clients are built only when needed.
+func (r *Migration) rollbackPending() (pending bool) {
+	spec := r.Migration.Spec
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if !vm.MarkedCompleted() || vm.HasCondition(RolledBack) {
+			continue
+		}
+		if spec.RollbackAll {
+			pending = true
+			return
+		}
+		for _, ref := range spec.Rollback {
+			if ref.ID != "" && ref.ID == vm.ID ||
+				ref.ID == "" && ref.Name != "" && ref.Name == vm.Name {
+				pending = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// Roll back a VM.
+func (r *Migration) rollback(vm *plan.VMStatus) {
+	step, found := vm.FindStep(Rollback)
+	if !found {
+		step = r.rollbackStep(vm)
+		vm.Pipeline = append(vm.Pipeline, step)
+		r.Log.Info(
+			"Migration [ROLLBACK]",
+			"vm",
+			vm.String())
+	}
+	step.MarkStarted()
+	step.Phase = Running
+	step.Error = nil
+	for _, task := range step.Tasks {
+		if task.MarkedCompleted() {
+			continue
+		}
+		task.MarkStarted()
+		task.Phase = Running
+		task.Error = nil
+		var err error
+		switch task.Name {
+		case DeleteTargetVM:
+			err = r.kubevirt.DeleteVM(vm)
+		case DeleteDisks:
+			err = r.kubevirt.DeleteDataVolumes(vm)
+			if err == nil && vm.DeltaSync != nil {
+				err = r.kubevirt.DeletePreviousDataVolumes(vm)
+			}
+			if err == nil {
+				err = r.kubevirt.DeletePVCs(vm)
+			}
+		case DeletePods:
+			err = r.deleteImporterPods(vm)
+			if err == nil {
+				err = r.kubevirt.DeletePVCConsumerPod(vm)
+			}
+			if err == nil {
+				err = r.kubevirt.DeleteGuestConversionPod(vm)
+			}
+			if err == nil {
+				err = r.kubevirt.DeletePopulatorPods(vm)
+			}
+			if err == nil {
+				err = r.kubevirt.DeleteHookJobs(vm)
+			}
+		case DeletePopulators:
+			err = r.destinationClient.DeletePopulatorDataSource(vm)
+		case RemoveSnapshots:
+			if vm.Warm != nil {
+				err = r.provider.RemoveSnapshots(vm.Ref, vm.Warm.Precopies)
+			}
+			if err == nil && vm.DeltaSync != nil {
+				err = r.provider.RemoveSnapshots(vm.Ref, vm.DeltaSync.Precopies)
+			}
+		case RestorePowerState:
+			if vm.RestorePowerState == On {
+				err = r.provider.PowerOn(vm.Ref)
+			}
+		}
+		if err != nil {
+			r.Log.Error(err,
+				"Rollback task failed.",
+				"vm",
+				vm.String(),
+				"task",
+				task.Name)
+			task.AddError(err.Error())
+			continue
+		}
+		task.Progress.Completed = task.Progress.Total
+		task.Phase = Completed
+		task.MarkCompleted()
+	}
+	step.ReflectTasks()
+	if !step.MarkedCompleted() {
+		return
+	}
+	step.Phase = Completed
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     RolledBack,
+			Status:   True,
+			Category: Advisory,
+			Reason:   UserRequested,
+			Message:  "The VM migration has been rolled back.",
+			Durable:  true,
+		})
+	r.Log.Info(
+		"Migration [ROLLED BACK]",
+		"vm",
+		vm.String())
+}
+
+// Build the rollback step of a VM pipeline.
+func (r *Migration) rollbackStep(vm *plan.VMStatus) (step *plan.Step) {
+	task := func(name, description string) *plan.Task {
+		return &plan.Task{
+			Name:        name,
+			Description: description,
+			Progress:    libitr.Progress{Total: 1},
+			Phase:       Pending,
+		}
+	}
+	step = &plan.Step{
+		Task: plan.Task{
+			Name:        Rollback,
+			Description: "Roll back the migration.",
+			Phase:       Pending,
+		},
+		Tasks: []*plan.Task{
+			task(DeleteTargetVM, "Delete the target VM."),
+			task(DeleteDisks, "Delete the DataVolumes and PVCs."),
+			task(DeletePods, "Delete the importer, conversion, populator and hook pods."),
+			task(DeletePopulators, "Delete the volume populator CRs."),
+		},
+	}
+	if vm.Warm != nil {
+		step.Tasks = append(
+			step.Tasks,
+			task(RemoveSnapshots, "Remove the warm migration snapshots."))
+	} else if vm.DeltaSync != nil {
+		step.Tasks = append(
+			step.Tasks,
+			task(RemoveSnapshots, "Remove the delta sync snapshots."))
+	}
+	step.Tasks = append(
+		step.Tasks,
+		task(RestorePowerState, "Restore the power state of the source VM."))
+	step.Progress.Total = int64(len(step.Tasks))
 
 	return
 }
@@ -495,6 +915,24 @@ func (r *Migration) resolveCanceledRefs() {
 	}
 }
 
+// Best effort attempt to resolve the refs of VMs to roll back.
+func (r *Migration) resolveRollbackRefs() {
+	for i := range r.Context.Migration.Spec.Rollback {
+		// resolve the VM ref in place
+		ref := &r.Context.Migration.Spec.Rollback[i]
+		_, _ = r.Source.Inventory.VM(ref)
+	}
+}
+
+// Best effort attempt to resolve the refs of VMs to retry.
+func (r *Migration) resolveRetryRefs() {
+	for i := range r.Context.Migration.Spec.Retry {
+		// resolve the VM ref in place
+		ref := &r.Context.Migration.Spec.Retry[i]
+		_, _ = r.Source.Inventory.VM(ref)
+	}
+}
+
 func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 	vms = make([]*plan.VMStatus, 0)
 	for i := range r.Plan.Status.Migration.VMs {
@@ -534,7 +972,7 @@ func (r *Migration) itinerary() *libitr.Itinerary {
 // Get the name of the pipeline step corresponding to the current VM phase.
 func (r *Migration) step(vm *plan.VMStatus) (step string) {
 	switch vm.Phase {
-	case Started, CreateInitialSnapshot, WaitForInitialSnapshot, CreateDataVolumes:
+	case Started, CreateInitialSnapshot, WaitForInitialSnapshot, CreateSyncSnapshot, WaitForSyncSnapshot, CreateDataVolumes:
 		step = Initialize
 	case AllocateDisks:
 		step = DiskAllocation
@@ -548,6 +986,8 @@ func (r *Migration) step(vm *plan.VMStatus) (step string) {
 		step = DiskTransferV2v
 	case CreateVM:
 		step = VMCreation
+	case VerifyVM:
+		step = Verification
 	case PreHook, PostHook:
 		step = vm.Phase
 	case StorePowerState, PowerOffSource, WaitForPowerOff:
@@ -586,6 +1026,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 	r.itinerary().Predicate = &Predicate{
 		vm:      &vm.VM,
 		context: r.Context,
+		builder: r.builder,
 	}
 
 	r.Log.Info(
@@ -615,6 +1056,29 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			err = nil
 			break
 		}
+		err = r.kubevirt.ResetPopulatorFailures(vm)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if vm.DeltaSync != nil {
+			var sync bool
+			sync, err = r.itinerary().Predicate.Evaluate(DeltaSync)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			if !sync {
+				err = r.dropDeltaSync(vm)
+				if err != nil {
+					step.AddError(err.Error())
+					err = nil
+					break
+				}
+			}
+		}
 		vm.Phase = r.next(vm.Phase)
 	case PreHook, PostHook:
 		runner := HookRunner{Context: r.Context}
@@ -699,7 +1163,18 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 					break
 				}
 			}
-			err = r.kubevirt.EnsureDataVolumes(vm, dataVolumes)
+			var sync bool
+			sync, err = r.deltaSync(vm)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
+			if sync {
+				err = r.syncDataVolumes(vm, dataVolumes)
+			} else {
+				err = r.kubevirt.EnsureDataVolumes(vm, dataVolumes)
+			}
 			if err != nil {
 				if !errors.As(err, &web.ProviderNotReadyError{}) {
 					step.AddError(err.Error())
@@ -760,6 +1235,26 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		step.MarkCompleted()
 		step.Phase = Completed
 		vm.Phase = r.next(vm.Phase)
+	case VerifyVM:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		step.MarkStarted()
+		step.Phase = Running
+		var done bool
+		done, err = r.verify(vm, step)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if done {
+			step.MarkCompleted()
+			step.Phase = Completed
+			vm.Phase = r.next(vm.Phase)
+		}
 	case AllocateDisks, CopyDisks:
 		step, found := vm.FindStep(r.step(vm))
 		if !found {
@@ -793,9 +1288,24 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			vm.Phase = r.next(vm.Phase)
 		}
 	case CopyingPaused:
-		if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
-			vm.Phase = StorePowerState
-		} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
+		now := time.Now()
+		if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(now) {
+			if r.Migration.Spec.CutoverWindowOpen(now) {
+				vm.DeleteCondition(WaitingForWindow)
+				vm.Phase = StorePowerState
+				break
+			}
+			// Keep copying the changes until a window opens.
+			vm.SetCondition(
+				libcnd.Condition{
+					Type:     WaitingForWindow,
+					Status:   True,
+					Category: Advisory,
+					Reason:   WindowClosed,
+					Message:  "The VM is waiting for a cutover window to open.",
+				})
+		}
+		if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(now) {
 			vm.Phase = CreateSnapshot
 		}
 	case CreateInitialSnapshot, CreateSnapshot, CreateFinalSnapshot:
@@ -835,6 +1345,49 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		if ready {
 			vm.Phase = r.next(vm.Phase)
 		}
+	case CreateSyncSnapshot:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		var snapshot string
+		snapshot, err = r.provider.CreateSnapshot(vm.Ref)
+		if err != nil {
+			if errors.As(err, &web.ProviderNotReadyError{}) || errors.As(err, &web.ConflictError{}) {
+				return
+			}
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if vm.DeltaSync == nil {
+			vm.DeltaSync = &plan.DeltaSync{}
+		}
+		now := meta.Now()
+		precopy := plan.Precopy{Snapshot: snapshot, Start: &now}
+		vm.DeltaSync.Precopies = append(vm.DeltaSync.Precopies, precopy)
+		vm.DeltaSync.Reused = 0
+		vm.Phase = r.next(vm.Phase)
+	case WaitForSyncSnapshot:
+		step, found := vm.FindStep(r.step(vm))
+		if !found {
+			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
+			break
+		}
+		n := len(vm.DeltaSync.Precopies)
+		precopy := &vm.DeltaSync.Precopies[n-1]
+		ready, err := r.provider.CheckSnapshotReady(vm.Ref, precopy.Snapshot)
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if ready {
+			now := meta.Now()
+			precopy.End = &now
+			vm.Phase = r.next(vm.Phase)
+		}
 	case AddCheckpoint, AddFinalCheckpoint:
 		step, found := vm.FindStep(r.step(vm))
 		if !found {
@@ -842,7 +1395,11 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			break
 		}
 
-		err = r.setDataVolumeCheckpoints(vm)
+		if vm.Warm != nil {
+			err = r.setDataVolumeCheckpoints(vm)
+		} else {
+			err = r.finalizeDataVolumes(vm)
+		}
 		if err != nil {
 			step.AddError(err.Error())
 			err = nil
@@ -917,18 +1474,24 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			vm.AddError(fmt.Sprintf("Step '%s' not found", r.step(vm)))
 			break
 		}
-		err = r.updateCopyProgress(vm, step)
+		if r.builder.SupportsVolumePopulators() {
+			err = r.updatePopulatorCopyProgress(vm, step)
+		} else {
+			err = r.updateCopyProgress(vm, step)
+		}
 		if err != nil {
 			return
 		}
 		if step.MarkedCompleted() {
-			err = r.provider.RemoveSnapshots(vm.Ref, vm.Warm.Precopies)
-			if err != nil {
-				r.Log.Info(
-					"Failed to clean up warm migration snapshots.",
-					"vm",
-					vm)
-				err = nil
+			if vm.Warm != nil {
+				err = r.provider.RemoveSnapshots(vm.Ref, vm.Warm.Precopies)
+				if err != nil {
+					r.Log.Info(
+						"Failed to clean up warm migration snapshots.",
+						"vm",
+						vm)
+					err = nil
+				}
 			}
 			if !step.HasError() {
 				step.Phase = Completed
@@ -943,6 +1506,20 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		}
 		step.MarkStarted()
 		step.Phase = Running
+		var ready bool
+		ready, err = r.kubevirt.EnsureOvaStore()
+		if err != nil {
+			step.AddError(err.Error())
+			err = nil
+			break
+		}
+		if !ready {
+			r.Log.Info(
+				"Waiting for the OVA store.",
+				"vm",
+				vm.String())
+			break
+		}
 		err = r.ensureGuestConversionPod(vm)
 		if err != nil {
 			step.AddError(err.Error())
@@ -1007,6 +1584,16 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				Message:  "The VM migration has SUCCEEDED.",
 				Durable:  true,
 			})
+		if vm.DeltaSync != nil {
+			errLocal := r.provider.RemoveSnapshots(vm.Ref, vm.DeltaSync.Precopies)
+			if errLocal != nil {
+				r.Log.Error(
+					errLocal,
+					"Failed to clean up delta sync snapshots.",
+					"vm",
+					vm.String())
+			}
+		}
 
 		// Power on the destination VM if the source VM was originally powered on.
 		err = r.setRunning(vm, vm.RestorePowerState == On)
@@ -1043,7 +1630,7 @@ func (r *Migration) resetPrecopyTasks(vm *plan.VMStatus, step *plan.Step) {
 
 // Build the pipeline for a VM status.
 func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error) {
-	r.itinerary().Predicate = &Predicate{vm: vm, context: r.Context}
+	r.itinerary().Predicate = &Predicate{vm: vm, context: r.Context, builder: r.builder}
 	step, _ := r.itinerary().First()
 	for {
 		switch step.Name {
@@ -1168,6 +1755,17 @@ func (r *Migration) buildPipeline(vm *plan.VM) (pipeline []*plan.Step, err error
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case VerifyVM:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        Verification,
+						Description: "Verify the target VM.",
+						Phase:       Pending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+				})
 		}
 		next, done, _ := r.itinerary().Next(step.Name)
 		if !done {
@@ -1200,6 +1798,11 @@ func (r *Migration) end() (completed bool, err error) {
 			succeeded++
 		}
 	}
+	// Retried until released, before the provider is finalized.
+	err = r.kubevirt.ReleaseOvaStore()
+	if err != nil {
+		return
+	}
 	go r.provider.Finalize(r.Plan.Status.Migration.VMs, r.Migration.Name)
 	r.Plan.Status.Migration.MarkCompleted()
 	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
@@ -1275,14 +1878,28 @@ func (r *Migration) ensureGuestConversionPod(vm *plan.VMStatus) (err error) {
 
 // Set the running state of the kubevirt VM.
 func (r *Migration) setRunning(vm *plan.VMStatus, running bool) (err error) {
+	vmCr, found, err := r.virtualMachine(vm)
+	if err != nil || !found {
+		return
+	}
+
+	if vmCr.Spec.Running != nil && *vmCr.Spec.Running == running {
+		return
+	}
+
+	err = r.kubevirt.SetRunning(&vmCr, running)
+	return
+}
+
+// Find the kubevirt VM.
+// An error is added to the VM when not found.
+func (r *Migration) virtualMachine(vm *plan.VMStatus) (vmCr VirtualMachine, found bool, err error) {
 	if r.vmMap == nil {
 		r.vmMap, err = r.kubevirt.VirtualMachineMap()
 		if err != nil {
 			return
 		}
 	}
-	var vmCr VirtualMachine
-	found := false
 	if vmCr, found = r.vmMap[vm.ID]; !found {
 		// Recreate the map and check again, the map may be stale
 		r.vmMap, err = r.kubevirt.VirtualMachineMap()
@@ -1296,12 +1913,6 @@ func (r *Migration) setRunning(vm *plan.VMStatus, running bool) (err error) {
 			return
 		}
 	}
-
-	if vmCr.Spec.Running != nil && *vmCr.Spec.Running == running {
-		return
-	}
-
-	err = r.kubevirt.SetRunning(&vmCr, running)
 	return
 }
 
@@ -1342,6 +1953,15 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 			}
 		}
 	} else {
+		// The precopies following the initial one may be transferred
+		// by the provider into the volumes that are already imported.
+		precopied := true
+		if vm.Warm != nil && len(vm.Warm.Precopies) > 1 {
+			precopied, err = r.provider.PreTransferActions(vm.Ref)
+			if err != nil {
+				return
+			}
+		}
 		for _, dv := range dvs {
 			var task *plan.Task
 			name := r.builder.ResolveDataVolumeIdentifier(dv.DataVolume)
@@ -1351,7 +1971,29 @@ func (r *Migration) updateCopyProgress(vm *plan.VMStatus, step *plan.Step) (err
 				continue
 			}
 			conditions := dv.Conditions()
-			switch dv.Status.Phase {
+			phase := dv.Status.Phase
+			if phase == cdi.Paused && vm.DeltaSync != nil {
+				// The checkpoint added to a reused
+				// DataVolume may not be copied yet and a
+				// finalized DataVolume is not complete
+				// until the import has succeeded.
+				copied := false
+				if !dv.Spec.FinalCheckpoint {
+					copied, err = r.kubevirt.checkpointCopied(dv.DataVolume)
+					if err != nil {
+						return
+					}
+				}
+				if !copied {
+					phase = cdi.ImportInProgress
+				}
+			}
+			if phase == cdi.Succeeded && !precopied {
+				running++
+				task.Phase = Running
+				continue
+			}
+			switch phase {
 			case cdi.Succeeded, cdi.Paused:
 				completed++
 				task.Phase = Completed
@@ -1582,6 +2224,16 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 		return
 	}
 
+	// The precopies following the initial one are transferred by
+	// the provider into the volumes that are already populated.
+	transferred := true
+	if vm.Warm != nil && len(vm.Warm.Precopies) > 1 {
+		transferred, err = r.provider.PreTransferActions(vm.Ref)
+		if err != nil {
+			return
+		}
+	}
+
 	for _, pvc := range pvcs {
 		if _, ok := pvc.Annotations["lun"]; ok {
 			// skip LUNs
@@ -1599,7 +2251,23 @@ func (r *Migration) updatePopulatorCopyProgress(vm *plan.VMStatus, step *plan.St
 			continue
 		}
 
+		if pvc.Status.Phase != core.ClaimBound && !task.HasError() {
+			var failure string
+			failure, err = r.kubevirt.getPopulatorFailure(&pvc)
+			if err != nil {
+				return
+			}
+			if failure != "" {
+				task.AddError(failure)
+				task.MarkCompleted()
+				continue
+			}
+		}
+
 		if pvc.Status.Phase == core.ClaimBound {
+			if !transferred {
+				continue
+			}
 			task.Phase = Completed
 			task.Reason = TransferCompleted
 			task.Progress.Completed = task.Progress.Total
@@ -1647,6 +2315,8 @@ type Predicate struct {
 	vm *plan.VM
 	// Plan context
 	context *plancontext.Context
+	// Builder
+	builder adapter.Builder
 }
 
 // Evaluate predicate flags.
@@ -1667,6 +2337,10 @@ func (r *Predicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		allowed = !el9
 	case VirtV2vDiskCopy:
 		allowed = el9
+	case DeltaSync:
+		allowed, err = r.deltaSync()
+	case Verify:
+		allowed = r.context.Plan.Spec.Verify
 	}
 
 	return
@@ -0,0 +1,579 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	webbase "github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	libitr "github.com/konveyor/forklift-controller/pkg/lib/itinerary"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	batch "k8s.io/api/batch/v1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Annotation of the bytes transferred by the (test) populator.
+const annTransferred = "test/transferred"
+
+// Builder of volume populator tasks named after the PVCs.
+type populatorBuilder struct {
+	adapter.Builder
+}
+
+func (r *populatorBuilder) SupportsVolumePopulators() bool {
+	return true
+}
+
+func (r *populatorBuilder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	taskName = pvc.Name
+	return
+}
+
+func (r *populatorBuilder) PopulatorTransferredBytes(pvc *core.PersistentVolumeClaim) (transferred int64, err error) {
+	transferred, err = strconv.ParseInt(pvc.Annotations[annTransferred], 10, 64)
+	return
+}
+
+// Provider client transferring the precopies.
+type populatorClient struct {
+	adapter.Client
+	// Precopy transferred.
+	ready bool
+	// Number of PreTransferActions() calls.
+	transfers int
+	// Number of RemoveSnapshots() calls.
+	removed int
+}
+
+func (r *populatorClient) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
+	r.transfers++
+	ready = r.ready
+	return
+}
+
+func (r *populatorClient) RemoveSnapshots(vmRef ref.Ref, precopies []plan.Precopy) (err error) {
+	r.removed++
+	return
+}
+
+func populatorMigration(warm bool, objects ...client.Object) (migration *Migration, provider *populatorClient) {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	openstack := api.OpenStack
+	host := api.OpenShift
+	ctx := &plancontext.Context{
+		Plan: &api.Plan{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "test",
+				UID:  "plan",
+			},
+			Spec: api.PlanSpec{
+				TargetNamespace: "test",
+				Warm:            warm,
+			},
+		},
+		Migration: &api.Migration{
+			ObjectMeta: meta.ObjectMeta{
+				Name: "test",
+				UID:  "migration",
+			},
+		},
+		Log: logging.WithName("test"),
+	}
+	ctx.Plan.Referenced.Provider.Source = &api.Provider{
+		Spec: api.ProviderSpec{Type: &openstack},
+	}
+	ctx.Plan.Referenced.Provider.Destination = &api.Provider{
+		Spec: api.ProviderSpec{Type: &host},
+	}
+	ctx.Source.Provider = ctx.Plan.Referenced.Provider.Source
+	ctx.Destination.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		Build()
+	builder := &populatorBuilder{}
+	provider = &populatorClient{}
+	migration = &Migration{
+		Context:  ctx,
+		builder:  builder,
+		provider: provider,
+		kubevirt: KubeVirt{
+			Context: ctx,
+			Builder: builder,
+		},
+	}
+	return
+}
+
+func populatorPVC(name string, phase core.PersistentVolumeClaimPhase, transferred int64) *core.PersistentVolumeClaim {
+	return &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			Annotations: map[string]string{
+				kVM:            "vm-1",
+				kPlan:          "plan",
+				annTransferred: strconv.FormatInt(transferred, 10),
+			},
+		},
+		Status: core.PersistentVolumeClaimStatus{
+			Phase: phase,
+		},
+	}
+}
+
+func populatorStep(name string, disks ...string) (step *plan.Step) {
+	step = &plan.Step{
+		Task: plan.Task{
+			Name: name,
+		},
+	}
+	for _, disk := range disks {
+		step.Tasks = append(
+			step.Tasks,
+			&plan.Task{
+				Name: disk,
+				Progress: libitr.Progress{
+					Total: 10,
+				},
+			})
+	}
+	return
+}
+
+func populatorVM(precopies int) (vm *plan.VMStatus) {
+	vm = &plan.VMStatus{
+		VM: plan.VM{
+			Ref: ref.Ref{ID: "vm-1"},
+		},
+	}
+	if precopies > 0 {
+		vm.Warm = &plan.Warm{}
+		for i := 0; i < precopies; i++ {
+			vm.Warm.Precopies = append(
+				vm.Warm.Precopies,
+				plan.Precopy{Snapshot: strconv.Itoa(i + 1)})
+		}
+	}
+	return
+}
+
+func TestUpdatePopulatorCopyProgress(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, provider := populatorMigration(
+		false,
+		populatorPVC("a", core.ClaimBound, 0),
+		populatorPVC("b", core.ClaimPending, 5*0x100000))
+	// Cold: the PVCs bound have been populated.
+	vm := populatorVM(0)
+	step := populatorStep(DiskTransfer, "a", "b")
+	err := migration.updatePopulatorCopyProgress(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(provider.transfers).To(gomega.Equal(0))
+	task, _ := step.FindTask("a")
+	g.Expect(task.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(task.Progress.Completed).To(gomega.Equal(int64(10)))
+	task, _ = step.FindTask("b")
+	g.Expect(task.MarkedCompleted()).To(gomega.BeFalse())
+	g.Expect(task.Progress.Completed).To(gomega.Equal(int64(5)))
+	g.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+}
+
+func TestUpdatePopulatorCopyProgressPrecopy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, provider := populatorMigration(
+		true,
+		populatorPVC("a", core.ClaimBound, 0),
+		populatorPVC("b", core.ClaimBound, 0))
+	// Initial precopy: populated by the populators.
+	vm := populatorVM(1)
+	step := populatorStep(DiskTransfer, "a", "b")
+	err := migration.updatePopulatorCopyProgress(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(provider.transfers).To(gomega.Equal(0))
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	// Following precopy: transferred by the provider.
+	vm = populatorVM(2)
+	step = populatorStep(DiskTransfer, "a", "b")
+	err = migration.updatePopulatorCopyProgress(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(provider.transfers).To(gomega.Equal(1))
+	for _, task := range step.Tasks {
+		g.Expect(task.MarkedCompleted()).To(gomega.BeFalse())
+	}
+	g.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+	provider.ready = true
+	err = migration.updatePopulatorCopyProgress(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(provider.transfers).To(gomega.Equal(2))
+	for _, task := range step.Tasks {
+		g.Expect(task.MarkedCompleted()).To(gomega.BeTrue())
+		g.Expect(task.Phase).To(gomega.Equal(Completed))
+	}
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+}
+
+func TestPopulatorFailure(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	group := api.SchemeGroupVersion.Group
+	pvc := populatorPVC("a", core.ClaimPending, 0)
+	pvc.Spec.DataSourceRef = &core.TypedObjectReference{
+		APIGroup: &group,
+		Kind:     "OpenstackVolumePopulator",
+		Name:     "a",
+	}
+	populator := &api.OpenstackVolumePopulator{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "a",
+			Namespace: "test",
+		},
+		Status: api.OpenstackVolumePopulatorStatus{
+			Retries: 3,
+			Failure: "image not found",
+		},
+	}
+	migration, _ := populatorMigration(false, pvc, populator)
+	vm := populatorVM(0)
+	// The failure recorded by the populator controller.
+	step := populatorStep(DiskTransfer, "a")
+	err := migration.updatePopulatorCopyProgress(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	task, _ := step.FindTask("a")
+	g.Expect(task.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(task.Error).ToNot(gomega.BeNil())
+	g.Expect(task.Error.Reasons).To(gomega.ContainElement("image not found"))
+	// Reset when the transfer is retried.
+	err = migration.kubevirt.ResetPopulatorFailures(vm)
+	g.Expect(err).To(gomega.BeNil())
+	err = migration.Destination.Client.Get(context.TODO(), client.ObjectKeyFromObject(populator), populator)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(populator.Status.Failure).To(gomega.BeEmpty())
+	g.Expect(populator.Status.Retries).To(gomega.Equal(0))
+	step = populatorStep(DiskTransfer, "a")
+	err = migration.updatePopulatorCopyProgress(vm, step)
+	g.Expect(err).To(gomega.BeNil())
+	task, _ = step.FindTask("a")
+	g.Expect(task.MarkedCompleted()).To(gomega.BeFalse())
+	g.Expect(task.Error).To(gomega.BeNil())
+}
+
+func TestFinalizePopulator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, provider := populatorMigration(
+		true,
+		populatorPVC("a", core.ClaimBound, 0))
+	vm := populatorVM(3)
+	vm.Phase = Finalize
+	vm.Pipeline = []*plan.Step{populatorStep(Cutover, "a")}
+	// The final precopy is not transferred yet.
+	err := migration.execute(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.Phase).To(gomega.Equal(Finalize))
+	g.Expect(provider.transfers).To(gomega.Equal(1))
+	g.Expect(provider.removed).To(gomega.Equal(0))
+	// Transferred: the snapshots are removed.
+	provider.ready = true
+	err = migration.execute(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(provider.transfers).To(gomega.Equal(2))
+	g.Expect(provider.removed).To(gomega.Equal(1))
+	g.Expect(vm.Pipeline[0].Phase).To(gomega.Equal(Completed))
+	g.Expect(vm.Phase).To(gomega.Equal(CreateVM))
+}
+
+// Provider client restoring the power state.
+type rollbackClient struct {
+	adapter.Client
+	// Number of RemoveSnapshots() calls.
+	removed int
+	// Number of PowerOn() calls.
+	powered int
+}
+
+func (r *rollbackClient) RemoveSnapshots(vmRef ref.Ref, precopies []plan.Precopy) (err error) {
+	r.removed++
+	return
+}
+
+func (r *rollbackClient) PowerOn(vmRef ref.Ref) (err error) {
+	r.powered++
+	return
+}
+
+// Destination client deleting the populator CRs.
+type rollbackDestination struct {
+	adapter.DestinationClient
+	// Error returned by DeletePopulatorDataSource().
+	err error
+	// Number of DeletePopulatorDataSource() calls.
+	deleted int
+}
+
+func (r *rollbackDestination) DeletePopulatorDataSource(vm *plan.VMStatus) (err error) {
+	r.deleted++
+	err = r.err
+	return
+}
+
+func rollbackMigration() (migration *Migration, provider *rollbackClient, destination *rollbackDestination) {
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = batch.AddToScheme(scheme)
+	_ = cnv.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
+	migration, _ = populatorMigration(true)
+	migration.Destination.Client = fake.NewClientBuilder().
+		WithScheme(scheme).
+		Build()
+	provider = &rollbackClient{}
+	destination = &rollbackDestination{}
+	migration.provider = provider
+	migration.destinationClient = destination
+	return
+}
+
+func rollbackVM(warm bool) (vm *plan.VMStatus) {
+	vm = populatorVM(0)
+	if warm {
+		vm = populatorVM(2)
+	}
+	vm.Name = "one"
+	vm.RestorePowerState = On
+	vm.MarkCompleted()
+	return
+}
+
+func TestRollbackStep(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, _, _ := rollbackMigration()
+	names := func(step *plan.Step) (list []string) {
+		for _, task := range step.Tasks {
+			list = append(list, task.Name)
+		}
+		return
+	}
+	// Cold.
+	step := migration.rollbackStep(rollbackVM(false))
+	g.Expect(names(step)).To(gomega.Equal(
+		[]string{
+			DeleteTargetVM,
+			DeleteDisks,
+			DeletePods,
+			DeletePopulators,
+			RestorePowerState,
+		}))
+	g.Expect(step.Progress.Total).To(gomega.Equal(int64(5)))
+	// Warm: the snapshots are removed before the
+	// power state is restored.
+	step = migration.rollbackStep(rollbackVM(true))
+	g.Expect(names(step)).To(gomega.Equal(
+		[]string{
+			DeleteTargetVM,
+			DeleteDisks,
+			DeletePods,
+			DeletePopulators,
+			RemoveSnapshots,
+			RestorePowerState,
+		}))
+	g.Expect(step.Progress.Total).To(gomega.Equal(int64(6)))
+}
+
+func TestRollbackIdempotent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, provider, destination := rollbackMigration()
+	vm := rollbackVM(true)
+	// A task fails: the others complete.
+	destination.err = errors.New("failed")
+	migration.rollback(vm)
+	g.Expect(vm.Pipeline).To(gomega.HaveLen(1))
+	step := vm.Pipeline[0]
+	g.Expect(step.Name).To(gomega.Equal(Rollback))
+	g.Expect(step.MarkedCompleted()).To(gomega.BeFalse())
+	g.Expect(vm.HasCondition(RolledBack)).To(gomega.BeFalse())
+	for _, task := range step.Tasks {
+		g.Expect(task.MarkedCompleted()).To(gomega.Equal(task.Name != DeletePopulators))
+	}
+	g.Expect(destination.deleted).To(gomega.Equal(1))
+	g.Expect(provider.removed).To(gomega.Equal(1))
+	g.Expect(provider.powered).To(gomega.Equal(1))
+	// Retried: only the failed task is run again.
+	destination.err = nil
+	migration.rollback(vm)
+	g.Expect(vm.Pipeline).To(gomega.HaveLen(1))
+	g.Expect(step.MarkedCompleted()).To(gomega.BeTrue())
+	g.Expect(step.Phase).To(gomega.Equal(Completed))
+	g.Expect(vm.HasCondition(RolledBack)).To(gomega.BeTrue())
+	g.Expect(destination.deleted).To(gomega.Equal(2))
+	g.Expect(provider.removed).To(gomega.Equal(1))
+	g.Expect(provider.powered).To(gomega.Equal(1))
+}
+
+func TestRollbackPending(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, _, _ := rollbackMigration()
+	vm := rollbackVM(false)
+	migration.Plan.Status.Migration.VMs = []*plan.VMStatus{vm}
+	// Not requested: the clients are not built.
+	g.Expect(migration.rollbackPending()).To(gomega.BeFalse())
+	err := migration.Rollback()
+	g.Expect(err).To(gomega.BeNil())
+	// Requested by name (not resolved).
+	migration.Migration.Spec.Rollback = []ref.Ref{{Name: "one"}}
+	g.Expect(migration.rollbackPending()).To(gomega.BeTrue())
+	// Requested by ID.
+	migration.Migration.Spec.Rollback = []ref.Ref{{ID: "vm-1"}}
+	g.Expect(migration.rollbackPending()).To(gomega.BeTrue())
+	// Already rolled back.
+	vm.SetCondition(libcnd.Condition{Type: RolledBack, Status: True})
+	g.Expect(migration.rollbackPending()).To(gomega.BeFalse())
+	err = migration.Rollback()
+	g.Expect(err).To(gomega.BeNil())
+	// Not completed.
+	vm = rollbackVM(false)
+	vm.MarkReset()
+	migration.Plan.Status.Migration.VMs = []*plan.VMStatus{vm}
+	migration.Migration.Spec.Rollback = nil
+	migration.Migration.Spec.RollbackAll = true
+	g.Expect(migration.rollbackPending()).To(gomega.BeFalse())
+}
+
+func TestResume(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	failedVM := func(phase string) (vm *plan.VMStatus) {
+		vm = rollbackVM(false)
+		vm.MarkStarted()
+		vm.AddError("failed")
+		vm.Error.Phase = phase
+		vm.SetCondition(libcnd.Condition{Type: Failed, Status: True})
+		completed := populatorStep(Initialize)
+		completed.MarkCompleted()
+		failed := populatorStep(DiskTransfer, "a", "b")
+		failed.MarkCompleted()
+		failed.AddError("failed")
+		failed.Tasks[0].MarkCompleted()
+		failed.Tasks[1].MarkCompleted()
+		failed.Tasks[1].AddError("failed")
+		vm.Pipeline = []*plan.Step{completed, failed}
+		return
+	}
+	for _, warm := range []bool{false, true} {
+		migration, _, _ := rollbackMigration()
+		migration.Plan.Spec.Warm = warm
+		for _, step := range migration.itinerary().Pipeline {
+			vm := failedVM(step.Name)
+			started, completed := vm.Started, vm.Completed
+			resumed, err := migration.resume(vm)
+			g.Expect(err).To(gomega.BeNil())
+			if step.Name == Completed {
+				g.Expect(resumed).To(gomega.BeFalse())
+				g.Expect(vm.Attempts).To(gomega.BeEmpty())
+				continue
+			}
+			g.Expect(resumed).To(gomega.BeTrue(), step.Name)
+			// The failed attempt is recorded.
+			g.Expect(vm.Attempts).To(gomega.HaveLen(1))
+			attempt := vm.Attempts[0]
+			g.Expect(attempt.Phase).To(gomega.Equal(step.Name))
+			g.Expect(attempt.Error.Reasons).To(gomega.Equal([]string{"failed"}))
+			g.Expect(attempt.Started).To(gomega.Equal(started))
+			g.Expect(attempt.Completed).To(gomega.Equal(completed))
+			// Resumed from the phase that failed.
+			switch step.Name {
+			case ConvertGuest, CopyDisksVirtV2V:
+				g.Expect(vm.Phase).To(gomega.Equal(CreateGuestConversionPod))
+			default:
+				g.Expect(vm.Phase).To(gomega.Equal(step.Name))
+			}
+			g.Expect(vm.Error).To(gomega.BeNil())
+			g.Expect(vm.Completed).To(gomega.BeNil())
+			g.Expect(vm.HasCondition(Failed)).To(gomega.BeFalse())
+			// Only the failed steps and tasks are reset.
+			g.Expect(vm.Pipeline[0].MarkedCompleted()).To(gomega.BeTrue())
+			failed := vm.Pipeline[1]
+			g.Expect(failed.Error).To(gomega.BeNil())
+			g.Expect(failed.MarkedCompleted()).To(gomega.BeFalse())
+			g.Expect(failed.Phase).To(gomega.Equal(Running))
+			g.Expect(failed.Tasks[0].MarkedCompleted()).To(gomega.BeTrue())
+			g.Expect(failed.Tasks[1].MarkedCompleted()).To(gomega.BeFalse())
+			g.Expect(failed.Tasks[1].Error).To(gomega.BeNil())
+		}
+	}
+	// Unknown phase.
+	migration, _, _ := rollbackMigration()
+	vm := failedVM(Unknown)
+	resumed, err := migration.resume(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(resumed).To(gomega.BeFalse())
+	g.Expect(vm.Attempts).To(gomega.BeEmpty())
+	g.Expect(vm.HasCondition(Failed)).To(gomega.BeTrue())
+}
+
+// Inventory reporting the concerns of the VM.
+type concernsInventory struct {
+	web.Client
+	concerns []model.Concern
+	// Error returned by VM().
+	err error
+}
+
+func (r *concernsInventory) VM(ref *webbase.Ref) (object interface{}, err error) {
+	vm := &vsphere.VM{}
+	vm.ID = ref.ID
+	vm.Concerns = r.concerns
+	object = vm
+	err = r.err
+	return
+}
+
+func TestSnapshotConcerns(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration, _, _ := rollbackMigration()
+	inventory := &concernsInventory{
+		concerns: []model.Concern{
+			{
+				Category:   "Warning",
+				Label:      "Changed Block Tracking (CBT) not enabled",
+				Assessment: "Changed Block Tracking (CBT) has not been enabled on this VM.",
+			},
+		},
+	}
+	migration.Source.Inventory = inventory
+	vm := rollbackVM(false)
+	err := migration.snapshotConcerns(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.Concerns).To(gomega.Equal([]plan.Concern{
+		{
+			Category:   "Warning",
+			Label:      "Changed Block Tracking (CBT) not enabled",
+			Assessment: "Changed Block Tracking (CBT) has not been enabled on this VM.",
+		},
+	}))
+	// The snapshot is kept when the concerns change afterwards.
+	inventory.concerns = nil
+	snapshot := vm.DeepCopy()
+	g.Expect(snapshot.Concerns).To(gomega.Equal(vm.Concerns))
+	g.Expect(migration.snapshotConcerns(snapshot)).To(gomega.BeNil())
+	g.Expect(snapshot.Concerns).To(gomega.BeEmpty())
+	g.Expect(vm.Concerns).To(gomega.HaveLen(1))
+	// Inventory not available.
+	inventory.err = errors.New("not available")
+	g.Expect(migration.snapshotConcerns(vm)).ToNot(gomega.BeNil())
+}
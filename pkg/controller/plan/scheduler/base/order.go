@@ -0,0 +1,125 @@
+package base
+
+import (
+	"sort"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+)
+
+// The name of the pipeline step during
+// which the VMs are cut over.
+const CutoverStep = "Cutover"
+
+// Return the VMs of the plan waiting to be started in the
+// order they should be started. The VMs of the ordering groups
+// that are not ready yet are excluded and the others are sorted
+// by descending priority, preserving the plan order otherwise.
+// The VMs of a group are also excluded while VMs of a group
+// listed before it are waiting, so that a later group cannot
+// overtake an earlier one that does not fit the capacity.
+func Pending(p *api.Plan) (pending []*plan.VMStatus) {
+	ordering := Ordering{Plan: p}
+	waiting := []*plan.VMStatus{}
+	first := len(p.Spec.Groups)
+	for _, vm := range p.Status.Migration.VMs {
+		if vm.MarkedStarted() || vm.MarkedCompleted() {
+			continue
+		}
+		waiting = append(waiting, vm)
+		if index, found := ordering.index(vm); found && index < first {
+			first = index
+		}
+	}
+	for _, vm := range waiting {
+		if !ordering.Ready(ordering.group(vm)) {
+			continue
+		}
+		if index, found := ordering.index(vm); found && index > first {
+			continue
+		}
+		pending = append(pending, vm)
+	}
+	sort.SliceStable(
+		pending,
+		func(i, j int) bool {
+			return ordering.priority(pending[i]) > ordering.priority(pending[j])
+		})
+
+	return
+}
+
+// VM ordering.
+type Ordering struct {
+	Plan *api.Plan
+}
+
+// Determine whether the VMs of a group may be started.
+// A group is ready once the VMs of the groups it depends on,
+// directly or not, have completed or reached cutover.
+func (r *Ordering) Ready(group string) bool {
+	return r.ready(group, map[string]bool{})
+}
+
+func (r *Ordering) ready(group string, visited map[string]bool) bool {
+	if group == "" || visited[group] {
+		return true
+	}
+	visited[group] = true
+	g, found := r.Plan.Spec.FindGroup(group)
+	if !found {
+		return true
+	}
+	for _, dependency := range g.DependsOn {
+		for _, vm := range r.Plan.Status.Migration.VMs {
+			if r.group(vm) == dependency && !r.reached(vm) {
+				return false
+			}
+		}
+		if !r.ready(dependency, visited) {
+			return false
+		}
+	}
+	return true
+}
+
+// Determine whether a VM has completed or reached cutover.
+func (r *Ordering) reached(vm *plan.VMStatus) bool {
+	if vm.MarkedCompleted() {
+		return true
+	}
+	step, found := vm.FindStep(CutoverStep)
+	return found && step.MarkedStarted()
+}
+
+// The ordering group of a VM as listed on the plan.
+func (r *Ordering) group(vm *plan.VMStatus) string {
+	if planned, found := r.Plan.Spec.FindVM(vm.Ref); found {
+		return planned.Group
+	}
+	return vm.Group
+}
+
+// The index of the ordering group of a VM in the plan.
+func (r *Ordering) index(vm *plan.VMStatus) (index int, found bool) {
+	group := r.group(vm)
+	if group == "" {
+		return
+	}
+	for i := range r.Plan.Spec.Groups {
+		if r.Plan.Spec.Groups[i].Name == group {
+			index = i
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// The priority of a VM as listed on the plan.
+func (r *Ordering) priority(vm *plan.VMStatus) int {
+	if planned, found := r.Plan.Spec.FindVM(vm.Ref); found {
+		return planned.Priority
+	}
+	return vm.Priority
+}
@@ -0,0 +1,101 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func TestPending(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := func(id, group string, priority int) plan.VM {
+		return plan.VM{
+			Ref:      ref.Ref{ID: id},
+			Group:    group,
+			Priority: priority,
+		}
+	}
+	p := &api.Plan{}
+	p.Spec.Groups = []plan.Group{
+		{Name: "db"},
+		{Name: "app", DependsOn: []string{"db"}},
+		{Name: "web", DependsOn: []string{"app"}},
+	}
+	p.Spec.VMs = []plan.VM{
+		vm("db1", "db", 0),
+		vm("app1", "app", 0),
+		vm("web1", "web", 0),
+		vm("misc1", "", 1),
+		vm("misc2", "", 5),
+		vm("misc3", "", 1),
+	}
+	for _, planned := range p.Spec.VMs {
+		p.Status.Migration.VMs = append(
+			p.Status.Migration.VMs,
+			&plan.VMStatus{VM: planned})
+	}
+	status := p.Status.Migration.VMs
+
+	// Only the VMs outside of groups and those of
+	// the db group are ready, sorted by priority.
+	g.Expect(Pending(p)).To(gomega.Equal([]*plan.VMStatus{
+		status[4],
+		status[3],
+		status[5],
+		status[0],
+	}))
+
+	// The app group is ready once the db VM reached cutover
+	// but the web group still waits on the app group.
+	status[0].MarkStarted()
+	status[0].Pipeline = []*plan.Step{{Task: plan.Task{Name: CutoverStep}}}
+	status[0].Pipeline[0].MarkStarted()
+	g.Expect(Pending(p)).To(gomega.Equal([]*plan.VMStatus{
+		status[4],
+		status[3],
+		status[5],
+		status[1],
+	}))
+}
+
+func TestPendingGroupOrder(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{}
+	p.Spec.Groups = []plan.Group{
+		{Name: "a"},
+		{Name: "b"},
+	}
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "b1"}, Group: "b", Priority: 5},
+		{Ref: ref.Ref{ID: "a1"}, Group: "a"},
+		{Ref: ref.Ref{ID: "a2"}, Group: "a"},
+	}
+	for _, planned := range p.Spec.VMs {
+		p.Status.Migration.VMs = append(
+			p.Status.Migration.VMs,
+			&plan.VMStatus{VM: planned})
+	}
+	status := p.Status.Migration.VMs
+
+	// The VMs of the later group wait while
+	// the earlier group has VMs waiting.
+	g.Expect(Pending(p)).To(gomega.Equal([]*plan.VMStatus{
+		status[1],
+		status[2],
+	}))
+	status[1].MarkStarted()
+	g.Expect(Pending(p)).To(gomega.Equal([]*plan.VMStatus{
+		status[2],
+	}))
+
+	// Pending once the VMs of the earlier group have started.
+	status[2].MarkStarted()
+	g.Expect(Pending(p)).To(gomega.Equal([]*plan.VMStatus{
+		status[0],
+	}))
+}
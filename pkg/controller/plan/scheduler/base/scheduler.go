@@ -0,0 +1,276 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+)
+
+// Package level mutex to ensure that
+// multiple concurrent reconciles don't
+// attempt to schedule VMs into the same
+// slots.
+var mutex sync.Mutex
+
+// Usage of the source bottlenecks by a VM.
+// Maps each bottleneck (storage domain, availability zone,
+// namespace, ...) the VM is read from to the cost of the VM
+// on that bottleneck.
+type Usage map[string]Cost
+
+// Cost of a VM on a bottleneck.
+type Cost struct {
+	// Number of disks.
+	Disks int
+	// Size of the disks in bytes.
+	Size int64
+}
+
+// Bytes in a GiB.
+const GiB = int64(1024 * 1024 * 1024)
+
+// Provider specific usage estimation.
+type Estimator interface {
+	// Determine the usage of the source bottlenecks by a VM.
+	Usage(vmRef ref.Ref) (usage Usage, err error)
+}
+
+// Capacity aware scheduler.
+// The in-flight VMs and the in-flight usage of each bottleneck
+// are shared across all of the plans migrating from the same provider.
+type Scheduler struct {
+	*plancontext.Context
+	// Maximum number of VMs that can be
+	// migrated at once per provider.
+	MaxInFlight int
+	// Maximum number of disks that can be in flight at
+	// once on each bottleneck. Not limited when zero.
+	MaxDiskInFlight int
+	// Maximum size (GiB) of the disks that can be in flight
+	// at once on each bottleneck. Not limited when zero.
+	MaxDiskSizeInFlight int
+	// Provider specific usage estimation.
+	Estimator Estimator
+	// Number of VMs currently being migrated.
+	running int
+	// Mapping of bottlenecks to the cost
+	// currently being migrated.
+	inFlight map[string]Cost
+	// VMs waiting to be migrated in the order
+	// they should be started.
+	pending []*pendingVM
+}
+
+// Convenience struct to package a
+// VMStatus with the usage that is calculated
+// from the inventory VM object.
+type pendingVM struct {
+	status *plan.VMStatus
+	usage  Usage
+}
+
+// Return the next VM to migrate.
+func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	err = r.buildSchedule()
+	if err != nil {
+		return
+	}
+	if r.running >= r.MaxInFlight {
+		return
+	}
+	schedulable := r.schedulable()
+	if len(schedulable) > 0 {
+		vm = schedulable[0].status
+		hasNext = true
+	}
+
+	if hasNext {
+		r.Log.Info(
+			"Next scheduled VM.",
+			"vm",
+			vm.String())
+	}
+
+	return
+}
+
+// Determine how much capacity is occupied
+// by running migrations across all plans for
+// the same provider, and determine which
+// VMs are still waiting to be started.
+func (r *Scheduler) buildSchedule() (err error) {
+	err = r.buildInFlight()
+	if err != nil {
+		return
+	}
+
+	err = r.buildPending()
+	if err != nil {
+		return
+	}
+
+	r.Log.V(1).Info(
+		"Schedule built.",
+		"running",
+		r.running,
+		"inflight",
+		r.inFlight,
+		"pending",
+		len(r.pending))
+
+	return
+}
+
+// Count the VMs that are currently in flight and build
+// the map of the cost that is in flight for each bottleneck.
+func (r *Scheduler) buildInFlight() (err error) {
+	r.running = 0
+	r.inFlight = make(map[string]Cost)
+
+	// Since we modify the plan VMStatuses in memory,
+	// we need to use the plan from the context rather
+	// than from the list of plans that are retrieved below.
+	for _, vmStatus := range r.Plan.Status.Migration.VMs {
+		if !vmStatus.Running() {
+			continue
+		}
+		var usage Usage
+		usage, err = r.Estimator.Usage(vmStatus.Ref)
+		if err != nil {
+			return
+		}
+		r.add(usage)
+	}
+
+	planList := &api.PlanList{}
+	err = r.List(context.TODO(), planList)
+	if err != nil {
+		return liberr.Wrap(err)
+	}
+	for _, p := range planList.Items {
+		// skip this plan, it's already done.
+		if p.Name == r.Plan.Name && p.Namespace == r.Plan.Namespace {
+			continue
+		}
+
+		// ignore plans that aren't using the same source provider
+		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
+			continue
+		}
+
+		// skip plans that aren't being executed
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		if !snapshot.HasCondition("Executing") {
+			continue
+		}
+
+		for _, vmStatus := range p.Status.Migration.VMs {
+			if !vmStatus.Running() {
+				continue
+			}
+			usage, uErr := r.Estimator.Usage(vmStatus.Ref)
+			if uErr != nil {
+				if errors.As(uErr, &web.NotFoundError{}) {
+					continue
+				}
+				if errors.As(uErr, &web.RefNotUniqueError{}) {
+					continue
+				}
+				return uErr
+			}
+			r.add(usage)
+		}
+	}
+
+	return
+}
+
+// Build the list of pending VMs.
+// Only the VMs of the ordering groups that
+// are ready are listed, by priority.
+func (r *Scheduler) buildPending() (err error) {
+	r.pending = []*pendingVM{}
+
+	for _, vmStatus := range Pending(r.Plan) {
+		var usage Usage
+		usage, err = r.Estimator.Usage(vmStatus.Ref)
+		if err != nil {
+			return
+		}
+		r.pending = append(
+			r.pending,
+			&pendingVM{
+				status: vmStatus,
+				usage:  usage,
+			})
+	}
+	return
+}
+
+// Add the usage of a running VM.
+func (r *Scheduler) add(usage Usage) {
+	r.running++
+	for bottleneck, cost := range usage {
+		inFlight := r.inFlight[bottleneck]
+		inFlight.Disks += cost.Disks
+		inFlight.Size += cost.Size
+		r.inFlight[bottleneck] = inFlight
+	}
+}
+
+// Return the VMs that could be scheduled
+// based on the available capacities, in order.
+// The bottlenecks of a VM that does not fit are
+// reserved for it: the VMs after it that use any
+// of them are not scheduled so that they cannot
+// keep it waiting forever.
+func (r *Scheduler) schedulable() (schedulable []*pendingVM) {
+	reserved := make(map[string]bool)
+	for _, vm := range r.pending {
+		fits := r.fits(vm.usage)
+		for bottleneck := range vm.usage {
+			if reserved[bottleneck] {
+				fits = false
+			}
+		}
+		if fits {
+			schedulable = append(schedulable, vm)
+			continue
+		}
+		for bottleneck := range vm.usage {
+			reserved[bottleneck] = true
+		}
+	}
+
+	return
+}
+
+// Determine whether the usage fits the available capacity
+// of every bottleneck. A VM costing more than the maximum
+// is allowed on an idle bottleneck so that it is not
+// left waiting forever.
+func (r *Scheduler) fits(usage Usage) bool {
+	for bottleneck, cost := range usage {
+		inFlight, found := r.inFlight[bottleneck]
+		if !found || inFlight.Disks == 0 {
+			continue
+		}
+		if r.MaxDiskInFlight > 0 && inFlight.Disks+cost.Disks > r.MaxDiskInFlight {
+			return false
+		}
+		maxSize := int64(r.MaxDiskSizeInFlight) * GiB
+		if maxSize > 0 && inFlight.Size+cost.Size > maxSize {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestScheduler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	domainA := "domainA"
+	domainB := "domainB"
+	domainC := "domainC"
+	domainD := "domainD"
+
+	scheduler := Scheduler{MaxInFlight: 20, MaxDiskInFlight: 10}
+	scheduler.inFlight = map[string]Cost{
+		domainA: {Disks: 6},
+		domainB: {Disks: 10},
+	}
+	scheduler.pending = []*pendingVM{
+		// Fits the remaining capacity of domain A.
+		{usage: Usage{domainA: {Disks: 4}}},
+		// Fits both domain C and domain D.
+		{usage: Usage{domainC: {Disks: 2}, domainD: {Disks: 3}}},
+		// Costs more than the maximum but
+		// domain D is idle.
+		{usage: Usage{domainD: {Disks: 11}}},
+		// Exceeds the remaining capacity of domain A.
+		{usage: Usage{domainA: {Disks: 5}}},
+		// Domain B has reached capacity.
+		{usage: Usage{domainB: {Disks: 1}}},
+		// Fits domain A but it is reserved.
+		{usage: Usage{domainA: {Disks: 1}}},
+		// Fits domain C but domain B is reserved.
+		{usage: Usage{domainB: {Disks: 1}, domainC: {Disks: 1}}},
+		// Fits domain C but it is reserved by the previous VM.
+		{usage: Usage{domainC: {Disks: 1}}},
+	}
+
+	expected := []*pendingVM{
+		scheduler.pending[0],
+		scheduler.pending[1],
+		scheduler.pending[2],
+	}
+	g.Expect(scheduler.schedulable()).To(gomega.Equal(expected))
+}
+
+func TestSchedulerReserved(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	domainA := "domainA"
+
+	// A large VM first in order is not overtaken
+	// by the smaller VMs after it.
+	scheduler := Scheduler{MaxInFlight: 20, MaxDiskInFlight: 4}
+	scheduler.inFlight = map[string]Cost{
+		domainA: {Disks: 2},
+	}
+	scheduler.pending = []*pendingVM{
+		{usage: Usage{domainA: {Disks: 3}}},
+		{usage: Usage{domainA: {Disks: 1}}},
+	}
+	g.Expect(scheduler.schedulable()).To(gomega.BeEmpty())
+
+	// Started once the bottleneck is idle.
+	scheduler.inFlight = map[string]Cost{}
+	g.Expect(scheduler.schedulable()).To(gomega.Equal(scheduler.pending))
+}
+
+func TestSchedulerSize(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	domainA := "domainA"
+	domainB := "domainB"
+
+	scheduler := Scheduler{MaxInFlight: 20, MaxDiskSizeInFlight: 100}
+	scheduler.inFlight = map[string]Cost{
+		domainA: {Disks: 1, Size: 80 * GiB},
+		domainB: {Disks: 1, Size: 20 * GiB},
+	}
+	scheduler.pending = []*pendingVM{
+		// Exceeds the remaining size of domain A.
+		{usage: Usage{domainA: {Disks: 1, Size: 30 * GiB}}},
+		// Fits the remaining size of domain B.
+		{usage: Usage{domainB: {Disks: 4, Size: 80 * GiB}}},
+	}
+	expected := []*pendingVM{
+		scheduler.pending[1],
+	}
+	g.Expect(scheduler.schedulable()).To(gomega.Equal(expected))
+}
+
+func TestSchedulerNotLimited(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	domainA := "domainA"
+
+	// The disks are not limited per bottleneck.
+	scheduler := Scheduler{MaxInFlight: 2}
+	scheduler.inFlight = map[string]Cost{}
+	scheduler.pending = []*pendingVM{
+		{usage: Usage{domainA: {Disks: 30, Size: 300 * GiB}}},
+		{usage: Usage{domainA: {Disks: 40, Size: 400 * GiB}}},
+	}
+	g.Expect(scheduler.schedulable()).To(gomega.Equal(scheduler.pending))
+
+	// The running VMs are counted per provider.
+	scheduler.add(Usage{domainA: {Disks: 1, Size: GiB}})
+	scheduler.add(Usage{})
+	g.Expect(scheduler.running).To(gomega.Equal(2))
+	g.Expect(scheduler.inFlight[domainA]).To(gomega.Equal(Cost{Disks: 1, Size: GiB}))
+}
@@ -4,6 +4,7 @@ import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/ova"
@@ -30,21 +31,34 @@ func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
 		}
 	case api.OVirt:
 		scheduler = &ovirt.Scheduler{
-			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			Context:             ctx,
+			MaxInFlight:         settings.Settings.MaxInFlight,
+			MaxDiskInFlight:     settings.Settings.MaxDiskInFlight,
+			MaxDiskSizeInFlight: settings.Settings.MaxDiskSizeInFlight,
 		}
 	case api.OpenStack:
 		scheduler = &openstack.Scheduler{
-			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			Context:             ctx,
+			MaxInFlight:         settings.Settings.MaxInFlight,
+			MaxDiskInFlight:     settings.Settings.MaxDiskInFlight,
+			MaxDiskSizeInFlight: settings.Settings.MaxDiskSizeInFlight,
 		}
 	case api.OpenShift:
 		scheduler = &ocp.Scheduler{
-			Context:     ctx,
-			MaxInFlight: settings.Settings.MaxInFlight,
+			Context:             ctx,
+			MaxInFlight:         settings.Settings.MaxInFlight,
+			MaxDiskInFlight:     settings.Settings.MaxDiskInFlight,
+			MaxDiskSizeInFlight: settings.Settings.MaxDiskSizeInFlight,
 		}
 	case api.Ova:
 		scheduler = &ova.Scheduler{
+			Context:             ctx,
+			MaxInFlight:         settings.Settings.MaxInFlight,
+			MaxDiskInFlight:     settings.Settings.MaxDiskInFlight,
+			MaxDiskSizeInFlight: settings.Settings.MaxDiskSizeInFlight,
+		}
+	case api.GCP:
+		scheduler = &gcp.Scheduler{
 			Context:     ctx,
 			MaxInFlight: settings.Settings.MaxInFlight,
 		}
@@ -0,0 +1,32 @@
+package gcp
+
+import (
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/base"
+)
+
+// Scheduler for migrations from GCP.
+type Scheduler struct {
+	*plancontext.Context
+	// Maximum number of VMs that can be
+	// migrated at once per provider.
+	MaxInFlight int
+}
+
+// Return the next VM to migrate.
+func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	scheduler := base.Scheduler{
+		Context:     r.Context,
+		MaxInFlight: r.MaxInFlight,
+		Estimator:   r,
+	}
+	return scheduler.Next()
+}
+
+// The VMs are only limited per provider.
+func (r *Scheduler) Usage(vmRef ref.Ref) (usage base.Usage, err error) {
+	usage = base.Usage{}
+	return
+}
@@ -1,74 +1,77 @@
 package ocp
 
 import (
-	"context"
-	"sync"
-
-	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
-	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/base"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
-// Package level mutex to ensure that
-// multiple concurrent reconciles don't
-// attempt to schedule VMs into the same
-// slots.
-var mutex sync.Mutex
-
-// Scheduler for migrations from OpenStack.
+// Scheduler for migrations from OpenShift.
+// The volumes are exported from the source namespace.
 type Scheduler struct {
 	*plancontext.Context
 	// Maximum number of VMs that can be
 	// migrated at once per provider.
 	MaxInFlight int
+	// Maximum number of volumes per source namespace
+	// that can be migrated at once.
+	MaxDiskInFlight int
+	// Maximum size (GiB) of the volumes per source namespace
+	// that can be migrated at once.
+	MaxDiskSizeInFlight int
 }
 
+// Return the next VM to migrate.
 func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
-	mutex.Lock()
-	defer mutex.Unlock()
-
-	planList := &api.PlanList{}
-	err = r.List(context.TODO(), planList)
-	if err != nil {
-		err = liberr.Wrap(err)
-		return
+	scheduler := base.Scheduler{
+		Context:             r.Context,
+		MaxInFlight:         r.MaxInFlight,
+		MaxDiskInFlight:     r.MaxDiskInFlight,
+		MaxDiskSizeInFlight: r.MaxDiskSizeInFlight,
+		Estimator:           r,
 	}
+	return scheduler.Next()
+}
 
-	if r.calcInFlight(planList) >= r.MaxInFlight {
+// The number and size of the volumes of the VM in its namespace.
+// The size is known for the volumes of the DataVolume templates.
+func (r *Scheduler) Usage(vmRef ref.Ref) (usage base.Usage, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
 		return
 	}
-
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
-			vm = vmStatus
-			hasNext = true
-			return
+	sizes := make(map[string]int64)
+	for _, dv := range vm.Object.Spec.DataVolumeTemplates {
+		var request resource.Quantity
+		if dv.Spec.Storage != nil {
+			request = dv.Spec.Storage.Resources.Requests[core.ResourceStorage]
+		} else if dv.Spec.PVC != nil {
+			request = dv.Spec.PVC.Resources.Requests[core.ResourceStorage]
 		}
+		sizes[dv.Name] = request.Value()
 	}
-
-	return
-}
-
-func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
-	inFlight := 0
-	for _, p := range planList.Items {
-		// ignore plans that aren't using the same source provider
-		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
-			continue
-		}
-
-		// skip plans that aren't being executed
-		snapshot := p.Status.Migration.ActiveSnapshot()
-		if !snapshot.HasCondition("Executing") {
-			continue
-		}
-
-		for _, vmStatus := range p.Status.Migration.VMs {
-			if vmStatus.Running() {
-				inFlight++
+	cost := base.Cost{}
+	if template := vm.Object.Spec.Template; template != nil {
+		for _, volume := range template.Spec.Volumes {
+			if volume.DataVolume != nil {
+				cost.Disks++
+				cost.Size += sizes[volume.DataVolume.Name]
+			}
+			if volume.PersistentVolumeClaim != nil {
+				cost.Disks++
 			}
 		}
 	}
-	return inFlight
+	if cost.Disks == 0 {
+		cost.Disks = 1
+	}
+	usage = base.Usage{
+		vm.Object.Namespace: cost,
+	}
+	return
 }
@@ -1,74 +1,63 @@
 package openstack
 
 import (
-	"context"
-	"sync"
+	"path"
 
-	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
-	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/base"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 )
 
-// Package level mutex to ensure that
-// multiple concurrent reconciles don't
-// attempt to schedule VMs into the same
-// slots.
-var mutex sync.Mutex
+// Image based VMs are read through the image service.
+const ImageService = "glance"
 
 // Scheduler for migrations from OpenStack.
+// The volumes are read through the Cinder backends,
+// identified by the availability zone and the volume type.
 type Scheduler struct {
 	*plancontext.Context
 	// Maximum number of VMs that can be
 	// migrated at once per provider.
 	MaxInFlight int
+	// Maximum number of volumes per Cinder backend
+	// that can be migrated at once.
+	MaxDiskInFlight int
+	// Maximum size (GiB) of the volumes per Cinder backend
+	// that can be migrated at once.
+	MaxDiskSizeInFlight int
 }
 
+// Return the next VM to migrate.
 func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
-	mutex.Lock()
-	defer mutex.Unlock()
+	scheduler := base.Scheduler{
+		Context:             r.Context,
+		MaxInFlight:         r.MaxInFlight,
+		MaxDiskInFlight:     r.MaxDiskInFlight,
+		MaxDiskSizeInFlight: r.MaxDiskSizeInFlight,
+		Estimator:           r,
+	}
+	return scheduler.Next()
+}
 
-	planList := &api.PlanList{}
-	err = r.List(context.TODO(), planList)
+// The number and size of the volumes of the VM on each Cinder backend.
+func (r *Scheduler) Usage(vmRef ref.Ref) (usage base.Usage, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
 	if err != nil {
-		err = liberr.Wrap(err)
 		return
 	}
-
-	if r.calcInFlight(planList) >= r.MaxInFlight {
-		return
+	usage = base.Usage{}
+	for _, volume := range vm.Volumes {
+		bottleneck := path.Join(volume.AvailabilityZone, volume.VolumeType)
+		cost := usage[bottleneck]
+		cost.Disks++
+		cost.Size += int64(volume.Size) * base.GiB
+		usage[bottleneck] = cost
 	}
-
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
-			vm = vmStatus
-			hasNext = true
-			return
-		}
+	if len(usage) == 0 {
+		usage[ImageService] = base.Cost{Disks: 1}
 	}
-
 	return
 }
-
-func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
-	inFlight := 0
-	for _, p := range planList.Items {
-		// ignore plans that aren't using the same source provider
-		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
-			continue
-		}
-
-		// skip plans that aren't being executed
-		snapshot := p.Status.Migration.ActiveSnapshot()
-		if !snapshot.HasCondition("Executing") {
-			continue
-		}
-
-		for _, vmStatus := range p.Status.Migration.VMs {
-			if vmStatus.Running() {
-				inFlight++
-			}
-		}
-	}
-	return inFlight
-}
@@ -1,71 +1,61 @@
 package ova
 
 import (
-	"context"
-	"sync"
-
-	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	adapter "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/ova"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
-	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/base"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ova"
 )
 
-// Package level mutex to ensure that
-// multiple concurrent reconciles don't
-// attempt to schedule VMs into the same
-// slots.
-var mutex sync.Mutex
-
 // Scheduler for migrations from OVA.
+// All of the disks are read from the NFS export of the provider.
 type Scheduler struct {
 	*plancontext.Context
 	// Maximum number of VMs that can be
 	// migrated at once per provider.
 	MaxInFlight int
+	// Maximum number of disks that can be
+	// read from the NFS export at once.
+	MaxDiskInFlight int
+	// Maximum size (GiB) of the disks that can be
+	// read from the NFS export at once.
+	MaxDiskSizeInFlight int
 }
 
+// Return the next VM to migrate.
 func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
-	mutex.Lock()
-	defer mutex.Unlock()
+	scheduler := base.Scheduler{
+		Context:             r.Context,
+		MaxInFlight:         r.MaxInFlight,
+		MaxDiskInFlight:     r.MaxDiskInFlight,
+		MaxDiskSizeInFlight: r.MaxDiskSizeInFlight,
+		Estimator:           r,
+	}
+	return scheduler.Next()
+}
 
-	planList := &api.PlanList{}
-	err = r.List(context.TODO(), planList)
+// The number and size of the disks of the VM read from the NFS export.
+// The size of a disk with invalid capacity units is not accounted.
+func (r *Scheduler) Usage(vmRef ref.Ref) (usage base.Usage, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
 	if err != nil {
-		err = liberr.Wrap(err)
 		return
 	}
-
-	inFlight := 0
-	for _, p := range planList.Items {
-		// ignore plans that aren't using the same source provider
-		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
-			continue
-		}
-
-		// skip plans that aren't being executed
-		snapshot := p.Status.Migration.ActiveSnapshot()
-		if !snapshot.HasCondition("Executing") {
-			continue
-		}
-
-		for _, vmStatus := range p.Status.Migration.VMs {
-			if vmStatus.Running() {
-				inFlight++
-			}
+	cost := base.Cost{Disks: len(vm.Disks)}
+	for i := range vm.Disks {
+		size, sErr := adapter.DiskSize(&vm.Disks[i])
+		if sErr == nil {
+			cost.Size += size
 		}
 	}
-
-	if inFlight >= r.MaxInFlight {
-		return
+	if cost.Disks == 0 {
+		cost.Disks = 1
 	}
-
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
-			vm = vmStatus
-			hasNext = true
-			return
-		}
+	usage = base.Usage{
+		r.Source.Provider.Spec.URL: cost,
 	}
-
 	return
 }
@@ -1,74 +1,60 @@
 package ovirt
 
 import (
-	"context"
-	"sync"
-
-	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
-	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/base"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/ovirt"
 )
 
-// Package level mutex to ensure that
-// multiple concurrent reconciles don't
-// attempt to schedule VMs into the same
-// slots.
-var mutex sync.Mutex
-
 // Scheduler for migrations from oVirt.
+// The disks are read through the storage domains.
 type Scheduler struct {
 	*plancontext.Context
 	// Maximum number of VMs that can be
 	// migrated at once per provider.
 	MaxInFlight int
+	// Maximum number of disks per storage domain
+	// that can be migrated at once.
+	MaxDiskInFlight int
+	// Maximum size (GiB) of the disks per storage domain
+	// that can be migrated at once.
+	MaxDiskSizeInFlight int
 }
 
+// Return the next VM to migrate.
 func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
-	mutex.Lock()
-	defer mutex.Unlock()
-
-	planList := &api.PlanList{}
-	err = r.List(context.TODO(), planList)
-	if err != nil {
-		err = liberr.Wrap(err)
-		return
+	scheduler := base.Scheduler{
+		Context:             r.Context,
+		MaxInFlight:         r.MaxInFlight,
+		MaxDiskInFlight:     r.MaxDiskInFlight,
+		MaxDiskSizeInFlight: r.MaxDiskSizeInFlight,
+		Estimator:           r,
 	}
+	return scheduler.Next()
+}
 
-	if r.calcInFlight(planList) >= r.MaxInFlight {
+// The number and size of the disks of the VM on each storage
+// domain. Diskless VMs are accounted on their cluster.
+func (r *Scheduler) Usage(vmRef ref.Ref) (usage base.Usage, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
 		return
 	}
-
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
-			vm = vmStatus
-			hasNext = true
-			return
-		}
-	}
-
-	return
-}
-
-func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
-	inFlight := 0
-	for _, p := range planList.Items {
-		// ignore plans that aren't using the same source provider
-		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
-			continue
-		}
-
-		// skip plans that aren't being executed
-		snapshot := p.Status.Migration.ActiveSnapshot()
-		if !snapshot.HasCondition("Executing") {
+	usage = base.Usage{}
+	for _, da := range vm.DiskAttachments {
+		if da.Disk.StorageDomain == "" {
 			continue
 		}
-
-		for _, vmStatus := range p.Status.Migration.VMs {
-			if vmStatus.Running() {
-				inFlight++
-			}
-		}
+		cost := usage[da.Disk.StorageDomain]
+		cost.Disks++
+		cost.Size += da.Disk.ProvisionedSize
+		usage[da.Disk.StorageDomain] = cost
 	}
-	return inFlight
+	if len(usage) == 0 {
+		usage[vm.Cluster.ID] = base.Cost{Disks: 1}
+	}
+	return
 }
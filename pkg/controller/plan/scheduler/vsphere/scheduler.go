@@ -9,6 +9,7 @@ import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/scheduler/base"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/web/vsphere"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 )
@@ -39,6 +40,8 @@ type Scheduler struct {
 type pendingVM struct {
 	status *plan.VMStatus
 	cost   int
+	// Position in the start order.
+	order int
 }
 
 // Return the next VM to migrate.
@@ -49,12 +52,16 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 	if err != nil {
 		return
 	}
+	var next *pendingVM
 	for _, vms := range r.schedulable() {
-		if len(vms) > 0 {
-			vm = vms[0].status
-			hasNext = true
+		if len(vms) > 0 && (next == nil || vms[0].order < next.order) {
+			next = vms[0]
 		}
 	}
+	if next != nil {
+		vm = next.status
+		hasNext = true
+	}
 
 	if hasNext {
 		r.Log.Info(
@@ -155,23 +162,23 @@ func (r *Scheduler) buildInFlight() (err error) {
 }
 
 // Build the map of pending VMs belonging to each host.
+// Only the VMs of the ordering groups that are
+// ready are listed, by priority.
 func (r *Scheduler) buildPending() (err error) {
 	r.pending = make(map[string][]*pendingVM)
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
+	for i, vmStatus := range base.Pending(r.Plan) {
 		vm := &model.VM{}
 		err = r.Source.Inventory.Find(vm, vmStatus.Ref)
 		if err != nil {
 			return
 		}
-
-		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
-			pending := &pendingVM{
-				status: vmStatus,
-				cost:   len(vm.Disks),
-			}
-			r.pending[vm.Host] = append(r.pending[vm.Host], pending)
+		pending := &pendingVM{
+			status: vmStatus,
+			cost:   len(vm.Disks),
+			order:  i,
 		}
+		r.pending[vm.Host] = append(r.pending[vm.Host], pending)
 	}
 	return
 }
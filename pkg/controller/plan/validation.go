@@ -10,6 +10,7 @@ import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
 	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
 	"github.com/konveyor/forklift-controller/pkg/controller/validation"
 	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
@@ -23,6 +24,7 @@ import (
 // Types
 const (
 	WarmMigrationNotReady        = "WarmMigrationNotReady"
+	DeltaSyncNotSupported        = "DeltaSyncNotSupported"
 	NamespaceNotValid            = "NamespaceNotValid"
 	TransferNetNotValid          = "TransferNetworkNotValid"
 	NetRefNotValid               = "NetworkMapRefNotValid"
@@ -34,7 +36,10 @@ const (
 	VMAlreadyExists              = "VMAlreadyExists"
 	VMNetworksNotMapped          = "VMNetworksNotMapped"
 	VMStorageNotMapped           = "VMStorageNotMapped"
+	VMDiskOverridesNotValid      = "VMDiskOverridesNotValid"
 	VMMultiplePodNetworkMappings = "VMMultiplePodNetworkMappings"
+	VMTargetNotCompatible        = "VMTargetNotCompatible"
+	VMTargetNotValidated         = "VMTargetNotValidated"
 	HostNotReady                 = "HostNotReady"
 	DuplicateVM                  = "DuplicateVM"
 	NameNotValid                 = "TargetNameNotValid"
@@ -52,6 +57,12 @@ const (
 	Blocked                      = "Blocked"
 	Archived                     = "Archived"
 	VDDKNotConfigured            = "VDDKNotConfigured"
+	WaitingForWindow             = "WaitingForWindow"
+	GroupNotValid                = "GroupNotValid"
+	GroupNameNotValid            = "GroupNameNotValid"
+	GroupNotUnique               = "GroupNotUnique"
+	GroupRefNotValid             = "GroupRefNotValid"
+	RolledBack                   = "RolledBack"
 )
 
 // Categories
@@ -74,6 +85,9 @@ const (
 	Modified          = "Modified"
 	UserRequested     = "UserRequested"
 	InMaintenanceMode = "InMaintenanceMode"
+	NotStarted        = "NotStarted"
+	WindowClosed      = "WindowClosed"
+	ValidationFailed  = "ValidationFailed"
 )
 
 // Statuses
@@ -119,6 +133,9 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 	//
+	// Delta sync
+	r.validateDeltaSync(plan)
+	//
 	// VM list.
 	err = r.validateVM(plan)
 	if err != nil {
@@ -130,6 +147,9 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 	if err != nil {
 		return err
 	}
+	//
+	// Ordering groups.
+	r.validateGroups(plan)
 	// VM Hooks.
 	err = r.validateHooks(plan)
 	if err != nil {
@@ -173,6 +193,35 @@ func (r *Reconciler) validateWarmMigration(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate the delta sync.
+// The disks are fully copied when the delta sync is not supported.
+func (r *Reconciler) validateDeltaSync(plan *api.Plan) {
+	if !plan.Spec.DeltaSync {
+		return
+	}
+	newCnd := libcnd.Condition{
+		Type:     DeltaSyncNotSupported,
+		Status:   True,
+		Category: Warn,
+		Reason:   NotSupported,
+	}
+	if plan.Spec.Warm {
+		newCnd.Message = "Delta sync is ignored by warm migrations."
+		plan.Status.SetCondition(newCnd)
+		return
+	}
+	provider := plan.Referenced.Provider.Source
+	if provider == nil {
+		return
+	}
+	switch provider.Type() {
+	case api.VSphere, api.OVirt:
+	default:
+		newCnd.Message = "Delta sync from the source provider is not supported, the disks are fully copied."
+		plan.Status.SetCondition(newCnd)
+	}
+}
+
 // Validate the target namespace.
 func (r *Reconciler) validateTargetNamespace(plan *api.Plan) (err error) {
 	newCnd := libcnd.Condition{
@@ -342,6 +391,14 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has unmapped storage.",
 		Items:    []string{},
 	}
+	diskOverridesNotValid := libcnd.Condition{
+		Type:     VMDiskOverridesNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "VM has disk overrides that are not valid.",
+		Items:    []string{},
+	}
 	maintenanceMode := libcnd.Condition{
 		Type:     HostNotReady,
 		Status:   True,
@@ -358,6 +415,35 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has more than one interface mapped to the pod network.",
 		Items:    []string{},
 	}
+	targetNotCompatible := libcnd.Condition{
+		Type:     VMTargetNotCompatible,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: Warn,
+		Message:  "VM has node or storage requirements that the target cluster does not satisfy.",
+		Items:    []string{},
+	}
+	targetNotValidated := libcnd.Condition{
+		Type:     VMTargetNotValidated,
+		Status:   True,
+		Reason:   ValidationFailed,
+		Category: Warn,
+		Message:  "VM requirements could not be validated against the target cluster.",
+		Items:    []string{},
+	}
+	// The target cluster is reached through the plan context
+	// which is built once for all of the VMs.
+	var targetCtx *plancontext.Context
+	var targetErr error
+	if plan.Referenced.Provider.Destination != nil &&
+		plan.Referenced.Map.Network != nil &&
+		plan.Referenced.Map.Storage != nil {
+		targetCtx, targetErr = plancontext.New(r, plan, r.Log)
+		if targetErr != nil {
+			r.Log.Error(targetErr, "Couldn't construct plan context when validating the target cluster.")
+		}
+	}
+	var validator adapter.Validator
 
 	setOf := map[string]bool{}
 	//
@@ -403,13 +489,15 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		} else {
 			setOf[ref.ID] = true
 		}
-		pAdapter, err := adapter.New(provider)
-		if err != nil {
-			return err
-		}
-		validator, err := pAdapter.Validator(plan)
-		if err != nil {
-			return err
+		if validator == nil {
+			pAdapter, err := adapter.New(provider)
+			if err != nil {
+				return err
+			}
+			validator, err = pAdapter.Validator(plan)
+			if err != nil {
+				return err
+			}
 		}
 		if plan.Referenced.Map.Network != nil {
 			ok, err := validator.NetworksMapped(*ref)
@@ -436,6 +524,10 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				unmappedStorage.Items = append(unmappedStorage.Items, ref.String())
 			}
 		}
+		diskOverridesOk, err := validator.DiskOverrides(*ref)
+		if err != nil {
+			return err
+		}
 		ok, err := validator.MaintenanceMode(*ref)
 		if err != nil {
 			return err
@@ -443,6 +535,17 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		if !ok {
 			maintenanceMode.Items = append(maintenanceMode.Items, ref.String())
 		}
+		if targetErr != nil {
+			targetNotValidated.Items = append(targetNotValidated.Items, ref.String())
+		} else {
+			ok, err = validator.TargetCompatible(targetCtx, *ref)
+			if err != nil {
+				r.Log.Error(err, "Couldn't validate the VM against the target cluster.", "vm", ref.String())
+				targetNotValidated.Items = append(targetNotValidated.Items, ref.String())
+			} else if !ok {
+				targetNotCompatible.Items = append(targetNotCompatible.Items, ref.String())
+			}
+		}
 		// Destination.
 		provider = plan.Referenced.Provider.Destination
 		if provider == nil {
@@ -469,6 +572,21 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				return liberr.Wrap(pErr)
 			}
 		}
+		for _, disk := range plan.Spec.VMs[i].Disks {
+			if disk.StorageClass == "" {
+				continue
+			}
+			_, pErr = inventory.Storage(&refapi.Ref{Name: disk.StorageClass})
+			if pErr != nil {
+				if !errors.As(pErr, &web.NotFoundError{}) {
+					return liberr.Wrap(pErr)
+				}
+				diskOverridesOk = false
+			}
+		}
+		if !diskOverridesOk {
+			diskOverridesNotValid.Items = append(diskOverridesNotValid.Items, ref.String())
+		}
 	}
 	if len(notFound.Items) > 0 {
 		plan.Status.SetCondition(notFound)
@@ -491,12 +609,21 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(unmappedStorage.Items) > 0 {
 		plan.Status.SetCondition(unmappedStorage)
 	}
+	if len(diskOverridesNotValid.Items) > 0 {
+		plan.Status.SetCondition(diskOverridesNotValid)
+	}
 	if len(maintenanceMode.Items) > 0 {
 		plan.Status.SetCondition(maintenanceMode)
 	}
 	if len(multiplePodNetworkMappings.Items) > 0 {
 		plan.Status.SetCondition(multiplePodNetworkMappings)
 	}
+	if len(targetNotCompatible.Items) > 0 {
+		plan.Status.SetCondition(targetNotCompatible)
+	}
+	if len(targetNotValidated.Items) > 0 {
+		plan.Status.SetCondition(targetNotValidated)
+	}
 
 	return nil
 }
@@ -531,6 +658,127 @@ func (r *Reconciler) validateTransferNetwork(plan *api.Plan) (err error) {
 	return
 }
 
+// Validate the ordering groups.
+// Groups must be uniquely named, may only depend on defined
+// groups and the dependencies must not form a cycle.
+func (r *Reconciler) validateGroups(plan *api.Plan) {
+	notSet := libcnd.Condition{
+		Type:     GroupNameNotValid,
+		Status:   True,
+		Reason:   NotSet,
+		Category: Critical,
+		Message:  "Ordering group `name` must be specified.",
+		Items:    []string{},
+	}
+	notUnique := libcnd.Condition{
+		Type:     GroupNotUnique,
+		Status:   True,
+		Reason:   NotUnique,
+		Category: Critical,
+		Message:  "Ordering group names must be unique.",
+		Items:    []string{},
+	}
+	notFound := libcnd.Condition{
+		Type:     GroupRefNotValid,
+		Status:   True,
+		Reason:   NotFound,
+		Category: Critical,
+		Message:  "Ordering group not found.",
+		Items:    []string{},
+	}
+	notValid := libcnd.Condition{
+		Type:     GroupNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: Critical,
+		Message:  "Ordering group dependencies form a cycle.",
+		Items:    []string{},
+	}
+	groups := make(map[string][]string)
+	for i, group := range plan.Spec.Groups {
+		if group.Name == "" {
+			description := fmt.Sprintf("groups[%d]", i)
+			notSet.Items = append(notSet.Items, description)
+			continue
+		}
+		if _, found := groups[group.Name]; found {
+			notUnique.Items = append(notUnique.Items, group.Name)
+			continue
+		}
+		groups[group.Name] = group.DependsOn
+	}
+	for _, group := range plan.Spec.Groups {
+		for _, dependency := range group.DependsOn {
+			if _, found := groups[dependency]; !found {
+				description := fmt.Sprintf(
+					"group: %s dependsOn: %s",
+					group.Name,
+					dependency)
+				notFound.Items = append(
+					notFound.Items,
+					description)
+			}
+		}
+	}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Group == "" {
+			continue
+		}
+		if _, found := groups[vm.Group]; !found {
+			description := fmt.Sprintf(
+				"VM: %s group: %s",
+				vm.String(),
+				vm.Group)
+			notFound.Items = append(
+				notFound.Items,
+				description)
+		}
+	}
+	// Depth-first search for cycles.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+		state[name] = visiting
+		for _, dependency := range groups[name] {
+			if visit(dependency) {
+				return true
+			}
+		}
+		state[name] = visited
+		return false
+	}
+	for _, group := range plan.Spec.Groups {
+		if _, found := state[group.Name]; found {
+			continue
+		}
+		if visit(group.Name) {
+			notValid.Items = append(notValid.Items, group.Name)
+		}
+	}
+	if len(notSet.Items) > 0 {
+		plan.Status.SetCondition(notSet)
+	}
+	if len(notUnique.Items) > 0 {
+		plan.Status.SetCondition(notUnique)
+	}
+	if len(notFound.Items) > 0 {
+		plan.Status.SetCondition(notFound)
+	}
+	if len(notValid.Items) > 0 {
+		plan.Status.SetCondition(notValid)
+	}
+}
+
 // Validate referenced hooks.
 func (r *Reconciler) validateHooks(plan *api.Plan) (err error) {
 	notSet := libcnd.Condition{
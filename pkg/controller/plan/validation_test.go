@@ -0,0 +1,39 @@
+package plan
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func TestValidateGroups(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	p := &api.Plan{}
+	p.Spec.Groups = []plan.Group{
+		{},
+		{Name: "a", DependsOn: []string{"b"}},
+		{Name: "a"},
+		{Name: "b", DependsOn: []string{"a", "c"}},
+	}
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}, Group: "d"},
+	}
+	reconciler := &Reconciler{}
+	reconciler.validateGroups(p)
+	// Each problem is reported by its own condition.
+	notSet := p.Status.FindCondition(GroupNameNotValid)
+	g.Expect(notSet).ToNot(gomega.BeNil())
+	g.Expect(notSet.Items).To(gomega.Equal([]string{"groups[0]"}))
+	notUnique := p.Status.FindCondition(GroupNotUnique)
+	g.Expect(notUnique).ToNot(gomega.BeNil())
+	g.Expect(notUnique.Items).To(gomega.Equal([]string{"a"}))
+	notFound := p.Status.FindCondition(GroupRefNotValid)
+	g.Expect(notFound).ToNot(gomega.BeNil())
+	g.Expect(notFound.Items).To(gomega.HaveLen(2))
+	cycle := p.Status.FindCondition(GroupNotValid)
+	g.Expect(cycle).ToNot(gomega.BeNil())
+	g.Expect(cycle.Items).ToNot(gomega.BeEmpty())
+}
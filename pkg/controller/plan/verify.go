@@ -0,0 +1,299 @@
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	libcnd "github.com/konveyor/forklift-controller/pkg/lib/condition"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Verification conditions.
+const (
+	Verified            = "Verified"
+	VerificationFailed  = "VerificationFailed"
+	VerificationSkipped = "VerificationSkipped"
+)
+
+// Post-migration verification.
+// The target VM is compared to the source VM found in the inventory:
+// the number of vCPUs, the memory, the NICs and their MAC addresses and
+// the disks and their sizes. A target VM that is powered on after the
+// migration, because the source VM was, is started and compared once the
+// guest agent has connected. Any other target VM is left powered off and
+// only its specification is compared. The result is recorded on the VM
+// as either the Verified or the VerificationFailed condition listing the
+// mismatches. A failed verification does not fail the migration.
+
+// Verify the target VM.
+// Returns true when the verification has ended.
+func (r *Migration) verify(vm *plan.VMStatus, step *plan.Step) (done bool, err error) {
+	spec, err := r.builder.SourceSpec(vm.Ref)
+	if err != nil {
+		return
+	}
+	if spec == nil {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     VerificationSkipped,
+				Status:   True,
+				Category: Advisory,
+				Message:  "The verification of the VM is not supported by the source provider.",
+				Durable:  true,
+			})
+		done = true
+		return
+	}
+	if vm.RestorePowerState != On {
+		done, err = r.verifySpec(vm, spec)
+		return
+	}
+	err = r.setRunning(vm, true)
+	if err != nil || vm.Error != nil {
+		return
+	}
+	vmCr := r.vmMap[vm.ID]
+	vmi := &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		types.NamespacedName{
+			Namespace: vmCr.Namespace,
+			Name:      vmCr.Name,
+		},
+		vmi)
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+		vmi = nil
+	}
+	if vmi == nil || vmi.Status.Phase != cnv.Running || !r.agentConnected(vmi) {
+		timeout := time.Duration(settings.Settings.Migration.VerificationTimeout) * time.Minute
+		if time.Since(step.Started.Time) < timeout {
+			return
+		}
+		r.verified(vm, []string{"The guest agent has not connected to the running VM."})
+		done = true
+		return
+	}
+	mismatches, err := r.compare(spec, vmi)
+	if err != nil {
+		return
+	}
+	r.verified(vm, mismatches)
+	done = true
+	return
+}
+
+// Verify the specification of the target VM that is not started.
+// Returns true when the verification has ended.
+func (r *Migration) verifySpec(vm *plan.VMStatus, spec *planbase.VMSpec) (done bool, err error) {
+	vmCr, found, err := r.virtualMachine(vm)
+	if err != nil || !found {
+		return
+	}
+	if vmCr.Spec.Template == nil {
+		r.verified(vm, []string{"The VM has no template."})
+		done = true
+		return
+	}
+	vmi := &cnv.VirtualMachineInstance{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: vmCr.Namespace,
+			Name:      vmCr.Name,
+		},
+		Spec: vmCr.Spec.Template.Spec,
+	}
+	mismatches, err := r.compare(spec, vmi)
+	if err != nil {
+		return
+	}
+	r.verified(vm, mismatches)
+	done = true
+	return
+}
+
+// Record the verification result.
+func (r *Migration) verified(vm *plan.VMStatus, mismatches []string) {
+	if len(mismatches) == 0 {
+		vm.DeleteCondition(VerificationFailed)
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     Verified,
+				Status:   True,
+				Category: Advisory,
+				Message:  "The VM verification has PASSED.",
+				Durable:  true,
+			})
+		r.Log.Info(
+			"VM verification [PASSED]",
+			"vm",
+			vm.String())
+		return
+	}
+	vm.DeleteCondition(Verified)
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     VerificationFailed,
+			Status:   True,
+			Category: Warn,
+			Message:  "The VM verification has FAILED.",
+			Items:    mismatches,
+			Durable:  true,
+		})
+	r.Log.Info(
+		"VM verification [FAILED]",
+		"vm",
+		vm.String(),
+		"mismatches",
+		mismatches)
+}
+
+// Whether the guest agent has connected.
+func (r *Migration) agentConnected(vmi *cnv.VirtualMachineInstance) bool {
+	for _, cnd := range vmi.Status.Conditions {
+		if cnd.Type == cnv.VirtualMachineInstanceAgentConnected {
+			return cnd.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Compare the source VM specification with the running target VM.
+// Returns the list of mismatches.
+func (r *Migration) compare(spec *planbase.VMSpec, vmi *cnv.VirtualMachineInstance) (mismatches []string, err error) {
+	domain := vmi.Spec.Domain
+	cpus := 1
+	if cpu := domain.CPU; cpu != nil {
+		for _, n := range []uint32{cpu.Sockets, cpu.Cores, cpu.Threads} {
+			if n > 0 {
+				cpus *= int(n)
+			}
+		}
+	}
+	if cpus != spec.CPUs {
+		mismatches = append(
+			mismatches,
+			fmt.Sprintf("vCPUs: source=%d target=%d.", spec.CPUs, cpus))
+	}
+	var memory int64
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		memory = domain.Memory.Guest.Value()
+	} else if quantity, found := domain.Resources.Requests[core.ResourceMemory]; found {
+		memory = quantity.Value()
+	}
+	if memory != spec.Memory {
+		mismatches = append(
+			mismatches,
+			fmt.Sprintf(
+				"Memory: source=%s target=%s.",
+				resource.NewQuantity(spec.Memory, resource.BinarySI),
+				resource.NewQuantity(memory, resource.BinarySI)))
+	}
+	mismatches = append(mismatches, r.compareNICs(spec, vmi)...)
+	disks, err := r.compareDisks(spec, vmi)
+	if err != nil {
+		return
+	}
+	mismatches = append(mismatches, disks...)
+	return
+}
+
+// Compare the NICs.
+// Unless regenerated, the source MAC addresses
+// must be found on the target VM.
+func (r *Migration) compareNICs(spec *planbase.VMSpec, vmi *cnv.VirtualMachineInstance) (mismatches []string) {
+	interfaces := vmi.Spec.Domain.Devices.Interfaces
+	if len(interfaces) != len(spec.MACs) {
+		mismatches = append(
+			mismatches,
+			fmt.Sprintf("NICs: source=%d target=%d.", len(spec.MACs), len(interfaces)))
+	}
+	if !r.Plan.Spec.PreserveMAC(true) {
+		return
+	}
+	macs := make(map[string]bool)
+	for _, iface := range interfaces {
+		macs[strings.ToLower(iface.MacAddress)] = true
+	}
+	for _, iface := range vmi.Status.Interfaces {
+		macs[strings.ToLower(iface.MAC)] = true
+	}
+	for _, mac := range spec.MACs {
+		if !macs[strings.ToLower(mac)] {
+			mismatches = append(
+				mismatches,
+				fmt.Sprintf("NIC: MAC address %s not found on the target.", mac))
+		}
+	}
+	return
+}
+
+// Compare the disks.
+// The disks are matched by size, largest first. The target disks
+// may not be smaller than the source disks.
+func (r *Migration) compareDisks(spec *planbase.VMSpec, vmi *cnv.VirtualMachineInstance) (mismatches []string, err error) {
+	target := []int64{}
+	for _, volume := range vmi.Spec.Volumes {
+		var claim string
+		switch {
+		case volume.PersistentVolumeClaim != nil:
+			claim = volume.PersistentVolumeClaim.ClaimName
+		case volume.DataVolume != nil:
+			claim = volume.DataVolume.Name
+		default:
+			continue
+		}
+		pvc := &core.PersistentVolumeClaim{}
+		err = r.Destination.Client.Get(
+			context.TODO(),
+			types.NamespacedName{
+				Namespace: vmi.Namespace,
+				Name:      claim,
+			},
+			pvc)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		size, found := pvc.Status.Capacity[core.ResourceStorage]
+		if !found {
+			size = pvc.Spec.Resources.Requests[core.ResourceStorage]
+		}
+		target = append(target, size.Value())
+	}
+	if len(target) != len(spec.Disks) {
+		mismatches = append(
+			mismatches,
+			fmt.Sprintf("Disks: source=%d target=%d.", len(spec.Disks), len(target)))
+		return
+	}
+	source := append([]int64{}, spec.Disks...)
+	sort.Slice(source, func(i, j int) bool { return source[i] > source[j] })
+	sort.Slice(target, func(i, j int) bool { return target[i] > target[j] })
+	for i := range source {
+		if target[i] < source[i] {
+			mismatches = append(
+				mismatches,
+				fmt.Sprintf(
+					"Disk: source=%s target=%s.",
+					resource.NewQuantity(source[i], resource.BinarySI),
+					resource.NewQuantity(target[i], resource.BinarySI)))
+		}
+	}
+	return
+}
@@ -0,0 +1,177 @@
+package plan
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/plan"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
+	planbase "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter/base"
+	plancontext "github.com/konveyor/forklift-controller/pkg/controller/plan/context"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	cnv "kubevirt.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Builder of the source VM specification.
+type specBuilder struct {
+	adapter.Builder
+	spec *planbase.VMSpec
+}
+
+func (r *specBuilder) SourceSpec(vmRef ref.Ref) (*planbase.VMSpec, error) {
+	return r.spec, nil
+}
+
+func TestVerify(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	migration := Migration{
+		Context: &plancontext.Context{
+			Plan: &api.Plan{},
+		},
+	}
+
+	vmi := &cnv.VirtualMachineInstance{
+		Spec: cnv.VirtualMachineInstanceSpec{
+			Domain: cnv.DomainSpec{
+				CPU: &cnv.CPU{Sockets: 2, Cores: 2},
+				Resources: cnv.ResourceRequirements{
+					Requests: core.ResourceList{
+						core.ResourceMemory: *resource.NewQuantity(0x80000000, resource.BinarySI),
+					},
+				},
+				Devices: cnv.Devices{
+					Interfaces: []cnv.Interface{{MacAddress: "00:50:56:AA:BB:CC"}},
+				},
+			},
+		},
+	}
+
+	//Test the agent connection
+	g.Expect(migration.agentConnected(vmi)).To(gomega.BeFalse())
+	vmi.Status.Conditions = []cnv.VirtualMachineInstanceCondition{
+		{Type: cnv.VirtualMachineInstanceAgentConnected, Status: core.ConditionTrue},
+	}
+	g.Expect(migration.agentConnected(vmi)).To(gomega.BeTrue())
+
+	//Test a matching VM
+	spec := &planbase.VMSpec{
+		CPUs:   4,
+		Memory: 0x80000000,
+		MACs:   []string{"00:50:56:aa:bb:cc"},
+	}
+	mismatches, err := migration.compare(spec, vmi)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(mismatches).To(gomega.BeEmpty())
+
+	//Test the mismatches
+	spec = &planbase.VMSpec{
+		CPUs:   2,
+		Memory: 0x40000000,
+		MACs:   []string{"00:50:56:aa:bb:cc", "00:50:56:aa:bb:cd"},
+	}
+	mismatches, err = migration.compare(spec, vmi)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(mismatches).To(gomega.ConsistOf(
+		"vCPUs: source=2 target=4.",
+		"Memory: source=1Gi target=2Gi.",
+		"NICs: source=2 target=1.",
+		"NIC: MAC address 00:50:56:aa:bb:cd not found on the target."))
+
+	//Test the regenerated MAC addresses are not compared
+	migration.Plan.Spec.MACPolicy = api.MACRegenerate
+	mismatches = migration.compareNICs(spec, vmi)
+	g.Expect(mismatches).To(gomega.ConsistOf("NICs: source=2 target=1."))
+}
+
+func TestVerifyNotRunning(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	_ = core.AddToScheme(scheme)
+	_ = cnv.AddToScheme(scheme)
+	running := false
+	vmCr := &cnv.VirtualMachine{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "vm"},
+		Spec: cnv.VirtualMachineSpec{
+			Running: &running,
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					Domain: cnv.DomainSpec{
+						CPU:    &cnv.CPU{Sockets: 1, Cores: 2},
+						Memory: &cnv.Memory{Guest: resource.NewQuantity(0x80000000, resource.BinarySI)},
+						Devices: cnv.Devices{
+							Interfaces: []cnv.Interface{{MacAddress: "00:50:56:aa:bb:cc"}},
+						},
+					},
+					Volumes: []cnv.Volume{
+						{
+							Name: "disk",
+							VolumeSource: cnv.VolumeSource{
+								PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+									PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+										ClaimName: "disk",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	pvc := &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "disk"},
+		Spec: core.PersistentVolumeClaimSpec{
+			Resources: core.ResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: *resource.NewQuantity(0x40000000, resource.BinarySI),
+				},
+			},
+		},
+	}
+	ctx := &plancontext.Context{Plan: &api.Plan{}, Log: logging.WithName("test")}
+	ctx.Destination.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(vmCr, pvc).Build()
+	builder := &specBuilder{
+		spec: &planbase.VMSpec{
+			CPUs:   2,
+			Memory: 0x80000000,
+			Disks:  []int64{0x80000000},
+			MACs:   []string{"00:50:56:aa:bb:cc"},
+		},
+	}
+	migration := Migration{
+		Context: ctx,
+		builder: builder,
+		kubevirt: KubeVirt{
+			Context: ctx,
+			Builder: builder,
+		},
+		vmMap: VirtualMachineMap{"vm-1": {VirtualMachine: vmCr}},
+	}
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	step := &plan.Step{}
+
+	//Test the specification of a VM powered off after the migration is verified
+	done, err := migration.verify(vm, step)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(done).To(gomega.BeTrue())
+	g.Expect(vm.HasCondition(Verified)).To(gomega.BeFalse())
+	failed := vm.FindCondition(VerificationFailed)
+	g.Expect(failed).ToNot(gomega.BeNil())
+	g.Expect(failed.Items).To(gomega.ConsistOf("Disk: source=2Gi target=1Gi."))
+
+	//Test the VM is not started
+	found := &cnv.VirtualMachine{}
+	err = ctx.Destination.Client.Get(context.TODO(), types.NamespacedName{Namespace: "test", Name: "vm"}, found)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(*found.Spec.Running).To(gomega.BeFalse())
+}
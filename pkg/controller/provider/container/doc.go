@@ -2,6 +2,7 @@ package container
 
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container/ova"
@@ -29,7 +30,22 @@ func Build(
 		return openstack.New(db, provider, secret)
 	case api.Ova:
 		return ova.New(db, provider, secret)
+	case api.GCP:
+		return gcp.New(db, provider, secret)
 	}
 
 	return nil
 }
+
+// Determine whether the collector resumes from the
+// inventory kept by a persistent DB backend.
+// The vSphere collector reads the full initial update set
+// again but serves the kept inventory in the meantime.
+func Resumable(provider *api.Provider) bool {
+	switch provider.Type() {
+	case api.OVirt, api.OpenStack, api.VSphere, api.Ova:
+		return true
+	}
+
+	return false
+}
@@ -2,8 +2,11 @@ package gcp
 
 import (
 	"context"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
@@ -77,7 +80,11 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) (r *Collec
 }
 
 // The name.
+// The GCP API endpoint is implicit, the project identifies the provider.
 func (r *Collector) Name() string {
+	if r.client.URL == "" {
+		return r.client.ProjectID
+	}
 	url, err := liburl.Parse(r.client.URL)
 	if err == nil {
 		return url.Host
@@ -108,7 +115,13 @@ func (r *Collector) HasParity() bool {
 
 // Test connect/logout.
 func (r *Collector) Test() (_ int, err error) {
-	err = r.client.Authenticate()
+	err = r.client.Connect()
+	if err != nil {
+		return
+	}
+	defer r.client.Close()
+	networkList := []*computepb.Network{}
+	err = r.client.List(&networkList, &libclient.NetworkListOpts{})
 	return
 }
 
@@ -193,6 +206,14 @@ func (r *Collector) run(ctx *Context) (err error) {
 	return
 }
 
+// Shutdown the collector.
+func (r *Collector) Shutdown() {
+	r.log.Info("Shutdown.")
+	if r.cancel != nil {
+		r.cancel()
+	}
+}
+
 // Load the inventory.
 func (r *Collector) load(ctx *Context) (err error) {
 	mark := time.Now()
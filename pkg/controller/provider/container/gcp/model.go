@@ -2,6 +2,13 @@ package gcp
 
 import (
 	"context"
+	"errors"
+	"path"
+	"sort"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
@@ -10,6 +17,19 @@ import (
 // All adapters.
 var adapterList []Adapter
 
+func init() {
+	adapterList = []Adapter{
+		&ImageAdapter{},
+		&NetworkAdapter{},
+		&DiskTypeAdapter{},
+		&ZoneAdapter{},
+		&DiskAdapter{},
+		&SubnetworkAdapter{},
+		&MachineTypeAdapter{},
+		&VMAdapter{},
+	}
+}
+
 // Updates the DB based on
 // changes described by an Event.
 type Updater func(tx *libmodel.Tx) error
@@ -20,7 +40,7 @@ type Context struct {
 	ctx context.Context
 	// DB client.
 	db libmodel.DB
-	// OpenStack client.
+	// GCP client.
 	client *Client
 	// Log.
 	log logging.LevelLogger
@@ -48,3 +68,811 @@ type Adapter interface {
 	// Clean unexisting objects within the database
 	DeleteUnexisting(ctx *Context) (updates []Updater, err error)
 }
+
+type ImageAdapter struct {
+}
+
+func (r *ImageAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	imageList := []*computepb.Image{}
+	err = ctx.client.List(&imageList, &libclient.ImageListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, image := range imageList {
+		i := &Image{image}
+		m := &model.Image{
+			Base: model.Base{ID: i.ID()},
+		}
+		i.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *ImageAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	imageList := []*computepb.Image{}
+	err = ctx.client.List(&imageList, &libclient.ImageListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range imageList {
+		image := &Image{imageList[i]}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Image{
+				Base: model.Base{ID: image.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					image.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if image.equalsTo(m) {
+				return
+			}
+			image.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *ImageAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	imageList := []*computepb.Image{}
+	err = ctx.client.List(&imageList, &libclient.ImageListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, image := range imageList {
+		existing[(&Image{image}).ID()] = true
+	}
+	modelList := []model.Image{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		image := &modelList[i]
+		if existing[image.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Image{
+				Base: model.Base{ID: image.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+type NetworkAdapter struct {
+}
+
+func (r *NetworkAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	networkList := []*computepb.Network{}
+	err = ctx.client.List(&networkList, &libclient.NetworkListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, network := range networkList {
+		n := &Network{network}
+		m := &model.Network{
+			Base: model.Base{ID: n.ID()},
+		}
+		n.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *NetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	networkList := []*computepb.Network{}
+	err = ctx.client.List(&networkList, &libclient.NetworkListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range networkList {
+		network := &Network{networkList[i]}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Network{
+				Base: model.Base{ID: network.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					network.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if network.equalsTo(m) {
+				return
+			}
+			network.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *NetworkAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	networkList := []*computepb.Network{}
+	err = ctx.client.List(&networkList, &libclient.NetworkListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, network := range networkList {
+		existing[(&Network{network}).ID()] = true
+	}
+	modelList := []model.Network{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		network := &modelList[i]
+		if existing[network.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Network{
+				Base: model.Base{ID: network.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+type ZoneAdapter struct {
+}
+
+func (r *ZoneAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	zoneList := []*computepb.Zone{}
+	err = ctx.client.List(&zoneList, &libclient.ZoneListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, zone := range zoneList {
+		n := &Zone{zone}
+		m := &model.Zone{
+			Base: model.Base{ID: n.ID()},
+		}
+		n.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *ZoneAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	zoneList := []*computepb.Zone{}
+	err = ctx.client.List(&zoneList, &libclient.ZoneListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range zoneList {
+		zone := &Zone{zoneList[i]}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Zone{
+				Base: model.Base{ID: zone.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					zone.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if zone.equalsTo(m) {
+				return
+			}
+			zone.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *ZoneAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	zoneList := []*computepb.Zone{}
+	err = ctx.client.List(&zoneList, &libclient.ZoneListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, zone := range zoneList {
+		existing[(&Zone{zone}).ID()] = true
+	}
+	modelList := []model.Zone{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		zone := &modelList[i]
+		if existing[zone.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Zone{
+				Base: model.Base{ID: zone.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+type DiskAdapter struct {
+}
+
+func (r *DiskAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	diskList := []*computepb.Disk{}
+	err = ctx.client.List(&diskList, &libclient.DiskListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, disk := range diskList {
+		n := &Disk{disk}
+		m := &model.Disk{
+			Base: model.Base{ID: n.ID()},
+		}
+		n.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *DiskAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	diskList := []*computepb.Disk{}
+	err = ctx.client.List(&diskList, &libclient.DiskListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range diskList {
+		disk := &Disk{diskList[i]}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Disk{
+				Base: model.Base{ID: disk.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					disk.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if disk.equalsTo(m) {
+				return
+			}
+			disk.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *DiskAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	diskList := []*computepb.Disk{}
+	err = ctx.client.List(&diskList, &libclient.DiskListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, disk := range diskList {
+		existing[(&Disk{disk}).ID()] = true
+	}
+	modelList := []model.Disk{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		disk := &modelList[i]
+		if existing[disk.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Disk{
+				Base: model.Base{ID: disk.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+type SubnetworkAdapter struct {
+}
+
+func (r *SubnetworkAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	subnetworkList := []*computepb.Subnetwork{}
+	err = ctx.client.List(&subnetworkList, &libclient.SubnetworkListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, subnetwork := range subnetworkList {
+		n := &Subnetwork{subnetwork}
+		m := &model.Subnetwork{
+			Base: model.Base{ID: n.ID()},
+		}
+		n.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *SubnetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	subnetworkList := []*computepb.Subnetwork{}
+	err = ctx.client.List(&subnetworkList, &libclient.SubnetworkListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range subnetworkList {
+		subnetwork := &Subnetwork{subnetworkList[i]}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Subnetwork{
+				Base: model.Base{ID: subnetwork.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					subnetwork.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if subnetwork.equalsTo(m) {
+				return
+			}
+			subnetwork.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *SubnetworkAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	subnetworkList := []*computepb.Subnetwork{}
+	err = ctx.client.List(&subnetworkList, &libclient.SubnetworkListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, subnetwork := range subnetworkList {
+		existing[(&Subnetwork{subnetwork}).ID()] = true
+	}
+	modelList := []model.Subnetwork{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		subnetwork := &modelList[i]
+		if existing[subnetwork.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.Subnetwork{
+				Base: model.Base{ID: subnetwork.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+type MachineTypeAdapter struct {
+}
+
+func (r *MachineTypeAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	machineTypeList, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, machineType := range machineTypeList {
+		m := &model.MachineType{
+			Base: model.Base{ID: machineType.ID()},
+		}
+		machineType.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *MachineTypeAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	machineTypeList, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	for i := range machineTypeList {
+		machineType := machineTypeList[i]
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.MachineType{
+				Base: model.Base{ID: machineType.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					machineType.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if machineType.equalsTo(m) {
+				return
+			}
+			machineType.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *MachineTypeAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	machineTypeList, err := r.list(ctx)
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, machineType := range machineTypeList {
+		existing[machineType.ID()] = true
+	}
+	modelList := []model.MachineType{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		machineType := &modelList[i]
+		if existing[machineType.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.MachineType{
+				Base: model.Base{ID: machineType.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+// List the machine types offered in the zones hosting
+// instances, reduced to one entry per name.
+func (r *MachineTypeAdapter) list(ctx *Context) (list []*MachineType, err error) {
+	vmList := []*computepb.Instance{}
+	err = ctx.client.List(&vmList, &libclient.VMListOpts{})
+	if err != nil {
+		return
+	}
+	zones := []string{}
+	found := map[string]bool{}
+	for _, instance := range vmList {
+		zone := libclient.ShortName(instance.GetZone())
+		if !found[zone] {
+			found[zone] = true
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+	byName := map[string]*MachineType{}
+	for _, zone := range zones {
+		machineTypeList := []*computepb.MachineType{}
+		err = ctx.client.List(&machineTypeList, &libclient.MachineTypeListOpts{Zone: zone})
+		if err != nil {
+			return
+		}
+		for _, machineType := range machineTypeList {
+			m, found := byName[machineType.GetName()]
+			if !found {
+				m = &MachineType{MachineType: machineType}
+				byName[machineType.GetName()] = m
+				list = append(list, m)
+			}
+			m.Zones = append(m.Zones, zone)
+		}
+	}
+	return
+}
+
+type DiskTypeAdapter struct {
+}
+
+func (r *DiskTypeAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	diskTypeList := []*computepb.DiskType{}
+	err = ctx.client.List(&diskTypeList, &libclient.DiskTypeListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, diskType := range diskTypeList {
+		d := &DiskType{diskType}
+		m := &model.DiskType{
+			Base: model.Base{ID: d.ID()},
+		}
+		d.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+func (r *DiskTypeAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	diskTypeList := []*computepb.DiskType{}
+	err = ctx.client.List(&diskTypeList, &libclient.DiskTypeListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range diskTypeList {
+		diskType := &DiskType{diskTypeList[i]}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.DiskType{
+				Base: model.Base{ID: diskType.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					diskType.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			if diskType.equalsTo(m) {
+				return
+			}
+			diskType.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *DiskTypeAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	diskTypeList := []*computepb.DiskType{}
+	err = ctx.client.List(&diskTypeList, &libclient.DiskTypeListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, diskType := range diskTypeList {
+		existing[diskType.GetName()] = true
+	}
+	modelList := []model.DiskType{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		diskType := &modelList[i]
+		if existing[diskType.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.DiskType{
+				Base: model.Base{ID: diskType.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+type VMAdapter struct {
+	// Machine types by zone/name.
+	machineTypes map[string]*computepb.MachineType
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context) (itr fb.Iterator, err error) {
+	vmList := []*computepb.Instance{}
+	err = ctx.client.List(&vmList, &libclient.VMListOpts{})
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, instance := range vmList {
+		var vm *VM
+		vm, err = r.resolve(ctx, instance)
+		if err != nil {
+			return
+		}
+		m := &model.VM{
+			Base: model.Base{ID: vm.ID()},
+		}
+		vm.ApplyTo(m)
+		list.Append(m)
+	}
+	itr = list.Iter()
+	return
+}
+
+// Get updates.
+// Only the changed instances have the machine type
+// and disks resolved.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	vmList := []*computepb.Instance{}
+	err = ctx.client.List(&vmList, &libclient.VMListOpts{})
+	if err != nil {
+		return
+	}
+	for i := range vmList {
+		vm := &VM{Instance: vmList[i]}
+		m := &model.VM{
+			Base: model.Base{ID: vm.ID()},
+		}
+		err = ctx.db.Get(m)
+		if err == nil && vm.equalsTo(m) {
+			continue
+		}
+		if err != nil && !errors.Is(err, libmodel.NotFound) {
+			return
+		}
+		vm, err = r.resolve(ctx, vmList[i])
+		if err != nil {
+			return
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.VM{
+				Base: model.Base{ID: vm.ID()},
+			}
+			err = tx.Get(m)
+			if err != nil {
+				if errors.Is(err, libmodel.NotFound) {
+					vm.ApplyTo(m)
+					err = tx.Insert(m)
+				}
+				return
+			}
+			vm.ApplyTo(m)
+			err = tx.Update(m)
+			return
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+func (r *VMAdapter) DeleteUnexisting(ctx *Context) (updates []Updater, err error) {
+	vmList := []*computepb.Instance{}
+	err = ctx.client.List(&vmList, &libclient.VMListOpts{})
+	if err != nil {
+		return
+	}
+	existing := map[string]bool{}
+	for _, instance := range vmList {
+		existing[(&VM{Instance: instance}).ID()] = true
+	}
+	modelList := []model.VM{}
+	err = ctx.db.List(&modelList, libmodel.FilterOptions{})
+	if err != nil {
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+	for i := range modelList {
+		vm := &modelList[i]
+		if existing[vm.ID] {
+			continue
+		}
+		updater := func(tx *libmodel.Tx) (err error) {
+			m := &model.VM{
+				Base: model.Base{ID: vm.ID},
+			}
+			return tx.Delete(m)
+		}
+		updates = append(updates, updater)
+	}
+	return
+}
+
+// Resolve the machine type and the persistent disks of an instance.
+func (r *VMAdapter) resolve(ctx *Context, instance *computepb.Instance) (vm *VM, err error) {
+	vm = &VM{
+		Instance: instance,
+		Disks:    map[string]*computepb.Disk{},
+	}
+	zone := libclient.ShortName(instance.GetZone())
+	vm.MachineType, err = r.machineType(ctx, zone, libclient.ShortName(instance.GetMachineType()))
+	if err != nil {
+		return
+	}
+	for _, attached := range instance.GetDisks() {
+		if attached.GetType() != libclient.DiskTypePersistent {
+			continue
+		}
+		name := libclient.ShortName(attached.GetSource())
+		disk := &computepb.Disk{}
+		err = ctx.client.Get(disk, name, zone)
+		if err != nil {
+			if ctx.client.IsNotFound(err) {
+				err = nil
+				continue
+			}
+			return
+		}
+		vm.Disks[name] = disk
+	}
+	return
+}
+
+// Get the machine type (cached).
+func (r *VMAdapter) machineType(ctx *Context, zone, name string) (machineType *computepb.MachineType, err error) {
+	if r.machineTypes == nil {
+		r.machineTypes = map[string]*computepb.MachineType{}
+	}
+	key := path.Join(zone, name)
+	machineType, found := r.machineTypes[key]
+	if found {
+		return
+	}
+	machineType = &computepb.MachineType{}
+	err = ctx.client.Get(machineType, name, zone)
+	if err != nil {
+		return
+	}
+	r.machineTypes[key] = machineType
+	return
+}
@@ -0,0 +1,371 @@
+package gcp
+
+import (
+	"strconv"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
+)
+
+type Image struct {
+	*computepb.Image
+}
+
+func (r *Image) ID() string {
+	return strconv.FormatUint(r.GetId(), 10)
+}
+
+func (r *Image) ApplyTo(m *model.Image) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.Status = r.GetStatus()
+	m.Family = r.GetFamily()
+	m.Architecture = r.GetArchitecture()
+	m.ArchiveSizeBytes = r.GetArchiveSizeBytes()
+	m.DiskSizeGb = r.GetDiskSizeGb()
+	m.SourceDisk = libclient.ShortName(r.GetSourceDisk())
+	m.SourceDiskID = r.GetSourceDiskId()
+	m.SourceType = r.GetSourceType()
+	m.Labels = r.GetLabels()
+	m.Licenses = shortNames(r.GetLicenses())
+	m.GuestOsFeatures = guestOsFeatures(r.GetGuestOsFeatures())
+	m.StorageLocations = r.GetStorageLocations()
+	m.CreationTimestamp = r.GetCreationTimestamp()
+	m.SelfLink = r.GetSelfLink()
+}
+
+func (r *Image) equalsTo(m *model.Image) bool {
+	updated := &model.Image{}
+	r.ApplyTo(updated)
+	return m.Status == updated.Status &&
+		m.Description == updated.Description &&
+		m.ArchiveSizeBytes == updated.ArchiveSizeBytes &&
+		m.DiskSizeGb == updated.DiskSizeGb &&
+		equalsMap(m.Labels, updated.Labels)
+}
+
+type VM struct {
+	*computepb.Instance
+	// Resolved machine type.
+	MachineType *computepb.MachineType
+	// Resolved persistent disks by name.
+	Disks map[string]*computepb.Disk
+}
+
+func (r *VM) ID() string {
+	return strconv.FormatUint(r.GetId(), 10)
+}
+
+func (r *VM) ApplyTo(m *model.VM) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.Status = r.GetStatus()
+	m.Zone = libclient.ShortName(r.GetZone())
+	m.MachineType = libclient.ShortName(r.Instance.GetMachineType())
+	m.CpuPlatform = r.GetCpuPlatform()
+	m.Hostname = r.GetHostname()
+	m.Labels = r.GetLabels()
+	m.Fingerprint = r.GetFingerprint()
+	m.CreationTimestamp = r.GetCreationTimestamp()
+	m.SelfLink = r.GetSelfLink()
+	if r.MachineType != nil {
+		m.GuestCpus = r.MachineType.GetGuestCpus()
+		m.MemoryMb = r.MachineType.GetMemoryMb()
+	}
+	shielded := r.GetShieldedInstanceConfig()
+	m.SecureBoot = shielded.GetEnableSecureBoot()
+	m.Vtpm = shielded.GetEnableVtpm()
+	m.SoleTenant = r.soleTenant()
+	r.addAccelerators(m)
+	r.addDisks(m)
+	r.addNICs(m)
+}
+
+// Determine if the instance is placed on sole-tenant nodes.
+func (r *VM) soleTenant() bool {
+	for _, affinity := range r.GetScheduling().GetNodeAffinities() {
+		switch affinity.GetKey() {
+		case libclient.NodeGroupAffinity, libclient.NodeAffinity:
+			return true
+		}
+	}
+	return false
+}
+
+// Add the accelerators attached to the instance and
+// those bundled with the machine type (A2, G2, ...).
+func (r *VM) addAccelerators(m *model.VM) {
+	m.Accelerators = []model.Accelerator{}
+	for _, accelerator := range r.GetGuestAccelerators() {
+		m.Accelerators = append(
+			m.Accelerators,
+			model.Accelerator{
+				Type:  libclient.ShortName(accelerator.GetAcceleratorType()),
+				Count: accelerator.GetAcceleratorCount(),
+			})
+	}
+	if len(m.Accelerators) > 0 || r.MachineType == nil {
+		return
+	}
+	m.Accelerators = accelerators(r.MachineType.GetAccelerators())
+}
+
+func (r *VM) addDisks(m *model.VM) {
+	m.Disks = []model.AttachedDisk{}
+	for _, attached := range r.Instance.GetDisks() {
+		disk := model.AttachedDisk{
+			Name:            libclient.ShortName(attached.GetSource()),
+			DeviceName:      attached.GetDeviceName(),
+			Type:            attached.GetType(),
+			Interface:       attached.GetInterface(),
+			Mode:            attached.GetMode(),
+			Boot:            attached.GetBoot(),
+			Index:           attached.GetIndex(),
+			SizeGb:          attached.GetDiskSizeGb(),
+			Licenses:        shortNames(attached.GetLicenses()),
+			GuestOsFeatures: guestOsFeatures(attached.GetGuestOsFeatures()),
+		}
+		if source, found := r.Disks[disk.Name]; found {
+			disk.DiskType = libclient.ShortName(source.GetType())
+			if disk.SizeGb == 0 {
+				disk.SizeGb = source.GetSizeGb()
+			}
+		}
+		m.Disks = append(m.Disks, disk)
+	}
+}
+
+func (r *VM) addNICs(m *model.VM) {
+	m.NICs = []model.NetworkInterface{}
+	for _, nic := range r.GetNetworkInterfaces() {
+		m.NICs = append(
+			m.NICs,
+			model.NetworkInterface{
+				Name:       nic.GetName(),
+				Network:    libclient.ShortName(nic.GetNetwork()),
+				Subnetwork: libclient.ShortName(nic.GetSubnetwork()),
+				NetworkIP:  nic.GetNetworkIP(),
+				NicType:    nic.GetNicType(),
+				StackType:  nic.GetStackType(),
+			})
+	}
+}
+
+func (r *VM) equalsTo(m *model.VM) bool {
+	return m.Fingerprint == r.GetFingerprint() &&
+		m.Status == r.GetStatus()
+}
+
+type Network struct {
+	*computepb.Network
+}
+
+func (r *Network) ID() string {
+	return strconv.FormatUint(r.GetId(), 10)
+}
+
+func (r *Network) ApplyTo(m *model.Network) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.IPv4Range = r.GetIPv4Range()
+	m.GatewayIPv4 = r.GetGatewayIPv4()
+	m.AutoCreateSubnetworks = r.GetAutoCreateSubnetworks()
+	m.Mtu = r.GetMtu()
+	m.RoutingMode = r.GetRoutingConfig().GetRoutingMode()
+	m.Subnetworks = shortNames(r.GetSubnetworks())
+	m.CreationTimestamp = r.GetCreationTimestamp()
+	m.SelfLink = r.GetSelfLink()
+}
+
+func (r *Network) equalsTo(m *model.Network) bool {
+	updated := &model.Network{}
+	r.ApplyTo(updated)
+	return m.Description == updated.Description &&
+		m.Mtu == updated.Mtu &&
+		m.RoutingMode == updated.RoutingMode &&
+		equalsList(m.Subnetworks, updated.Subnetworks)
+}
+
+type DiskType struct {
+	*computepb.DiskType
+}
+
+func (r *DiskType) ID() string {
+	return r.GetName()
+}
+
+func (r *DiskType) ApplyTo(m *model.DiskType) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.DefaultDiskSizeGb = r.GetDefaultDiskSizeGb()
+	m.ValidDiskSize = r.GetValidDiskSize()
+	m.SelfLink = r.GetSelfLink()
+}
+
+func (r *DiskType) equalsTo(m *model.DiskType) bool {
+	return m.Description == r.GetDescription() &&
+		m.DefaultDiskSizeGb == r.GetDefaultDiskSizeGb() &&
+		m.ValidDiskSize == r.GetValidDiskSize()
+}
+
+type Zone struct {
+	*computepb.Zone
+}
+
+func (r *Zone) ID() string {
+	return strconv.FormatUint(r.GetId(), 10)
+}
+
+func (r *Zone) ApplyTo(m *model.Zone) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.Region = libclient.ShortName(r.GetRegion())
+	m.Status = r.GetStatus()
+	m.SelfLink = r.GetSelfLink()
+}
+
+func (r *Zone) equalsTo(m *model.Zone) bool {
+	return m.Description == r.GetDescription() &&
+		m.Status == r.GetStatus()
+}
+
+type Disk struct {
+	*computepb.Disk
+}
+
+func (r *Disk) ID() string {
+	return strconv.FormatUint(r.GetId(), 10)
+}
+
+func (r *Disk) ApplyTo(m *model.Disk) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.Zone = libclient.ShortName(r.GetZone())
+	m.DiskType = libclient.ShortName(r.GetType())
+	m.SizeGb = r.GetSizeGb()
+	m.Status = r.GetStatus()
+	m.SourceImage = libclient.ShortName(r.GetSourceImage())
+	m.Users = shortNames(r.GetUsers())
+	m.Labels = r.GetLabels()
+	m.CreationTimestamp = r.GetCreationTimestamp()
+	m.SelfLink = r.GetSelfLink()
+}
+
+func (r *Disk) equalsTo(m *model.Disk) bool {
+	updated := &model.Disk{}
+	r.ApplyTo(updated)
+	return m.Description == updated.Description &&
+		m.DiskType == updated.DiskType &&
+		m.SizeGb == updated.SizeGb &&
+		m.Status == updated.Status &&
+		equalsList(m.Users, updated.Users) &&
+		equalsMap(m.Labels, updated.Labels)
+}
+
+type Subnetwork struct {
+	*computepb.Subnetwork
+}
+
+func (r *Subnetwork) ID() string {
+	return strconv.FormatUint(r.GetId(), 10)
+}
+
+func (r *Subnetwork) ApplyTo(m *model.Subnetwork) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.Region = libclient.ShortName(r.GetRegion())
+	m.Network = libclient.ShortName(r.GetNetwork())
+	m.IpCidrRange = r.GetIpCidrRange()
+	m.GatewayAddress = r.GetGatewayAddress()
+	m.Purpose = r.GetPurpose()
+	m.StackType = r.GetStackType()
+	m.CreationTimestamp = r.GetCreationTimestamp()
+	m.SelfLink = r.GetSelfLink()
+}
+
+func (r *Subnetwork) equalsTo(m *model.Subnetwork) bool {
+	return m.Description == r.GetDescription() &&
+		m.IpCidrRange == r.GetIpCidrRange() &&
+		m.GatewayAddress == r.GetGatewayAddress() &&
+		m.Purpose == r.GetPurpose() &&
+		m.StackType == r.GetStackType()
+}
+
+type MachineType struct {
+	*computepb.MachineType
+	// Zones offering the machine type.
+	Zones []string
+}
+
+func (r *MachineType) ID() string {
+	return r.GetName()
+}
+
+func (r *MachineType) ApplyTo(m *model.MachineType) {
+	m.Name = r.GetName()
+	m.Description = r.GetDescription()
+	m.GuestCpus = r.GetGuestCpus()
+	m.MemoryMb = r.GetMemoryMb()
+	m.IsSharedCpu = r.GetIsSharedCpu()
+	m.Accelerators = accelerators(r.GetAccelerators())
+	m.Zones = r.Zones
+}
+
+func (r *MachineType) equalsTo(m *model.MachineType) bool {
+	return m.Description == r.GetDescription() &&
+		m.GuestCpus == r.GetGuestCpus() &&
+		m.MemoryMb == r.GetMemoryMb() &&
+		equalsList(m.Zones, r.Zones)
+}
+
+func accelerators(list []*computepb.Accelerators) (accelerators []model.Accelerator) {
+	accelerators = []model.Accelerator{}
+	for _, accelerator := range list {
+		accelerators = append(
+			accelerators,
+			model.Accelerator{
+				Type:  accelerator.GetGuestAcceleratorType(),
+				Count: accelerator.GetGuestAcceleratorCount(),
+			})
+	}
+	return
+}
+
+func shortNames(urls []string) (names []string) {
+	for _, url := range urls {
+		names = append(names, libclient.ShortName(url))
+	}
+	return
+}
+
+func guestOsFeatures(features []*computepb.GuestOsFeature) (types []string) {
+	for _, feature := range features {
+		types = append(types, feature.GetType())
+	}
+	return
+}
+
+func equalsList(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalsMap(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
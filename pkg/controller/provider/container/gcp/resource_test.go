@@ -0,0 +1,330 @@
+package gcp
+
+import (
+	"testing"
+
+	"cloud.google.com/go/compute/apiv1/computepb"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	libclient "github.com/konveyor/forklift-controller/pkg/lib/client/gcp"
+	"github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	testProject = "https://www.googleapis.com/compute/v1/projects/test"
+	testZone    = testProject + "/zones/us-central1-a"
+)
+
+func instance() *computepb.Instance {
+	return &computepb.Instance{
+		Id:          proto.Uint64(4711),
+		Name:        proto.String("vm"),
+		Status:      proto.String("RUNNING"),
+		Zone:        proto.String(testZone),
+		MachineType: proto.String(testZone + "/machineTypes/a2-highgpu-1g"),
+		Fingerprint: proto.String("fingerprint"),
+		Disks: []*computepb.AttachedDisk{
+			{
+				Source:     proto.String(testZone + "/disks/boot"),
+				DeviceName: proto.String("persistent-disk-0"),
+				Type:       proto.String("PERSISTENT"),
+				Interface:  proto.String("SCSI"),
+				Mode:       proto.String("READ_WRITE"),
+				Boot:       proto.Bool(true),
+				DiskSizeGb: proto.Int64(20),
+				Licenses:   []string{testProject + "/global/licenses/rhel-9"},
+				GuestOsFeatures: []*computepb.GuestOsFeature{
+					{Type: proto.String("UEFI_COMPATIBLE")},
+				},
+			},
+			{
+				Source:     proto.String(testZone + "/disks/data"),
+				DeviceName: proto.String("persistent-disk-1"),
+				Type:       proto.String("PERSISTENT"),
+				Index:      proto.Int32(1),
+			},
+		},
+		NetworkInterfaces: []*computepb.NetworkInterface{
+			{
+				Name:       proto.String("nic0"),
+				Network:    proto.String(testProject + "/global/networks/default"),
+				Subnetwork: proto.String(testProject + "/regions/us-central1/subnetworks/default"),
+				NetworkIP:  proto.String("10.128.0.2"),
+			},
+		},
+	}
+}
+
+func TestVMApplyTo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	vm := &VM{
+		Instance: instance(),
+		MachineType: &computepb.MachineType{
+			GuestCpus: proto.Int32(12),
+			MemoryMb:  proto.Int32(87040),
+			Accelerators: []*computepb.Accelerators{
+				{
+					GuestAcceleratorType:  proto.String("nvidia-tesla-a100"),
+					GuestAcceleratorCount: proto.Int32(1),
+				},
+			},
+		},
+		Disks: map[string]*computepb.Disk{
+			"boot": {
+				Type:   proto.String(testZone + "/diskTypes/pd-balanced"),
+				SizeGb: proto.Int64(50),
+			},
+			"data": {
+				Type:   proto.String(testZone + "/diskTypes/pd-ssd"),
+				SizeGb: proto.Int64(100),
+			},
+		},
+	}
+	g.Expect(vm.ID()).To(gomega.Equal("4711"))
+
+	m := &model.VM{}
+	vm.ApplyTo(m)
+	g.Expect(m.Name).To(gomega.Equal("vm"))
+	g.Expect(m.Zone).To(gomega.Equal("us-central1-a"))
+	g.Expect(m.MachineType).To(gomega.Equal("a2-highgpu-1g"))
+	g.Expect(m.GuestCpus).To(gomega.Equal(int32(12)))
+	g.Expect(m.MemoryMb).To(gomega.Equal(int32(87040)))
+	g.Expect(m.SoleTenant).To(gomega.BeFalse())
+	// Bundled with the machine type.
+	g.Expect(m.Accelerators).To(gomega.Equal([]model.Accelerator{{Type: "nvidia-tesla-a100", Count: 1}}))
+	// The type and the size of the disks are
+	// resolved when not reported by the instance.
+	g.Expect(m.Disks).To(gomega.Equal([]model.AttachedDisk{
+		{
+			Name:            "boot",
+			DeviceName:      "persistent-disk-0",
+			DiskType:        "pd-balanced",
+			Type:            "PERSISTENT",
+			Interface:       "SCSI",
+			Mode:            "READ_WRITE",
+			Boot:            true,
+			SizeGb:          20,
+			Licenses:        []string{"rhel-9"},
+			GuestOsFeatures: []string{"UEFI_COMPATIBLE"},
+		},
+		{
+			Name:       "data",
+			DeviceName: "persistent-disk-1",
+			DiskType:   "pd-ssd",
+			Type:       "PERSISTENT",
+			Index:      1,
+			SizeGb:     100,
+		},
+	}))
+	g.Expect(m.NICs).To(gomega.Equal([]model.NetworkInterface{
+		{
+			Name:       "nic0",
+			Network:    "default",
+			Subnetwork: "default",
+			NetworkIP:  "10.128.0.2",
+		},
+	}))
+	g.Expect(m.UEFI()).To(gomega.BeTrue())
+	g.Expect(vm.equalsTo(m)).To(gomega.BeTrue())
+
+	// Attached accelerators, shielded and sole-tenant.
+	vm.GuestAccelerators = []*computepb.AcceleratorConfig{
+		{
+			AcceleratorType:  proto.String(testZone + "/acceleratorTypes/nvidia-l4"),
+			AcceleratorCount: proto.Int32(2),
+		},
+	}
+	vm.ShieldedInstanceConfig = &computepb.ShieldedInstanceConfig{
+		EnableSecureBoot: proto.Bool(true),
+		EnableVtpm:       proto.Bool(true),
+	}
+	vm.Scheduling = &computepb.Scheduling{
+		NodeAffinities: []*computepb.SchedulingNodeAffinity{
+			{Key: proto.String(libclient.NodeGroupAffinity)},
+		},
+	}
+	vm.Status = proto.String("TERMINATED")
+	g.Expect(vm.equalsTo(m)).To(gomega.BeFalse())
+	m = &model.VM{}
+	vm.ApplyTo(m)
+	g.Expect(m.Accelerators).To(gomega.Equal([]model.Accelerator{{Type: "nvidia-l4", Count: 2}}))
+	g.Expect(m.SecureBoot).To(gomega.BeTrue())
+	g.Expect(m.Vtpm).To(gomega.BeTrue())
+	g.Expect(m.SoleTenant).To(gomega.BeTrue())
+
+	// Not resolved.
+	vm = &VM{Instance: instance()}
+	m = &model.VM{}
+	vm.ApplyTo(m)
+	g.Expect(m.GuestCpus).To(gomega.BeZero())
+	g.Expect(m.Accelerators).To(gomega.BeEmpty())
+	g.Expect(m.Disks[0].DiskType).To(gomega.BeEmpty())
+	g.Expect(m.Disks[1].SizeGb).To(gomega.BeZero())
+}
+
+func TestImageApplyTo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	image := &Image{
+		Image: &computepb.Image{
+			Id:           proto.Uint64(1),
+			Name:         proto.String("forklift-migration-vm-4711-disk-0"),
+			Status:       proto.String("PENDING"),
+			DiskSizeGb:   proto.Int64(20),
+			SourceDisk:   proto.String(testZone + "/disks/boot"),
+			SourceDiskId: proto.String("2"),
+			Labels:       map[string]string{"migration": "1"},
+			Licenses:     []string{testProject + "/global/licenses/rhel-9"},
+		},
+	}
+	g.Expect(image.ID()).To(gomega.Equal("1"))
+
+	m := &model.Image{}
+	image.ApplyTo(m)
+	g.Expect(m.Name).To(gomega.Equal("forklift-migration-vm-4711-disk-0"))
+	g.Expect(m.SourceDisk).To(gomega.Equal("boot"))
+	g.Expect(m.SourceDiskID).To(gomega.Equal("2"))
+	g.Expect(m.Licenses).To(gomega.Equal([]string{"rhel-9"}))
+	g.Expect(image.equalsTo(m)).To(gomega.BeTrue())
+
+	image.Status = proto.String("READY")
+	g.Expect(image.equalsTo(m)).To(gomega.BeFalse())
+	image.ApplyTo(m)
+	image.Labels = map[string]string{"migration": "2"}
+	g.Expect(image.equalsTo(m)).To(gomega.BeFalse())
+}
+
+func TestDiskApplyTo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	disk := &Disk{
+		Disk: &computepb.Disk{
+			Id:          proto.Uint64(2),
+			Name:        proto.String("boot"),
+			Zone:        proto.String(testZone),
+			Type:        proto.String(testZone + "/diskTypes/pd-balanced"),
+			SizeGb:      proto.Int64(50),
+			SourceImage: proto.String(testProject + "/global/images/rhel-9"),
+			Users:       []string{testZone + "/instances/vm"},
+		},
+	}
+	g.Expect(disk.ID()).To(gomega.Equal("2"))
+
+	m := &model.Disk{}
+	disk.ApplyTo(m)
+	g.Expect(m.Zone).To(gomega.Equal("us-central1-a"))
+	g.Expect(m.DiskType).To(gomega.Equal("pd-balanced"))
+	g.Expect(m.SizeGb).To(gomega.Equal(int64(50)))
+	g.Expect(m.SourceImage).To(gomega.Equal("rhel-9"))
+	g.Expect(m.Users).To(gomega.Equal([]string{"vm"}))
+	g.Expect(disk.equalsTo(m)).To(gomega.BeTrue())
+
+	// Detached.
+	disk.Users = nil
+	g.Expect(disk.equalsTo(m)).To(gomega.BeFalse())
+}
+
+func TestNetworkApplyTo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	network := &Network{
+		Network: &computepb.Network{
+			Id:                    proto.Uint64(3),
+			Name:                  proto.String("default"),
+			AutoCreateSubnetworks: proto.Bool(true),
+			Mtu:                   proto.Int32(1460),
+			RoutingConfig:         &computepb.NetworkRoutingConfig{RoutingMode: proto.String("REGIONAL")},
+			Subnetworks:           []string{testProject + "/regions/us-central1/subnetworks/default"},
+		},
+	}
+	g.Expect(network.ID()).To(gomega.Equal("3"))
+
+	m := &model.Network{}
+	network.ApplyTo(m)
+	g.Expect(m.AutoCreateSubnetworks).To(gomega.BeTrue())
+	g.Expect(m.Mtu).To(gomega.Equal(int32(1460)))
+	g.Expect(m.RoutingMode).To(gomega.Equal("REGIONAL"))
+	g.Expect(m.Subnetworks).To(gomega.Equal([]string{"default"}))
+	g.Expect(network.equalsTo(m)).To(gomega.BeTrue())
+
+	network.Subnetworks = append(network.Subnetworks, testProject+"/regions/europe-west1/subnetworks/default")
+	g.Expect(network.equalsTo(m)).To(gomega.BeFalse())
+}
+
+func TestSubnetworkApplyTo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	subnetwork := &Subnetwork{
+		Subnetwork: &computepb.Subnetwork{
+			Id:          proto.Uint64(4),
+			Name:        proto.String("default"),
+			Region:      proto.String(testProject + "/regions/us-central1"),
+			Network:     proto.String(testProject + "/global/networks/default"),
+			IpCidrRange: proto.String("10.128.0.0/20"),
+		},
+	}
+	g.Expect(subnetwork.ID()).To(gomega.Equal("4"))
+
+	m := &model.Subnetwork{}
+	subnetwork.ApplyTo(m)
+	g.Expect(m.Region).To(gomega.Equal("us-central1"))
+	g.Expect(m.Network).To(gomega.Equal("default"))
+	g.Expect(m.IpCidrRange).To(gomega.Equal("10.128.0.0/20"))
+	g.Expect(subnetwork.equalsTo(m)).To(gomega.BeTrue())
+
+	subnetwork.IpCidrRange = proto.String("10.128.0.0/16")
+	g.Expect(subnetwork.equalsTo(m)).To(gomega.BeFalse())
+}
+
+func TestZonalTypesApplyTo(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Keyed by name.
+	diskType := &DiskType{
+		DiskType: &computepb.DiskType{
+			Id:                proto.Uint64(5),
+			Name:              proto.String("pd-ssd"),
+			DefaultDiskSizeGb: proto.Int64(100),
+		},
+	}
+	g.Expect(diskType.ID()).To(gomega.Equal("pd-ssd"))
+	dt := &model.DiskType{}
+	diskType.ApplyTo(dt)
+	g.Expect(dt.Name).To(gomega.Equal("pd-ssd"))
+	g.Expect(dt.DefaultDiskSizeGb).To(gomega.Equal(int64(100)))
+	g.Expect(diskType.equalsTo(dt)).To(gomega.BeTrue())
+
+	machineType := &MachineType{
+		MachineType: &computepb.MachineType{
+			Id:        proto.Uint64(6),
+			Name:      proto.String("e2-medium"),
+			GuestCpus: proto.Int32(2),
+			MemoryMb:  proto.Int32(4096),
+		},
+		Zones: []string{"us-central1-a"},
+	}
+	g.Expect(machineType.ID()).To(gomega.Equal("e2-medium"))
+	mt := &model.MachineType{}
+	machineType.ApplyTo(mt)
+	g.Expect(mt.GuestCpus).To(gomega.Equal(int32(2)))
+	g.Expect(mt.MemoryMb).To(gomega.Equal(int32(4096)))
+	g.Expect(mt.Accelerators).To(gomega.BeEmpty())
+	g.Expect(mt.Zones).To(gomega.Equal([]string{"us-central1-a"}))
+	g.Expect(machineType.equalsTo(mt)).To(gomega.BeTrue())
+
+	// Offered in another zone hosting instances.
+	machineType.Zones = append(machineType.Zones, "us-central1-b")
+	g.Expect(machineType.equalsTo(mt)).To(gomega.BeFalse())
+
+	zone := &Zone{
+		Zone: &computepb.Zone{
+			Id:     proto.Uint64(7),
+			Name:   proto.String("us-central1-a"),
+			Region: proto.String(testProject + "/regions/us-central1"),
+			Status: proto.String("UP"),
+		},
+	}
+	g.Expect(zone.ID()).To(gomega.Equal("7"))
+	z := &model.Zone{}
+	zone.ApplyTo(z)
+	g.Expect(z.Region).To(gomega.Equal("us-central1"))
+	g.Expect(zone.equalsTo(z)).To(gomega.BeTrue())
+	zone.Status = proto.String("DOWN")
+	g.Expect(zone.equalsTo(z)).To(gomega.BeFalse())
+}
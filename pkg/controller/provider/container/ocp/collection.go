@@ -381,11 +381,29 @@ func (r *VM) Update(e event.UpdateEvent) bool {
 	}
 	m := &model.VM{}
 	m.With(object)
+	r.preserve(m)
 	r.Collector.Update(m)
 
 	return false
 }
 
+// Preserve the validation of the stored VM when
+// the spec (generation) has not changed.
+func (r *VM) preserve(m *model.VM) {
+	stored := &model.VM{
+		Base: model.Base{UID: m.UID},
+	}
+	err := r.Collector.DB().Get(stored)
+	if err != nil {
+		return
+	}
+	if stored.Revision == m.Revision {
+		m.RevisionValidated = stored.RevisionValidated
+		m.PolicyVersion = stored.PolicyVersion
+		m.Concerns = stored.Concerns
+	}
+}
+
 // Resource deleted watch event.
 func (r *VM) Delete(e event.DeleteEvent) bool {
 	object, cast := e.Object.(*cnv.VirtualMachine)
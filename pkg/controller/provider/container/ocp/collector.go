@@ -1,18 +1,27 @@
 package ocp
 
 import (
+	"path"
+
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
 	libcontainer "github.com/konveyor/forklift-controller/pkg/lib/inventory/container"
 	libocp "github.com/konveyor/forklift-controller/pkg/lib/inventory/container/ocp"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
 	core "k8s.io/api/core/v1"
-	"path"
 )
 
 // New collector.
 func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) libcontainer.Collector {
+	log := logging.WithName("collector|ocp").WithValues(
+		"provider",
+		path.Join(
+			provider.GetNamespace(),
+			provider.GetName()))
 	return &Collector{
+		provider: provider,
+		log:      log,
 		Collector: libocp.New(
 			db,
 			provider,
@@ -51,4 +60,55 @@ func New(db libmodel.DB, provider *api.Provider, secret *core.Secret) libcontain
 // OCP collector.
 type Collector struct {
 	*libocp.Collector
+	// Provider
+	provider *api.Provider
+	// Logger.
+	log logging.LevelLogger
+	// DB watches.
+	watches []*libmodel.Watch
+}
+
+// Start the collector.
+func (r *Collector) Start() (err error) {
+	err = r.Collector.Start()
+	if err != nil {
+		return
+	}
+	err = r.beginWatch()
+	return
+}
+
+// Shutdown the collector.
+func (r *Collector) Shutdown() {
+	r.endWatch()
+	r.Collector.Shutdown()
+}
+
+// Add model watches.
+func (r *Collector) beginWatch() (err error) {
+	defer func() {
+		if err != nil {
+			r.endWatch()
+		}
+	}()
+	w, err := r.DB().Watch(
+		&model.VM{},
+		&VMEventHandler{
+			Provider: r.provider,
+			DB:       r.DB(),
+			log:      r.log,
+		})
+	if err == nil {
+		r.watches = append(r.watches, w)
+	}
+
+	return
+}
+
+// End watches.
+func (r *Collector) endWatch() {
+	for _, watch := range r.watches {
+		watch.End()
+	}
+	r.watches = nil
 }
@@ -32,6 +32,9 @@ const (
 	Refresh = "refresh"
 )
 
+// Checkpoint recording the last refresh.
+const RefreshCheckpoint = "openstack.refresh"
+
 // Openstack data collector.
 type Collector struct {
 	// Provider
@@ -153,7 +156,16 @@ func (r *Collector) run(ctx *Context) (err error) {
 			return
 		}
 		r.startTime = time.Now()
-		r.phase = Load
+		var resumed bool
+		resumed, err = r.resume()
+		if err != nil {
+			return
+		}
+		if resumed {
+			r.phase = Loaded
+		} else {
+			r.phase = Load
+		}
 	case Load:
 		err = r.load(ctx)
 		if err == nil {
@@ -163,6 +175,7 @@ func (r *Collector) run(ctx *Context) (err error) {
 		err = r.refresh(ctx)
 		if err == nil {
 			r.phase = Parity
+			err = r.checkpoint()
 		}
 	case Parity:
 		r.endWatch()
@@ -173,6 +186,9 @@ func (r *Collector) run(ctx *Context) (err error) {
 		}
 	case Refresh:
 		err = r.refresh(ctx)
+		if err == nil {
+			err = r.checkpoint()
+		}
 		if err == nil {
 			r.parity = true
 			time.Sleep(RefreshInterval)
@@ -202,6 +218,33 @@ func (r *Collector) Shutdown() {
 	}
 }
 
+// Resume with the inventory kept by a persistent DB.
+// The resources are then refreshed (upserted) rather than
+// loaded, so the inventory is served while catching up.
+func (r *Collector) resume() (resumed bool, err error) {
+	revision, found, err := libmodel.GetCheckpoint(r.db, RefreshCheckpoint)
+	if err != nil || !found {
+		return
+	}
+	resumed = true
+
+	r.log.Info(
+		"Resumed.",
+		"refreshed",
+		revision)
+
+	return
+}
+
+// Record the last refresh.
+func (r *Collector) checkpoint() (err error) {
+	err = libmodel.SetCheckpoint(
+		r.db,
+		RefreshCheckpoint,
+		time.Now().UTC().Format(time.RFC3339))
+	return
+}
+
 // Load the inventory.
 func (r *Collector) load(ctx *Context) (err error) {
 	mark := time.Now()
@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/http"
 	liburl "net/url"
+	"strconv"
 	"time"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -70,14 +71,14 @@ func (r *Client) Connect(provider *api.Provider) (err error) {
 }
 
 // List collection.
-func (r *Client) list(path string, list interface{}) (err error) {
+func (r *Client) list(path string, list interface{}, param ...libweb.Param) (err error) {
 	url, err := liburl.Parse(r.serviceURL)
 	if err != nil {
 		err = liberr.Wrap(err)
 		return
 	}
 	url.Path += "/" + path
-	status, err := r.client.Get(url.String(), list)
+	status, err := r.client.Get(url.String(), list, param...)
 	if err != nil {
 		return
 	}
@@ -89,6 +90,23 @@ func (r *Client) list(path string, list interface{}) (err error) {
 	return
 }
 
+// Get the changes since the revision.
+func (r *Client) Changes(epoch string, since int64) (changes *ChangeList, err error) {
+	changes = &ChangeList{}
+	err = r.list(
+		"changes",
+		changes,
+		libweb.Param{
+			Key:   "epoch",
+			Value: epoch,
+		},
+		libweb.Param{
+			Key:   "since",
+			Value: strconv.FormatInt(since, 10),
+		})
+	return
+}
+
 // Get a resource.
 func (r *Client) get(path string, object interface{}) (err error) {
 	url, err := liburl.Parse(r.serviceURL)
@@ -4,6 +4,8 @@ import (
 	"context"
 	liburl "net/url"
 	libpath "path"
+	"strconv"
+	"strings"
 	"time"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
@@ -32,6 +34,10 @@ const (
 	Refresh = "refresh"
 )
 
+// Checkpoint recording the last change applied.
+// Format: <epoch>/<revision>.
+const ChangeCheckpoint = "ova.change"
+
 // OVA data collector.
 type Collector struct {
 	// Provider
@@ -52,6 +58,10 @@ type Collector struct {
 	phase string
 	// List of watches.
 	watches []*libmodel.Watch
+	// Epoch of the OVA server index.
+	epoch string
+	// Last revision (change) applied.
+	revision int64
 }
 
 // New collector.
@@ -157,16 +167,30 @@ func (r *Collector) run(ctx *Context) (err error) {
 			return
 		}
 		r.startTime = time.Now()
-		r.phase = Load
+		var resumed bool
+		resumed, err = r.resume()
+		if err != nil {
+			break
+		}
+		if resumed {
+			r.phase = Loaded
+			break
+		}
+		err = r.noteRevision()
+		if err == nil {
+			r.phase = Load
+		}
 	case Load:
 		err = r.load(ctx)
 		if err == nil {
 			r.phase = Loaded
+			err = r.checkpoint()
 		}
 	case Loaded:
 		err = r.refresh(ctx)
 		if err == nil {
 			r.phase = Parity
+			err = r.checkpoint()
 		}
 	case Parity:
 		r.endWatch()
@@ -177,6 +201,9 @@ func (r *Collector) run(ctx *Context) (err error) {
 		}
 	case Refresh:
 		err = r.refresh(ctx)
+		if err == nil {
+			err = r.checkpoint()
+		}
 		if err == nil {
 			r.parity = true
 			time.Sleep(RefreshInterval)
@@ -206,6 +233,60 @@ func (r *Collector) Shutdown() {
 	}
 }
 
+// Fetch and note the current revision (of the OVA server index).
+func (r *Collector) noteRevision() (err error) {
+	changes, err := r.client.Changes("", 0)
+	if err != nil {
+		return
+	}
+	r.epoch = changes.Epoch
+	r.revision = changes.Revision
+
+	r.log.Info(
+		"Revision noted.",
+		"epoch",
+		r.epoch,
+		"revision",
+		r.revision)
+
+	return
+}
+
+// Resume from the last change recorded with
+// the inventory kept by a persistent DB.
+func (r *Collector) resume() (resumed bool, err error) {
+	checkpoint, found, err := libmodel.GetCheckpoint(r.db, ChangeCheckpoint)
+	if err != nil || !found {
+		return
+	}
+	epoch, revision, _ := strings.Cut(checkpoint, "/")
+	r.revision, err = strconv.ParseInt(revision, 10, 64)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.epoch = epoch
+	resumed = true
+
+	r.log.Info(
+		"Resumed.",
+		"epoch",
+		r.epoch,
+		"revision",
+		r.revision)
+
+	return
+}
+
+// Record the last change applied.
+func (r *Collector) checkpoint() (err error) {
+	err = libmodel.SetCheckpoint(
+		r.db,
+		ChangeCheckpoint,
+		r.epoch+"/"+strconv.FormatInt(r.revision, 10))
+	return
+}
+
 // Load the inventory.
 func (r *Collector) load(ctx *Context) (err error) {
 	mark := time.Now()
@@ -295,16 +376,68 @@ func (r *Collector) endWatch() {
 }
 
 // Refresh the inventory.
-//   - List modified vms.
+//   - Fetch the changes since the last revision applied.
 //   - Build the changeSet.
 //   - Apply the changeSet.
 //
+// All collections are listed (resync) when the OVA
+// server cannot report the changes.
+//
 // The two-phased approach ensures we do not hold the
 // DB transaction while using the provider API which
 // can block or be slow.
 func (r *Collector) refresh(ctx *Context) (err error) {
-	var updates []Updater
 	mark := time.Now()
+	changes, err := r.client.Changes(r.epoch, r.revision)
+	if err != nil {
+		return
+	}
+	if changes.Reset {
+		err = r.resync(ctx)
+		if err != nil {
+			return
+		}
+	} else {
+		var updates []Updater
+		for i := range changes.Changes {
+			change := &changes.Changes[i]
+			adapter, found := adapterMap[change.Kind]
+			if !found {
+				continue
+			}
+			var updater Updater
+			updater, err = adapter.Apply(change)
+			if err != nil {
+				return
+			}
+			if updater != nil {
+				updates = append(updates, updater)
+			}
+		}
+		err = r.apply(updates)
+		if err != nil {
+			return
+		}
+	}
+	r.epoch = changes.Epoch
+	r.revision = changes.Revision
+	r.log.Info(
+		"Refresh finished.",
+		"reset",
+		changes.Reset,
+		"changes",
+		len(changes.Changes),
+		"revision",
+		r.revision,
+		"duration",
+		time.Since(mark))
+	return
+}
+
+// Resync the inventory.
+// All collections are listed and applied.
+func (r *Collector) resync(ctx *Context) (err error) {
+	var updates []Updater
 	for _, adapter := range adapterList {
 		if ctx.canceled() {
 			return
@@ -318,10 +451,6 @@ func (r *Collector) refresh(ctx *Context) (err error) {
 			return
 		}
 	}
-	r.log.Info(
-		"Refresh finished.",
-		"duration",
-		time.Since(mark))
 	return
 }
 
@@ -0,0 +1,205 @@
+package ova
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ova"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	libweb "github.com/konveyor/forklift-controller/pkg/lib/inventory/web"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+)
+
+// OVA server reporting the changes recorded in its journal.
+type ovaServer struct {
+	epoch    string
+	revision int64
+	journal  []Change
+	vms      []VM
+	disks    []Disk
+	networks []Network
+}
+
+func (r *ovaServer) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	var reply interface{}
+	switch request.URL.Path {
+	case "/changes":
+		q := request.URL.Query()
+		since, _ := strconv.ParseInt(q.Get("since"), 10, 64)
+		list := ChangeList{
+			Epoch:    r.epoch,
+			Revision: r.revision,
+			Changes:  []Change{},
+		}
+		oldest := r.revision
+		if len(r.journal) > 0 {
+			oldest = r.journal[0].Revision - 1
+		}
+		if q.Get("epoch") != r.epoch || since > r.revision || since < oldest {
+			list.Reset = true
+		} else {
+			for _, change := range r.journal {
+				if change.Revision > since {
+					list.Changes = append(list.Changes, change)
+				}
+			}
+		}
+		reply = list
+	case "/vms":
+		reply = r.vms
+	case "/disks":
+		reply = r.disks
+	case "/networks":
+		reply = r.networks
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	b, _ := json.Marshal(reply)
+	_, _ = w.Write(b)
+}
+
+// Record a change.
+func (r *ovaServer) change(kind, action, id string, object interface{}) {
+	b, _ := json.Marshal(object)
+	r.revision++
+	r.journal = append(
+		r.journal,
+		Change{
+			Revision: r.revision,
+			Kind:     kind,
+			Action:   action,
+			ID:       id,
+			Object:   b,
+		})
+}
+
+func ovaCollector(g *gomega.WithT, server *ovaServer) (collector *Collector, ctx *Context, done func()) {
+	db := libmodel.New("/tmp/test-ova-collector.db", model.All()...)
+	err := db.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	httpServer := httptest.NewServer(server)
+	collector = New(db, &api.Provider{}, &core.Secret{})
+	collector.client.client = &libweb.Client{}
+	collector.client.serviceURL = httpServer.URL
+	collector.epoch = server.epoch
+	collector.revision = server.revision
+	ctx = &Context{
+		ctx:    context.TODO(),
+		client: collector.client,
+		db:     db,
+		log:    collector.log,
+	}
+	done = func() {
+		httpServer.Close()
+		_ = db.Close(true)
+	}
+	// Initial inventory.
+	err = collector.load(ctx)
+	g.Expect(err).To(gomega.BeNil())
+	return
+}
+
+// VM names keyed by ID.
+func ovaVMs(g *gomega.WithT, db libmodel.DB) (names map[string]string) {
+	list := []model.VM{}
+	err := db.List(&list, libmodel.ListOptions{})
+	g.Expect(err).To(gomega.BeNil())
+	names = map[string]string{}
+	for _, vm := range list {
+		names[vm.ID] = vm.Name
+	}
+	return
+}
+
+func TestCollectorRefreshChanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := &ovaServer{
+		epoch:    "1",
+		vms:      []VM{{UUID: "vm-1", Name: "one"}, {UUID: "vm-2", Name: "two"}},
+		networks: []Network{{ID: "net-1", Name: "net"}},
+	}
+	server.change(VMKind, Added, "vm-1", server.vms[0])
+	server.change(VMKind, Added, "vm-2", server.vms[1])
+	server.change(NetworkKind, Added, "net-1", server.networks[0])
+	collector, ctx, done := ovaCollector(g, server)
+	defer done()
+	// Validation preserved on update.
+	vm := &model.VM{Base: model.Base{ID: "vm-1"}}
+	err := collector.db.Get(vm)
+	g.Expect(err).To(gomega.BeNil())
+	vm.RevisionValidated = vm.Revision
+	err = collector.db.Update(vm)
+	g.Expect(err).To(gomega.BeNil())
+	validated := vm.RevisionValidated
+	// Added, updated and deleted.
+	server.vms = []VM{{UUID: "vm-1", Name: "renamed"}, {UUID: "vm-3", Name: "three"}}
+	server.disks = []Disk{{ID: "disk-1", Name: "disk"}}
+	server.change(VMKind, Updated, "vm-1", server.vms[0])
+	server.change(VMKind, Deleted, "vm-2", VM{UUID: "vm-2", Name: "two"})
+	server.change(VMKind, Added, "vm-3", server.vms[1])
+	server.change(DiskKind, Added, "disk-1", server.disks[0])
+	server.change(NetworkKind, Deleted, "net-1", server.networks[0])
+	server.networks = nil
+	err = collector.refresh(ctx)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(collector.revision).To(gomega.Equal(server.revision))
+	g.Expect(ovaVMs(g, collector.db)).To(gomega.Equal(
+		map[string]string{
+			"vm-1": "renamed",
+			"vm-3": "three",
+		}))
+	vm = &model.VM{Base: model.Base{ID: "vm-1"}}
+	err = collector.db.Get(vm)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(vm.RevisionValidated).To(gomega.Equal(validated))
+	err = collector.db.Get(&model.Disk{Base: model.Base{ID: "disk-1"}})
+	g.Expect(err).To(gomega.BeNil())
+	err = collector.db.Get(&model.Network{Base: model.Base{ID: "net-1"}})
+	g.Expect(errors.Is(err, libmodel.NotFound)).To(gomega.BeTrue())
+}
+
+func TestCollectorRefreshReset(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := &ovaServer{
+		epoch: "1",
+		vms:   []VM{{UUID: "vm-1", Name: "one"}, {UUID: "vm-2", Name: "two"}},
+	}
+	server.change(VMKind, Added, "vm-1", server.vms[0])
+	server.change(VMKind, Added, "vm-2", server.vms[1])
+	collector, ctx, done := ovaCollector(g, server)
+	defer done()
+	// The changes are no longer in the journal.
+	server.vms = []VM{{UUID: "vm-1", Name: "renamed"}}
+	server.change(VMKind, Updated, "vm-1", server.vms[0])
+	server.change(VMKind, Deleted, "vm-2", VM{UUID: "vm-2"})
+	server.journal = server.journal[3:]
+	err := collector.refresh(ctx)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(collector.revision).To(gomega.Equal(server.revision))
+	g.Expect(ovaVMs(g, collector.db)).To(gomega.Equal(
+		map[string]string{
+			"vm-1": "renamed",
+		}))
+	// The index has been rebuilt (epoch changed).
+	server.epoch = "2"
+	server.revision = collector.revision
+	server.vms = []VM{{UUID: "vm-3", Name: "three"}}
+	server.journal = nil
+	server.change(VMKind, Added, "vm-3", server.vms[0])
+	err = collector.refresh(ctx)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(collector.epoch).To(gomega.Equal("2"))
+	g.Expect(collector.revision).To(gomega.Equal(server.revision))
+	g.Expect(ovaVMs(g, collector.db)).To(gomega.Equal(
+		map[string]string{
+			"vm-3": "three",
+		}))
+}
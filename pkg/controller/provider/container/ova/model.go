@@ -2,11 +2,13 @@ package ova
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ova"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"github.com/konveyor/forklift-controller/pkg/lib/logging"
@@ -15,16 +17,24 @@ import (
 // All adapters.
 var adapterList []Adapter
 
-// Event (type) mapped to adapter.
-var adapterMap = map[int][]Adapter{}
+// Change (kind) mapped to adapter.
+var adapterMap = map[string]Adapter{}
 
 func init() {
+	networkAdapter := &NetworkAdapter{}
+	diskAdapter := &DiskAdapter{}
+	vmAdapter := &VMAdapter{}
 	adapterList = []Adapter{
-		&NetworkAdapter{},
-		&DiskAdapter{},
-		&VMAdapter{},
+		networkAdapter,
+		diskAdapter,
+		vmAdapter,
 		&StorageAdapter{},
 	}
+	adapterMap = map[string]Adapter{
+		NetworkKind: networkAdapter,
+		DiskKind:    diskAdapter,
+		VMKind:      vmAdapter,
+	}
 }
 
 // Updates the DB based on
@@ -60,8 +70,12 @@ func (r *Context) canceled() (done bool) {
 type Adapter interface {
 	// List REST collections.
 	List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error)
-	// Get object updates
+	// Get object updates.
+	// All objects are listed and the models not
+	// listed are deleted.
 	GetUpdates(ctx *Context) (updater []Updater, err error)
+	// Apply a change reported by the OVA server.
+	Apply(change *Change) (updater Updater, err error)
 }
 
 // Base adapter.
@@ -83,7 +97,7 @@ func (r *NetworkAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iter
 	list := fb.NewList()
 	for _, object := range networkList {
 		m := &model.Network{
-			Base: model.Base{Name: object.Name},
+			Base: model.Base{ID: object.ID},
 		}
 		object.ApplyTo(m)
 		list.Append(m)
@@ -100,29 +114,70 @@ func (r *NetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error)
 	if err != nil {
 		return
 	}
+	listed := map[string]bool{}
 	for i := range networkList {
 		network := &networkList[i]
-		updater := func(tx *libmodel.Tx) (err error) {
-			m := &model.Network{
-				Base: model.Base{Name: network.Name},
-			}
-			err = tx.Get(m)
+		listed[network.ID] = true
+		updates = append(updates, r.updater(network))
+	}
+	updates = append(
+		updates,
+		func(tx *libmodel.Tx) (err error) {
+			list := []model.Network{}
+			err = tx.List(&list, libmodel.ListOptions{})
 			if err != nil {
-				if errors.Is(err, libmodel.NotFound) {
-					network.ApplyTo(m)
-					err = tx.Insert(m)
-				}
 				return
 			}
-			network.ApplyTo(m)
-			err = tx.Update(m)
+			for i := range list {
+				m := &list[i]
+				if !listed[m.ID] {
+					err = tx.Delete(m)
+					if err != nil {
+						return
+					}
+				}
+			}
 			return
-		}
-		updates = append(updates, updater)
+		})
+	return
+}
+
+// Apply a change.
+func (r *NetworkAdapter) Apply(change *Change) (updater Updater, err error) {
+	network := &Network{}
+	err = json.Unmarshal(change.Object, network)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
 	}
+	if change.Action == Deleted {
+		updater = deleter(&model.Network{Base: model.Base{ID: network.ID}})
+		return
+	}
+	updater = r.updater(network)
 	return
 }
 
+// Build an updater that creates or updates the model.
+func (r *NetworkAdapter) updater(network *Network) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		m := &model.Network{
+			Base: model.Base{ID: network.ID},
+		}
+		err = tx.Get(m)
+		if err != nil {
+			if errors.Is(err, libmodel.NotFound) {
+				network.ApplyTo(m)
+				err = tx.Insert(m)
+			}
+			return
+		}
+		network.ApplyTo(m)
+		err = tx.Update(m)
+		return
+	}
+}
+
 // VM adapter.
 type VMAdapter struct {
 	BaseAdapter
@@ -155,26 +210,70 @@ func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
 	if err != nil {
 		return
 	}
+	listed := map[string]bool{}
 	for i := range vmList {
 		vm := &vmList[i]
-		updater := func(tx *libmodel.Tx) (err error) {
-			m := &model.VM{
-				Base: model.Base{ID: vm.UUID},
+		listed[vm.UUID] = true
+		updates = append(updates, r.updater(vm))
+	}
+	updates = append(
+		updates,
+		func(tx *libmodel.Tx) (err error) {
+			list := []model.VM{}
+			err = tx.List(&list, libmodel.ListOptions{})
+			if err != nil {
+				return
 			}
-			if err = tx.Get(m); err != nil {
-				if errors.Is(err, libmodel.NotFound) {
-					vm.ApplyTo(m)
-					err = tx.Insert(m)
+			for i := range list {
+				m := &list[i]
+				if !listed[m.ID] {
+					err = tx.Delete(m)
+					if err != nil {
+						return
+					}
 				}
-			} else if vm.OvaPath != m.OvaPath {
+			}
+			return
+		})
+	return
+}
+
+// Apply a change.
+func (r *VMAdapter) Apply(change *Change) (updater Updater, err error) {
+	vm := &VM{}
+	err = json.Unmarshal(change.Object, vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if change.Action == Deleted {
+		updater = deleter(&model.VM{Base: model.Base{ID: vm.UUID}})
+		return
+	}
+	updater = r.updater(vm)
+	return
+}
+
+// Build an updater that creates or updates the model.
+// The validation of updated VMs is preserved.
+func (r *VMAdapter) updater(vm *VM) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		m := &model.VM{
+			Base: model.Base{ID: vm.UUID},
+		}
+		if err = tx.Get(m); err != nil {
+			if errors.Is(err, libmodel.NotFound) {
 				vm.ApplyTo(m)
-				err = tx.Update(m)
+				err = tx.Insert(m)
 			}
 			return
 		}
-		updates = append(updates, updater)
+		vm.RevisionValidated = m.RevisionValidated
+		vm.PolicyVersion = m.PolicyVersion
+		vm.ApplyTo(m)
+		err = tx.Update(m)
+		return
 	}
-	return
 }
 
 // Disk adapter.
@@ -192,7 +291,7 @@ func (r *DiskAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterato
 	list := fb.NewList()
 	for _, object := range diskList {
 		m := &model.Disk{
-			Base: model.Base{ID: object.DiskId},
+			Base: model.Base{ID: object.ID},
 		}
 		object.ApplyTo(m)
 		list.Append(m)
@@ -209,29 +308,70 @@ func (r *DiskAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
 	if err != nil {
 		return
 	}
+	listed := map[string]bool{}
 	for i := range diskList {
 		disk := &diskList[i]
-		updater := func(tx *libmodel.Tx) (err error) {
-			m := &model.Disk{
-				Base: model.Base{ID: disk.DiskId},
-			}
-			err = tx.Get(m)
+		listed[disk.ID] = true
+		updates = append(updates, r.updater(disk))
+	}
+	updates = append(
+		updates,
+		func(tx *libmodel.Tx) (err error) {
+			list := []model.Disk{}
+			err = tx.List(&list, libmodel.ListOptions{})
 			if err != nil {
-				if errors.Is(err, libmodel.NotFound) {
-					disk.ApplyTo(m)
-					err = tx.Insert(m)
-				}
 				return
 			}
-			disk.ApplyTo(m)
-			err = tx.Update(m)
+			for i := range list {
+				m := &list[i]
+				if !listed[m.ID] {
+					err = tx.Delete(m)
+					if err != nil {
+						return
+					}
+				}
+			}
 			return
-		}
-		updates = append(updates, updater)
+		})
+	return
+}
+
+// Apply a change.
+func (r *DiskAdapter) Apply(change *Change) (updater Updater, err error) {
+	disk := &Disk{}
+	err = json.Unmarshal(change.Object, disk)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if change.Action == Deleted {
+		updater = deleter(&model.Disk{Base: model.Base{ID: disk.ID}})
+		return
 	}
+	updater = r.updater(disk)
 	return
 }
 
+// Build an updater that creates or updates the model.
+func (r *DiskAdapter) updater(disk *Disk) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		m := &model.Disk{
+			Base: model.Base{ID: disk.ID},
+		}
+		err = tx.Get(m)
+		if err != nil {
+			if errors.Is(err, libmodel.NotFound) {
+				disk.ApplyTo(m)
+				err = tx.Insert(m)
+			}
+			return
+		}
+		disk.ApplyTo(m)
+		err = tx.Update(m)
+		return
+	}
+}
+
 type StorageAdapter struct {
 	BaseAdapter
 }
@@ -240,6 +380,10 @@ func (r *StorageAdapter) GetUpdates(ctx *Context) (updates []Updater, err error)
 	return
 }
 
+func (r *StorageAdapter) Apply(change *Change) (updater Updater, err error) {
+	return
+}
+
 // List the collection.
 func (r *StorageAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
 	storageName := fmt.Sprintf("Dummy storage for source provider %s", provider.Name)
@@ -261,3 +405,14 @@ func (r *StorageAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iter
 
 	return
 }
+
+// Build an updater that deletes the model.
+func deleter(m libmodel.Model) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		err = tx.Delete(m)
+		if errors.Is(err, libmodel.NotFound) {
+			err = nil
+		}
+		return
+	}
+}
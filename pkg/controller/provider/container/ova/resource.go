@@ -1,6 +1,7 @@
 package ova
 
 import (
+	"encoding/json"
 	"strconv"
 
 	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/ova"
@@ -32,6 +33,7 @@ func (b *Base) int64(s string) (v int64) {
 type VM struct {
 	Name                  string   `json:"Name"`
 	OvaPath               string   `json:"OvaPath"`
+	OsType                string   `json:"OsType"`
 	RevisionValidated     int64    `json:"RevisionValidated"`
 	PolicyVersion         int      `json:"PolicyVersion"`
 	UUID                  string   `json:"UUID"`
@@ -51,6 +53,9 @@ type VM struct {
 	NumaNodeAffinity      []string `json:"NumaNodeAffinity"`
 	StorageUsed           int64    `json:"StorageUsed"`
 	ChangeTrackingEnabled bool     `json:"ChangeTrackingEnabled"`
+	IntegrityVerified     bool     `json:"IntegrityVerified"`
+	MissingFiles          []string `json:"MissingFiles"`
+	CorruptFiles          []string `json:"CorruptFiles"`
 	Devices               []struct {
 		Kind string `json:"Kind"`
 	} `json:"Devices"`
@@ -73,6 +78,9 @@ type VM struct {
 		FileRef                 string `json:"FileRef"`
 		Format                  string `json:"Format"`
 		PopulatedSize           int64  `json:"PopulatedSize"`
+		Shared                  bool   `json:"Shared"`
+		Compression             string `json:"Compression"`
+		ChunkSize               int64  `json:"ChunkSize"`
 	} `json:"Disks"`
 	Networks []struct {
 		ID          string `json:"ID"`
@@ -86,6 +94,7 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.Name = r.Name
 	m.ID = r.UUID
 	m.OvaPath = r.OvaPath
+	m.OsType = r.OsType
 	m.RevisionValidated = r.RevisionValidated
 	m.PolicyVersion = r.PolicyVersion
 	m.UUID = r.UUID
@@ -105,6 +114,9 @@ func (r *VM) ApplyTo(m *model.VM) {
 	m.NumaNodeAffinity = r.NumaNodeAffinity
 	m.StorageUsed = r.StorageUsed
 	m.ChangeTrackingEnabled = r.ChangeTrackingEnabled
+	m.IntegrityVerified = r.IntegrityVerified
+	m.MissingFiles = r.MissingFiles
+	m.CorruptFiles = r.CorruptFiles
 	r.addNICs(m)
 	r.addDisks(m)
 	r.addDevices(m)
@@ -150,6 +162,9 @@ func (r *VM) addDisks(m *model.VM) {
 				FileRef:                 disk.FileRef,
 				Format:                  disk.Format,
 				PopulatedSize:           disk.PopulatedSize,
+				Shared:                  disk.Shared,
+				Compression:             disk.Compression,
+				ChunkSize:               disk.ChunkSize,
 			})
 	}
 }
@@ -229,3 +244,38 @@ func (r *Storage) ApplyTo(m *model.Storage) {
 	m.Base.Name = m.Name
 	m.Base.ID = m.ID
 }
+
+// Change actions.
+const (
+	Added   = "added"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+// Change kinds.
+const (
+	VMKind      = "vm"
+	DiskKind    = "disk"
+	NetworkKind = "network"
+)
+
+// Change reported by the OVA server.
+type Change struct {
+	Revision int64           `json:"revision"`
+	Kind     string          `json:"kind"`
+	Action   string          `json:"action"`
+	ID       string          `json:"id"`
+	Object   json.RawMessage `json:"object"`
+}
+
+// Changes since a revision.
+type ChangeList struct {
+	// Index epoch.
+	Epoch string `json:"epoch"`
+	// Current revision.
+	Revision int64 `json:"revision"`
+	// The changes cannot be reported.
+	// The collections must be listed.
+	Reset   bool     `json:"reset"`
+	Changes []Change `json:"changes"`
+}
@@ -39,6 +39,9 @@ const (
 	Refresh = "refresh"
 )
 
+// Checkpoint recording the last event applied.
+const EventCheckpoint = "ovirt.event"
+
 // oVirt data collector.
 type Collector struct {
 	// Provider
@@ -167,6 +170,15 @@ func (r *Collector) run(ctx *Context) (err error) {
 		r.phase)
 	switch r.phase {
 	case Started:
+		var resumed bool
+		resumed, err = r.resume()
+		if err != nil {
+			break
+		}
+		if resumed {
+			r.phase = Loaded
+			break
+		}
 		err = r.noteLastEvent()
 		if err == nil {
 			r.phase = Load
@@ -175,11 +187,13 @@ func (r *Collector) run(ctx *Context) (err error) {
 		err = r.load(ctx)
 		if err == nil {
 			r.phase = Loaded
+			err = r.checkpoint()
 		}
 	case Loaded:
 		err = r.refresh(ctx)
 		if err == nil {
 			r.phase = Parity
+			err = r.checkpoint()
 		}
 	case Parity:
 		r.endWatch()
@@ -190,6 +204,9 @@ func (r *Collector) run(ctx *Context) (err error) {
 		}
 	case Refresh:
 		err = r.refresh(ctx)
+		if err == nil {
+			err = r.checkpoint()
+		}
 		if err == nil {
 			r.parity = true
 			time.Sleep(RefreshInterval)
@@ -248,6 +265,37 @@ func (r *Collector) noteLastEvent() (err error) {
 	return
 }
 
+// Resume from the last event recorded with
+// the inventory kept by a persistent DB.
+func (r *Collector) resume() (resumed bool, err error) {
+	revision, found, err := libmodel.GetCheckpoint(r.db, EventCheckpoint)
+	if err != nil || !found {
+		return
+	}
+	r.lastEvent, err = strconv.Atoi(revision)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	resumed = true
+
+	r.log.Info(
+		"Resumed.",
+		"event",
+		r.lastEvent)
+
+	return
+}
+
+// Record the last event applied.
+func (r *Collector) checkpoint() (err error) {
+	err = libmodel.SetCheckpoint(
+		r.db,
+		EventCheckpoint,
+		strconv.Itoa(r.lastEvent))
+	return
+}
+
 // Load the inventory.
 func (r *Collector) load(ctx *Context) (err error) {
 	err = r.connect()
@@ -2,6 +2,7 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	liburl "net/url"
 	"path"
@@ -31,6 +32,8 @@ const (
 	RetryDelay = time.Second * 5
 	// Max object in each update.
 	MaxObjectUpdates = 10000
+	// Checkpoint recording the last initial parity.
+	ParityCheckpoint = "vsphere.parity"
 )
 
 // Types
@@ -85,8 +88,9 @@ const (
 	// Network
 	fTag = "tag"
 	// PortGroup
-	fDVSwitch = "config.distributedVirtualSwitch"
-	fKey      = "key"
+	fDVSwitch          = "config.distributedVirtualSwitch"
+	fKey               = "key"
+	fDefaultPortConfig = "config.defaultPortConfig"
 	// DV Switch
 	fDVSwitchHost = "config.host"
 	// Datastore
@@ -112,6 +116,7 @@ const (
 	fGuestID             = "summary.guest.guestId"
 	fBalloonedMemory     = "summary.quickStats.balloonedMemory"
 	fVmIpAddress         = "summary.guest.ipAddress"
+	fGuestNet            = "guest.net"
 	fStorageUsed         = "summary.storage.committed"
 	fRuntimeHost         = "runtime.host"
 	fPowerState          = "runtime.powerState"
@@ -225,6 +230,12 @@ type Collector struct {
 	cancel func()
 	// has parity.
 	parity bool
+	// The inventory kept by a persistent DB is served
+	// while the initial update set is applied.
+	resumed bool
+	// Objects reported by the initial update set.
+	// Keyed by kind and ID.
+	reported map[string]bool
 }
 
 // New collector.
@@ -266,11 +277,12 @@ func (r *Collector) DB() libmodel.DB {
 // Reset.
 func (r *Collector) Reset() {
 	r.parity = false
+	r.resumed = false
 }
 
 // Reset.
 func (r *Collector) HasParity() bool {
-	return r.parity
+	return r.parity || r.resumed
 }
 
 // Test connect/logout.
@@ -336,12 +348,11 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 		return err
 	}
 	defer r.close()
-	about := r.client.ServiceContent.About
-	err = r.db.Insert(
-		&model.About{
-			APIVersion: about.ApiVersion,
-			Product:    about.LicenseProductName,
-		})
+	err = r.noteAbout()
+	if err != nil {
+		return err
+	}
+	r.resumed, err = r.resume()
 	if err != nil {
 		return err
 	}
@@ -364,8 +375,11 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	}
 	var tx *libmodel.Tx
 	watchList := []*libmodel.Watch{}
+	r.reported = make(map[string]bool)
 	defer func() {
 		r.parity = false
+		r.resumed = false
+		r.reported = nil
 		for _, w := range watchList {
 			w.End()
 		}
@@ -412,7 +426,16 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 		}
 		if updateSet.Truncated == nil || !*updateSet.Truncated {
 			if !r.parity {
+				err = r.prune()
+				if err != nil {
+					return err
+				}
+				err = r.checkpoint()
+				if err != nil {
+					return err
+				}
 				r.parity = true
+				r.resumed = false
 				r.log.Info(
 					"Initial parity.",
 					"duration",
@@ -425,6 +448,113 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	return nil
 }
 
+// Resume with the inventory kept by a persistent DB.
+// The property collector versions are only valid within the
+// session that created the collector, so the initial update
+// set is read in full again. The inventory recorded at the last
+// parity is served meanwhile and the objects no longer reported
+// are pruned once the update set has been applied.
+func (r *Collector) resume() (resumed bool, err error) {
+	mark, found, err := libmodel.GetCheckpoint(r.db, ParityCheckpoint)
+	if err != nil || !found {
+		return
+	}
+	resumed = true
+
+	r.log.Info(
+		"Resumed.",
+		"parity",
+		mark)
+
+	return
+}
+
+// Record the initial parity.
+func (r *Collector) checkpoint() (err error) {
+	err = libmodel.SetCheckpoint(
+		r.db,
+		ParityCheckpoint,
+		time.Now().Format(time.RFC3339))
+	return
+}
+
+// Note the API version and product.
+func (r *Collector) noteAbout() (err error) {
+	about := r.client.ServiceContent.About
+	m := &model.About{
+		APIVersion: about.ApiVersion,
+		Product:    about.LicenseProductName,
+	}
+	err = r.db.Update(m)
+	if errors.Is(err, libmodel.NotFound) {
+		err = r.db.Insert(m)
+	}
+	return
+}
+
+// Delete the objects not reported by the initial update set.
+// The inventory is kept by the DB when the collector is restarted
+// (reconnected) or resumed. The objects deleted in the meantime
+// are not reported by the new property collector.
+func (r *Collector) prune() (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	deleted := 0
+	for _, kind := range []model.Model{
+		&model.Folder{},
+		&model.Datacenter{},
+		&model.Cluster{},
+		&model.Host{},
+		&model.Network{},
+		&model.Datastore{},
+		&model.VM{},
+	} {
+		itr, fErr := tx.Find(kind, libmodel.ListOptions{})
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			m := object.(model.Model)
+			if r.reported[r.key(m)] {
+				continue
+			}
+			err = tx.Delete(m)
+			if err != nil {
+				return
+			}
+			deleted++
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+	r.reported = nil
+	if deleted > 0 {
+		r.log.Info(
+			"Pruned.",
+			"deleted",
+			deleted)
+	}
+
+	return
+}
+
+// The key of a reported object.
+func (r *Collector) key(m model.Model) string {
+	return libmodel.Table{}.Name(m) + "/" + m.Pk()
+}
+
 // Add model watches.
 func (r *Collector) watch() (list []*libmodel.Watch) {
 	// Cluster
@@ -652,6 +782,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fDVSwitch,
 				fTag,
 				fKey,
+				fDefaultPortConfig,
 			},
 		},
 		{
@@ -695,6 +826,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fGuestID,
 				fBalloonedMemory,
 				fVmIpAddress,
+				fGuestNet,
 				fStorageUsed,
 				fDatastore,
 				fNetwork,
@@ -826,6 +958,8 @@ func (r *Collector) selectAdapter(u types.ObjectUpdate) (Adapter, bool) {
 }
 
 // Object created.
+// The object may already be stored when the collector
+// has been restarted (reconnected) or resumed.
 func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	adapter, selected := r.selectAdapter(u)
 	if !selected {
@@ -833,7 +967,13 @@ func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	}
 	adapter.Apply(u)
 	m := adapter.Model()
-	err := tx.Insert(m)
+	if r.reported != nil {
+		r.reported[r.key(m)] = true
+	}
+	err := tx.Update(m)
+	if errors.Is(err, libmodel.NotFound) {
+		err = tx.Insert(m)
+	}
 	if err != nil {
 		return liberr.Wrap(err)
 	}
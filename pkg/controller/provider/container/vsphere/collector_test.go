@@ -0,0 +1,114 @@
+package vsphere
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"github.com/onsi/gomega"
+	"github.com/vmware/govmomi/vim25/types"
+	core "k8s.io/api/core/v1"
+)
+
+func enter(kind, id, name string) types.ObjectUpdate {
+	return types.ObjectUpdate{
+		Kind: Enter,
+		Obj: types.ManagedObjectReference{
+			Type:  kind,
+			Value: id,
+		},
+		ChangeSet: []types.PropertyChange{
+			{
+				Op:   Assign,
+				Name: fName,
+				Val:  name,
+			},
+		},
+	}
+}
+
+func TestCollectorResume(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := libmodel.New("/tmp/test-vsphere-collector.db", model.All()...)
+	err := db.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	collector := New(db, &api.Provider{}, &core.Secret{})
+	// Initial update set.
+	tx, err := db.Begin()
+	g.Expect(err).To(gomega.BeNil())
+	err = collector.apply(
+		context.TODO(),
+		tx,
+		[]types.ObjectUpdate{
+			enter(VirtualMachine, "vm-1", "one"),
+			enter(VirtualMachine, "vm-2", "two"),
+			enter(Host, "host-1", "host"),
+		})
+	g.Expect(err).To(gomega.BeNil())
+	err = tx.Commit()
+	g.Expect(err).To(gomega.BeNil())
+	// Restarted: the objects already stored are updated
+	// and the objects no longer reported are deleted.
+	collector.reported = make(map[string]bool)
+	tx, err = db.Begin()
+	g.Expect(err).To(gomega.BeNil())
+	err = collector.apply(
+		context.TODO(),
+		tx,
+		[]types.ObjectUpdate{
+			enter(VirtualMachine, "vm-1", "renamed"),
+			enter(VirtualMachine, "vm-3", "three"),
+			enter(Host, "host-1", "host"),
+		})
+	g.Expect(err).To(gomega.BeNil())
+	err = tx.Commit()
+	g.Expect(err).To(gomega.BeNil())
+	err = collector.prune()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(collector.reported).To(gomega.BeNil())
+	vms := []model.VM{}
+	err = db.List(&vms, libmodel.ListOptions{})
+	g.Expect(err).To(gomega.BeNil())
+	names := map[string]string{}
+	for _, vm := range vms {
+		names[vm.ID] = vm.Name
+	}
+	g.Expect(names).To(gomega.Equal(
+		map[string]string{
+			"vm-1": "renamed",
+			"vm-3": "three",
+		}))
+	host := &model.Host{Base: model.Base{ID: "host-1"}}
+	err = db.Get(host)
+	g.Expect(err).To(gomega.BeNil())
+}
+
+func TestCollectorResumeParity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := libmodel.New("/tmp/test-vsphere-collector-parity.db", model.All()...)
+	err := db.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	collector := New(db, &api.Provider{}, &core.Secret{})
+	// Not resumed before the initial parity.
+	resumed, err := collector.resume()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(resumed).To(gomega.BeFalse())
+	err = collector.checkpoint()
+	g.Expect(err).To(gomega.BeNil())
+	// The kept inventory is served while the
+	// initial update set is applied again.
+	collector.resumed, err = collector.resume()
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(collector.resumed).To(gomega.BeTrue())
+	g.Expect(collector.HasParity()).To(gomega.BeTrue())
+	collector.Reset()
+	g.Expect(collector.HasParity()).To(gomega.BeFalse())
+}
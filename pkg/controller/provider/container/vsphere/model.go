@@ -6,6 +6,7 @@ import (
 	"github.com/vmware/govmomi/vim25/types"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -302,6 +303,7 @@ func (v *HostAdapter) Apply(u types.ObjectUpdate) {
 								Key:    portGroup.Key,
 								Name:   portGroup.Spec.Name,
 								Switch: portGroup.Vswitch,
+								VlanId: portGroup.Spec.VlanId,
 							})
 					}
 				}
@@ -386,6 +388,12 @@ func (v *NetworkAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.Key = s
 				}
+			case fDefaultPortConfig:
+				if setting, cast := p.Val.(*types.VMwareDVSPortSetting); cast {
+					if vlan, cast := setting.Vlan.(*types.VmwareDistributedVirtualSwitchVlanIdSpec); cast && vlan.VlanId > 0 {
+						v.model.Tag = strconv.Itoa(int(vlan.VlanId))
+					}
+				}
 			case fDVSwitch:
 				v.model.DVSwitch = v.Ref(p.Val)
 			}
@@ -590,6 +598,30 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if s, cast := p.Val.(string); cast {
 					v.model.IpAddress = s
 				}
+			case fGuestNet:
+				if nics, cast := p.Val.(types.ArrayOfGuestNicInfo); cast {
+					// The guest tools don't report the networks when the
+					// VM isn't powered on. Only set the guest networks if
+					// reported so that the stored value isn't erased.
+					guestNetworks := []model.GuestNetwork{}
+					for _, nic := range nics.GuestNicInfo {
+						if nic.IpConfig == nil {
+							continue
+						}
+						for _, ip := range nic.IpConfig.IpAddress {
+							guestNetworks = append(
+								guestNetworks,
+								model.GuestNetwork{
+									MAC:          nic.MacAddress,
+									IP:           ip.IpAddress,
+									PrefixLength: ip.PrefixLength,
+								})
+						}
+					}
+					if len(guestNetworks) > 0 {
+						v.model.GuestNetworks = guestNetworks
+					}
+				}
 			case fFtInfo:
 				if _, cast := p.Val.(types.FaultToleranceConfigInfo); cast {
 					v.model.FaultToleranceEnabled = true
@@ -42,6 +42,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apiserver/pkg/storage/names"
@@ -69,6 +70,11 @@ const (
 	ovaImageVar         = "OVA_PROVIDER_SERVER_IMAGE"
 	nfsVolumeNamePrefix = "nfs-volume"
 	mountPath           = "/ova"
+	ovaStoreVolumeName  = "ova-store"
+	ovaStoreSize        = "100Gi"
+	ovaIndexVolumeName  = "ova-index"
+	ovaIndexMountPath   = "/var/lib/ova-provider-server"
+	ovaIndexSize        = "1Gi"
 )
 
 // Creates a new Inventory Controller and adds it to the Manager.
@@ -298,18 +304,27 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 		return
 	}
 	log.Info("Update container.")
+	// The inventory kept by a persistent backend is reused when
+	// the collector is first started after a restart, provided
+	// the collector supports resuming and the provider has not
+	// been updated in the meantime.
+	purge := !provider.HasReconciled() || !container.Resumable(provider)
 	if current, found := r.container.Get(provider); found {
 		current.Shutdown()
 		_ = current.DB().Close(true)
+		purge = true
 		r.Log.V(2).Info(
 			"Shutdown found collector.")
 	}
-	db := r.getDB(provider)
+	db, err := r.getDB(provider)
+	if err != nil {
+		return
+	}
 	secret, err := r.getSecret(provider)
 	if err != nil {
 		return
 	}
-	err = db.Open(true)
+	err = db.Open(purge)
 	if err != nil {
 		return
 	}
@@ -327,7 +342,7 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 }
 
 // Build DB for provider.
-func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
+func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB, err error) {
 	dir := Settings.Inventory.WorkingDir
 	dir = filepath.Join(
 		dir,
@@ -336,10 +351,17 @@ func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	_ = os.MkdirAll(dir, 0755)
 	file := string(provider.UID) + ".db"
 	path := filepath.Join(dir, file)
+	backend, err := libmodel.NewBackend(Settings.Inventory.DBBackend, path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
 	models := model.Models(provider)
-	db = libmodel.New(path, models...)
+	db = libmodel.NewWith(backend, models...)
 	r.Log.Info(
 		"Opening DB.",
+		"backend",
+		Settings.Inventory.DBBackend,
 		"path",
 		path)
 	return
@@ -369,7 +391,6 @@ func (r *Reconciler) createOVAServerDeployment(provider *api.Provider, ctx conte
 	deploymentName := fmt.Sprintf("%s-deployment-%s", ovaServerPrefix, provider.Name)
 	annotations := make(map[string]string)
 	labels := map[string]string{"providerName": provider.Name, "app": "forklift"}
-	url := provider.Spec.URL
 	var replicas int32 = 1
 
 	ownerReference := metav1.OwnerReference{
@@ -379,6 +400,22 @@ func (r *Reconciler) createOVAServerDeployment(provider *api.Provider, ctx conte
 		UID:        provider.UID,
 	}
 
+	// Index of the OVA files.
+	err := r.createOVAIndex(provider, ownerReference, ctx)
+	if err != nil {
+		r.Log.Error(err, "Failed to create OVA index")
+		return
+	}
+
+	// Store for the mirrored OVA files.
+	if provider.OvaMirrored() {
+		err := r.createOVAStore(provider, ownerReference, ctx)
+		if err != nil {
+			r.Log.Error(err, "Failed to create OVA store")
+			return
+		}
+	}
+
 	//OVA server deployment
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -390,6 +427,10 @@ func (r *Reconciler) createOVAServerDeployment(provider *api.Provider, ctx conte
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
+			// The index volume cannot be shared by the pods.
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RecreateDeploymentStrategyType,
+			},
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app": "forklift",
@@ -402,12 +443,12 @@ func (r *Reconciler) createOVAServerDeployment(provider *api.Provider, ctx conte
 						"app":          "forklift",
 					},
 				},
-				Spec: r.makeOvaProviderPodSpec(url, string(provider.Name)),
+				Spec: r.makeOvaProviderPodSpec(provider),
 			},
 		},
 	}
 
-	err := r.Create(ctx, deployment)
+	err = r.Create(ctx, deployment)
 	if err != nil {
 		r.Log.Error(err, "Failed to create OVA server deployment")
 		return
@@ -446,25 +487,124 @@ func (r *Reconciler) createOVAServerDeployment(provider *api.Provider, ctx conte
 	}
 }
 
-func (r *Reconciler) makeOvaProviderPodSpec(url string, providerName string) v1.PodSpec {
-	splitted := strings.Split(url, ":")
-	nonRoot := false
+// Create the PVC used to store the OVA files mirrored
+// from an http or s3 source.
+func (r *Reconciler) createOVAStore(provider *api.Provider, ownerReference metav1.OwnerReference, ctx context.Context) (err error) {
+	size := ovaStoreSize
+	if s, found := provider.Spec.Settings[api.OvaStoreSize]; found && s != "" {
+		size = s
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		err = liberr.Wrap(err, "size", size)
+		return
+	}
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            provider.OvaStoreName(),
+			Namespace:       provider.Namespace,
+			Labels:          map[string]string{"providerName": provider.Name, "app": "forklift"},
+			OwnerReferences: []metav1.OwnerReference{ownerReference},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{
+				v1.ReadWriteMany,
+			},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: quantity,
+				},
+			},
+		},
+	}
+	if class, found := provider.Spec.Settings[api.OvaStoreStorageClass]; found && class != "" {
+		pvc.Spec.StorageClassName = &class
+	}
+	err = r.Create(ctx, pvc)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+	return
+}
 
-	if len(splitted) != 2 {
-		r.Log.Error(nil, "NFS server path doesn't contains :")
+// Create the PVC used to persist the index of the OVA files.
+// The index is kept out of the catalog so that the (shared) NFS
+// share is not written and survives the restarts of the server.
+func (r *Reconciler) createOVAIndex(provider *api.Provider, ownerReference metav1.OwnerReference, ctx context.Context) (err error) {
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            provider.OvaIndexName(),
+			Namespace:       provider.Namespace,
+			Labels:          map[string]string{"providerName": provider.Name, "app": "forklift"},
+			OwnerReferences: []metav1.OwnerReference{ownerReference},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{
+				v1.ReadWriteOnce,
+			},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse(ovaIndexSize),
+				},
+			},
+		},
+	}
+	err = r.Create(ctx, pvc)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
 	}
-	nfsServer := splitted[0]
-	nfsPath := splitted[1]
+	return
+}
+
+func (r *Reconciler) makeOvaProviderPodSpec(provider *api.Provider) v1.PodSpec {
+	nonRoot := false
+	providerName := provider.Name
 
 	imageName, ok := os.LookupEnv(ovaImageVar)
 	if !ok {
 		r.Log.Error(nil, "Failed to find OVA server image")
 	}
 
-	nfsVolumeName := fmt.Sprintf("%s-%s", nfsVolumeNamePrefix, providerName)
-
 	ovaContainerName := fmt.Sprintf("%s-pod-%s", ovaServerPrefix, providerName)
 
+	var volume v1.Volume
+	var env []v1.EnvVar
+	if provider.OvaMirrored() {
+		volume = v1.Volume{
+			Name: ovaStoreVolumeName,
+			VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: provider.OvaStoreName(),
+				},
+			},
+		}
+		env = r.makeOvaSourceEnv(provider)
+	} else {
+		splitted := strings.Split(provider.Spec.URL, ":")
+		if len(splitted) != 2 {
+			r.Log.Error(nil, "NFS server path doesn't contains :")
+			splitted = append(splitted, "", "")
+		}
+		volume = v1.Volume{
+			Name: fmt.Sprintf("%s-%s", nfsVolumeNamePrefix, providerName),
+			VolumeSource: v1.VolumeSource{
+				NFS: &v1.NFSVolumeSource{
+					Server:   splitted[0],
+					Path:     splitted[1],
+					ReadOnly: false,
+				},
+			},
+		}
+	}
+
 	return v1.PodSpec{
 
 		Containers: []v1.Container{
@@ -475,23 +615,28 @@ func (r *Reconciler) makeOvaProviderPodSpec(url string, providerName string) v1.
 					RunAsNonRoot: &nonRoot,
 				},
 				Image: imageName,
+				Env:   env,
 				VolumeMounts: []v1.VolumeMount{
 					{
-						Name:      nfsVolumeName,
-						MountPath: "/ova",
+						Name:      volume.Name,
+						MountPath: mountPath,
+					},
+					{
+						Name:      ovaIndexVolumeName,
+						MountPath: ovaIndexMountPath,
 					},
 				},
 			},
 		},
 		ServiceAccountName: "forklift-controller",
 		Volumes: []v1.Volume{
+			volume,
+			// The index is kept out of the (shared) catalog.
 			{
-				Name: nfsVolumeName,
+				Name: ovaIndexVolumeName,
 				VolumeSource: v1.VolumeSource{
-					NFS: &v1.NFSVolumeSource{
-						Server:   nfsServer,
-						Path:     nfsPath,
-						ReadOnly: false,
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+						ClaimName: provider.OvaIndexName(),
 					},
 				},
 			},
@@ -499,6 +644,51 @@ func (r *Reconciler) makeOvaProviderPodSpec(url string, providerName string) v1.
 	}
 }
 
+// Environment used by the OVA server to mirror an http or s3 source.
+// The credentials are referenced in the provider secret (when specified):
+// user and password are used for basic authentication (http) or
+// as the access key ID and secret access key (s3).
+func (r *Reconciler) makeOvaSourceEnv(provider *api.Provider) (env []v1.EnvVar) {
+	env = []v1.EnvVar{
+		{Name: "SOURCE", Value: provider.OvaSource()},
+		{Name: "SOURCE_URL", Value: provider.Spec.URL},
+	}
+	if region, found := provider.Spec.Settings[api.OvaS3Region]; found && region != "" {
+		env = append(env, v1.EnvVar{Name: "S3_REGION", Value: region})
+	}
+	if provider.Spec.Secret.Name == "" {
+		return
+	}
+	optional := true
+	secretEnv := func(name, key string) v1.EnvVar {
+		return v1.EnvVar{
+			Name: name,
+			ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: provider.Spec.Secret.Name,
+					},
+					Key:      key,
+					Optional: &optional,
+				},
+			},
+		}
+	}
+	if provider.OvaSource() == api.OvaSourceS3 {
+		env = append(
+			env,
+			secretEnv("S3_ACCESS_KEY_ID", "user"),
+			secretEnv("S3_SECRET_ACCESS_KEY", "password"))
+	} else {
+		env = append(
+			env,
+			secretEnv("SOURCE_USER", "user"),
+			secretEnv("SOURCE_PASSWORD", "password"))
+	}
+	env = append(env, secretEnv("INSECURE_SKIP_VERIFY", "insecureSkipVerify"))
+	return
+}
+
 // Provider catalog.
 type Catalog struct {
 	mutex   sync.Mutex
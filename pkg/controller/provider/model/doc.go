@@ -2,6 +2,7 @@ package model
 
 import (
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/model/ova"
@@ -32,6 +33,10 @@ func Models(provider *api.Provider) (all []interface{}) {
 		all = append(
 			all,
 			ova.All()...)
+	case api.GCP:
+		all = append(
+			all,
+			gcp.All()...)
 	}
 
 	return
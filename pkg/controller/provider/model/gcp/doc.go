@@ -9,5 +9,10 @@ func All() []interface{} {
 		&Image{},
 		&VM{},
 		&Network{},
+		&DiskType{},
+		&Zone{},
+		&Disk{},
+		&Subnetwork{},
+		&MachineType{},
 	}
 }
@@ -20,7 +20,7 @@ type ListOptions = base.ListOptions
 type Concern = base.Concern
 type Ref = base.Ref
 
-// Base OpenStack model.
+// Base GCP model.
 type Base struct {
 	// Managed object ID.
 	ID string `sql:"pk"`
@@ -43,79 +43,183 @@ func (m *Base) String() string {
 // GCP Image model.
 type Image struct {
 	Base
-	RevisionValidated int64             `sql:"d0,index(revision_validated)"`
-	Architecture      *string           `sql:""`
-	ArchiveSizeBytes  *int64            `sql:""`
-	CreationTimestamp *string           `sql:""`
-	Description       *string           `sql:""`
-	DiskSizeGb        *int64            `sql:""`
-	Family            *string           `sql:""`
-	Id                *uint64           `sql:""`
-	Kind              *string           `sql:""`
-	LabelFingerprint  *string           `sql:""`
+	Description       string            `sql:""`
+	Status            string            `sql:""`
+	Family            string            `sql:""`
+	Architecture      string            `sql:""`
+	ArchiveSizeBytes  int64             `sql:""`
+	DiskSizeGb        int64             `sql:""`
+	SourceDisk        string            `sql:""`
+	SourceDiskID      string            `sql:""`
+	SourceType        string            `sql:""`
 	Labels            map[string]string `sql:""`
-	LicenseCodes      []int64           `sql:""`
 	Licenses          []string          `sql:""`
-	Name              *string           `sql:""`
-	SatisfiesPzs      *bool             `sql:""`
-	SelfLink          *string           `sql:""`
-	SourceDisk        *string           `sql:""`
-	SourceDiskId      *string           `sql:""`
-	SourceImage       *string           `sql:""`
-	SourceImageId     *string           `sql:""`
-	SourceSnapshot    *string           `sql:""`
-	SourceSnapshotId  *string           `sql:""`
-	SourceType        *string           `sql:""`
-	Status            *string           `sql:""`
+	GuestOsFeatures   []string          `sql:""`
 	StorageLocations  []string          `sql:""`
+	CreationTimestamp string            `sql:""`
+	SelfLink          string            `sql:""`
 }
 
+// GCP VM (instance) model.
+type VM struct {
+	Base
+	RevisionValidated int64              `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int                `sql:"d0,index(policyVersion)" eq:"-"`
+	Description       string             `sql:""`
+	Status            string             `sql:""`
+	Zone              string             `sql:"d0,index(zone)"`
+	MachineType       string             `sql:""`
+	CpuPlatform       string             `sql:""`
+	GuestCpus         int32              `sql:""`
+	MemoryMb          int32              `sql:""`
+	Hostname          string             `sql:""`
+	Labels            map[string]string  `sql:""`
+	SecureBoot        bool               `sql:""`
+	Vtpm              bool               `sql:""`
+	Accelerators      []Accelerator      `sql:""`
+	SoleTenant        bool               `sql:""`
+	Disks             []AttachedDisk     `sql:""`
+	NICs              []NetworkInterface `sql:""`
+	Fingerprint       string             `sql:""`
+	CreationTimestamp string             `sql:""`
+	SelfLink          string             `sql:""`
+	Concerns          []Concern          `sql:"" eq:"-"`
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// Determine if the VM boots with UEFI.
+// Shielded VMs and images flagged UEFI compatible boot with UEFI.
+func (m *VM) UEFI() bool {
+	if m.SecureBoot || m.Vtpm {
+		return true
+	}
+	for _, disk := range m.Disks {
+		if !disk.Boot {
+			continue
+		}
+		for _, feature := range disk.GuestOsFeatures {
+			if feature == "UEFI_COMPATIBLE" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Disk attached to an instance.
 type AttachedDisk struct {
-	ID string `sql:"d0,fk(volume)"`
+	// Disk name.
+	Name string `json:"name"`
+	// Device name exposed to the guest.
+	DeviceName string `json:"deviceName"`
+	// Disk type (pd-standard, pd-balanced, pd-ssd, ...).
+	DiskType string `json:"diskType"`
+	// PERSISTENT or SCRATCH.
+	Type string `json:"type"`
+	// SCSI or NVME.
+	Interface string `json:"interface"`
+	// READ_WRITE or READ_ONLY.
+	Mode            string   `json:"mode"`
+	Boot            bool     `json:"boot"`
+	Index           int32    `json:"index"`
+	SizeGb          int64    `json:"sizeGb"`
+	Licenses        []string `json:"licenses"`
+	GuestOsFeatures []string `json:"guestOsFeatures"`
 }
 
-type VM struct {
-	Base
-	RevisionValidated int64               `sql:"d0,index(revisionValidated)"`
-	PolicyVersion     int                 `sql:"d0,index(policyVersion)" eq:"-"`
-	Concerns          []Concern           `sql:"" eq:"-"`
-	CpuPlatform       *string             `sql:""`
-	CreationTimestamp *string             `sql:""`
-	Description       *string             `sql:""`
-	Disks             []*AttachedDisk     `sql:""`
-	Fingerprint       *string             `sql:""`
-	Hostname          *string             `sql:""`
-	NetworkInterfaces []*NetworkInterface `sql:""`
-	SelfLink          *string             `sql:""`
-	Status            *string             `sql:""`
-	Zone              *string             `sql:""`
+// Accelerator (GPU) attached to an instance.
+type Accelerator struct {
+	Type  string `json:"type"`
+	Count int32  `json:"count"`
 }
 
+// Network interface of an instance.
 type NetworkInterface struct {
-	Ipv6AccessType *string `sql:""`
-	Ipv6Address    *string `sql:""`
-	Name           *string `sql:""`
-	Network        *string `sql:""`
-	NetworkIP      *string `sql:""`
+	Name       string `json:"name"`
+	Network    string `json:"network"`
+	Subnetwork string `json:"subnetwork"`
+	NetworkIP  string `json:"networkIP"`
+	NicType    string `json:"nicType"`
+	StackType  string `json:"stackType"`
 }
 
+// GCP network (VPC) model.
 type Network struct {
 	Base
-	IPv4Range                             *string  `sql:""`
-	CreationTimestamp                     *string  `sql:""`
-	Description                           *string  `sql:""`
-	EnableUlaInternalIpv6                 *bool    `sql:""`
-	FirewallPolicy                        *string  `sql:""`
-	GatewayIPv4                           *string  `sql:""`
-	Id                                    *uint64  `sql:""`
-	InternalIpv6Range                     *string  `sql:""`
-	NetworkFirewallPolicyEnforcementOrder *string  `sql:""`
-	SelfLink                              *string  `sql:""`
-	SelfLinkWithId                        *string  `sql:""`
-	Subnetworks                           []string `sql:""`
+	Description           string   `sql:""`
+	IPv4Range             string   `sql:""`
+	GatewayIPv4           string   `sql:""`
+	AutoCreateSubnetworks bool     `sql:""`
+	Mtu                   int32    `sql:""`
+	RoutingMode           string   `sql:""`
+	Subnetworks           []string `sql:""`
+	CreationTimestamp     string   `sql:""`
+	SelfLink              string   `sql:""`
 }
 
-// Determine if current revision has been validated.
-func (m *VM) Validated() bool {
-	return m.RevisionValidated == m.Revision
+// GCP disk type model.
+// Disk types are zonal resources, the inventory
+// keeps one entry per name keyed by the name.
+type DiskType struct {
+	Base
+	Description       string `sql:""`
+	DefaultDiskSizeGb int64  `sql:""`
+	ValidDiskSize     string `sql:""`
+	SelfLink          string `sql:""`
+}
+
+// GCP zone model.
+type Zone struct {
+	Base
+	Description string `sql:""`
+	Region      string `sql:"d0,index(region)"`
+	Status      string `sql:""`
+	SelfLink    string `sql:""`
+}
+
+// GCP persistent disk model.
+type Disk struct {
+	Base
+	Description       string            `sql:""`
+	Zone              string            `sql:"d0,index(zone)"`
+	DiskType          string            `sql:""`
+	SizeGb            int64             `sql:""`
+	Status            string            `sql:""`
+	SourceImage       string            `sql:""`
+	Users             []string          `sql:""`
+	Labels            map[string]string `sql:""`
+	CreationTimestamp string            `sql:""`
+	SelfLink          string            `sql:""`
+}
+
+// GCP subnetwork model.
+type Subnetwork struct {
+	Base
+	Description       string `sql:""`
+	Region            string `sql:"d0,index(region)"`
+	Network           string `sql:"d0,index(network)"`
+	IpCidrRange       string `sql:""`
+	GatewayAddress    string `sql:""`
+	Purpose           string `sql:""`
+	StackType         string `sql:""`
+	CreationTimestamp string `sql:""`
+	SelfLink          string `sql:""`
+}
+
+// GCP machine type model.
+// Like the disk types, machine types are zonal and the
+// inventory keeps one entry per name keyed by the name.
+// Only the zones hosting instances are collected.
+type MachineType struct {
+	Base
+	Description  string        `sql:""`
+	GuestCpus    int32         `sql:""`
+	MemoryMb     int32         `sql:""`
+	IsSharedCpu  bool          `sql:""`
+	Accelerators []Accelerator `sql:""`
+	Zones        []string      `sql:""`
 }
@@ -7,9 +7,14 @@ import (
 
 // Kind
 var (
-	VMKind      = libref.ToKind(VM{})
-	ImageKind   = libref.ToKind(Image{})
-	NetworkKind = libref.ToKind(Network{})
+	VMKind          = libref.ToKind(VM{})
+	ImageKind       = libref.ToKind(Image{})
+	NetworkKind     = libref.ToKind(Network{})
+	DiskTypeKind    = libref.ToKind(DiskType{})
+	ZoneKind        = libref.ToKind(Zone{})
+	DiskKind        = libref.ToKind(Disk{})
+	SubnetworkKind  = libref.ToKind(Subnetwork{})
+	MachineTypeKind = libref.ToKind(MachineType{})
 )
 
 // Types.
@@ -28,6 +28,7 @@ const (
 type Model = base.Model
 type ListOptions = base.ListOptions
 type Ref = base.Ref
+type Concern = base.Concern
 
 // k8s Resource.
 type Resource interface {
@@ -124,10 +125,21 @@ func (m *NetworkAttachmentDefinition) With(n *net.NetworkAttachmentDefinition) {
 // VM
 type VM struct {
 	Base
-	Object cnv.VirtualMachine `sql:""`
+	// The VM (spec) generation.
+	Revision          int64              `sql:"d0,index(revision)"`
+	RevisionValidated int64              `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int                `sql:"d0,index(policyVersion)"`
+	Concerns          []Concern          `sql:""`
+	Object            cnv.VirtualMachine `sql:""`
 }
 
 func (m *VM) With(v *cnv.VirtualMachine) {
 	m.Base.With(v)
+	m.Revision = v.Generation
 	m.Object = *v
 }
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
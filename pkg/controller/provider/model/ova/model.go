@@ -69,6 +69,7 @@ type Network struct {
 type VM struct {
 	Base
 	OvaPath               string    `sql:""`
+	OsType                string    `sql:""`
 	RevisionValidated     int64     `sql:"d0,index(revisionValidated)"`
 	PolicyVersion         int       `sql:"d0,index(policyVersion)"`
 	UUID                  string    `sql:""`
@@ -88,6 +89,9 @@ type VM struct {
 	NumaNodeAffinity      []string  `sql:""`
 	StorageUsed           int64     `sql:""`
 	ChangeTrackingEnabled bool      `sql:""`
+	IntegrityVerified     bool      `sql:""`
+	MissingFiles          []string  `sql:""`
+	CorruptFiles          []string  `sql:""`
 	Devices               []Device  `sql:""`
 	NICs                  []NIC     `sql:""`
 	Disks                 []Disk    `sql:""`
@@ -105,6 +109,9 @@ type Disk struct {
 	FileRef                 string `sql:""`
 	Format                  string `sql:""`
 	PopulatedSize           int64  `sql:""`
+	Shared                  bool   `sql:""`
+	Compression             string `sql:""`
+	ChunkSize               int64  `sql:""`
 }
 
 // Virtual Device.
@@ -199,6 +199,7 @@ type PortGroup struct {
 	Key    string `json:"key"`
 	Name   string `json:"name"`
 	Switch string `json:"vSwitch"`
+	VlanId int32  `json:"vlanId"`
 }
 
 type Switch struct {
@@ -261,6 +262,8 @@ type VM struct {
 	Disks                 []Disk    `sql:""`
 	Networks              []Ref     `sql:""`
 	Concerns              []Concern `sql:""`
+	// Reported by the guest tools.
+	GuestNetworks []GuestNetwork `sql:""`
 }
 
 // Determine if current revision has been validated.
@@ -288,3 +291,11 @@ type NIC struct {
 	Network Ref    `json:"network"`
 	MAC     string `json:"mac"`
 }
+
+// Guest network.
+// An IP address of a NIC reported by the guest tools.
+type GuestNetwork struct {
+	MAC          string `json:"mac"`
+	IP           string `json:"ip"`
+	PrefixLength int32  `json:"prefix"`
+}
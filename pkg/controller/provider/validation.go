@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strings"
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/container"
@@ -132,6 +133,10 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 	if provider.IsHost() {
 		return nil
 	}
+	// The GCP endpoint is implied by the project.
+	if provider.Type() == api.GCP && provider.Spec.URL == "" {
+		return nil
+	}
 	if provider.Spec.URL == "" {
 		provider.Status.Phase = ValidationFailed
 		provider.Status.SetCondition(
@@ -144,6 +149,30 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 			})
 	}
 	if provider.Type() == api.Ova {
+		r.validateOvaURL(provider)
+		return nil
+	}
+	_, err := url.Parse(provider.Spec.URL)
+	if err != nil {
+		provider.Status.Phase = ValidationFailed
+		provider.Status.SetCondition(
+			libcnd.Condition{
+				Type:     UrlNotValid,
+				Status:   True,
+				Reason:   Malformed,
+				Category: Critical,
+				Message:  fmt.Sprintf("The `url` is malformed: %s", err.Error()),
+			})
+	}
+
+	return nil
+}
+
+// Validate the OVA source and URL.
+// NFS: <server>:<path>. HTTP and S3: http(s)://<host>/<path>.
+func (r *Reconciler) validateOvaURL(provider *api.Provider) {
+	switch provider.OvaSource() {
+	case api.OvaSourceNFS:
 		if !isValidNFSPath(provider.Spec.URL) {
 			provider.Status.Phase = ValidationFailed
 			provider.Status.SetCondition(
@@ -155,22 +184,44 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 					Message:  fmt.Sprintf("The NFS path is malformed"),
 				})
 		}
-		return nil
-	}
-	_, err := url.Parse(provider.Spec.URL)
-	if err != nil {
+	case api.OvaSourceHTTP, api.OvaSourceS3:
+		u, err := url.Parse(provider.Spec.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			provider.Status.Phase = ValidationFailed
+			provider.Status.SetCondition(
+				libcnd.Condition{
+					Type:     UrlNotValid,
+					Status:   True,
+					Reason:   Malformed,
+					Category: Critical,
+					Message:  "The `url` must be an http(s) URL.",
+				})
+			return
+		}
+		if provider.OvaSource() == api.OvaSourceS3 && strings.Trim(u.Path, "/") == "" {
+			provider.Status.Phase = ValidationFailed
+			provider.Status.SetCondition(
+				libcnd.Condition{
+					Type:     UrlNotValid,
+					Status:   True,
+					Reason:   Malformed,
+					Category: Critical,
+					Message:  "The `url` must include the bucket: <endpoint>/<bucket>[/<prefix>].",
+				})
+		}
+	default:
 		provider.Status.Phase = ValidationFailed
 		provider.Status.SetCondition(
 			libcnd.Condition{
-				Type:     UrlNotValid,
+				Type:     SettingsNotValid,
 				Status:   True,
-				Reason:   Malformed,
+				Reason:   NotSupported,
 				Category: Critical,
-				Message:  fmt.Sprintf("The `url` is malformed: %s", err.Error()),
+				Message: fmt.Sprintf(
+					"The OVA `source` setting is not supported. Supported: %s.",
+					strings.Join(api.OvaSources, ", ")),
 			})
 	}
-
-	return nil
 }
 
 // Validate secret (ref).
@@ -260,6 +311,10 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 			"url",
 			"insecureSkipVerify",
 		}
+	case api.GCP:
+		keyList = []string{
+			"serviceAccountKey",
+		}
 	}
 	for _, key := range keyList {
 		if _, found := secret.Data[key]; !found {
@@ -14,6 +14,7 @@ import (
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	auth "k8s.io/api/authentication/v1"
 	auth2 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -104,20 +105,8 @@ func (r *Auth) permit(token string, ns string, p *api.Provider) (allowed bool, e
 	if !tr.Status.Authenticated {
 		return
 	}
-	user := tr.Status.User
-	extra := map[string]auth2.ExtraValue{}
-	for k, v := range user.Extra {
-		extra[k] = append(
-			auth2.ExtraValue{},
-			v...)
-	}
 	// Users should be able to query information on providers from the inventory
 	// only if they have permissions for list/get 'providers' in the K8s API
-	group, resource, err := api.GetGroupResource(p)
-	if err != nil {
-		err = liberr.Wrap(err)
-		return
-	}
 	var verb, namespace string
 	if p.ObjectMeta.UID != "" {
 		verb = "get"
@@ -126,13 +115,103 @@ func (r *Auth) permit(token string, ns string, p *api.Provider) (allowed bool, e
 		verb = "list"
 		namespace = ns
 	}
+	allowed, err = r.review(w, tr.Status.User, p, verb, namespace, p.Name)
+	return
+}
+
+// Authenticate token.
+// Token must have "get" on the object.
+func (r *Auth) PermitObject(ctx *gin.Context, object client.Object) (status int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	status = http.StatusOK
+	if r.cache == nil {
+		r.cache = make(map[string]time.Time)
+	}
+	r.prune()
+	token := r.token(ctx)
+	if token == "" {
+		status = http.StatusUnauthorized
+		return
+	}
+	_, resource, err := api.GetGroupResource(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		status = http.StatusInternalServerError
+		return
+	}
+	key := path.Join(
+		token,
+		resource,
+		object.GetNamespace(),
+		object.GetName())
+	if t, found := r.cache[key]; found {
+		if time.Since(t) <= r.TTL {
+			return
+		}
+	}
+	allowed := false
+	tr := &auth.TokenReview{
+		Spec: auth.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	w, err := r.writer()
+	if err == nil {
+		err = w.Create(context.TODO(), tr)
+	}
+	if err != nil {
+		log.Error(err, "Authorization failed.")
+		status = http.StatusInternalServerError
+		return
+	}
+	if tr.Status.Authenticated {
+		allowed, err = r.review(
+			w,
+			tr.Status.User,
+			object,
+			"get",
+			object.GetNamespace(),
+			object.GetName())
+	}
+	if allowed {
+		r.cache[key] = time.Now()
+	} else {
+		status = http.StatusForbidden
+		log.Info(
+			http.StatusText(status),
+			"token",
+			token)
+	}
+
+	return
+}
+
+// Review the access of the user to the object.
+func (r *Auth) review(
+	w client.Writer,
+	user auth.UserInfo,
+	object runtime.Object,
+	verb, namespace, name string) (allowed bool, err error) {
+	//
+	extra := map[string]auth2.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = append(
+			auth2.ExtraValue{},
+			v...)
+	}
+	group, resource, err := api.GetGroupResource(object)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
 	review := &auth2.SubjectAccessReview{
 		Spec: auth2.SubjectAccessReviewSpec{
 			ResourceAttributes: &auth2.ResourceAttributes{
 				Group:     group,
 				Resource:  resource,
 				Namespace: namespace,
-				Name:      p.Name,
+				Name:      name,
 				Verb:      verb,
 			},
 			Extra:  extra,
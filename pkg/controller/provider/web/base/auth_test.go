@@ -125,3 +125,44 @@ func TestAuth(t *testing.T) {
 	auth.prune()
 	g.Expect(0).To(gomega.Equal(len(auth.cache)))
 }
+
+func TestAuthObject(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	writer := &fakeWriter{allowed: true}
+	auth := Auth{
+		Writer: writer,
+		TTL:    time.Second,
+	}
+	ctx := &gin.Context{
+		Request: &http.Request{
+			Header: map[string][]string{
+				"Authorization": {"Bearer 12345"},
+			},
+			URL: &url.URL{},
+		},
+	}
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "konveyor-forklift",
+			Name:      "test",
+		},
+	}
+	// First call with no cached token.
+	status, _ := auth.PermitObject(ctx, migration)
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(writer.trCount).To(gomega.Equal(1))
+	g.Expect(writer.arCount).To(gomega.Equal(1))
+	// Second call with cached token.
+	status, _ = auth.PermitObject(ctx, migration)
+	g.Expect(status).To(gomega.Equal(http.StatusOK))
+	g.Expect(writer.trCount).To(gomega.Equal(1))
+	// Other object not cached.
+	writer.allowed = false
+	status, _ = auth.PermitObject(ctx, &api.Plan{ObjectMeta: migration.ObjectMeta})
+	g.Expect(status).To(gomega.Equal(http.StatusForbidden))
+	g.Expect(writer.trCount).To(gomega.Equal(2))
+	// Missing token.
+	ctx.Request.Header = map[string][]string{}
+	status, _ = auth.PermitObject(ctx, migration)
+	g.Expect(status).To(gomega.Equal(http.StatusUnauthorized))
+}
@@ -7,6 +7,7 @@ import (
 
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ova"
@@ -95,6 +96,14 @@ func NewClient(provider *api.Provider) (client Client, err error) {
 				Resolver: &ova.Resolver{Provider: provider},
 			},
 		}
+	case api.GCP:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &gcp.Finder{},
+			restClient: base.RestClient{
+				Resolver: &gcp.Resolver{Provider: provider},
+			},
+		}
 	default:
 		err = liberr.Wrap(
 			ProviderNotSupportedError{
@@ -2,6 +2,7 @@ package web
 
 import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/gcp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ocp"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/openstack"
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/ova"
@@ -15,6 +16,8 @@ import (
 func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = []libweb.RequestHandler{
 		&libweb.SchemaHandler{},
+		&PolicyHandler{},
+		&ReportHandler{},
 		&ProviderHandler{
 			Handler: base.Handler{
 				Container: container,
@@ -36,5 +39,8 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = append(
 		all,
 		ova.Handlers(container)...)
+	all = append(
+		all,
+		gcp.Handlers(container)...)
 	return
 }
@@ -0,0 +1,162 @@
+package gcp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"net/http"
+)
+
+// Routes
+const (
+	DiskTypeParam      = "disktype"
+	DiskTypeCollection = "disktypes"
+	DiskTypesRoot      = ProviderRoot + "/" + DiskTypeCollection
+	DiskTypeRoot       = DiskTypesRoot + "/:" + DiskTypeParam
+)
+
+// DiskType handler.
+type DiskTypeHandler struct {
+	Handler
+}
+
+type DiskType struct {
+	Resource
+	Description       string `json:"description"`
+	DefaultDiskSizeGb int64  `json:"defaultDiskSizeGb"`
+	ValidDiskSize     string `json:"validDiskSize"`
+}
+
+// Add routes to the `gin` router.
+func (h *DiskTypeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(DiskTypesRoot, h.List)
+	e.GET(DiskTypesRoot+"/", h.List)
+	e.GET(DiskTypeRoot, h.Get)
+}
+
+// Build the resource using the model.
+func (r *DiskType) With(m *model.DiskType) {
+	r.Resource.With(&m.Base)
+	r.Description = m.Description
+	r.DefaultDiskSizeGb = m.DefaultDiskSizeGb
+	r.ValidDiskSize = m.ValidDiskSize
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h DiskTypeHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.DiskType{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &DiskType{}
+		r.With(&m)
+		r.Link(h.Provider)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h DiskTypeHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	h.Detail = model.MaxDetail
+	m := &model.DiskType{
+		Base: model.Base{
+			ID: ctx.Param(DiskTypeParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &DiskType{}
+	r.With(m)
+	r.Link(h.Provider)
+	content := r.Content(h.Detail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *DiskTypeHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.DiskType{},
+		func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.DiskType)
+			diskType := &DiskType{}
+			diskType.With(m)
+			diskType.Link(h.Provider)
+			r = diskType
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
+
+// Build self link (URI).
+func (r *DiskType) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		DiskTypeRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			DiskTypeParam:      r.ID,
+		})
+}
+
+// As content.
+func (r *DiskType) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
@@ -25,28 +25,20 @@ type ImageHandler struct {
 
 type Image struct {
 	Resource
+	Description       string            `json:"description"`
+	Status            string            `json:"status"`
+	Family            string            `json:"family"`
 	Architecture      string            `json:"architecture"`
 	ArchiveSizeBytes  int64             `json:"archiveSizeBytes"`
-	CreationTimestamp string            `json:"creationTimestamp"`
-	Description       string            `json:"description"`
 	DiskSizeGb        int64             `json:"diskSizeGb"`
-	Family            string            `json:"family"`
-	Kind              string            `json:"kind"`
-	LabelFingerprint  string            `json:"labelFingerprint"`
-	Labels            map[string]string `json:"labels"`
-	LicenseCodes      []int64           `json:"licenseCodes"`
-	Licenses          []string          `json:"licenses"`
-	SatisfiesPzs      bool              `json:"satisfiesPzs"`
-	SelfLink          string            `json:"selfLink"`
 	SourceDisk        string            `json:"sourceDisk"`
-	SourceDiskId      string            `json:"sourceDiskId"`
-	SourceImage       string            `json:"sourceImage"`
-	SourceImageId     string            `json:"sourceImageId"`
-	SourceSnapshot    string            `json:"sourceSnapshot"`
-	SourceSnapshotId  string            `json:"sourceSnapshotId"`
+	SourceDiskID      string            `json:"sourceDiskID"`
 	SourceType        string            `json:"sourceType"`
-	Status            string            `json:"status"`
-	StorageLocations  []string          `json:"storageLocations"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Licenses          []string          `json:"licenses,omitempty"`
+	GuestOsFeatures   []string          `json:"guestOsFeatures,omitempty"`
+	StorageLocations  []string          `json:"storageLocations,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp"`
 }
 
 // Add routes to the `gin` router.
@@ -58,31 +50,21 @@ func (h *ImageHandler) AddRoutes(e *gin.Engine) {
 
 // Build the resource using the model.
 func (r *Image) With(m *model.Image) {
-	r.Resource.ID = m.ID
-	r.Resource.Revision = m.Revision
-	r.Resource.Name = *m.Name
-	r.Status = *m.Status
-	r.Architecture = *m.Architecture
-	r.ArchiveSizeBytes = *m.ArchiveSizeBytes
-	r.CreationTimestamp = *m.CreationTimestamp
-	r.Description = *m.Description
-	r.DiskSizeGb = *m.DiskSizeGb
-	r.Family = *m.Family
-	r.Kind = *m.Kind
-	r.LabelFingerprint = *m.LabelFingerprint
+	r.Resource.With(&m.Base)
+	r.Description = m.Description
+	r.Status = m.Status
+	r.Family = m.Family
+	r.Architecture = m.Architecture
+	r.ArchiveSizeBytes = m.ArchiveSizeBytes
+	r.DiskSizeGb = m.DiskSizeGb
+	r.SourceDisk = m.SourceDisk
+	r.SourceDiskID = m.SourceDiskID
+	r.SourceType = m.SourceType
 	r.Labels = m.Labels
-	r.LicenseCodes = m.LicenseCodes
 	r.Licenses = m.Licenses
-	r.SatisfiesPzs = *m.SatisfiesPzs
-	r.SelfLink = *m.SelfLink
-	r.SourceDisk = *m.SourceDisk
-	r.SourceDiskId = *m.SourceDiskId
-	r.SourceImage = *m.SourceImage
-	r.SourceImageId = *m.SourceImageId
-	r.SourceSnapshot = *m.SourceSnapshot
-	r.SourceSnapshotId = *m.SourceSnapshotId
-	r.SourceType = *m.SourceType
+	r.GuestOsFeatures = m.GuestOsFeatures
 	r.StorageLocations = m.StorageLocations
+	r.CreationTimestamp = m.CreationTimestamp
 }
 
 // List resources in a REST collection.
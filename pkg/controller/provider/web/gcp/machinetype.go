@@ -0,0 +1,168 @@
+package gcp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"net/http"
+)
+
+// Routes
+const (
+	MachineTypeParam      = "machinetype"
+	MachineTypeCollection = "machinetypes"
+	MachineTypesRoot      = ProviderRoot + "/" + MachineTypeCollection
+	MachineTypeRoot       = MachineTypesRoot + "/:" + MachineTypeParam
+)
+
+// MachineType handler.
+type MachineTypeHandler struct {
+	Handler
+}
+
+type MachineType struct {
+	Resource
+	Description  string        `json:"description"`
+	GuestCpus    int32         `json:"guestCpus"`
+	MemoryMb     int32         `json:"memoryMb"`
+	IsSharedCpu  bool          `json:"isSharedCpu"`
+	Accelerators []Accelerator `json:"accelerators"`
+	Zones        []string      `json:"zones"`
+}
+
+// Add routes to the `gin` router.
+func (h *MachineTypeHandler) AddRoutes(e *gin.Engine) {
+	e.GET(MachineTypesRoot, h.List)
+	e.GET(MachineTypesRoot+"/", h.List)
+	e.GET(MachineTypeRoot, h.Get)
+}
+
+// Build the resource using the model.
+func (r *MachineType) With(m *model.MachineType) {
+	r.Resource.With(&m.Base)
+	r.Description = m.Description
+	r.GuestCpus = m.GuestCpus
+	r.MemoryMb = m.MemoryMb
+	r.IsSharedCpu = m.IsSharedCpu
+	r.Accelerators = m.Accelerators
+	r.Zones = m.Zones
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h MachineTypeHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.MachineType{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &MachineType{}
+		r.With(&m)
+		r.Link(h.Provider)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h MachineTypeHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	h.Detail = model.MaxDetail
+	m := &model.MachineType{
+		Base: model.Base{
+			ID: ctx.Param(MachineTypeParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &MachineType{}
+	r.With(m)
+	r.Link(h.Provider)
+	content := r.Content(h.Detail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *MachineTypeHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.MachineType{},
+		func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.MachineType)
+			machineType := &MachineType{}
+			machineType.With(m)
+			machineType.Link(h.Provider)
+			r = machineType
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
+
+// Build self link (URI).
+func (r *MachineType) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		MachineTypeRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			MachineTypeParam:   r.ID,
+		})
+}
+
+// As content.
+func (r *MachineType) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
@@ -103,6 +103,7 @@ func (h NetworkHandler) Get(ctx *gin.Context) {
 			"url",
 			ctx.Request.URL)
 		ctx.Status(http.StatusInternalServerError)
+		return
 	}
 	pb := PathBuilder{DB: db}
 	r := &Network{}
@@ -172,35 +173,27 @@ func (h *NetworkHandler) filter(ctx *gin.Context, list *[]model.Network) (err er
 // REST Resource.
 type Network struct {
 	Resource
-	Description                           string   `json:"description"`
-	Status                                string   `json:"status"`
-	RevisionNumber                        int      `json:"revisionNumber"`
-	IPv4Range                             string   `json:"ipv4Range"`
-	CreationTimestamp                     string   `json:"creationTimestamp"`
-	EnableUlaInternalIpv6                 bool     `json:"enableUlaInternalIpv6"`
-	FirewallPolicy                        string   `json:"firewallPolicy"`
-	GatewayIPv4                           string   `json:"gatewayIPv4"`
-	InternalIpv6Range                     string   `json:"internalIpv6Range"`
-	NetworkFirewallPolicyEnforcementOrder string   `json:"networkFirewallPolicyEnforcementOrder"`
-	SelfLink                              string   `json:"selfLink"`
-	SelfLinkWithId                        string   `json:"selfLinkWithId"`
-	Subnetworks                           []string `json:"subnetworks"`
+	Description           string   `json:"description"`
+	IPv4Range             string   `json:"ipv4Range,omitempty"`
+	GatewayIPv4           string   `json:"gatewayIPv4,omitempty"`
+	AutoCreateSubnetworks bool     `json:"autoCreateSubnetworks"`
+	Mtu                   int32    `json:"mtu"`
+	RoutingMode           string   `json:"routingMode"`
+	Subnetworks           []string `json:"subnetworks"`
+	CreationTimestamp     string   `json:"creationTimestamp"`
 }
 
 // Build the resource using the model.
 func (r *Network) With(m *model.Network) {
 	r.Resource.With(&m.Base)
-	r.Description = *m.Description
-	r.IPv4Range = *m.IPv4Range
-	r.CreationTimestamp = *m.CreationTimestamp
-	r.EnableUlaInternalIpv6 = *m.EnableUlaInternalIpv6
-	r.FirewallPolicy = *m.FirewallPolicy
-	r.GatewayIPv4 = *m.GatewayIPv4
-	r.InternalIpv6Range = *m.InternalIpv6Range
-	r.NetworkFirewallPolicyEnforcementOrder = *m.NetworkFirewallPolicyEnforcementOrder
-	r.SelfLink = *m.SelfLink
-	r.SelfLinkWithId = *m.SelfLinkWithId
+	r.Description = m.Description
+	r.IPv4Range = m.IPv4Range
+	r.GatewayIPv4 = m.GatewayIPv4
+	r.AutoCreateSubnetworks = m.AutoCreateSubnetworks
+	r.Mtu = m.Mtu
+	r.RoutingMode = m.RoutingMode
 	r.Subnetworks = m.Subnetworks
+	r.CreationTimestamp = m.CreationTimestamp
 }
 
 // Build self link (URI).
@@ -0,0 +1,171 @@
+package gcp
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	"github.com/onsi/gomega"
+)
+
+func provider() *api.Provider {
+	p := &api.Provider{}
+	p.UID = "provider"
+	return p
+}
+
+func TestVM(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	m := &model.VM{
+		Base:              model.Base{ID: "4711", Name: "vm", Revision: 2},
+		RevisionValidated: 2,
+		Status:            "RUNNING",
+		Zone:              "us-central1-a",
+		MachineType:       "e2-medium",
+		GuestCpus:         2,
+		MemoryMb:          4096,
+		Accelerators:      []model.Accelerator{{Type: "nvidia-l4", Count: 1}},
+		Disks: []model.AttachedDisk{
+			{Name: "boot", Boot: true, GuestOsFeatures: []string{"UEFI_COMPATIBLE"}},
+		},
+		NICs:     []model.NetworkInterface{{Name: "nic0", Network: "default"}},
+		Concerns: []model.Concern{{Category: "Warning", Label: "GPU"}},
+	}
+	r := &VM{}
+	r.With(m)
+	r.Link(provider())
+	g.Expect(r.SelfLink).To(gomega.Equal("providers/gcp/provider/vms/4711"))
+	g.Expect(r.Status).To(gomega.Equal("RUNNING"))
+	g.Expect(r.Zone).To(gomega.Equal("us-central1-a"))
+	g.Expect(r.RevisionValidated).To(gomega.Equal(int64(2)))
+	g.Expect(r.Concerns).To(gomega.Equal(m.Concerns))
+	g.Expect(r.MachineType).To(gomega.Equal("e2-medium"))
+	g.Expect(r.GuestCpus).To(gomega.Equal(int32(2)))
+	g.Expect(r.MemoryMb).To(gomega.Equal(int32(4096)))
+	g.Expect(r.Accelerators).To(gomega.Equal(m.Accelerators))
+	g.Expect(r.Disks).To(gomega.Equal(m.Disks))
+	g.Expect(r.NICs).To(gomega.Equal(m.NICs))
+	g.Expect(r.UEFI()).To(gomega.BeTrue())
+
+	// Content by detail.
+	vm0, cast := r.Content(0).(*VM0)
+	g.Expect(cast).To(gomega.BeTrue())
+	g.Expect(*vm0).To(gomega.Equal(Resource{
+		ID:       "4711",
+		Name:     "vm",
+		Revision: 2,
+		SelfLink: "providers/gcp/provider/vms/4711",
+	}))
+	vm1, cast := r.Content(1).(*VM1)
+	g.Expect(cast).To(gomega.BeTrue())
+	g.Expect(vm1.Zone).To(gomega.Equal("us-central1-a"))
+	g.Expect(vm1.Concerns).To(gomega.Equal(m.Concerns))
+	g.Expect(r.Content(2)).To(gomega.BeIdenticalTo(r))
+
+	// BIOS.
+	r.Disks[0].GuestOsFeatures = nil
+	g.Expect(r.UEFI()).To(gomega.BeFalse())
+	r.SecureBoot = true
+	g.Expect(r.UEFI()).To(gomega.BeTrue())
+}
+
+func TestWorkload(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	r := &Workload{}
+	r.ID = "4711"
+	r.Zone = "us-central1-a"
+	r.NICs = []NetworkInterface{
+		{Name: "nic0", Network: "default", Subnetwork: "default"},
+		{Name: "nic1", Network: "backend"},
+	}
+	r.Networks = []Network{
+		{Resource: Resource{ID: "1", Name: "default"}},
+		{Resource: Resource{ID: "2", Name: "backend"}},
+	}
+	r.Subnetworks = []Subnetwork{
+		{Resource: Resource{ID: "3", Name: "default"}, Network: "default"},
+	}
+	r.Link(provider())
+	g.Expect(r.SelfLink).To(gomega.Equal("providers/gcp/provider/workloads/4711"))
+	g.Expect(r.VM.SelfLink).To(gomega.Equal("providers/gcp/provider/vms/4711"))
+	g.Expect(r.Networks[0].SelfLink).To(gomega.Equal("providers/gcp/provider/networks/1"))
+	g.Expect(r.Subnetworks[0].SelfLink).To(gomega.Equal("providers/gcp/provider/subnetworks/3"))
+
+	// The subnetwork takes precedence.
+	g.Expect(r.NICNetworkIDs(&r.NICs[0])).To(gomega.Equal([]string{"3", "1"}))
+	g.Expect(r.NICNetworkIDs(&r.NICs[1])).To(gomega.Equal([]string{"2"}))
+	g.Expect(r.NICNetworkIDs(&NetworkInterface{Network: "other"})).To(gomega.BeEmpty())
+
+	g.Expect(region(r.Zone)).To(gomega.Equal("us-central1"))
+	g.Expect(region("global")).To(gomega.Equal("global"))
+}
+
+func TestDisk(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	m := &model.Disk{
+		Base:        model.Base{ID: "2", Name: "boot", Revision: 1},
+		Zone:        "us-central1-a",
+		DiskType:    "pd-balanced",
+		SizeGb:      50,
+		Status:      "READY",
+		SourceImage: "rhel-9",
+		Users:       []string{"vm"},
+		Labels:      map[string]string{"app": "db"},
+	}
+	r := &Disk{}
+	r.With(m)
+	r.Link(provider())
+	g.Expect(r.SelfLink).To(gomega.Equal("providers/gcp/provider/disks/2"))
+	g.Expect(r.Zone).To(gomega.Equal("us-central1-a"))
+	g.Expect(r.DiskType).To(gomega.Equal("pd-balanced"))
+	g.Expect(r.SizeGb).To(gomega.Equal(int64(50)))
+	g.Expect(r.SourceImage).To(gomega.Equal("rhel-9"))
+	g.Expect(r.Users).To(gomega.Equal([]string{"vm"}))
+	g.Expect(r.Labels).To(gomega.Equal(m.Labels))
+
+	g.Expect(r.Content(0)).To(gomega.Equal(r.Resource))
+	g.Expect(r.Content(1)).To(gomega.BeIdenticalTo(r))
+}
+
+func TestImage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	m := &model.Image{
+		Base:         model.Base{ID: "1", Name: "forklift-migration-vm-4711-disk-0"},
+		Status:       "READY",
+		DiskSizeGb:   20,
+		SourceDisk:   "boot",
+		SourceDiskID: "2",
+	}
+	r := &Image{}
+	r.With(m)
+	r.Link(provider())
+	g.Expect(r.SelfLink).To(gomega.Equal("providers/gcp/provider/images/1"))
+	g.Expect(r.Status).To(gomega.Equal("READY"))
+	g.Expect(r.DiskSizeGb).To(gomega.Equal(int64(20)))
+	g.Expect(r.SourceDisk).To(gomega.Equal("boot"))
+
+	g.Expect(r.Content(0)).To(gomega.Equal(r.Resource))
+	g.Expect(r.Content(1)).To(gomega.BeIdenticalTo(r))
+}
+
+func TestNetwork(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	m := &model.Network{
+		Base:                  model.Base{ID: "3", Name: "default"},
+		AutoCreateSubnetworks: true,
+		Mtu:                   1460,
+		RoutingMode:           "REGIONAL",
+		Subnetworks:           []string{"default"},
+	}
+	r := &Network{}
+	r.With(m)
+	r.Link(provider())
+	g.Expect(r.SelfLink).To(gomega.Equal("providers/gcp/provider/networks/3"))
+	g.Expect(r.AutoCreateSubnetworks).To(gomega.BeTrue())
+	g.Expect(r.Mtu).To(gomega.Equal(int32(1460)))
+	g.Expect(r.RoutingMode).To(gomega.Equal("REGIONAL"))
+	g.Expect(r.Subnetworks).To(gomega.Equal([]string{"default"}))
+
+	g.Expect(r.Content(0)).To(gomega.Equal(r.Resource))
+	g.Expect(r.Content(1)).To(gomega.BeIdenticalTo(r))
+}
@@ -0,0 +1,170 @@
+package gcp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"net/http"
+)
+
+// Routes
+const (
+	SubnetworkParam      = "subnetwork"
+	SubnetworkCollection = "subnetworks"
+	SubnetworksRoot      = ProviderRoot + "/" + SubnetworkCollection
+	SubnetworkRoot       = SubnetworksRoot + "/:" + SubnetworkParam
+)
+
+// Subnetwork handler.
+type SubnetworkHandler struct {
+	Handler
+}
+
+type Subnetwork struct {
+	Resource
+	Description    string `json:"description"`
+	Region         string `json:"region"`
+	Network        string `json:"network"`
+	IpCidrRange    string `json:"ipCidrRange"`
+	GatewayAddress string `json:"gatewayAddress"`
+	Purpose        string `json:"purpose"`
+	StackType      string `json:"stackType"`
+}
+
+// Add routes to the `gin` router.
+func (h *SubnetworkHandler) AddRoutes(e *gin.Engine) {
+	e.GET(SubnetworksRoot, h.List)
+	e.GET(SubnetworksRoot+"/", h.List)
+	e.GET(SubnetworkRoot, h.Get)
+}
+
+// Build the resource using the model.
+func (r *Subnetwork) With(m *model.Subnetwork) {
+	r.Resource.With(&m.Base)
+	r.Description = m.Description
+	r.Region = m.Region
+	r.Network = m.Network
+	r.IpCidrRange = m.IpCidrRange
+	r.GatewayAddress = m.GatewayAddress
+	r.Purpose = m.Purpose
+	r.StackType = m.StackType
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h SubnetworkHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.Subnetwork{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &Subnetwork{}
+		r.With(&m)
+		r.Link(h.Provider)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h SubnetworkHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	h.Detail = model.MaxDetail
+	m := &model.Subnetwork{
+		Base: model.Base{
+			ID: ctx.Param(SubnetworkParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &Subnetwork{}
+	r.With(m)
+	r.Link(h.Provider)
+	content := r.Content(h.Detail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *SubnetworkHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.Subnetwork{},
+		func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Subnetwork)
+			subnetwork := &Subnetwork{}
+			subnetwork.With(m)
+			subnetwork.Link(h.Provider)
+			r = subnetwork
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
+
+// Build self link (URI).
+func (r *Subnetwork) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		SubnetworkRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			SubnetworkParam:    r.ID,
+		})
+}
+
+// As content.
+func (r *Subnetwork) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
@@ -37,11 +37,9 @@ func (h *VMHandler) AddRoutes(e *gin.Engine) {
 // to a websocket and push watch events.
 func (h VMHandler) List(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
-	if err != nil {
-		return
-	}
 	if status != http.StatusOK {
 		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
 		return
 	}
 	if h.WatchRequest {
@@ -83,11 +81,9 @@ func (h VMHandler) List(ctx *gin.Context) {
 // Get a specific REST resource.
 func (h VMHandler) Get(ctx *gin.Context) {
 	status, err := h.Prepare(ctx)
-	if err != nil {
-		return
-	}
 	if status != http.StatusOK {
 		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
 		return
 	}
 	m := &model.VM{
@@ -181,20 +177,18 @@ type VM0 = Resource
 // VM detail=1
 type VM1 struct {
 	VM0
-	Status            string          `json:"status"`
-	HostID            string          `json:"hostID,omitempty"`
-	RevisionValidated int64           `json:"revisionValidated"`
-	AttachedDisk      []*AttachedDisk `json:"attachedVolumes,omitempty"`
-	Concerns          []Concern       `json:"concerns"`
+	Status            string    `json:"status"`
+	Zone              string    `json:"zone"`
+	RevisionValidated int64     `json:"revisionValidated"`
+	Concerns          []Concern `json:"concerns"`
 }
 
 // Build the resource using the model.
 func (r *VM1) With(m *model.VM) {
 	r.VM0.With(&m.Base)
-	r.Status = *m.Status
-	r.HostID = *m.Hostname
+	r.Status = m.Status
+	r.Zone = m.Zone
 	r.RevisionValidated = m.RevisionValidated
-	r.AttachedDisk = m.Disks
 	r.Concerns = m.Concerns
 }
 
@@ -210,35 +204,54 @@ func (r *VM1) Content(detail int) interface{} {
 // VM resource.
 type VM struct {
 	VM1
-	Progress       int                      `json:"progress"`
-	AccessIPv4     string                   `json:"accessIPv4,omitempty"`
-	AccessIPv6     string                   `json:"accessIPv6,omitempty"`
-	Metadata       map[string]string        `json:"metadata,omitempty"`
-	KeyName        string                   `json:"keyName,omitempty"`
-	AdminPass      string                   `json:"adminPass,omitempty"`
-	SecurityGroups []map[string]interface{} `json:"securityGroups,omitempty"`
-	Tags           *[]string                `json:"tags,omitempty"`
-	ServerGroups   *[]string                `json:"serverGroups,omitempty"`
+	Description       string             `json:"description"`
+	MachineType       string             `json:"machineType"`
+	CpuPlatform       string             `json:"cpuPlatform"`
+	GuestCpus         int32              `json:"guestCpus"`
+	MemoryMb          int32              `json:"memoryMb"`
+	Hostname          string             `json:"hostname,omitempty"`
+	Labels            map[string]string  `json:"labels,omitempty"`
+	SecureBoot        bool               `json:"secureBoot"`
+	Vtpm              bool               `json:"vtpm"`
+	Accelerators      []Accelerator      `json:"accelerators"`
+	SoleTenant        bool               `json:"soleTenant"`
+	Disks             []AttachedDisk     `json:"disks"`
+	NICs              []NetworkInterface `json:"nics"`
+	CreationTimestamp string             `json:"creationTimestamp"`
 }
 
 type AttachedDisk = model.AttachedDisk
+type NetworkInterface = model.NetworkInterface
+type Accelerator = model.Accelerator
 type Concern = model.Concern
 
-//type Fault = model.Fault
-
 // Build the resource using the model.
 func (r *VM) With(m *model.VM) {
 	r.VM1.With(m)
-	//r.Progress = m.Progress
-	//r.AccessIPv4 = m.AccessIPv4
-	//r.AccessIPv6 = m.AccessIPv6
-	//r.Metadata = m.Metadata
-	//r.KeyName = m.KeyName
-	//r.AdminPass = m.AdminPass
-	//r.SecurityGroups = m.SecurityGroups
-	//r.Fault = m.Fault
-	//r.Tags = m.Tags
-	//r.ServerGroups = m.ServerGroups
+	r.Description = m.Description
+	r.MachineType = m.MachineType
+	r.CpuPlatform = m.CpuPlatform
+	r.GuestCpus = m.GuestCpus
+	r.MemoryMb = m.MemoryMb
+	r.Hostname = m.Hostname
+	r.Labels = m.Labels
+	r.SecureBoot = m.SecureBoot
+	r.Vtpm = m.Vtpm
+	r.Accelerators = m.Accelerators
+	r.SoleTenant = m.SoleTenant
+	r.Disks = m.Disks
+	r.NICs = m.NICs
+	r.CreationTimestamp = m.CreationTimestamp
+}
+
+// Determine if the VM boots with UEFI.
+func (r *VM) UEFI() bool {
+	m := &model.VM{
+		SecureBoot: r.SecureBoot,
+		Vtpm:       r.Vtpm,
+		Disks:      r.Disks,
+	}
+	return m.UEFI()
 }
 
 // Build self link (URI).
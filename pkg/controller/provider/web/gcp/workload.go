@@ -8,6 +8,7 @@ import (
 	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
 	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
 	"net/http"
+	"strings"
 )
 
 // Routes.
@@ -94,45 +95,99 @@ func (r *Workload) Link(p *api.Provider) {
 // Expanded: VM.
 type XVM struct {
 	VM
-	Image    Image     `json:"image"`
-	Networks []Network `json:"networks"`
+	Networks    []Network    `json:"networks"`
+	Subnetworks []Subnetwork `json:"subnetworks"`
 }
 
 // Expand references.
+// The NICs reference the networks and subnetworks by name.
+// Subnetwork names are unique within a region so the
+// subnetworks are matched in the region of the VM zone.
 func (r *XVM) Expand(db libmodel.DB) (err error) {
-	var imageID string
-	var networks []Network
-	for name := range r.Networks {
+	r.Networks = []Network{}
+	r.Subnetworks = []Subnetwork{}
+	added := map[string]bool{}
+	for _, nic := range r.NICs {
+		if added[nic.Network] {
+			continue
+		}
+		added[nic.Network] = true
 		networkList := []model.Network{}
 		err = db.List(&networkList, model.ListOptions{
-			Predicate: libmodel.Eq("Name", name),
+			Predicate: libmodel.Eq("Name", nic.Network),
 			Detail:    model.MaxDetail,
 		})
 		if err != nil {
 			return
 		}
-		for _, networkModel := range networkList {
-			network := &Network{}
-			network.With(&networkModel)
-			networks = append(networks, *network)
+		for i := range networkList {
+			network := Network{}
+			network.With(&networkList[i])
+			r.Networks = append(r.Networks, network)
 		}
 	}
-	r.Networks = networks
-	if imageID != "" {
-		image := model.Image{Base: model.Base{
-			ID: imageID,
-		}}
-
-		err = db.Get(&image)
+	added = map[string]bool{}
+	for _, nic := range r.NICs {
+		if nic.Subnetwork == "" || added[nic.Subnetwork] {
+			continue
+		}
+		added[nic.Subnetwork] = true
+		subnetworkList := []model.Subnetwork{}
+		err = db.List(&subnetworkList, model.ListOptions{
+			Predicate: libmodel.And(
+				libmodel.Eq("Name", nic.Subnetwork),
+				libmodel.Eq("Network", nic.Network),
+				libmodel.Eq("Region", region(r.Zone))),
+			Detail: model.MaxDetail,
+		})
 		if err != nil {
-			// The image the VM has been based on could have been removed
-			if errors.Is(err, model.NotFound) {
-				err = nil
-				return
-			}
 			return
 		}
-		r.Image.With(&image)
+		for i := range subnetworkList {
+			subnetwork := Subnetwork{}
+			subnetwork.With(&subnetworkList[i])
+			r.Subnetworks = append(r.Subnetworks, subnetwork)
+		}
+	}
+	return
+}
+
+// The IDs of the network and the subnetwork a NIC is attached to.
+// The subnetwork comes first so that mapping a subnetwork takes
+// precedence over mapping the whole network.
+func (r *XVM) NICNetworkIDs(nic *NetworkInterface) (ids []string) {
+	for _, subnetwork := range r.Subnetworks {
+		if subnetwork.Name == nic.Subnetwork && subnetwork.Network == nic.Network {
+			ids = append(ids, subnetwork.ID)
+			break
+		}
+	}
+	for _, network := range r.Networks {
+		if network.Name == nic.Network {
+			ids = append(ids, network.ID)
+			break
+		}
 	}
 	return
 }
+
+// Build self link (URI).
+func (r *XVM) Link(p *api.Provider) {
+	r.VM.Link(p)
+	for i := range r.Networks {
+		r.Networks[i].Link(p)
+	}
+	for i := range r.Subnetworks {
+		r.Subnetworks[i].Link(p)
+	}
+}
+
+// The region of a zone.
+// Zones are named <region>-<letter>.
+func region(zone string) string {
+	i := strings.LastIndex(zone, "-")
+	if i < 0 {
+		return zone
+	}
+	return zone[:i]
+}
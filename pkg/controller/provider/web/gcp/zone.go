@@ -0,0 +1,162 @@
+package gcp
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/gcp"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	libmodel "github.com/konveyor/forklift-controller/pkg/lib/inventory/model"
+	"net/http"
+)
+
+// Routes
+const (
+	ZoneParam      = "zone"
+	ZoneCollection = "zones"
+	ZonesRoot      = ProviderRoot + "/" + ZoneCollection
+	ZoneRoot       = ZonesRoot + "/:" + ZoneParam
+)
+
+// Zone handler.
+type ZoneHandler struct {
+	Handler
+}
+
+type Zone struct {
+	Resource
+	Description string `json:"description"`
+	Region      string `json:"region"`
+	Status      string `json:"status"`
+}
+
+// Add routes to the `gin` router.
+func (h *ZoneHandler) AddRoutes(e *gin.Engine) {
+	e.GET(ZonesRoot, h.List)
+	e.GET(ZonesRoot+"/", h.List)
+	e.GET(ZoneRoot, h.Get)
+}
+
+// Build the resource using the model.
+func (r *Zone) With(m *model.Zone) {
+	r.Resource.With(&m.Base)
+	r.Description = m.Description
+	r.Region = m.Region
+	r.Status = m.Status
+}
+
+// List resources in a REST collection.
+// A GET onn the collection that includes the `X-Watch`
+// header will negotiate an upgrade of the connection
+// to a websocket and push watch events.
+func (h ZoneHandler) List(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if h.WatchRequest {
+		h.watch(ctx)
+		return
+	}
+	db := h.Collector.DB()
+	list := []model.Zone{}
+	err = db.List(&list, h.ListOptions(ctx))
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	content := []interface{}{}
+	for _, m := range list {
+		r := &Zone{}
+		r.With(&m)
+		r.Link(h.Provider)
+		content = append(content, r.Content(h.Detail))
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Get a specific REST resource.
+func (h ZoneHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	h.Detail = model.MaxDetail
+	m := &model.Zone{
+		Base: model.Base{
+			ID: ctx.Param(ZoneParam),
+		},
+	}
+	db := h.Collector.DB()
+	err = db.Get(m)
+	if errors.Is(err, model.NotFound) {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	r := &Zone{}
+	r.With(m)
+	r.Link(h.Provider)
+	content := r.Content(h.Detail)
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Watch.
+func (h *ZoneHandler) watch(ctx *gin.Context) {
+	db := h.Collector.DB()
+	err := h.Watch(
+		ctx,
+		db,
+		&model.Zone{},
+		func(in libmodel.Model) (r interface{}) {
+			m := in.(*model.Zone)
+			zone := &Zone{}
+			zone.With(m)
+			zone.Link(h.Provider)
+			r = zone
+			return
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
+
+// Build self link (URI).
+func (r *Zone) Link(p *api.Provider) {
+	r.SelfLink = base.Link(
+		ZoneRoot,
+		base.Params{
+			base.ProviderParam: string(p.UID),
+			ZoneParam:          r.ID,
+		})
+}
+
+// As content.
+func (r *Zone) Content(detail int) interface{} {
+	if detail == 0 {
+		return r.Resource
+	}
+
+	return r
+}
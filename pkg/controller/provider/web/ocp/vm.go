@@ -130,12 +130,16 @@ func (h VMHandler) watch(ctx *gin.Context) {
 // REST Resource.
 type VM struct {
 	Resource
-	Object cnv.VirtualMachine `json:"object"`
+	RevisionValidated int64              `json:"revisionValidated"`
+	Concerns          []model.Concern    `json:"concerns"`
+	Object            cnv.VirtualMachine `json:"object"`
 }
 
 // Set fields with the specified object.
 func (r *VM) With(m *model.VM) {
 	r.Resource.With(&m.Base)
+	r.RevisionValidated = m.RevisionValidated
+	r.Concerns = m.Concerns
 	r.Object = m.Object
 }
 
@@ -204,6 +204,7 @@ func (r *VM1) Content(detail int) interface{} {
 type VM struct {
 	VM1
 	OvaPath               string
+	OsType                string
 	RevisionValidated     int64
 	PolicyVersion         int
 	UUID                  string
@@ -223,6 +224,9 @@ type VM struct {
 	NumaNodeAffinity      []string
 	StorageUsed           int64
 	ChangeTrackingEnabled bool
+	IntegrityVerified     bool
+	MissingFiles          []string
+	CorruptFiles          []string
 	Devices               []model.Device
 	NICs                  []model.NIC
 	Disks                 []model.Disk
@@ -236,6 +240,9 @@ func (r *VM) With(m *model.VM) {
 	r.UUID = m.UUID
 	r.Firmware = m.Firmware
 	r.ChangeTrackingEnabled = m.ChangeTrackingEnabled
+	r.IntegrityVerified = m.IntegrityVerified
+	r.MissingFiles = m.MissingFiles
+	r.CorruptFiles = m.CorruptFiles
 	r.CpuAffinity = m.CpuAffinity
 	r.CpuHotAddEnabled = m.CpuHotAddEnabled
 	r.CpuHotRemoveEnabled = m.CpuHotRemoveEnabled
@@ -253,6 +260,7 @@ func (r *VM) With(m *model.VM) {
 	r.NumaNodeAffinity = m.NumaNodeAffinity
 	r.NICs = m.NICs
 	r.OvaPath = m.OvaPath
+	r.OsType = m.OsType
 	r.Disks = m.Disks
 	r.Networks = m.Networks
 }
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/validation/policy"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+)
+
+// Routes.
+const (
+	PolicyRoot = "/policy"
+)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// Policy packages by provider type.
+var PolicyPackages = map[api.ProviderType]string{
+	api.VSphere:   "vmware",
+	api.OVirt:     "ovirt",
+	api.OpenStack: "openstack",
+	api.Ova:       "ova",
+	api.OpenShift: "ocp",
+	api.GCP:       "gcp",
+}
+
+// Policy handler.
+// Reports the validation mode, the evaluator that served
+// the last evaluation and the rules version used for
+// each provider type.
+type PolicyHandler struct {
+}
+
+// Add routes to the `gin` router.
+func (h *PolicyHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PolicyRoot, h.Get)
+}
+
+// Get the policy.
+func (h PolicyHandler) Get(ctx *gin.Context) {
+	r := Policy{}
+	r.With(&policy.Agent)
+	ctx.JSON(http.StatusOK, r)
+}
+
+// REST Resource.
+type Policy struct {
+	// Mode (remote|embedded).
+	Mode string `json:"mode"`
+	// The evaluator that served the last evaluation
+	// (remote|embedded|embedded (fallback)).
+	// Omitted until evaluated.
+	Served string `json:"served,omitempty"`
+	// Enabled.
+	Enabled bool `json:"enabled"`
+	// Validation service URL (remote).
+	URL string `json:"url,omitempty"`
+	// Policies directory (embedded|embedded (fallback)).
+	Policies string `json:"policies,omitempty"`
+	// Rules version by provider type.
+	// Omitted when the version cannot be determined.
+	Rules map[api.ProviderType]int `json:"rules"`
+}
+
+// Build the resource using the pool.
+func (r *Policy) With(pool *policy.Pool) {
+	r.Mode = pool.Mode()
+	r.Enabled = pool.Enabled()
+	if Settings.PolicyAgent.Embedded() {
+		r.Policies = Settings.PolicyAgent.Policies
+	} else {
+		r.URL = Settings.PolicyAgent.URL
+	}
+	r.Rules = map[api.ProviderType]int{}
+	if !r.Enabled {
+		return
+	}
+	for pType, pkg := range PolicyPackages {
+		version, err := pool.Version(
+			policy.DataPath + "io/konveyor/forklift/" + pkg + "/rules_version")
+		if err != nil {
+			log.V(3).Info(
+				"Policy version not found.",
+				"type",
+				pType,
+				"reason",
+				err.Error())
+			continue
+		}
+		r.Rules[pType] = version
+	}
+	r.Served = pool.Served()
+	if r.Served == policy.EmbeddedFallback {
+		r.Policies = Settings.PolicyAgent.Policies
+	}
+}
@@ -0,0 +1,234 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/migration/report"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web/base"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Routes.
+const (
+	MigrationReportRoot = "/namespaces/:" + base.NsParam + "/migrations/:" + base.NameParam + "/report"
+	PlanReportsRoot     = "/namespaces/:" + base.NsParam + "/plans/:" + base.NameParam + "/reports"
+)
+
+// Params.
+const (
+	// Report format (json|csv).
+	FormatParam = "format"
+)
+
+// Report formats.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Report handler.
+// Serves the reports of the completed migrations
+// persisted by the migration controller.
+type ReportHandler struct {
+	// k8s API reader.
+	Reader client.Reader
+	// Mutex.
+	mutex sync.Mutex
+}
+
+// Add routes to the `gin` router.
+func (h *ReportHandler) AddRoutes(e *gin.Engine) {
+	e.GET(MigrationReportRoot, h.Get)
+	e.GET(PlanReportsRoot, h.List)
+}
+
+// Get the report of a migration.
+func (h *ReportHandler) Get(ctx *gin.Context) {
+	migration := &api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(base.NameParam),
+		},
+	}
+	status, err := h.permit(ctx, migration)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	reader, err := h.reader()
+	if err != nil {
+		h.failed(ctx, err)
+		return
+	}
+	err = reader.Get(context.TODO(), client.ObjectKeyFromObject(migration), migration)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		h.failed(ctx, err)
+		return
+	}
+	ref := migration.Status.Report
+	if ref == nil {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	configMap := &core.ConfigMap{}
+	err = reader.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		configMap)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		h.failed(ctx, err)
+		return
+	}
+	switch h.format(ctx) {
+	case FormatCSV:
+		ctx.Data(http.StatusOK, "text/csv", []byte(configMap.Data[report.CSV]))
+	default:
+		ctx.Data(http.StatusOK, "application/json", []byte(configMap.Data[report.JSON]))
+	}
+}
+
+// List the reports of the migrations of a plan.
+func (h *ReportHandler) List(ctx *gin.Context) {
+	plan := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(base.NameParam),
+		},
+	}
+	status, err := h.permit(ctx, plan)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	reader, err := h.reader()
+	if err != nil {
+		h.failed(ctx, err)
+		return
+	}
+	err = reader.Get(context.TODO(), client.ObjectKeyFromObject(plan), plan)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		h.failed(ctx, err)
+		return
+	}
+	list := &core.ConfigMapList{}
+	err = reader.List(
+		context.TODO(),
+		list,
+		client.InNamespace(plan.Namespace),
+		client.MatchingLabels{
+			report.LabelReport: "true",
+			report.LabelPlan:   string(plan.UID),
+		})
+	if err != nil {
+		h.failed(ctx, err)
+		return
+	}
+	content := report.List{}
+	for i := range list.Items {
+		r, err := report.With(&list.Items[i])
+		if err != nil {
+			log.Trace(
+				err,
+				"configMap",
+				list.Items[i].Name)
+			continue
+		}
+		content = append(content, r)
+	}
+	switch h.format(ctx) {
+	case FormatCSV:
+		csv, err := content.CSV()
+		if err != nil {
+			h.failed(ctx, err)
+			return
+		}
+		ctx.Data(http.StatusOK, "text/csv", csv)
+	default:
+		ctx.JSON(http.StatusOK, content)
+	}
+}
+
+// Requested format.
+// The `format` parameter has precedence over the Accept header.
+func (h *ReportHandler) format(ctx *gin.Context) string {
+	format := strings.ToLower(ctx.Query(FormatParam))
+	if format == "" && strings.Contains(ctx.GetHeader("Accept"), "text/csv") {
+		format = FormatCSV
+	}
+
+	return format
+}
+
+// Permit request - Authorization.
+func (h *ReportHandler) permit(ctx *gin.Context, object client.Object) (status int, err error) {
+	status = http.StatusOK
+	if base.Settings.AuthRequired {
+		return base.DefaultAuth.PermitObject(ctx, object)
+	}
+
+	return
+}
+
+// Report an internal error.
+func (h *ReportHandler) failed(ctx *gin.Context, err error) {
+	log.Trace(
+		err,
+		"url",
+		ctx.Request.URL)
+	ctx.Status(http.StatusInternalServerError)
+}
+
+// Build k8s API reader.
+func (h *ReportHandler) reader() (r client.Reader, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.Reader != nil {
+		r = h.Reader
+		return
+	}
+	cfg, err := config.GetConfig()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r, err = client.New(
+		cfg,
+		client.Options{
+			Scheme: scheme.Scheme,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	h.Reader = r
+
+	return
+}
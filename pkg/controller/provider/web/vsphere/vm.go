@@ -232,6 +232,8 @@ type VM struct {
 	NumaNodeAffinity      []string       `json:"numaNodeAffinity"`
 	Devices               []model.Device `json:"devices"`
 	NICs                  []model.NIC    `json:"nics"`
+	// Reported by the guest tools.
+	GuestNetworks []model.GuestNetwork `json:"guestNetworks"`
 }
 
 // Build the resource using the model.
@@ -259,6 +261,7 @@ func (r *VM) With(m *model.VM) {
 	r.Devices = m.Devices
 	r.NumaNodeAffinity = m.NumaNodeAffinity
 	r.NICs = m.NICs
+	r.GuestNetworks = m.GuestNetworks
 }
 
 // Build self link (URI).
@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	liburl "net/url"
 	"os"
+	"sync"
 	"time"
 
 	refapi "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
@@ -30,6 +32,17 @@ var Settings = &settings.Settings
 // Pool (singleton).
 var Agent Pool
 
+// Evaluators serving an evaluation.
+const (
+	// The validation service.
+	Remote = "remote"
+	// The in-process engine.
+	Embedded = "embedded"
+	// The in-process engine, used while
+	// the validation service is unavailable.
+	EmbeddedFallback = "embedded (fallback)"
+)
+
 // Error reported by the service.
 type ValidationError struct {
 	Errors []string
@@ -39,7 +52,18 @@ func (r *ValidationError) Error() string {
 	return fmt.Sprintf("%v", r.Errors)
 }
 
+// Policy evaluator.
+type Evaluator interface {
+	// Enabled.
+	Enabled() bool
+	// Policy version.
+	Version(path string) (version int, err error)
+	// Validate the VM.
+	Validate(path string, workload interface{}) (version int, concerns []model.Concern, err error)
+}
+
 // Client.
+// Validation delegated to the validation service.
 type Client struct {
 	LibClient
 }
@@ -286,8 +310,8 @@ func (r *Task) canceled() bool {
 // Task worker.
 type Worker struct {
 	id int
-	// Client.
-	client Client
+	// Evaluator.
+	evaluator Evaluator
 	// Input queue.
 	input chan *Task
 	// Output (result) queue.
@@ -296,7 +320,7 @@ type Worker struct {
 
 // Main worker run.
 // Process input queue. Validation delegated to the
-// policy agent.
+// evaluator.
 func (r *Worker) run() {
 	go func() {
 		log.V(1).Info(
@@ -315,7 +339,7 @@ func (r *Worker) run() {
 			task.started = time.Now()
 			workload, err := task.Workload(task.Ref.ID)
 			if err == nil {
-				task.Version, task.Concerns, task.Error = r.client.Validate(task.Path, workload)
+				task.Version, task.Concerns, task.Error = r.evaluator.Validate(task.Path, workload)
 				task.completed = time.Now()
 			} else {
 				task.Error = err
@@ -330,9 +354,93 @@ func (r *Worker) run() {
 	}()
 }
 
+// Fallback evaluator.
+// Validation delegated to the remote evaluator. The local
+// evaluator is used when the remote is unavailable.
+type Fallback struct {
+	// Remote (validation service).
+	Remote Evaluator
+	// Local (in-process engine).
+	Local Evaluator
+	// The evaluator that served the last evaluation.
+	served string
+	// Protect the served evaluator.
+	mutex sync.Mutex
+}
+
+// Enabled.
+func (r *Fallback) Enabled() bool {
+	return r.Remote.Enabled() || r.Local.Enabled()
+}
+
+// Policy version.
+func (r *Fallback) Version(path string) (version int, err error) {
+	if r.Remote.Enabled() {
+		version, err = r.Remote.Version(path)
+		if !r.unavailable(err) {
+			r.serve(Remote)
+			return
+		}
+		log.Info(
+			"Policy agent unavailable, using embedded policies.",
+			"reason",
+			err.Error())
+	}
+	version, err = r.Local.Version(path)
+	r.serve(EmbeddedFallback)
+	return
+}
+
+// Validate the VM.
+func (r *Fallback) Validate(
+	path string,
+	workload interface{}) (version int, concerns []model.Concern, err error) {
+	//
+	if r.Remote.Enabled() {
+		version, concerns, err = r.Remote.Validate(path, workload)
+		if !r.unavailable(err) {
+			r.serve(Remote)
+			return
+		}
+		log.Info(
+			"Policy agent unavailable, using embedded policies.",
+			"reason",
+			err.Error())
+	}
+	version, concerns, err = r.Local.Validate(path, workload)
+	r.serve(EmbeddedFallback)
+	return
+}
+
+// The evaluator (remote|embedded (fallback)) that
+// served the last evaluation. Empty until evaluated.
+func (r *Fallback) Served() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.served
+}
+
+// Record the evaluator that served the evaluation.
+func (r *Fallback) serve(served string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.served = served
+}
+
+// The remote evaluator could not be reached.
+func (r *Fallback) unavailable(err error) bool {
+	if err == nil || !r.Local.Enabled() {
+		return false
+	}
+	netErr := (net.Error)(nil)
+	return errors.As(err, &netErr)
+}
+
 // Policy agent task pool.
 type Pool struct {
-	Client
+	// Evaluator.
+	// Selected by the mode when not specified.
+	Evaluator Evaluator
 	// Worker input queue.
 	input chan *Task
 	// Worker output queue.
@@ -347,14 +455,17 @@ func (r *Pool) Start() {
 	if r.started {
 		return
 	}
+	if r.Evaluator == nil {
+		r.Evaluator = r.build()
+	}
 	r.output = make(chan *Task)
 	r.input = make(chan *Task)
 	for id := 0; id < r.parallel(); id++ {
 		w := Worker{
-			id:     id,
-			client: r.Client,
-			input:  r.input,
-			output: r.output,
+			id:        id,
+			evaluator: r.Evaluator,
+			input:     r.input,
+			output:    r.output,
 		}
 		w.run()
 	}
@@ -395,7 +506,62 @@ func (r *Pool) Shutdown() {
 
 // Policy version.
 func (r *Pool) Version(path string) (version int, err error) {
-	return r.Client.Version(path)
+	if r.Evaluator == nil {
+		return
+	}
+	return r.Evaluator.Version(path)
+}
+
+// The mode (remote|embedded).
+func (r *Pool) Mode() string {
+	return Settings.PolicyAgent.Mode
+}
+
+// The evaluator (remote|embedded|embedded (fallback))
+// that served the last evaluation.
+func (r *Pool) Served() (served string) {
+	switch evaluator := r.Evaluator.(type) {
+	case nil:
+	case *Fallback:
+		served = evaluator.Served()
+	case *Client:
+		served = Remote
+	default:
+		served = Embedded
+	}
+	return
+}
+
+// Enabled.
+func (r *Pool) Enabled() bool {
+	return r.Evaluator != nil && r.Evaluator.Enabled()
+}
+
+// Build the evaluator for the mode.
+// In embedded mode, the policies are loaded when the
+// pool is started so that errors are reported early. The
+// load is retried on the first evaluation when this fails.
+// In remote mode, the in-process engine is used when the
+// validation service is unavailable.
+func (r *Pool) build() (evaluator Evaluator) {
+	engine := &Engine{
+		Path: Settings.PolicyAgent.Policies,
+	}
+	if !Settings.PolicyAgent.Embedded() {
+		evaluator = &Fallback{
+			Remote: &Client{},
+			Local:  engine,
+		}
+		return
+	}
+	if engine.Enabled() {
+		err := engine.Load()
+		if err != nil {
+			log.Error(err, "Load policies failed.")
+		}
+	}
+	evaluator = engine
+	return
 }
 
 // Submit validation task.
@@ -0,0 +1,231 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	model "github.com/konveyor/forklift-controller/pkg/controller/provider/model/vsphere"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/loader"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/util"
+)
+
+// Data API path prefix.
+// Endpoints are mapped to queries. Example:
+//
+//	/v1/data/io/konveyor/forklift/ova/validate => data.io.konveyor.forklift.ova.validate
+const DataPath = "/v1/data/"
+
+// Embedded policy engine.
+// Evaluates the policies (.rego) found in the policies
+// directory in-process. The endpoints (paths) are the same
+// as used with the validation service.
+type Engine struct {
+	// Policies directory.
+	Path string
+	// Compiled policies.
+	compiler *ast.Compiler
+	// Prepared queries keyed by query.
+	prepared map[string]rego.PreparedEvalQuery
+	// Mutex.
+	mutex sync.Mutex
+}
+
+// Enabled.
+func (r *Engine) Enabled() bool {
+	return r.Path != ""
+}
+
+// Load (compile) the policies.
+// Test modules (_test.rego) are ignored.
+func (r *Engine) Load() (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	result, err := loader.NewFileLoader().Filtered(
+		[]string{r.Path},
+		func(path string, info os.FileInfo, _ int) bool {
+			if info.IsDir() {
+				return false
+			}
+			return !strings.HasSuffix(path, ".rego") ||
+				strings.HasSuffix(path, "_test.rego")
+		})
+	if err != nil {
+		err = liberr.Wrap(err, "path", r.Path)
+		return
+	}
+	compiler := ast.NewCompiler()
+	compiler.Compile(result.ParsedModules())
+	if compiler.Failed() {
+		err = liberr.Wrap(compiler.Errors, "path", r.Path)
+		return
+	}
+	r.compiler = compiler
+	r.prepared = make(map[string]rego.PreparedEvalQuery)
+
+	log.Info(
+		"Policies loaded.",
+		"path",
+		r.Path,
+		"modules",
+		len(compiler.Modules))
+
+	return
+}
+
+// Policy version.
+func (r *Engine) Version(path string) (version int, err error) {
+	if !r.Enabled() {
+		return
+	}
+	out := &struct {
+		Version int `json:"rules_version"`
+	}{}
+	err = r.eval(path, nil, out)
+	if err != nil {
+		return
+	}
+
+	version = out.Version
+
+	log.V(3).Info(
+		"Policy version detected.",
+		"endpoint",
+		path,
+		"version",
+		version)
+
+	return
+}
+
+// Validate the VM.
+func (r *Engine) Validate(
+	path string,
+	workload interface{}) (version int, concerns []model.Concern, err error) {
+	//
+	if !r.Enabled() {
+		return
+	}
+	input, err := r.input(workload)
+	if err != nil {
+		return
+	}
+	out := &struct {
+		Version  int             `json:"rules_version"`
+		Concerns []model.Concern `json:"concerns"`
+		Errors   []string        `json:"errors"`
+	}{}
+	err = r.eval(path, input, out)
+	if err != nil {
+		return
+	}
+	if len(out.Errors) > 0 {
+		err = liberr.Wrap(
+			&ValidationError{
+				Errors: out.Errors,
+			})
+		return
+	}
+
+	concerns = out.Concerns
+	version = out.Version
+
+	return
+}
+
+// Evaluate the query mapped to the path and
+// decode the result into the output object.
+func (r *Engine) eval(path string, input interface{}, out interface{}) (err error) {
+	query, err := r.prepare(path)
+	if err != nil {
+		return
+	}
+	options := []rego.EvalOption{}
+	if input != nil {
+		options = append(options, rego.EvalInput(input))
+	}
+	rs, err := query.Eval(context.TODO(), options...)
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		err = liberr.New("policy not found.", "path", path)
+		return
+	}
+	b, err := json.Marshal(rs[0].Expressions[0].Value)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = json.Unmarshal(b, out)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+// Get the prepared query mapped to the path.
+// The policies are loaded as needed.
+func (r *Engine) prepare(path string) (query rego.PreparedEvalQuery, err error) {
+	if r.compiled() == nil {
+		err = r.Load()
+		if err != nil {
+			return
+		}
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	query, found := r.prepared[path]
+	if found {
+		return
+	}
+	query, err = rego.New(
+		rego.Query(r.query(path)),
+		rego.Compiler(r.compiler)).PrepareForEval(context.TODO())
+	if err != nil {
+		err = liberr.Wrap(err, "path", path)
+		return
+	}
+	r.prepared[path] = query
+	return
+}
+
+// The compiler.
+func (r *Engine) compiled() *ast.Compiler {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.compiler
+}
+
+// Map the path to a query.
+func (r *Engine) query(path string) string {
+	path = strings.TrimPrefix(path, DataPath)
+	path = strings.Trim(path, "/")
+	return "data." + strings.ReplaceAll(path, "/", ".")
+}
+
+// Build the input.
+// The workload is converted to the same (JSON) document
+// that is posted to the validation service.
+func (r *Engine) input(workload interface{}) (input interface{}, err error) {
+	b, err := json.Marshal(workload)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = util.UnmarshalJSON(b, &input)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
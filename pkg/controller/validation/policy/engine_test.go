@@ -0,0 +1,145 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"github.com/onsi/gomega"
+)
+
+const (
+	Policies           = "../../../../validation/policies"
+	VersionEndpoint    = "/v1/data/io/konveyor/forklift/ova/rules_version"
+	ValidationEndpoint = "/v1/data/io/konveyor/forklift/ova/validate"
+)
+
+func TestEngineQuery(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	engine := &Engine{}
+	g.Expect(engine.query(ValidationEndpoint)).To(
+		gomega.Equal("data.io.konveyor.forklift.ova.validate"))
+	g.Expect(engine.query("io/konveyor/forklift/ova/rules_version/")).To(
+		gomega.Equal("data.io.konveyor.forklift.ova.rules_version"))
+}
+
+func TestEngine(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	engine := &Engine{Path: Policies}
+	g.Expect(engine.Enabled()).To(gomega.BeTrue())
+	// Version.
+	version, err := engine.Version(VersionEndpoint)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.BeNumerically(">", 0))
+	// Validate.
+	workload := struct {
+		Name     string `json:"name"`
+		Firmware string
+		OsType   string
+	}{
+		Name:     "test",
+		Firmware: "efi",
+		OsType:   "rhel8_64Guest",
+	}
+	v, concerns, err := engine.Validate(ValidationEndpoint, workload)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(v).To(gomega.Equal(version))
+	g.Expect(len(concerns)).To(gomega.Equal(1))
+	g.Expect(concerns[0].Label).To(gomega.Equal("UEFI detected"))
+	// Errors reported by the policies.
+	_, _, err = engine.Validate(ValidationEndpoint, struct{}{})
+	g.Expect(err).ToNot(gomega.BeNil())
+	// Unknown endpoint.
+	_, err = engine.Version("/v1/data/io/konveyor/forklift/unknown/rules_version")
+	g.Expect(err).ToNot(gomega.BeNil())
+}
+
+func TestEngineDisabled(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	engine := &Engine{}
+	g.Expect(engine.Enabled()).To(gomega.BeFalse())
+	version, concerns, err := engine.Validate(ValidationEndpoint, struct{}{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.Equal(0))
+	g.Expect(concerns).To(gomega.BeNil())
+}
+
+func TestFallback(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	saved := Settings.PolicyAgent
+	defer func() {
+		Settings.PolicyAgent = saved
+	}()
+	Settings.PolicyAgent.Mode = settings.PolicyModeRemote
+	Settings.PolicyAgent.TLS.CA = ""
+	workload := struct {
+		Name     string `json:"name"`
+		Firmware string
+		OsType   string
+	}{
+		Name:     "test",
+		Firmware: "efi",
+		OsType:   "rhel8_64Guest",
+	}
+	// Agent available.
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"result":{"rules_version":99,"concerns":[]}}`))
+		}))
+	Settings.PolicyAgent.URL = server.URL
+	evaluator := &Fallback{
+		Remote: &Client{},
+		Local:  &Engine{Path: Policies},
+	}
+	g.Expect(evaluator.Enabled()).To(gomega.BeTrue())
+	g.Expect(evaluator.Served()).To(gomega.BeEmpty())
+	version, err := evaluator.Version(VersionEndpoint)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.Equal(99))
+	g.Expect(evaluator.Served()).To(gomega.Equal(Remote))
+	version, concerns, err := evaluator.Validate(ValidationEndpoint, workload)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.Equal(99))
+	g.Expect(len(concerns)).To(gomega.Equal(0))
+	// Agent unavailable.
+	server.Close()
+	evaluator = &Fallback{
+		Remote: &Client{},
+		Local:  &Engine{Path: Policies},
+	}
+	version, err = evaluator.Version(VersionEndpoint)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(version).To(gomega.BeNumerically(">", 0))
+	g.Expect(version).ToNot(gomega.Equal(99))
+	g.Expect(evaluator.Served()).To(gomega.Equal(EmbeddedFallback))
+	_, concerns, err = evaluator.Validate(ValidationEndpoint, workload)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(concerns)).To(gomega.Equal(1))
+	g.Expect(concerns[0].Label).To(gomega.Equal("UEFI detected"))
+	g.Expect(evaluator.Served()).To(gomega.Equal(EmbeddedFallback))
+	// Agent unavailable without embedded policies.
+	evaluator = &Fallback{
+		Remote: &Client{},
+		Local:  &Engine{},
+	}
+	_, err = evaluator.Version(VersionEndpoint)
+	g.Expect(err).ToNot(gomega.BeNil())
+}
+
+func TestPoolServed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pool := &Pool{}
+	g.Expect(pool.Served()).To(gomega.BeEmpty())
+	pool.Evaluator = &Engine{Path: Policies}
+	g.Expect(pool.Served()).To(gomega.Equal(Embedded))
+	pool.Evaluator = &Client{}
+	g.Expect(pool.Served()).To(gomega.Equal(Remote))
+	fallback := &Fallback{
+		Remote: &Client{},
+		Local:  &Engine{Path: Policies},
+	}
+	fallback.serve(EmbeddedFallback)
+	pool.Evaluator = fallback
+	g.Expect(pool.Served()).To(gomega.Equal(EmbeddedFallback))
+}
@@ -18,3 +18,19 @@ func ServePlanCreate(resp http.ResponseWriter, req *http.Request) {
 func ServeProviderCreate(resp http.ResponseWriter, req *http.Request) {
 	validating_webhooks.Serve(resp, req, &admitters.ProviderAdmitter{})
 }
+
+func ServeNetworkMapCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.NetworkMapAdmitter{})
+}
+
+func ServeStorageMapCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.StorageMapAdmitter{})
+}
+
+func ServeHookCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.HookAdmitter{})
+}
+
+func ServeMigrationCreate(resp http.ResponseWriter, req *http.Request) {
+	validating_webhooks.Serve(resp, req, &admitters.MigrationAdmitter{})
+}
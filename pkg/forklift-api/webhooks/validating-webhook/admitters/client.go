@@ -0,0 +1,61 @@
+package admitters
+
+import (
+	"context"
+
+	"github.com/konveyor/forklift-controller/pkg/apis"
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/provider"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Build a client of the cluster the webhook runs on.
+func newClient() (cl client.Client, err error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		log.Error(err, "Couldn't get the cluster configuration")
+		return
+	}
+	err = api.SchemeBuilder.AddToScheme(scheme.Scheme)
+	if err != nil {
+		log.Error(err, "Couldn't build the scheme")
+		return
+	}
+	err = apis.AddToScheme(scheme.Scheme)
+	if err != nil {
+		log.Error(err, "Couldn't add forklift API to the scheme")
+		return
+	}
+	cl, err = client.New(config, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		log.Error(err, "Couldn't create a cluster client")
+		return
+	}
+	return
+}
+
+// Get the source and destination providers of a map.
+func getProviders(cl client.Client, pair provider.Pair) (source, destination *api.Provider, err error) {
+	source = &api.Provider{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: pair.Source.Namespace,
+			Name:      pair.Source.Name,
+		},
+		source)
+	if err != nil {
+		return
+	}
+	destination = &api.Provider{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: pair.Destination.Namespace,
+			Name:      pair.Destination.Name,
+		},
+		destination)
+	return
+}
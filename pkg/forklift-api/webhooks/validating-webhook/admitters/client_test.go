@@ -0,0 +1,123 @@
+package admitters
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Kubeconfig used by the inventory client to build the auth header.
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+  user:
+    token: test
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+`
+
+// Inventory reporting the number of (openshift) resources
+// found by name. Keyed by collection/name.
+type testInventory map[string]int
+
+func (r testInventory) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	collection := request.URL.Path[strings.LastIndex(request.URL.Path, "/")+1:]
+	q := request.URL.Query()
+	name := q.Get("name")
+	if ns := q.Get("namespace"); ns != "" {
+		name = ns + "/" + name
+	}
+	list := []map[string]string{}
+	for i := 0; i < r[collection+"/"+name]; i++ {
+		list = append(list, map[string]string{"name": name})
+	}
+	b, _ := json.Marshal(list)
+	_, _ = w.Write(b)
+}
+
+// Serve the inventory used by the admitters.
+func serveInventory(t *testing.T, g *gomega.WithT, inventory testInventory) {
+	server := httptest.NewTLSServer(inventory)
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "ca.pem")
+	err := os.WriteFile(
+		ca,
+		pem.EncodeToMemory(
+			&pem.Block{
+				Type:  "CERTIFICATE",
+				Bytes: server.Certificate().Raw,
+			}),
+		0644)
+	g.Expect(err).To(gomega.BeNil())
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	err = os.WriteFile(kubeconfig, []byte(testKubeconfig), 0644)
+	g.Expect(err).To(gomega.BeNil())
+	t.Setenv("KUBECONFIG", kubeconfig)
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	g.Expect(err).To(gomega.BeNil())
+	saved := settings.Settings.Inventory
+	t.Cleanup(func() {
+		settings.Settings.Inventory = saved
+	})
+	settings.Settings.Inventory.Host = host
+	settings.Settings.Inventory.Port, _ = strconv.Atoi(port)
+	settings.Settings.Inventory.TLS.CA = ca
+}
+
+// Openshift provider.
+func testProvider(name string) *api.Provider {
+	openshift := api.OpenShift
+	provider := &api.Provider{
+		Spec: api.ProviderSpec{
+			Type: &openshift,
+		},
+	}
+	provider.Namespace = "test"
+	provider.Name = name
+	provider.UID = types.UID("uid-" + name)
+	return provider
+}
+
+// Build an admission review of the object.
+func testReview(g *gomega.WithT, operation admissionv1.Operation, object interface{}) *admissionv1.AdmissionReview {
+	raw, err := json.Marshal(object)
+	g.Expect(err).To(gomega.BeNil())
+	return &admissionv1.AdmissionReview{
+		Request: &admissionv1.AdmissionRequest{
+			Operation: operation,
+			Object: runtime.RawExtension{
+				Raw: raw,
+			},
+		},
+	}
+}
+
+// Expect the admission response to reject with the message.
+func expectRejected(g *gomega.WithT, response *admissionv1.AdmissionResponse, message string) {
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Result.Message).To(gomega.ContainSubstring(message))
+}
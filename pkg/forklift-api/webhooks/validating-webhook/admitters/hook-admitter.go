@@ -0,0 +1,65 @@
+package admitters
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/controller/hook"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+type HookAdmitter struct {
+	hook api.Hook
+}
+
+func (admitter *HookAdmitter) validateImage() error {
+	image := admitter.hook.Spec.Image
+	if image == "" {
+		err := liberr.New("Hook image is required")
+		log.Error(err, "Hook image not set, failing")
+		return err
+	}
+	if !hook.ReferenceRegexp.MatchString(image) {
+		err := liberr.New(fmt.Sprintf("Hook image name is invalid: %s", image))
+		log.Error(err, "Hook image not valid, failing", "image", image)
+		return err
+	}
+
+	return nil
+}
+
+func (admitter *HookAdmitter) validatePlaybook() error {
+	if _, err := base64.StdEncoding.DecodeString(admitter.hook.Spec.Playbook); err != nil {
+		err = liberr.New("Hook playbook should contain a base64 encoded playbook")
+		log.Error(err, "Hook playbook not valid, failing")
+		return err
+	}
+
+	return nil
+}
+
+func (admitter *HookAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("Hook admitter was called")
+	raw := ar.Request.Object.Raw
+
+	err := json.Unmarshal(raw, &admitter.hook)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validateImage()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validatePlaybook()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	return util.ToAdmissionResponseAllow()
+}
@@ -0,0 +1,64 @@
+package admitters
+
+import (
+	"encoding/base64"
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+func TestHookAdmitter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	playbook := base64.StdEncoding.EncodeToString([]byte("- hosts: localhost"))
+	cases := []struct {
+		name     string
+		image    string
+		playbook string
+		message  string
+	}{
+		{
+			name:     "valid",
+			image:    "quay.io/konveyor/hook-runner:latest",
+			playbook: playbook,
+		},
+		{
+			name:     "image not set",
+			playbook: playbook,
+			message:  "Hook image is required",
+		},
+		{
+			name:     "image not valid",
+			image:    "quay.io/konveyor/Hook Runner",
+			playbook: playbook,
+			message:  "Hook image name is invalid",
+		},
+		{
+			name:     "playbook not encoded",
+			image:    "quay.io/konveyor/hook-runner:latest",
+			playbook: "- hosts: localhost",
+			message:  "base64 encoded playbook",
+		},
+	}
+	for _, c := range cases {
+		hook := &api.Hook{
+			Spec: api.HookSpec{
+				Image:    c.image,
+				Playbook: c.playbook,
+			},
+		}
+		admitter := &HookAdmitter{}
+		response := admitter.Admit(testReview(g, admissionv1.Create, hook))
+		if c.message == "" {
+			g.Expect(response.Allowed).To(gomega.BeTrue(), c.name)
+			continue
+		}
+		expectRejected(g, response, c.message)
+	}
+	// Not a hook.
+	admitter := &HookAdmitter{}
+	review := testReview(g, admissionv1.Create, nil)
+	review.Request.Object.Raw = []byte("{")
+	g.Expect(admitter.Admit(review).Allowed).To(gomega.BeFalse())
+}
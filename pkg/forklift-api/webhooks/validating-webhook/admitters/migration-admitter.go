@@ -0,0 +1,100 @@
+package admitters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	libref "github.com/konveyor/forklift-controller/pkg/lib/ref"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type MigrationAdmitter struct {
+	client    client.Client
+	migration api.Migration
+	plan      api.Plan
+}
+
+func (admitter *MigrationAdmitter) validateCutoverWindows() error {
+	for i := range admitter.migration.Spec.CutoverWindows {
+		err := admitter.migration.Spec.CutoverWindows[i].Validate()
+		if err != nil {
+			err = liberr.New(fmt.Sprintf("Cutover window [%d] not valid: %s", i, err.Error()))
+			log.Error(err, "Cutover window not valid, failing")
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (admitter *MigrationAdmitter) validatePlan(operation admissionv1.Operation) error {
+	// The plan is only required when the migration is created.
+	// It may since have been deleted or archived.
+	if operation != admissionv1.Create {
+		return nil
+	}
+	ref := admitter.migration.Spec.Plan
+	if !libref.RefSet(&ref) {
+		err := liberr.New("Migration plan: `namespace` and `name` required")
+		log.Error(err, "Migration plan not set, failing")
+		return err
+	}
+
+	err := admitter.client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		&admitter.plan)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = liberr.New(fmt.Sprintf("Migration plan %s/%s not found", ref.Namespace, ref.Name))
+			log.Error(err, "Migration plan not found, failing")
+			return err
+		}
+		log.Error(err, "Couldn't get the migration plan, passing unwillingly")
+		return nil
+	}
+
+	if admitter.plan.Spec.Archived {
+		err = liberr.New(fmt.Sprintf("Migration plan %s/%s is archived", ref.Namespace, ref.Name))
+		log.Error(err, "Migration plan is archived, failing")
+		return err
+	}
+
+	return nil
+}
+
+func (admitter *MigrationAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("Migration admitter was called")
+	raw := ar.Request.Object.Raw
+
+	err := json.Unmarshal(raw, &admitter.migration)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validateCutoverWindows()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	admitter.client, err = newClient()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validatePlan(ar.Request.Operation)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	return util.ToAdmissionResponseAllow()
+}
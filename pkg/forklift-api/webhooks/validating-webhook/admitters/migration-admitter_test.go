@@ -0,0 +1,119 @@
+package admitters
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func migrationAdmitter(plan core.ObjectReference, plans ...*api.Plan) *MigrationAdmitter {
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, object := range plans {
+		builder = builder.WithObjects(object)
+	}
+	return &MigrationAdmitter{
+		client: builder.Build(),
+		migration: api.Migration{
+			Spec: api.MigrationSpec{
+				Plan: plan,
+			},
+		},
+	}
+}
+
+func TestMigrationAdmitterCutoverWindows(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	admitter := migrationAdmitter(core.ObjectReference{})
+	admitter.migration.Spec.CutoverWindows = []api.CutoverWindow{
+		{Start: "22:00", End: "02:00"},
+		{Start: "25:00", End: "02:00"},
+	}
+	err := admitter.validateCutoverWindows()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Cutover window [1] not valid"))
+	// Rejected before the plan is validated.
+	response := admitter.Admit(testReview(g, admissionv1.Create, &admitter.migration))
+	expectRejected(g, response, "Cutover window [1] not valid")
+}
+
+func TestMigrationAdmitterPlan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	archived := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "archived",
+		},
+		Spec: api.PlanSpec{
+			Archived: true,
+		},
+	}
+	plan := &api.Plan{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "plan",
+		},
+	}
+	cases := []struct {
+		name      string
+		plan      core.ObjectReference
+		operation admissionv1.Operation
+		message   string
+	}{
+		{
+			name:      "valid",
+			plan:      core.ObjectReference{Namespace: "test", Name: "plan"},
+			operation: admissionv1.Create,
+		},
+		{
+			name:      "plan not set",
+			plan:      core.ObjectReference{Name: "plan"},
+			operation: admissionv1.Create,
+			message:   "`namespace` and `name` required",
+		},
+		{
+			name:      "plan not found",
+			plan:      core.ObjectReference{Namespace: "test", Name: "missing"},
+			operation: admissionv1.Create,
+			message:   "Migration plan test/missing not found",
+		},
+		{
+			name:      "plan archived",
+			plan:      core.ObjectReference{Namespace: "test", Name: "archived"},
+			operation: admissionv1.Create,
+			message:   "Migration plan test/archived is archived",
+		},
+		// The plan is only required on create.
+		{
+			name:      "updated plan not found",
+			plan:      core.ObjectReference{Namespace: "test", Name: "missing"},
+			operation: admissionv1.Update,
+		},
+		{
+			name:      "updated plan archived",
+			plan:      core.ObjectReference{Namespace: "test", Name: "archived"},
+			operation: admissionv1.Update,
+		},
+		{
+			name:      "updated plan not set",
+			operation: admissionv1.Update,
+		},
+	}
+	for _, c := range cases {
+		admitter := migrationAdmitter(c.plan, plan, archived)
+		err := admitter.validatePlan(c.operation)
+		if c.message == "" {
+			g.Expect(err).To(gomega.BeNil(), c.name)
+			continue
+		}
+		g.Expect(err).ToNot(gomega.BeNil(), c.name)
+		g.Expect(err.Error()).To(gomega.ContainSubstring(c.message), c.name)
+	}
+}
@@ -0,0 +1,194 @@
+package admitters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Network types.
+const (
+	Pod    = "pod"
+	Multus = "multus"
+)
+
+type NetworkMapAdmitter struct {
+	client              client.Client
+	networkMap          api.NetworkMap
+	sourceProvider      *api.Provider
+	destinationProvider *api.Provider
+}
+
+// Validate the map entries that do not need the inventory.
+func (admitter *NetworkMapAdmitter) validatePairs() error {
+	podMapped := 0
+	var badSubnets, incomplete []string
+	for _, pair := range admitter.networkMap.Spec.Map {
+		if pair.Subnet != "" {
+			if _, _, err := net.ParseCIDR(pair.Subnet); err != nil {
+				badSubnets = append(badSubnets, pair.Subnet)
+			}
+		}
+		switch pair.Destination.Type {
+		case Pod:
+			podMapped++
+		case Multus:
+			if pair.Destination.Namespace == "" || pair.Destination.Name == "" {
+				incomplete = append(incomplete, path.Join(pair.Destination.Namespace, pair.Destination.Name))
+			}
+		}
+	}
+	if podMapped > 1 {
+		err := liberr.New("Only one network can be mapped to the pod network")
+		log.Error(err, "Multiple networks mapped to the pod network, failing", "count", podMapped)
+		return err
+	}
+	if len(badSubnets) > 0 {
+		err := liberr.New(fmt.Sprintf("Source subnet(s) not a valid CIDR: %v", badSubnets))
+		log.Error(err, "Invalid source subnets found, failing", "subnets", badSubnets)
+		return err
+	}
+	if len(incomplete) > 0 {
+		err := liberr.New(fmt.Sprintf("Destination network(s) (NAD) require a namespace and a name: %v", incomplete))
+		log.Error(err, "Incomplete destination networks found, failing", "networks", incomplete)
+		return err
+	}
+
+	return nil
+}
+
+// Validate the source networks against the inventory.
+func (admitter *NetworkMapAdmitter) validateSource() error {
+	inventory, err := web.NewClient(admitter.sourceProvider)
+	if err != nil {
+		log.Error(err, "Couldn't create the inventory client, passing unwillingly")
+		return nil
+	}
+	var notFound, ambiguous, vlans []string
+	for i := range admitter.networkMap.Spec.Map {
+		pair := &admitter.networkMap.Spec.Map[i]
+		if pair.Selector() {
+			if pair.VLAN != "" && !admitter.sourceProvider.SupportsVLAN() {
+				vlans = append(vlans, pair.VLAN)
+			}
+			continue
+		}
+		if pair.Source.NotSet() {
+			err := liberr.New("Source network: either `ID` or `Name` required")
+			log.Error(err, "Source network not set, failing")
+			return err
+		}
+		_, err = inventory.Network(&pair.Source)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				notFound = append(notFound, pair.Source.String())
+				continue
+			}
+			if errors.As(err, &web.RefNotUniqueError{}) {
+				ambiguous = append(ambiguous, pair.Source.String())
+				continue
+			}
+			log.Error(err, "Couldn't get the source network, passing unwillingly")
+			return nil
+		}
+	}
+	if len(vlans) > 0 {
+		err := liberr.New(fmt.Sprintf("Source VLAN tag(s) not reported by the %s provider: %v", admitter.sourceProvider.Type(), vlans))
+		log.Error(err, "Source VLAN tags not supported, failing", "vlans", vlans)
+		return err
+	}
+	if len(notFound) > 0 {
+		err := liberr.New(fmt.Sprintf("Source network(s) not found: %v", notFound))
+		log.Error(err, "Source networks not found, failing", "networks", notFound)
+		return err
+	}
+	if len(ambiguous) > 0 {
+		err := liberr.New(fmt.Sprintf("Source network(s) have an ambiguous ref: %v", ambiguous))
+		log.Error(err, "Ambiguous source networks found, failing", "networks", ambiguous)
+		return err
+	}
+
+	return nil
+}
+
+// Validate the destination networks (NADs) against the destination cluster.
+func (admitter *NetworkMapAdmitter) validateDestination() error {
+	inventory, err := web.NewClient(admitter.destinationProvider)
+	if err != nil {
+		log.Error(err, "Couldn't create the inventory client, passing unwillingly")
+		return nil
+	}
+	var notFound []string
+	for _, pair := range admitter.networkMap.Spec.Map {
+		if pair.Destination.Type != Multus {
+			continue
+		}
+		id := path.Join(pair.Destination.Namespace, pair.Destination.Name)
+		_, err = inventory.Network(&ref.Ref{Name: id})
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				notFound = append(notFound, id)
+				continue
+			}
+			log.Error(err, "Couldn't get the destination network, passing unwillingly")
+			return nil
+		}
+	}
+	if len(notFound) > 0 {
+		err := liberr.New(fmt.Sprintf("Destination network(s) (NAD) not found: %v", notFound))
+		log.Error(err, "Destination networks not found, failing", "networks", notFound)
+		return err
+	}
+
+	return nil
+}
+
+func (admitter *NetworkMapAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("NetworkMap admitter was called")
+	raw := ar.Request.Object.Raw
+
+	err := json.Unmarshal(raw, &admitter.networkMap)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validatePairs()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	admitter.client, err = newClient()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	admitter.sourceProvider, admitter.destinationProvider, err = getProviders(
+		admitter.client,
+		admitter.networkMap.Spec.Provider)
+	if err != nil {
+		log.Error(err, "Couldn't get the providers, passing unwillingly")
+		return util.ToAdmissionResponseAllow()
+	}
+
+	err = admitter.validateSource()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validateDestination()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	return util.ToAdmissionResponseAllow()
+}
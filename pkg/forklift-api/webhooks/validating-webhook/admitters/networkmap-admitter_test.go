@@ -0,0 +1,130 @@
+package admitters
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+func networkMapAdmitter(pairs ...api.NetworkPair) *NetworkMapAdmitter {
+	return &NetworkMapAdmitter{
+		networkMap: api.NetworkMap{
+			Spec: api.NetworkMapSpec{
+				Map: pairs,
+			},
+		},
+		sourceProvider:      testProvider("source"),
+		destinationProvider: testProvider("destination"),
+	}
+}
+
+func TestNetworkMapAdmitterPairs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	pod := api.DestinationNetwork{Type: Pod}
+	cases := []struct {
+		name    string
+		pairs   []api.NetworkPair
+		message string
+	}{
+		{
+			name: "valid",
+			pairs: []api.NetworkPair{
+				{Source: ref.Ref{Name: "ns/a"}, Destination: pod},
+				{Source: ref.Ref{Name: "ns/b"}, Subnet: "10.0.0.0/24", Destination: api.DestinationNetwork{Type: Multus, Namespace: "ns", Name: "nad"}},
+			},
+		},
+		{
+			name: "pod mapped twice",
+			pairs: []api.NetworkPair{
+				{Source: ref.Ref{Name: "ns/a"}, Destination: pod},
+				{Source: ref.Ref{Name: "ns/b"}, Destination: pod},
+			},
+			message: "Only one network can be mapped to the pod network",
+		},
+		{
+			name: "subnet not valid",
+			pairs: []api.NetworkPair{
+				{Subnet: "10.0.0.0", Destination: pod},
+			},
+			message: "Source subnet(s) not a valid CIDR: [10.0.0.0]",
+		},
+		{
+			name: "multus incomplete",
+			pairs: []api.NetworkPair{
+				{Source: ref.Ref{Name: "ns/a"}, Destination: api.DestinationNetwork{Type: Multus, Name: "nad"}},
+			},
+			message: "Destination network(s) (NAD) require a namespace and a name: [nad]",
+		},
+	}
+	for _, c := range cases {
+		admitter := networkMapAdmitter(c.pairs...)
+		err := admitter.validatePairs()
+		if c.message == "" {
+			g.Expect(err).To(gomega.BeNil(), c.name)
+			continue
+		}
+		g.Expect(err).ToNot(gomega.BeNil(), c.name)
+		g.Expect(err.Error()).To(gomega.ContainSubstring(c.message), c.name)
+		// Rejected before the providers are fetched.
+		response := admitter.Admit(testReview(g, admissionv1.Create, &admitter.networkMap))
+		expectRejected(g, response, c.message)
+	}
+}
+
+func TestNetworkMapAdmitterInventory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	serveInventory(
+		t,
+		g,
+		testInventory{
+			"networkattachmentdefinitions/ns/a":   1,
+			"networkattachmentdefinitions/ns/b":   2,
+			"networkattachmentdefinitions/ns/nad": 1,
+		})
+	multus := func(name string) api.DestinationNetwork {
+		return api.DestinationNetwork{Type: Multus, Namespace: "ns", Name: name}
+	}
+	// Valid.
+	admitter := networkMapAdmitter(
+		api.NetworkPair{Source: ref.Ref{Name: "ns/a"}, Destination: multus("nad")},
+		api.NetworkPair{Subnet: "10.0.0.0/24", Destination: api.DestinationNetwork{Type: Pod}})
+	g.Expect(admitter.validateSource()).To(gomega.BeNil())
+	g.Expect(admitter.validateDestination()).To(gomega.BeNil())
+	// Source not set.
+	admitter = networkMapAdmitter(
+		api.NetworkPair{Destination: multus("nad")})
+	err := admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("either `ID` or `Name` required"))
+	// Source not found.
+	admitter = networkMapAdmitter(
+		api.NetworkPair{Source: ref.Ref{Name: "ns/missing"}, Destination: multus("nad")})
+	err = admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Source network(s) not found"))
+	// Source ambiguous.
+	admitter = networkMapAdmitter(
+		api.NetworkPair{Source: ref.Ref{Name: "ns/b"}, Destination: multus("nad")})
+	err = admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Source network(s) have an ambiguous ref"))
+	// VLAN tag not reported by the source provider.
+	admitter = networkMapAdmitter(
+		api.NetworkPair{VLAN: "100", Destination: multus("nad")})
+	err = admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Source VLAN tag(s) not reported by the openshift provider: [100]"))
+	// VLAN tag reported by the source provider.
+	vsphere := api.VSphere
+	admitter.sourceProvider.Spec.Type = &vsphere
+	g.Expect(admitter.validateSource()).To(gomega.BeNil())
+	// Destination not found.
+	admitter = networkMapAdmitter(
+		api.NetworkPair{Source: ref.Ref{Name: "ns/a"}, Destination: multus("missing")})
+	err = admitter.validateDestination()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Destination network(s) (NAD) not found: [ns/missing]"))
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/konveyor/forklift-controller/pkg/apis"
 	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	planadapter "github.com/konveyor/forklift-controller/pkg/controller/plan/adapter"
 	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 )
@@ -108,11 +109,23 @@ func (admitter *PlanAdmitter) validateVDDK() error {
 }
 
 func (admitter *PlanAdmitter) validateWarmMigrations() error {
+	if !admitter.plan.Spec.Warm {
+		return nil
+	}
 	providerType := admitter.sourceProvider.Type()
-	isWarmMigration := admitter.plan.Spec.Warm
-	if providerType == api.OpenStack && isWarmMigration {
-		err := liberr.New("warm migration is not supported by the provider")
-		log.Error(err, "provider", providerType)
+	adapter, err := planadapter.New(&admitter.sourceProvider)
+	if err != nil {
+		log.Error(err, "Couldn't get the provider adapter, passing unwillingly", "provider", providerType)
+		return nil
+	}
+	validator, err := adapter.Validator(&admitter.plan)
+	if err != nil {
+		log.Error(err, "Couldn't get the provider validator, passing unwillingly", "provider", providerType)
+		return nil
+	}
+	if !validator.WarmMigration() {
+		err = liberr.New("warm migration is not supported by the provider")
+		log.Error(err, "Warm migration is not supported, failing", "provider", providerType)
 		return err
 	}
 	return nil
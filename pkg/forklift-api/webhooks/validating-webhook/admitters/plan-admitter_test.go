@@ -0,0 +1,48 @@
+package admitters
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/settings"
+	"github.com/onsi/gomega"
+)
+
+func TestPlanAdmitterWarmMigration(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ovirtWarmMigration := settings.Settings.Features.OvirtWarmMigration
+	defer func() {
+		settings.Settings.Features.OvirtWarmMigration = ovirtWarmMigration
+	}()
+	settings.Settings.Features.OvirtWarmMigration = false
+
+	cases := []struct {
+		name     string
+		provider api.ProviderType
+		warm     bool
+		rejected bool
+	}{
+		{name: "cold ova", provider: api.Ova},
+		{name: "warm ova", provider: api.Ova, warm: true, rejected: true},
+		{name: "warm ovirt without the feature", provider: api.OVirt, warm: true, rejected: true},
+		{name: "warm openstack", provider: api.OpenStack, warm: true, rejected: true},
+		{name: "warm vsphere", provider: api.VSphere, warm: true},
+		{name: "warm gcp", provider: api.GCP, warm: true, rejected: true},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			providerType := testCase.provider
+			admitter := &PlanAdmitter{}
+			admitter.sourceProvider.Spec.Type = &providerType
+			admitter.plan.Spec.Warm = testCase.warm
+			admitter.plan.Referenced.Provider.Source = &admitter.sourceProvider
+			err := admitter.validateWarmMigrations()
+			if testCase.rejected {
+				g.Expect(err).ToNot(gomega.BeNil())
+				g.Expect(err.Error()).To(gomega.ContainSubstring("warm migration is not supported"))
+			} else {
+				g.Expect(err).To(gomega.BeNil())
+			}
+		})
+	}
+}
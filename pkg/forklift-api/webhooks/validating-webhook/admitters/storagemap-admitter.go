@@ -0,0 +1,147 @@
+package admitters
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/konveyor/forklift-controller/pkg/controller/provider/web"
+	"github.com/konveyor/forklift-controller/pkg/forklift-api/webhooks/util"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type StorageMapAdmitter struct {
+	client              client.Client
+	storageMap          api.StorageMap
+	sourceProvider      *api.Provider
+	destinationProvider *api.Provider
+}
+
+// Validate the map entries that do not need the inventory.
+func (admitter *StorageMapAdmitter) validatePairs() error {
+	for _, pair := range admitter.storageMap.Spec.Map {
+		if pair.Destination.StorageClass == "" {
+			err := liberr.New("Destination storage: `storageClass` required")
+			log.Error(err, "Destination storage class not set, failing", "source", pair.Source.String())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate the source storage against the inventory.
+func (admitter *StorageMapAdmitter) validateSource() error {
+	inventory, err := web.NewClient(admitter.sourceProvider)
+	if err != nil {
+		log.Error(err, "Couldn't create the inventory client, passing unwillingly")
+		return nil
+	}
+	var notFound, ambiguous []string
+	for i := range admitter.storageMap.Spec.Map {
+		pair := &admitter.storageMap.Spec.Map[i]
+		if pair.Source.NotSet() {
+			err := liberr.New("Source storage: either `ID` or `Name` required")
+			log.Error(err, "Source storage not set, failing")
+			return err
+		}
+		_, err = inventory.Storage(&pair.Source)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				notFound = append(notFound, pair.Source.String())
+				continue
+			}
+			if errors.As(err, &web.RefNotUniqueError{}) {
+				ambiguous = append(ambiguous, pair.Source.String())
+				continue
+			}
+			log.Error(err, "Couldn't get the source storage, passing unwillingly")
+			return nil
+		}
+	}
+	if len(notFound) > 0 {
+		err := liberr.New(fmt.Sprintf("Source storage not found: %v", notFound))
+		log.Error(err, "Source storage not found, failing", "storage", notFound)
+		return err
+	}
+	if len(ambiguous) > 0 {
+		err := liberr.New(fmt.Sprintf("Source storage has an ambiguous ref: %v", ambiguous))
+		log.Error(err, "Ambiguous source storage found, failing", "storage", ambiguous)
+		return err
+	}
+
+	return nil
+}
+
+// Validate the storage classes against the destination cluster.
+func (admitter *StorageMapAdmitter) validateDestination() error {
+	inventory, err := web.NewClient(admitter.destinationProvider)
+	if err != nil {
+		log.Error(err, "Couldn't create the inventory client, passing unwillingly")
+		return nil
+	}
+	var notFound []string
+	for _, pair := range admitter.storageMap.Spec.Map {
+		name := pair.Destination.StorageClass
+		_, err = inventory.Storage(&ref.Ref{Name: name})
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				notFound = append(notFound, name)
+				continue
+			}
+			log.Error(err, "Couldn't get the destination storage class, passing unwillingly")
+			return nil
+		}
+	}
+	if len(notFound) > 0 {
+		err := liberr.New(fmt.Sprintf("Destination storage class(es) not found: %v", notFound))
+		log.Error(err, "Destination storage classes not found, failing", "classes", notFound)
+		return err
+	}
+
+	return nil
+}
+
+func (admitter *StorageMapAdmitter) Admit(ar *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	log.Info("StorageMap admitter was called")
+	raw := ar.Request.Object.Raw
+
+	err := json.Unmarshal(raw, &admitter.storageMap)
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validatePairs()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	admitter.client, err = newClient()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	admitter.sourceProvider, admitter.destinationProvider, err = getProviders(
+		admitter.client,
+		admitter.storageMap.Spec.Provider)
+	if err != nil {
+		log.Error(err, "Couldn't get the providers, passing unwillingly")
+		return util.ToAdmissionResponseAllow()
+	}
+
+	err = admitter.validateSource()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	err = admitter.validateDestination()
+	if err != nil {
+		return util.ToAdmissionResponseError(err)
+	}
+
+	return util.ToAdmissionResponseAllow()
+}
@@ -0,0 +1,80 @@
+package admitters
+
+import (
+	"testing"
+
+	api "github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
+	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1beta1"
+)
+
+func storageMapAdmitter(pairs ...api.StoragePair) *StorageMapAdmitter {
+	return &StorageMapAdmitter{
+		storageMap: api.StorageMap{
+			Spec: api.StorageMapSpec{
+				Map: pairs,
+			},
+		},
+		sourceProvider:      testProvider("source"),
+		destinationProvider: testProvider("destination"),
+	}
+}
+
+func TestStorageMapAdmitterPairs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	admitter := storageMapAdmitter(
+		api.StoragePair{Source: ref.Ref{Name: "a"}, Destination: api.DestinationStorage{StorageClass: "standard"}})
+	g.Expect(admitter.validatePairs()).To(gomega.BeNil())
+	// Storage class not set.
+	admitter = storageMapAdmitter(
+		api.StoragePair{Source: ref.Ref{Name: "a"}})
+	err := admitter.validatePairs()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("`storageClass` required"))
+	// Rejected before the providers are fetched.
+	response := admitter.Admit(testReview(g, admissionv1.Create, &admitter.storageMap))
+	expectRejected(g, response, "`storageClass` required")
+}
+
+func TestStorageMapAdmitterInventory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	serveInventory(
+		t,
+		g,
+		testInventory{
+			"storageclasses/a":        1,
+			"storageclasses/b":        2,
+			"storageclasses/standard": 1,
+		})
+	standard := api.DestinationStorage{StorageClass: "standard"}
+	// Valid.
+	admitter := storageMapAdmitter(
+		api.StoragePair{Source: ref.Ref{Name: "a"}, Destination: standard})
+	g.Expect(admitter.validateSource()).To(gomega.BeNil())
+	g.Expect(admitter.validateDestination()).To(gomega.BeNil())
+	// Source not set.
+	admitter = storageMapAdmitter(
+		api.StoragePair{Destination: standard})
+	err := admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("either `ID` or `Name` required"))
+	// Source not found.
+	admitter = storageMapAdmitter(
+		api.StoragePair{Source: ref.Ref{Name: "missing"}, Destination: standard})
+	err = admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Source storage not found"))
+	// Source ambiguous.
+	admitter = storageMapAdmitter(
+		api.StoragePair{Source: ref.Ref{Name: "b"}, Destination: standard})
+	err = admitter.validateSource()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Source storage has an ambiguous ref"))
+	// Destination not found.
+	admitter = storageMapAdmitter(
+		api.StoragePair{Source: ref.Ref{Name: "a"}, Destination: api.DestinationStorage{StorageClass: "missing"}})
+	err = admitter.validateDestination()
+	g.Expect(err).ToNot(gomega.BeNil())
+	g.Expect(err.Error()).To(gomega.ContainSubstring("Destination storage class(es) not found: [missing]"))
+}
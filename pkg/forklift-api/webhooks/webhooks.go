@@ -14,6 +14,10 @@ const SecretMutatorPath = "/secret-mutate"
 const PlanValidatePath = "/plan-validate"
 const PlanMutatorPath = "/plan-mutate"
 const ProviderValidatePath = "/provider-validate"
+const NetworkMapValidatePath = "/networkmap-validate"
+const StorageMapValidatePath = "/storagemap-validate"
+const HookValidatePath = "/hook-validate"
+const MigrationValidatePath = "/migration-validate"
 
 // AddToManagerFuncs is a list of functions to add all Controllers to the Manager
 var AddToManagerFuncs []func(manager.Manager) error
@@ -39,6 +43,18 @@ func RegisterValidatingWebhooks(mux *http.ServeMux) {
 	mux.HandleFunc(ProviderValidatePath, func(w http.ResponseWriter, r *http.Request) {
 		ServeProviderCreate(w, r)
 	})
+	mux.HandleFunc(NetworkMapValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeNetworkMapCreate(w, r)
+	})
+	mux.HandleFunc(StorageMapValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeStorageMapCreate(w, r)
+	})
+	mux.HandleFunc(HookValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeHookCreate(w, r)
+	})
+	mux.HandleFunc(MigrationValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		ServeMigrationCreate(w, r)
+	})
 }
 
 func RegisterMutatingWebhooks(mux *http.ServeMux) {
@@ -23,22 +23,17 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/konveyor/forklift-controller/pkg/apis/forklift/v1beta1"
-	"github.com/konveyor/forklift-controller/pkg/controller/plan"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -88,28 +83,9 @@ type stringSet struct {
 }
 
 var (
-	populatorToResource = map[string]*populatorResource{
-		"OvirtVolumePopulator": {
-			storageResourceKey: "disk_id",
-			resource:           "ovirtvolumepopulators",
-			regexKey:           "ovirt_volume_populator",
-		},
-		"OpenstackVolumePopulator": {
-			storageResourceKey: "image_id",
-			resource:           "openstackvolumepopulators",
-			regexKey:           "openstack_volume_populator",
-		},
-	}
-
 	monitoredPVCs = map[string]interface{}{}
 )
 
-type populatorResource struct {
-	storageResourceKey string
-	resource           string
-	regexKey           string
-}
-
 type controller struct {
 	populatedFromAnno string
 	pvcFinalizer      string
@@ -132,16 +108,15 @@ type controller struct {
 	notifyMap         map[string]*stringSet
 	cleanupMap        map[string]*stringSet
 	workqueue         workqueue.RateLimitingInterface
-	populatorArgs     func(bool, *unstructured.Unstructured) ([]string, error)
-	gk                schema.GroupKind
+	populator         *Populator
 	metrics           *metricsManager
 	recorder          record.EventRecorder
 }
 
-func RunController(masterURL, kubeconfig, imageName, httpEndpoint, metricsPath, prefix string,
-	gk schema.GroupKind, gvr schema.GroupVersionResource, mountPath, devicePath string,
-	populatorArgs func(bool, *unstructured.Unstructured) ([]string, error),
-) {
+// Run the controller for the (registered) populator.
+func RunController(masterURL, kubeconfig, metricsPath, prefix, mountPath, devicePath string, populator *Populator) {
+	gk := populator.GK
+	gvr := populator.GVR
 	klog.Infof("Starting populator controller for %s", gk)
 
 	stopCh := make(chan struct{})
@@ -181,7 +156,7 @@ func RunController(masterURL, kubeconfig, imageName, httpEndpoint, metricsPath,
 	c := &controller{
 		kubeClient:        kubeClient,
 		dynamicClient:     dynClient,
-		imageName:         imageName,
+		imageName:         populator.Image,
 		devicePath:        devicePath,
 		mountPath:         mountPath,
 		populatedFromAnno: prefix + "/" + populatedFromAnnoSuffix,
@@ -199,13 +174,12 @@ func RunController(masterURL, kubeconfig, imageName, httpEndpoint, metricsPath,
 		notifyMap:         make(map[string]*stringSet),
 		cleanupMap:        make(map[string]*stringSet),
 		workqueue:         workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
-		populatorArgs:     populatorArgs,
-		gk:                gk,
+		populator:         populator,
 		metrics:           initMetrics(),
 		recorder:          getRecorder(kubeClient, prefix+"-"+controllerNameSuffix),
 	}
 
-	c.metrics.startListener(httpEndpoint, metricsPath)
+	c.metrics.startListener(populator.MetricsEndpoint, metricsPath)
 	defer c.metrics.stopListener()
 
 	pvcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -477,7 +451,7 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 	if dataSourceRef.APIGroup != nil {
 		apiGroup = *dataSourceRef.APIGroup
 	}
-	if c.gk.Group != apiGroup || c.gk.Kind != dataSourceRef.Kind || "" == dataSourceRef.Name {
+	if c.populator.GK.Group != apiGroup || c.populator.GK.Kind != dataSourceRef.Kind || "" == dataSourceRef.Name {
 		// Ignore PVCs that aren't for this populator to handle
 		return nil
 	}
@@ -498,7 +472,7 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 	}
 
 	// Set the args for the populator pod
-	args, err := c.populatorArgs(rawBlock, crInstance)
+	args, err := c.populator.Args(rawBlock, crInstance)
 	if err != nil {
 		return err
 	}
@@ -580,6 +554,11 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 		// Record start time for populator metric
 		c.metrics.operationStart(pvc.UID)
 
+		// The population has failed and the retries are exhausted.
+		if Failure(crInstance) != "" {
+			return nil
+		}
+
 		// If the pod doesn't exist yet, create it
 		if pod == nil {
 			transferNetwork, found, err := unstructured.NestedStringMap(crInstance.Object, "spec", "transferNetwork")
@@ -672,13 +651,13 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 			// We'll get called again later when the pod exists
 			return nil
 		} else {
-			if pod.Status.PodIP != "" {
+			if pod.Status.PodIP != "" && c.populator.Metric != "" {
 				if _, ok := monitoredPVCs[string(pvc.UID)]; !ok {
 					monitoredPVCs[string(pvc.UID)] = true
 					go func() {
 						c.recorder.Eventf(pod, corev1.EventTypeWarning, reasonPopulatorProgress, "Starting to monitor progress for PVC %s", pvc.Name)
 						for {
-							c.updateProgress(pvc, pod.Status.PodIP, crInstance)
+							_ = c.updateProgress(pvc, pod.Status.PodIP, crInstance)
 							pod, err = c.podLister.Pods(populatorNamespace).Get(pod.Name)
 							if err != nil {
 								break
@@ -697,14 +676,7 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 
 		if corev1.PodSucceeded != pod.Status.Phase {
 			if corev1.PodFailed == pod.Status.Phase {
-				c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPodFailed, "Populator failed: %s", pod.Status.Message)
-				// Delete failed pods so we can try again
-				if !plan.RestartLimitExceeded(pod) {
-					err = c.kubeClient.CoreV1().Pods(populatorNamespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
-					if err != nil {
-						return err
-					}
-				}
+				return c.retry(ctx, key, pvc, pod, crInstance)
 			}
 			// We'll get called again later when the pod succeeds
 			return nil
@@ -800,17 +772,15 @@ func (c *controller) syncPvc(ctx context.Context, key, pvcNamespace, pvcName str
 }
 
 func (c *controller) updateProgress(pvc *corev1.PersistentVolumeClaim, podIP string, cr *unstructured.Unstructured) error {
-	populatorKind := pvc.Spec.DataSourceRef.Kind
-	var diskRegex = regexp.MustCompile(fmt.Sprintf(`volume_populators_%s\{%s=\"([0-9a-fA-F]{8}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{4}\b-[0-9a-fA-F]{12})"\} (\d{1,3}.*)`,
-		populatorToResource[populatorKind].regexKey,
-		populatorToResource[populatorKind].storageResourceKey))
-	url := fmt.Sprintf("http://%s:2112/metrics", podIP)
+	populatorKind := c.populator.GK.Kind
+	diskRegex := c.populator.progressRegex()
+	url := fmt.Sprintf("http://%s:%d/metrics", podIP, c.populator.MetricsPort)
 	resp, err := http.Get(url)
 	if err != nil {
 		if strings.Contains(err.Error(), "connection refused") {
 			return nil
 		}
-		c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to get url: %s, err: %w", url, err)
+		c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to get url: %s, err: %s", url, err)
 		return nil
 	}
 
@@ -818,7 +788,7 @@ func (c *controller) updateProgress(pvc *corev1.PersistentVolumeClaim, podIP str
 	body, err := io.ReadAll(resp.Body)
 
 	if err != nil {
-		c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to read response, error: %w", url, err)
+		c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to read response: %s, error: %s", url, err)
 	}
 
 	matches := diskRegex.FindAllStringSubmatch(string(body), -1)
@@ -831,44 +801,25 @@ func (c *controller) updateProgress(pvc *corev1.PersistentVolumeClaim, podIP str
 		imageID := match[1]
 		progress, err := strconv.ParseFloat(string(match[2]), 64)
 		if err != nil {
-			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Could not convert progress: %w", err)
+			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Could not convert progress: %s", err)
 			break
 		}
 
-		gvr := schema.GroupVersionResource{
-			Group:    *pvc.Spec.DataSourceRef.APIGroup,
-			Version:  "v1beta1",
-			Resource: populatorToResource[populatorKind].resource,
-		}
-
-		latestPopulator, err := c.dynamicClient.Resource(gvr).Namespace(pvc.Namespace).Get(context.TODO(), cr.GetName(), metav1.GetOptions{})
+		latestPopulator, err := c.dynamicClient.Resource(c.populator.GVR).Namespace(pvc.Namespace).Get(context.TODO(), cr.GetName(), metav1.GetOptions{})
 		if err != nil {
-			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to get CR for kind: %s error: %w", populatorKind, err)
+			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to get CR for kind: %s error: %s", populatorKind, err)
 			break
 		}
 
-		var updatedPopulator *unstructured.Unstructured
-		switch populatorKind {
-		case v1beta1.OpenstackVolumePopulatorKind:
-			updatedPopulator, err = createUpdatedOpenstackPopulatorProgress(int64(progress), latestPopulator)
-		case v1beta1.OvirtVolumePopulatorKind:
-			updatedPopulator, err = updateOvirtPopulatorProgress(int64(progress), latestPopulator)
-		default:
-			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Unsupported populator kind: %s", populatorKind)
-		}
+		err = c.populator.setProgress(latestPopulator, int64(progress))
 		if err != nil {
-			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to updated CR for kind: %s error: %w", populatorKind, err)
+			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to updated CR for kind: %s error: %s", populatorKind, err)
 			break
 		}
 
-		_, err = c.dynamicClient.Resource(gvr).Namespace(pvc.Namespace).Update(context.TODO(), updatedPopulator, metav1.UpdateOptions{})
+		_, err = c.dynamicClient.Resource(c.populator.GVR).Namespace(pvc.Namespace).Update(context.TODO(), latestPopulator, metav1.UpdateOptions{})
 		if err != nil {
-			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to update CR: %w", err)
-			break
-		}
-
-		if err != nil {
-			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to update CR: %w", err)
+			c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPopulatorProgress, "Failed to update CR: %s", err)
 			break
 		}
 
@@ -877,28 +828,84 @@ func (c *controller) updateProgress(pvc *corev1.PersistentVolumeClaim, podIP str
 	return nil
 }
 
-func createUpdatedOpenstackPopulatorProgress(progress int64, cr *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	openstackPopulator := &v1beta1.OpenstackVolumePopulator{}
-	runtime.DefaultUnstructuredConverter.FromUnstructured(cr.UnstructuredContent(), openstackPopulator)
-	openstackPopulator.Status.Transferred = fmt.Sprintf("%d", progress)
-	unstructuredPopulator, err := runtime.DefaultUnstructuredConverter.ToUnstructured(openstackPopulator)
+// Retry the failed population.
+// The failed pod is deleted (so that it is recreated) once the
+// backoff has expired. When the retries are exhausted, the failure
+// is recorded on the CR and the pod is kept for troubleshooting.
+func (c *controller) retry(ctx context.Context, key string, pvc *corev1.PersistentVolumeClaim, pod *corev1.Pod, cr *unstructured.Unstructured) error {
+	message := terminationMessage(pod)
+	retries := c.populator.retries(cr)
+	if retries >= c.populator.Retries {
+		c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPodFailed, "Populator failed after %d retries: %s", retries, message)
+		c.metrics.recordMetrics(pvc.UID, "failure")
+		return c.updateCR(ctx, cr, func(latest *unstructured.Unstructured) error {
+			return c.populator.setFailure(latest, message)
+		})
+	}
+	delay := c.populator.backoff(retries) - time.Since(finishedAt(pod))
+	if delay > 0 {
+		c.workqueue.AddAfter(key, delay)
+		return nil
+	}
+	c.recorder.Eventf(pvc, corev1.EventTypeWarning, reasonPodFailed, "Populator failed (retry %d of %d): %s", retries+1, c.populator.Retries, message)
+	err := c.updateCR(ctx, cr, func(latest *unstructured.Unstructured) error {
+		return c.populator.setRetries(latest, retries+1)
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	return &unstructured.Unstructured{Object: unstructuredPopulator}, nil
+	// Delete failed pods so we can try again
+	err = c.kubeClient.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	delete(monitoredPVCs, string(pvc.UID))
+	return nil
 }
 
-func updateOvirtPopulatorProgress(progress int64, cr *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-	ovirtPopulator := &v1beta1.OvirtVolumePopulator{}
-	runtime.DefaultUnstructuredConverter.FromUnstructured(cr.UnstructuredContent(), ovirtPopulator)
-	ovirtPopulator.Status.Progress = fmt.Sprintf("%d", progress)
-	unstructuredPopulator, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ovirtPopulator)
+// Update the latest CR.
+func (c *controller) updateCR(ctx context.Context, cr *unstructured.Unstructured, update func(*unstructured.Unstructured) error) error {
+	client := c.dynamicClient.Resource(c.populator.GVR).Namespace(cr.GetNamespace())
+	latest, err := client.Get(ctx, cr.GetName(), metav1.GetOptions{})
 	if err != nil {
-		return nil, err
+		return err
+	}
+	err = update(latest)
+	if err != nil {
+		return err
 	}
+	_, err = client.Update(ctx, latest, metav1.UpdateOptions{})
+	return err
+}
 
-	return &unstructured.Unstructured{Object: unstructuredPopulator}, nil
+// The reason the pod failed.
+func terminationMessage(pod *corev1.Pod) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			continue
+		}
+		if terminated.Message != "" {
+			return strings.TrimSpace(terminated.Message)
+		}
+		if terminated.Reason != "" {
+			return fmt.Sprintf("%s (exit code %d)", terminated.Reason, terminated.ExitCode)
+		}
+	}
+	if pod.Status.Message != "" {
+		return pod.Status.Message
+	}
+	return fmt.Sprintf("populator pod %s/%s failed", pod.Namespace, pod.Name)
+}
+
+// When the pod finished.
+func finishedAt(pod *corev1.Pod) (finished time.Time) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(finished) {
+			finished = terminated.FinishedAt.Time
+		}
+	}
+	return
 }
 
 func makePopulatePodSpec(pvcPrimeName, secretName string) corev1.PodSpec {
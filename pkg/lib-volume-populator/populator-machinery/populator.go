@@ -0,0 +1,205 @@
+package populator_machinery
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Defaults.
+const (
+	// Port of the metrics endpoint exposed by the populator pod.
+	DefaultMetricsPort = 2112
+	// Number of times a failed population is retried.
+	DefaultRetries = 3
+	// Initial delay before a failed population is retried.
+	// Doubled on each retry.
+	DefaultBackoff = 10 * time.Second
+	// Max delay before a failed population is retried.
+	MaxBackoff = 5 * time.Minute
+)
+
+// Status paths.
+// Fixed so that the populator controller and the forklift controller
+// agree on them, every populator CR has these status fields.
+var (
+	// Number of times the population has been retried.
+	RetriesPath = []string{"status", "retries"}
+	// The reason the population failed.
+	FailurePath = []string{"status", "failure"}
+)
+
+// Populator pod args builder.
+// Called with whether the volume is a raw block device and the CR.
+type ArgsFunc func(rawBlock bool, cr *unstructured.Unstructured) ([]string, error)
+
+// Populator registration.
+// Describes a volume populator so that the machinery can
+// watch its CR, run its pod and report its progress without
+// any knowledge of the populator kind.
+type Populator struct {
+	// CR group kind.
+	GK schema.GroupKind
+	// CR group version resource.
+	GVR schema.GroupVersionResource
+	// Populator image.
+	Image string
+	// Populator pod args builder.
+	Args ArgsFunc
+	// Controller metrics endpoint. Example: ":8080".
+	MetricsEndpoint string
+	// Name of the (gauge) metric reporting the progress
+	// exposed by the populator pod. Example: volume_populators_ovirt_volume_populator.
+	Metric string
+	// Label of the metric identifying the storage resource. Example: disk_id.
+	MetricLabel string
+	// Pattern matching the label value.
+	MetricLabelRegex string
+	// Port of the metrics endpoint exposed by the populator pod.
+	MetricsPort int
+	// Path of the CR field the progress is written to. Example: ["status", "progress"].
+	ProgressPath []string
+	// Number of times a failed population is retried.
+	Retries int
+	// Initial delay before a failed population is retried.
+	Backoff time.Duration
+}
+
+// Validate and apply defaults.
+func (r *Populator) validate() (err error) {
+	if r.GK.Kind == "" || r.GVR.Resource == "" {
+		err = fmt.Errorf("populator: group kind and resource must be specified")
+		return
+	}
+	if r.Args == nil {
+		err = fmt.Errorf("populator %s: args builder must be specified", r.GK)
+		return
+	}
+	if r.Metric != "" {
+		if len(r.ProgressPath) == 0 {
+			err = fmt.Errorf("populator %s: progress path must be specified with the metric", r.GK)
+			return
+		}
+		if r.MetricLabel == "" || r.MetricLabelRegex == "" {
+			err = fmt.Errorf("populator %s: metric label must be specified with the metric", r.GK)
+			return
+		}
+		_, err = regexp.Compile(r.MetricLabelRegex)
+		if err != nil {
+			err = fmt.Errorf("populator %s: %w", r.GK, err)
+			return
+		}
+	}
+	if r.MetricsPort == 0 {
+		r.MetricsPort = DefaultMetricsPort
+	}
+	if r.Retries == 0 {
+		r.Retries = DefaultRetries
+	}
+	if r.Backoff == 0 {
+		r.Backoff = DefaultBackoff
+	}
+	return
+}
+
+// Pattern matching the progress metric.
+// The first group is the label value and the second is the progress.
+func (r *Populator) progressRegex() *regexp.Regexp {
+	return regexp.MustCompile(
+		fmt.Sprintf(
+			`%s\{%s="(%s)"\} (\d{1,3}.*)`,
+			regexp.QuoteMeta(r.Metric),
+			regexp.QuoteMeta(r.MetricLabel),
+			r.MetricLabelRegex))
+}
+
+// Delay before the retry.
+func (r *Populator) backoff(retries int) (delay time.Duration) {
+	delay = r.Backoff
+	for i := 0; i < retries && delay < MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxBackoff {
+		delay = MaxBackoff
+	}
+	return
+}
+
+// Registered populators keyed by kind.
+var registry = struct {
+	sync.Mutex
+	populators map[string]*Populator
+}{
+	populators: map[string]*Populator{},
+}
+
+// Register a populator.
+func Register(populator *Populator) (err error) {
+	err = populator.validate()
+	if err != nil {
+		return
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, found := registry.populators[populator.GK.Kind]; found {
+		err = fmt.Errorf("populator %s: already registered", populator.GK)
+		return
+	}
+	registry.populators[populator.GK.Kind] = populator
+	return
+}
+
+// Registered populators.
+func Registered() (list []*Populator) {
+	registry.Lock()
+	defer registry.Unlock()
+	for _, populator := range registry.populators {
+		list = append(list, populator)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].GK.Kind < list[j].GK.Kind
+	})
+	return
+}
+
+// The failure recorded on the CR.
+func Failure(cr *unstructured.Unstructured) (failure string) {
+	failure, _, _ = unstructured.NestedString(cr.Object, FailurePath...)
+	return
+}
+
+// Clear the failure and the retries recorded on the CR
+// so that the population is retried.
+func ResetFailure(cr *unstructured.Unstructured) {
+	unstructured.RemoveNestedField(cr.Object, FailurePath...)
+	unstructured.RemoveNestedField(cr.Object, RetriesPath...)
+}
+
+// Set the progress on the CR.
+func (r *Populator) setProgress(cr *unstructured.Unstructured, progress int64) error {
+	return unstructured.SetNestedField(cr.Object, fmt.Sprintf("%d", progress), r.ProgressPath...)
+}
+
+// The number of retries recorded on the CR.
+func (r *Populator) retries(cr *unstructured.Unstructured) (n int) {
+	retries, found, err := unstructured.NestedInt64(cr.Object, RetriesPath...)
+	if err == nil && found {
+		n = int(retries)
+	}
+	return
+}
+
+// Set the number of retries on the CR.
+func (r *Populator) setRetries(cr *unstructured.Unstructured, n int) error {
+	return unstructured.SetNestedField(cr.Object, int64(n), RetriesPath...)
+}
+
+// Set the failure on the CR.
+func (r *Populator) setFailure(cr *unstructured.Unstructured, failure string) error {
+	return unstructured.SetNestedField(cr.Object, failure, FailurePath...)
+}
@@ -0,0 +1,177 @@
+package populator_machinery
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func testPopulator() *Populator {
+	return &Populator{
+		GK:  schema.GroupKind{Group: "forklift.konveyor.io", Kind: "TestVolumePopulator"},
+		GVR: schema.GroupVersionResource{Group: "forklift.konveyor.io", Version: "v1beta1", Resource: "testvolumepopulators"},
+		Args: func(bool, *unstructured.Unstructured) ([]string, error) {
+			return nil, nil
+		},
+		Metric:           "volume_populators_test_volume_populator",
+		MetricLabel:      "disk_id",
+		MetricLabelRegex: `[0-9a-f-]+`,
+		ProgressPath:     []string{"status", "progress"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	populator := testPopulator()
+	if err := populator.validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if populator.MetricsPort != DefaultMetricsPort ||
+		populator.Retries != DefaultRetries ||
+		populator.Backoff != DefaultBackoff {
+		t.Errorf("defaults not applied: %+v", populator)
+	}
+	populator = testPopulator()
+	populator.ProgressPath = nil
+	if err := populator.validate(); err == nil {
+		t.Error("expected error: progress path not specified")
+	}
+	populator = testPopulator()
+	populator.Args = nil
+	if err := populator.validate(); err == nil {
+		t.Error("expected error: args builder not specified")
+	}
+	populator = testPopulator()
+	populator.MetricLabelRegex = "("
+	if err := populator.validate(); err == nil {
+		t.Error("expected error: invalid regex")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	populator := testPopulator()
+	if err := Register(populator); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() {
+		registry.Lock()
+		delete(registry.populators, populator.GK.Kind)
+		registry.Unlock()
+	}()
+	if err := Register(testPopulator()); err == nil {
+		t.Error("expected error: already registered")
+	}
+	found := false
+	for _, registered := range Registered() {
+		if registered == populator {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("populator not registered")
+	}
+}
+
+func TestProgressRegex(t *testing.T) {
+	populator := testPopulator()
+	body := "# TYPE volume_populators_test_volume_populator gauge\n" +
+		"volume_populators_test_volume_populator{disk_id=\"0a1b-2c3d\"} 42\n" +
+		"volume_populators_other_volume_populator{disk_id=\"0a1b-2c3d\"} 17\n"
+	matches := populator.progressRegex().FindAllStringSubmatch(body, -1)
+	if len(matches) != 1 || matches[0][1] != "0a1b-2c3d" || matches[0][2] != "42" {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+}
+
+func TestProgressMultiDisk(t *testing.T) {
+	populator := &Populator{
+		GK:  schema.GroupKind{Group: "forklift.konveyor.io", Kind: "GcpVolumePopulator"},
+		GVR: schema.GroupVersionResource{Group: "forklift.konveyor.io", Version: "v1beta1", Resource: "gcpvolumepopulators"},
+		Args: func(bool, *unstructured.Unstructured) ([]string, error) {
+			return nil, nil
+		},
+		Metric:           "volume_populators_gcp_volume_populator",
+		MetricLabel:      "image_name",
+		MetricLabelRegex: `[a-z](?:[-a-z0-9]*[a-z0-9])?`,
+		ProgressPath:     []string{"status", "transferred"},
+	}
+	if err := populator.validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := "# TYPE volume_populators_gcp_volume_populator gauge\n" +
+		"volume_populators_gcp_volume_populator{image_name=\"forklift-migration-vm-4711-disk-0\"} 1.073741824e+09\n" +
+		"volume_populators_gcp_volume_populator{image_name=\"forklift-migration-vm-4711-disk-1\"} 524288\n"
+	matches := populator.progressRegex().FindAllStringSubmatch(body, -1)
+	if len(matches) != 2 ||
+		matches[0][1] != "forklift-migration-vm-4711-disk-0" || matches[0][2] != "1.073741824e+09" ||
+		matches[1][1] != "forklift-migration-vm-4711-disk-1" || matches[1][2] != "524288" {
+		t.Fatalf("unexpected matches: %v", matches)
+	}
+	// Each disk has its own CR.
+	for i, progress := range []int64{1073741824, 524288} {
+		cr := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		if err := populator.setProgress(cr, progress); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		transferred, _, _ := unstructured.NestedString(cr.Object, "status", "transferred")
+		if transferred != strconv.FormatInt(progress, 10) {
+			t.Errorf("disk: %d, expected transferred: %d, actual: %s", i, progress, transferred)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	populator := testPopulator()
+	_ = populator.validate()
+	expected := []time.Duration{
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+	}
+	for retries, delay := range expected {
+		if actual := populator.backoff(retries); actual != delay {
+			t.Errorf("retries: %d, expected: %s, actual: %s", retries, delay, actual)
+		}
+	}
+	if actual := populator.backoff(100); actual != MaxBackoff {
+		t.Errorf("expected: %s, actual: %s", MaxBackoff, actual)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	populator := testPopulator()
+	_ = populator.validate()
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	if populator.retries(cr) != 0 || Failure(cr) != "" {
+		t.Fatalf("unexpected status: %v", cr.Object)
+	}
+	if err := populator.setProgress(cr, 42); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := populator.setRetries(cr, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := populator.setFailure(cr, "disk not found"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	progress, _, _ := unstructured.NestedString(cr.Object, "status", "progress")
+	if progress != "42" {
+		t.Errorf("expected progress: 42, actual: %s", progress)
+	}
+	if populator.retries(cr) != 2 {
+		t.Errorf("expected retries: 2, actual: %d", populator.retries(cr))
+	}
+	if Failure(cr) != "disk not found" {
+		t.Errorf("unexpected failure: %s", Failure(cr))
+	}
+	ResetFailure(cr)
+	if populator.retries(cr) != 0 || Failure(cr) != "" {
+		t.Errorf("failure not reset: %v", cr.Object)
+	}
+	progress, _, _ = unstructured.NestedString(cr.Object, "status", "progress")
+	if progress != "42" {
+		t.Errorf("expected progress: 42, actual: %s", progress)
+	}
+}
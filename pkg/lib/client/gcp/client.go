@@ -1,42 +1,60 @@
 package gcp
 
 import (
-	cloudbuild "cloud.google.com/go/cloudbuild/apiv1/v2"
-	"cloud.google.com/go/cloudbuild/apiv1/v2/cloudbuildpb"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
-	"github.com/konveyor/forklift-controller/pkg/lib/logging"
-	"google.golang.org/api/iterator"
-	"google.golang.org/protobuf/proto"
 	"io"
-	core "k8s.io/api/core/v1"
-	"log"
-	"os"
+	"strings"
 	"time"
 
+	cloudbuild "cloud.google.com/go/cloudbuild/apiv1/v2"
+	"cloud.google.com/go/cloudbuild/apiv1/v2/cloudbuildpb"
 	compute "cloud.google.com/go/compute/apiv1"
 	"cloud.google.com/go/compute/apiv1/computepb"
 	"cloud.google.com/go/storage"
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	"github.com/konveyor/forklift-controller/pkg/lib/logging"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	core "k8s.io/api/core/v1"
+)
+
+// The image export builder and how long an export may run.
+const (
+	ExportBuilder = "gcr.io/compute-image-tools/gce_vm_image_export:release"
+	ExportTimeout = 2 * time.Hour
 )
 
+// Client struct
 type Client struct {
-	URL               string
-	Options           map[string]string
-	GoogleAuthPath    string
-	ProjectID         string
-	Zone              string
-	BucketName        string
-	Log               logging.LevelLogger
-	ctx               context.Context
-	computeService    *compute.InstancesClient
-	imageService      *compute.ImagesClient
-	networkService    *compute.NetworksClient
-	diskService       *compute.DisksClient
-	storageService    *storage.Client
-	cloudBuildService *cloudbuild.Client
+	URL                string
+	Options            map[string]string
+	GoogleAuthPath     string
+	ProjectID          string
+	BucketName         string
+	Log                logging.LevelLogger
+	ctx                context.Context
+	instanceService    *compute.InstancesClient
+	imageService       *compute.ImagesClient
+	networkService     *compute.NetworksClient
+	diskService        *compute.DisksClient
+	snapshotService    *compute.SnapshotsClient
+	diskTypeService    *compute.DiskTypesClient
+	machineTypeService *compute.MachineTypesClient
+	zoneService        *compute.ZonesClient
+	subnetworkService  *compute.SubnetworksClient
+	storageService     *storage.Client
+	cloudBuildService  *cloudbuild.Client
 }
 
+// Load the options from the provider secret.
 func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
 	c.Options = make(map[string]string)
 	for key, value := range secret.Data {
@@ -44,105 +62,304 @@ func (c *Client) LoadOptionsFromSecret(secret *core.Secret) {
 	}
 }
 
-func (c *Client) Authenticate() (err error) {
-	err = os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", c.GoogleAuthPath)
+// Connect.
+// Resolves the project and the staging bucket and
+// validates the credentials by building the compute client.
+func (c *Client) Connect() (err error) {
+	if c.ctx == nil {
+		c.ctx = context.Background()
+	}
+	err = c.Resolve()
 	if err != nil {
 		return
 	}
+	err = c.connectInstanceServiceAPI()
 	return
 }
 
-// Connect
-func (c *Client) Connect() (err error) {
-	err = c.Authenticate()
+// Resolve the project and the staging bucket
+// from the options without connecting.
+func (c *Client) Resolve() (err error) {
+	if c.ProjectID == "" {
+		c.ProjectID = c.Options[Project]
+	}
+	if c.ProjectID == "" {
+		c.ProjectID, err = c.credentialsProject()
+		if err != nil {
+			return
+		}
+	}
+	if c.ProjectID == "" {
+		err = liberr.New("project ID not found in the secret or the service account key.")
+		return
+	}
+	if c.BucketName == "" {
+		c.BucketName = c.Options[Bucket]
+	}
+	if c.BucketName == "" {
+		c.BucketName = fmt.Sprintf("%s-forklift", c.ProjectID)
+	}
+	return
+}
+
+// Close the service connections.
+func (c *Client) Close() {
+	closers := []io.Closer{}
+	if c.instanceService != nil {
+		closers = append(closers, c.instanceService)
+	}
+	if c.imageService != nil {
+		closers = append(closers, c.imageService)
+	}
+	if c.networkService != nil {
+		closers = append(closers, c.networkService)
+	}
+	if c.diskService != nil {
+		closers = append(closers, c.diskService)
+	}
+	if c.snapshotService != nil {
+		closers = append(closers, c.snapshotService)
+	}
+	if c.diskTypeService != nil {
+		closers = append(closers, c.diskTypeService)
+	}
+	if c.machineTypeService != nil {
+		closers = append(closers, c.machineTypeService)
+	}
+	if c.zoneService != nil {
+		closers = append(closers, c.zoneService)
+	}
+	if c.subnetworkService != nil {
+		closers = append(closers, c.subnetworkService)
+	}
+	if c.storageService != nil {
+		closers = append(closers, c.storageService)
+	}
+	if c.cloudBuildService != nil {
+		closers = append(closers, c.cloudBuildService)
+	}
+	for _, closer := range closers {
+		_ = closer.Close()
+	}
+	c.instanceService = nil
+	c.imageService = nil
+	c.networkService = nil
+	c.diskService = nil
+	c.snapshotService = nil
+	c.diskTypeService = nil
+	c.machineTypeService = nil
+	c.zoneService = nil
+	c.subnetworkService = nil
+	c.storageService = nil
+	c.cloudBuildService = nil
+}
+
+// Check if the error is a not found error.
+func (c *Client) IsNotFound(err error) bool {
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return true
+	}
+	gErr := &googleapi.Error{}
+	if errors.As(err, &gErr) {
+		return gErr.Code == 404
+	}
+	return status.Code(errors.Unwrap(err)) == codes.NotFound || status.Code(err) == codes.NotFound
+}
+
+// Client options built from the service account key.
+// The key from the secret takes precedence over the key file.
+func (c *Client) clientOptions() (options []option.ClientOption, err error) {
+	if key, found := c.Options[ServiceAccount]; found && key != "" {
+		options = append(options, option.WithCredentialsJSON([]byte(key)))
+		return
+	}
+	if c.GoogleAuthPath != "" {
+		options = append(options, option.WithCredentialsFile(c.GoogleAuthPath))
+		return
+	}
+	err = liberr.New("service account key not found.")
+	return
+}
+
+// The project the service account belongs to.
+func (c *Client) credentialsProject() (project string, err error) {
+	key, found := c.Options[ServiceAccount]
+	if !found {
+		return
+	}
+	credentials := struct {
+		ProjectID string `json:"project_id"`
+	}{}
+	err = json.Unmarshal([]byte(key), &credentials)
 	if err != nil {
+		err = liberr.Wrap(err, "the service account key is not valid JSON.")
 		return
 	}
-	//userProjects := &[]Project{}
-	//err = c.GetUserProjects(userProjects)
+	project = credentials.ProjectID
 	return
 }
 
-func (c *Client) connectComputeServiceAPI() (err error) {
-	err = c.Authenticate()
+func (c *Client) connectInstanceServiceAPI() (err error) {
+	if c.instanceService != nil {
+		return
+	}
+	options, err := c.clientOptions()
 	if err != nil {
 		return
 	}
-	if c.computeService == nil {
-		instancesClient, err := compute.NewInstancesRESTClient(c.ctx)
-		if err != nil {
-			c.computeService = instancesClient
-		}
+	c.instanceService, err = compute.NewInstancesRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
 	}
 	return
 }
 
 func (c *Client) connectImageServiceAPI() (err error) {
-	err = c.Authenticate()
+	if c.imageService != nil {
+		return
+	}
+	options, err := c.clientOptions()
 	if err != nil {
 		return
 	}
-	if c.imageService == nil {
-		imagesClient, err := compute.NewImagesRESTClient(c.ctx)
-		if err != nil {
-			c.imageService = imagesClient
-		}
+	c.imageService, err = compute.NewImagesRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
 	}
 	return
 }
 
-func (c *Client) connectStorageServiceAPI() (err error) {
-	err = c.Authenticate()
+func (c *Client) connectNetworkServiceAPI() (err error) {
+	if c.networkService != nil {
+		return
+	}
+	options, err := c.clientOptions()
 	if err != nil {
 		return
 	}
-	if c.storageService == nil {
-		storageClient, err := storage.NewClient(c.ctx)
-		if err != nil {
-			c.storageService = storageClient
-		}
+	c.networkService, err = compute.NewNetworksRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
 	}
 	return
 }
 
-func (c *Client) connectNetworkServiceAPI() (err error) {
-	err = c.Authenticate()
+func (c *Client) connectDiskServiceAPI() (err error) {
+	if c.diskService != nil {
+		return
+	}
+	options, err := c.clientOptions()
 	if err != nil {
 		return
 	}
-	if c.networkService == nil {
-		networksClient, err := compute.NewNetworksRESTClient(c.ctx)
-		if err != nil {
-			c.networkService = networksClient
-		}
+	c.diskService, err = compute.NewDisksRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
 	}
 	return
 }
 
-func (c *Client) connectDiskServiceAPI() (err error) {
-	err = c.Authenticate()
+func (c *Client) connectSnapshotServiceAPI() (err error) {
+	if c.snapshotService != nil {
+		return
+	}
+	options, err := c.clientOptions()
 	if err != nil {
 		return
 	}
-	if c.diskService == nil {
-		disksClient, err := compute.NewDisksRESTClient(c.ctx)
-		if err != nil {
-			c.diskService = disksClient
-		}
+	c.snapshotService, err = compute.NewSnapshotsRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) connectDiskTypeServiceAPI() (err error) {
+	if c.diskTypeService != nil {
+		return
+	}
+	options, err := c.clientOptions()
+	if err != nil {
+		return
+	}
+	c.diskTypeService, err = compute.NewDiskTypesRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) connectMachineTypeServiceAPI() (err error) {
+	if c.machineTypeService != nil {
+		return
+	}
+	options, err := c.clientOptions()
+	if err != nil {
+		return
+	}
+	c.machineTypeService, err = compute.NewMachineTypesRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) connectZoneServiceAPI() (err error) {
+	if c.zoneService != nil {
+		return
+	}
+	options, err := c.clientOptions()
+	if err != nil {
+		return
+	}
+	c.zoneService, err = compute.NewZonesRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) connectSubnetworkServiceAPI() (err error) {
+	if c.subnetworkService != nil {
+		return
+	}
+	options, err := c.clientOptions()
+	if err != nil {
+		return
+	}
+	c.subnetworkService, err = compute.NewSubnetworksRESTClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+func (c *Client) connectStorageServiceAPI() (err error) {
+	if c.storageService != nil {
+		return
+	}
+	options, err := c.clientOptions()
+	if err != nil {
+		return
+	}
+	c.storageService, err = storage.NewClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
 	}
 	return
 }
 
 func (c *Client) connectCloudBuildServiceAPI() (err error) {
-	err = c.Authenticate()
+	if c.cloudBuildService != nil {
+		return
+	}
+	options, err := c.clientOptions()
 	if err != nil {
 		return
 	}
-	if c.cloudBuildService == nil {
-		cloudBuildClient, err := cloudbuild.NewClient(c.ctx)
-		if err != nil {
-			c.cloudBuildService = cloudBuildClient
-		}
+	c.cloudBuildService, err = cloudbuild.NewClient(c.ctx, options...)
+	if err != nil {
+		err = liberr.Wrap(err)
 	}
 	return
 }
@@ -150,84 +367,87 @@ func (c *Client) connectCloudBuildServiceAPI() (err error) {
 // List a resource.
 func (c *Client) List(object interface{}, opts interface{}) (err error) {
 	switch object.(type) {
-	case *[]computepb.Instance:
-		err = c.computeServiceAPI(object, opts)
-	case *[]computepb.Image:
+	case *[]*computepb.Instance:
+		err = c.instanceServiceAPI(object, opts)
+	case *[]*computepb.Image:
 		err = c.imageServiceAPI(object, opts)
-	case *[]computepb.Network:
+	case *[]*computepb.Network:
 		err = c.networkServiceAPI(object, opts)
+	case *[]*computepb.DiskType:
+		err = c.diskTypeServiceAPI(object, opts)
+	case *[]*computepb.Disk:
+		err = c.diskServiceAPI(object, opts)
+	case *[]*computepb.Zone:
+		err = c.zoneServiceAPI(object, opts)
+	case *[]*computepb.Subnetwork:
+		err = c.subnetworkServiceAPI(object, opts)
+	case *[]*computepb.MachineType:
+		err = c.machineTypeServiceAPI(object, opts)
 	default:
 		err = c.unsupportedTypeError(object)
 	}
-	if err != nil {
-		return
-	}
 	return
 }
 
 // Get a resource.
-func (c *Client) Get(object interface{}, ID string) (err error) {
+// Zonal resources (instances, disks, machine types) need the zone.
+func (c *Client) Get(object interface{}, ID string, zone ...string) (err error) {
+	opts := &GetOpts{ID: ID}
+	if len(zone) > 0 {
+		opts.Zone = zone[0]
+	}
 	switch object.(type) {
 	case *computepb.Instance:
-		err = c.computeServiceAPI(object, &GetOpts{ID: ID})
+		err = c.instanceServiceAPI(object, opts)
 	case *computepb.Image:
-		err = c.imageServiceAPI(object, &GetOpts{ID: ID})
+		err = c.imageServiceAPI(object, opts)
 	case *computepb.Network:
-		err = c.networkServiceAPI(object, &GetOpts{ID: ID})
+		err = c.networkServiceAPI(object, opts)
+	case *computepb.Disk:
+		err = c.diskServiceAPI(object, opts)
+	case *computepb.Snapshot:
+		err = c.snapshotServiceAPI(object, opts)
+	case *computepb.MachineType:
+		err = c.machineTypeServiceAPI(object, opts)
 	default:
 		err = c.unsupportedTypeError(object)
 	}
 	if err != nil {
-		err = liberr.Wrap(err, "trying to get object", object, "ID", ID)
+		err = liberr.Wrap(err, "trying to get object", "object", fmt.Sprintf("%T", object), "ID", ID)
 	}
 	return
 }
 
-func (c *Client) computeServiceAPI(object interface{}, opts interface{}) (err error) {
-	err = c.connectComputeServiceAPI()
-	if err != nil {
-		err = liberr.Wrap(err)
-		return
-	}
-	switch object.(type) {
-	case *[]computepb.Instance, *computepb.Instance:
-		err = c.vmAPI(object, opts)
-	default:
-		err = c.unsupportedTypeError(object)
-	}
+func (c *Client) instanceServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectInstanceServiceAPI()
 	if err != nil {
 		return
 	}
-	return
-}
-
-func (c *Client) vmAPI(object interface{}, opts interface{}) (err error) {
 	switch object.(type) {
-	case *[]computepb.Instance:
-		object := object.(*[]computepb.Instance)
+	case *[]*computepb.Instance:
+		object := object.(*[]*computepb.Instance)
 		switch opts.(type) {
 		case *VMListOpts:
-			//opts := opts.(*VMListOpts)
 			err = c.vmList(object)
-		}
-		if err != nil {
-			return
+		default:
+			err = c.unsupportedTypeError(opts)
 		}
 	case *computepb.Instance:
 		object := object.(*computepb.Instance)
 		switch opts.(type) {
 		case *GetOpts:
 			opts := opts.(*GetOpts)
-			req := &computepb.GetInstanceRequest{
+			var instance *computepb.Instance
+			instance, err = c.instanceService.Get(c.ctx, &computepb.GetInstanceRequest{
 				Project:  c.ProjectID,
+				Zone:     opts.Zone,
 				Instance: opts.ID,
-				Zone:     c.Zone,
-			}
-			instance, err := c.computeService.Get(c.ctx, req)
+			})
 			if err != nil {
-				return err
+				return
 			}
-			*object = *instance
+			proto.Reset(object)
+			proto.Merge(object, instance)
 		default:
 			err = c.unsupportedTypeError(opts)
 		}
@@ -237,29 +457,21 @@ func (c *Client) vmAPI(object interface{}, opts interface{}) (err error) {
 	return
 }
 
-func (c *Client) vmList(object *[]computepb.Instance) (err error) {
-	err = c.connectComputeServiceAPI()
-	if err != nil {
-		return
-	}
-	req := &computepb.AggregatedListInstancesRequest{
-		Project:    c.ProjectID,
-		MaxResults: proto.Uint32(3),
-	}
-	it := c.computeService.AggregatedList(c.ctx, req)
+func (c *Client) vmList(object *[]*computepb.Instance) (err error) {
+	it := c.instanceService.AggregatedList(c.ctx, &computepb.AggregatedListInstancesRequest{
+		Project: c.ProjectID,
+	})
 	for {
-		pair, err := it.Next()
-		if err == iterator.Done {
+		pair, nErr := it.Next()
+		if nErr == iterator.Done {
 			break
 		}
-		if err != nil {
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
 			return
 		}
-		instances := pair.Value.Instances
-		if len(instances) > 0 {
-			for _, instance := range instances {
-				*object = append(*object, *instance)
-			}
+		for _, instance := range pair.Value.Instances {
+			*object = append(*object, instance)
 		}
 	}
 	return
@@ -271,21 +483,8 @@ func (c *Client) imageServiceAPI(object interface{}, opts interface{}) (err erro
 		return
 	}
 	switch object.(type) {
-	case *computepb.Image, *[]computepb.Image:
-		err = c.imageAPI(object, opts)
-	default:
-		err = c.unsupportedTypeError(object)
-	}
-	if err != nil {
-		err = liberr.Wrap(err)
-	}
-	return
-}
-
-func (c *Client) imageAPI(object interface{}, opts interface{}) (err error) {
-	switch object.(type) {
-	case *[]computepb.Image:
-		object := object.(*[]computepb.Image)
+	case *[]*computepb.Image:
+		object := object.(*[]*computepb.Image)
 		switch opts.(type) {
 		case *ImageListOpts:
 			err = c.imageList(object)
@@ -297,54 +496,44 @@ func (c *Client) imageAPI(object interface{}, opts interface{}) (err error) {
 		switch opts.(type) {
 		case *GetOpts:
 			opts := opts.(*GetOpts)
-			req := &computepb.GetImageRequest{
+			var image *computepb.Image
+			image, err = c.imageService.Get(c.ctx, &computepb.GetImageRequest{
 				Project: c.ProjectID,
 				Image:   opts.ID,
-			}
-			image, err := c.imageService.Get(c.ctx, req)
+			})
 			if err != nil {
 				return
 			}
-			*object = *image
+			proto.Reset(object)
+			proto.Merge(object, image)
 		case *DeleteOpts:
-			req := &computepb.DeleteImageRequest{
+			_, err = c.imageService.Delete(c.ctx, &computepb.DeleteImageRequest{
 				Project: c.ProjectID,
 				Image:   object.GetName(),
-			}
-			_, err = c.imageService.Delete(c.ctx, req)
-			if err != nil {
-				return
-			}
+			})
 		default:
 			err = c.unsupportedTypeError(opts)
 		}
 	default:
 		err = c.unsupportedTypeError(object)
 	}
-	if err != nil {
-		return
-	}
 	return
 }
 
-func (c *Client) imageList(object *[]computepb.Image) (err error) {
-	err = c.connectComputeServiceAPI()
-	if err != nil {
-		return
-	}
-	req := &computepb.ListImagesRequest{
+func (c *Client) imageList(object *[]*computepb.Image) (err error) {
+	it := c.imageService.List(c.ctx, &computepb.ListImagesRequest{
 		Project: c.ProjectID,
-	}
-	it := c.imageService.List(c.ctx, req)
+	})
 	for {
-		image, err := it.Next()
-		if err == iterator.Done {
+		image, nErr := it.Next()
+		if nErr == iterator.Done {
 			break
 		}
-		if err != nil {
-			return err
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
 		}
-		*object = append(*object, *image)
+		*object = append(*object, image)
 	}
 	return
 }
@@ -355,21 +544,8 @@ func (c *Client) networkServiceAPI(object interface{}, opts interface{}) (err er
 		return
 	}
 	switch object.(type) {
-	case *computepb.Network, *[]computepb.Network:
-		err = c.networkAPI(object, opts)
-	default:
-		err = c.unsupportedTypeError(object)
-	}
-	if err != nil {
-		err = liberr.Wrap(err)
-	}
-	return
-}
-
-func (c *Client) networkAPI(object interface{}, opts interface{}) (err error) {
-	switch object.(type) {
-	case *[]computepb.Network:
-		object := object.(*[]computepb.Network)
+	case *[]*computepb.Network:
+		object := object.(*[]*computepb.Network)
 		switch opts.(type) {
 		case *NetworkListOpts:
 			err = c.networkList(object)
@@ -381,315 +557,674 @@ func (c *Client) networkAPI(object interface{}, opts interface{}) (err error) {
 		switch opts.(type) {
 		case *GetOpts:
 			opts := opts.(*GetOpts)
-			req := &computepb.GetNetworkRequest{
+			var network *computepb.Network
+			network, err = c.networkService.Get(c.ctx, &computepb.GetNetworkRequest{
 				Project: c.ProjectID,
 				Network: opts.ID,
-			}
-			network, err := c.networkService.Get(c.ctx, req)
+			})
 			if err != nil {
 				return
 			}
-			*object = *network
+			proto.Reset(object)
+			proto.Merge(object, network)
 		default:
 			err = c.unsupportedTypeError(opts)
 		}
 	default:
 		err = c.unsupportedTypeError(object)
 	}
+	return
+}
+
+func (c *Client) networkList(object *[]*computepb.Network) (err error) {
+	it := c.networkService.List(c.ctx, &computepb.ListNetworksRequest{
+		Project: c.ProjectID,
+	})
+	for {
+		network, nErr := it.Next()
+		if nErr == iterator.Done {
+			break
+		}
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		*object = append(*object, network)
+	}
+	return
+}
+
+func (c *Client) snapshotServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectSnapshotServiceAPI()
 	if err != nil {
 		return
 	}
+	switch object.(type) {
+	case *computepb.Snapshot:
+		object := object.(*computepb.Snapshot)
+		switch opts.(type) {
+		case *GetOpts:
+			opts := opts.(*GetOpts)
+			var snapshot *computepb.Snapshot
+			snapshot, err = c.snapshotService.Get(c.ctx, &computepb.GetSnapshotRequest{
+				Project:  c.ProjectID,
+				Snapshot: opts.ID,
+			})
+			if err != nil {
+				return
+			}
+			proto.Reset(object)
+			proto.Merge(object, snapshot)
+		case *DeleteOpts:
+			_, err = c.snapshotService.Delete(c.ctx, &computepb.DeleteSnapshotRequest{
+				Project:  c.ProjectID,
+				Snapshot: object.GetName(),
+			})
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
+	}
 	return
 }
 
-func (c *Client) networkList(object *[]computepb.Network) (err error) {
-	err = c.connectNetworkServiceAPI()
+func (c *Client) diskServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectDiskServiceAPI()
 	if err != nil {
 		return
 	}
-	req := &computepb.ListNetworksRequest{
-		Project: c.ProjectID,
+	switch object.(type) {
+	case *[]*computepb.Disk:
+		object := object.(*[]*computepb.Disk)
+		switch opts.(type) {
+		case *DiskListOpts:
+			err = c.diskList(object)
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	case *computepb.Disk:
+		object := object.(*computepb.Disk)
+		switch opts.(type) {
+		case *GetOpts:
+			opts := opts.(*GetOpts)
+			var disk *computepb.Disk
+			disk, err = c.diskService.Get(c.ctx, &computepb.GetDiskRequest{
+				Project: c.ProjectID,
+				Zone:    opts.Zone,
+				Disk:    opts.ID,
+			})
+			if err != nil {
+				return
+			}
+			proto.Reset(object)
+			proto.Merge(object, disk)
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
 	}
-	it := c.networkService.List(c.ctx, req)
+	return
+}
+
+// List the zonal persistent disks.
+func (c *Client) diskList(object *[]*computepb.Disk) (err error) {
+	it := c.diskService.AggregatedList(c.ctx, &computepb.AggregatedListDisksRequest{
+		Project: c.ProjectID,
+	})
 	for {
-		network, err := it.Next()
-		if err == iterator.Done {
+		pair, nErr := it.Next()
+		if nErr == iterator.Done {
 			break
 		}
-		if err != nil {
-			return err
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		for _, disk := range pair.Value.Disks {
+			*object = append(*object, disk)
 		}
-		*object = append(*object, *network)
 	}
 	return
 }
 
-//func (c *Client) subnetList(object *[]computepb.Subnetwork) (err error) {
-//	err = c.connectNetworkServiceAPI()
-//	if err != nil {
-//		return
-//	}
-//	req := &computepb.ListSubnetworksRequest{
-//		Project: c.ProjectID,
-//	}
-//	it := c.networkService.List(c.ctx, req)
-//	for {
-//		subnetwork, err := it.Next()
-//		if err == iterator.Done {
-//			break
-//		}
-//		if err != nil {
-//			return err
-//		}
-//		*object = append(*object, *subnetwork)
-//	}
-//	return
-//}
-
-func (c *Client) createBucket(bucketName string) error {
-	// projectID := "my-project-id"
-	// bucketName := "bucket-name"
-	err := c.connectStorageServiceAPI()
+func (c *Client) diskTypeServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectDiskTypeServiceAPI()
 	if err != nil {
-		return err
+		return
 	}
-	bucket := c.storageService.Bucket(bucketName)
-	if err := bucket.Create(c.ctx, c.ProjectID, nil); err != nil {
-		return fmt.Errorf("Bucket(%q).Create: %w", bucketName, err)
+	switch object.(type) {
+	case *[]*computepb.DiskType:
+		object := object.(*[]*computepb.DiskType)
+		switch opts.(type) {
+		case *DiskTypeListOpts:
+			err = c.diskTypeList(object)
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
 	}
-	c.BucketName = bucketName
-	return nil
+	return
 }
 
-func (c *Client) checkObjectExists(bucketName string, objectName string) (bool, error) {
-	err := c.connectStorageServiceAPI()
-	if err != nil {
-		return false, err
+// List the disk types.
+// Disk types are zonal, the list is reduced to one
+// entry per name since the mapping is by name.
+func (c *Client) diskTypeList(object *[]*computepb.DiskType) (err error) {
+	it := c.diskTypeService.AggregatedList(c.ctx, &computepb.AggregatedListDiskTypesRequest{
+		Project: c.ProjectID,
+	})
+	seen := map[string]bool{}
+	for {
+		pair, nErr := it.Next()
+		if nErr == iterator.Done {
+			break
+		}
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		for _, diskType := range pair.Value.DiskTypes {
+			if seen[diskType.GetName()] {
+				continue
+			}
+			seen[diskType.GetName()] = true
+			*object = append(*object, diskType)
+		}
 	}
-	bucket := c.storageService.Bucket(bucketName)
-	object := bucket.Object(objectName)
-	_, err = object.Attrs(c.ctx)
+	return
+}
+
+func (c *Client) machineTypeServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectMachineTypeServiceAPI()
 	if err != nil {
-		return false, err
+		return
+	}
+	switch object.(type) {
+	case *[]*computepb.MachineType:
+		object := object.(*[]*computepb.MachineType)
+		switch opts.(type) {
+		case *MachineTypeListOpts:
+			err = c.machineTypeList(object, opts.(*MachineTypeListOpts))
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	case *computepb.MachineType:
+		object := object.(*computepb.MachineType)
+		switch opts.(type) {
+		case *GetOpts:
+			opts := opts.(*GetOpts)
+			var machineType *computepb.MachineType
+			machineType, err = c.machineTypeService.Get(c.ctx, &computepb.GetMachineTypeRequest{
+				Project:     c.ProjectID,
+				Zone:        opts.Zone,
+				MachineType: opts.ID,
+			})
+			if err != nil {
+				return
+			}
+			proto.Reset(object)
+			proto.Merge(object, machineType)
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
 	}
-	return true, nil
+	return
 }
 
-func (c *Client) VMExportImageToBucket(imageName, imageType, objectName string) error {
-	err := c.connectCloudBuildServiceAPI()
+// List the machine types offered in a zone.
+func (c *Client) machineTypeList(object *[]*computepb.MachineType, opts *MachineTypeListOpts) (err error) {
+	it := c.machineTypeService.List(c.ctx, &computepb.ListMachineTypesRequest{
+		Project: c.ProjectID,
+		Zone:    opts.Zone,
+	})
+	for {
+		machineType, nErr := it.Next()
+		if nErr == iterator.Done {
+			break
+		}
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		*object = append(*object, machineType)
+	}
+	return
+}
+
+func (c *Client) zoneServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectZoneServiceAPI()
 	if err != nil {
-		return err
+		return
 	}
-	if c.BucketName == "" {
-		c.BucketName = fmt.Sprintf("forklift-%s", time.Now().Format("20060102150405"))
-		err = c.createBucket(c.BucketName)
-		if err != nil {
-			return err
+	switch object.(type) {
+	case *[]*computepb.Zone:
+		object := object.(*[]*computepb.Zone)
+		switch opts.(type) {
+		case *ZoneListOpts:
+			err = c.zoneList(object)
+		default:
+			err = c.unsupportedTypeError(opts)
 		}
-	} else {
-		exists, err := c.checkObjectExists(c.BucketName, objectName)
-		if err != nil {
-			return err
+	default:
+		err = c.unsupportedTypeError(object)
+	}
+	return
+}
+
+func (c *Client) zoneList(object *[]*computepb.Zone) (err error) {
+	it := c.zoneService.List(c.ctx, &computepb.ListZonesRequest{
+		Project: c.ProjectID,
+	})
+	for {
+		zone, nErr := it.Next()
+		if nErr == iterator.Done {
+			break
 		}
-		if exists {
-			return nil
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
 		}
+		*object = append(*object, zone)
 	}
+	return
+}
 
-	// Create a CloudBuild build object.
-	destinationURI := fmt.Sprintf("gs://%s/%s.%s", c.BucketName, objectName, imageType)
-	build := &cloudbuildpb.Build{
-		Steps: []*cloudbuildpb.BuildStep{
-			{
-				Name: "gcr.io/compute-image-tools/gce_vm_image_export:release",
-				Args: []string{
-					"--timeout=7000s",
-					"--source_image=" + imageName,
-					"--client_id=api",
-					"--format=qcow2",
-					"--destination_uri=" + destinationURI,
-				},
-			},
-		},
+func (c *Client) subnetworkServiceAPI(object interface{}, opts interface{}) (err error) {
+	err = c.connectSubnetworkServiceAPI()
+	if err != nil {
+		return
 	}
-	req := &cloudbuildpb.CreateBuildRequest{
-		ProjectId: c.ProjectID,
-		Build:     build,
+	switch object.(type) {
+	case *[]*computepb.Subnetwork:
+		object := object.(*[]*computepb.Subnetwork)
+		switch opts.(type) {
+		case *SubnetworkListOpts:
+			err = c.subnetworkList(object)
+		default:
+			err = c.unsupportedTypeError(opts)
+		}
+	default:
+		err = c.unsupportedTypeError(object)
 	}
-	// Submit the build request.
-	op, err := c.cloudBuildService.CreateBuild(c.ctx, req)
-	if err != nil {
-		log.Fatalf("Error creating Cloud Build: %v", err)
-		return err
+	return
+}
+
+// List the subnetworks of all the regions.
+func (c *Client) subnetworkList(object *[]*computepb.Subnetwork) (err error) {
+	it := c.subnetworkService.AggregatedList(c.ctx, &computepb.AggregatedListSubnetworksRequest{
+		Project: c.ProjectID,
+	})
+	for {
+		pair, nErr := it.Next()
+		if nErr == iterator.Done {
+			break
+		}
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		for _, subnetwork := range pair.Value.Subnetworks {
+			*object = append(*object, subnetwork)
+		}
 	}
+	return
+}
 
-	// Wait for the build operation to complete.
-	_, err = op.Wait(c.ctx)
+// Return the instance status.
+func (c *Client) VMStatus(zone, instance string) (status string, err error) {
+	vm := &computepb.Instance{}
+	err = c.Get(vm, instance, zone)
 	if err != nil {
-		log.Fatalf("Error waiting for build operation to complete: %v", err)
-		return err
+		return
 	}
-	return nil
+	status = vm.GetStatus()
+	return
 }
 
-func (c *Client) VMGetInstanceBootDisk(instanceName string) (disk *computepb.AttachedDisk, err error) {
-	err = c.connectComputeServiceAPI()
+// Start the instance.
+// The operation is not awaited, the caller is expected to poll the status.
+func (c *Client) VMStart(zone, instance string) (err error) {
+	err = c.connectInstanceServiceAPI()
 	if err != nil {
 		return
 	}
-	req := &computepb.GetInstanceRequest{
+	_, err = c.instanceService.Start(c.ctx, &computepb.StartInstanceRequest{
 		Project:  c.ProjectID,
-		Zone:     c.Zone,
-		Instance: instanceName,
+		Zone:     zone,
+		Instance: instance,
+	})
+	if err != nil {
+		err = liberr.Wrap(err, "instance", instance)
 	}
-	instance, err := c.computeService.Get(c.ctx, req)
+	return
+}
+
+// Stop the instance.
+// The operation is not awaited, the caller is expected to poll the status.
+func (c *Client) VMStop(zone, instance string) (err error) {
+	err = c.connectInstanceServiceAPI()
 	if err != nil {
 		return
 	}
-	for _, disk := range instance.Disks {
-		if disk.GetBoot() {
-			return disk, nil
-		}
+	_, err = c.instanceService.Stop(c.ctx, &computepb.StopInstanceRequest{
+		Project:  c.ProjectID,
+		Zone:     zone,
+		Instance: instance,
+	})
+	if err != nil {
+		err = liberr.Wrap(err, "instance", instance)
 	}
 	return
 }
 
-func (c *Client) getInstanceBootDisk(instanceName string) (disk *computepb.AttachedDisk, err error) {
-	err = c.connectComputeServiceAPI()
+// Create an image from a persistent disk.
+// The operation is not awaited, the caller is expected to poll the image status.
+func (c *Client) ImageCreateFromDisk(zone, disk, imageName string, labels map[string]string, forceCreate bool) (err error) {
+	err = c.connectImageServiceAPI()
 	if err != nil {
 		return
 	}
-	req := &computepb.GetInstanceRequest{
-		Project:  c.ProjectID,
-		Zone:     c.Zone,
-		Instance: instanceName,
-	}
-	instance, err := c.computeService.Get(c.ctx, req)
+	sourceDisk := &computepb.Disk{}
+	err = c.Get(sourceDisk, disk, zone)
 	if err != nil {
 		return
 	}
-	for _, disk := range instance.Disks {
-		if disk.GetBoot() {
-			return disk, nil
-		}
+	_, err = c.imageService.Insert(c.ctx, &computepb.InsertImageRequest{
+		Project: c.ProjectID,
+		ImageResource: &computepb.Image{
+			Name:       &imageName,
+			SourceDisk: sourceDisk.SelfLink,
+			Labels:     labels,
+		},
+		ForceCreate: &forceCreate,
+	})
+	if err != nil {
+		err = liberr.Wrap(err, "image", imageName, "disk", disk)
 	}
 	return
 }
 
-func (c *Client) VMCreateImageFromDisk(instanceName string, forceCreate bool) (imageName string, err error) {
-	err = c.connectComputeServiceAPI()
+// Create an image from a snapshot.
+// The operation is not awaited, the caller is expected to poll the image status.
+func (c *Client) ImageCreateFromSnapshot(snapshotName, imageName string, labels map[string]string) (err error) {
+	err = c.connectImageServiceAPI()
 	if err != nil {
 		return
 	}
-	err = c.connectDiskServiceAPI()
+	snapshot := &computepb.Snapshot{}
+	err = c.Get(snapshot, snapshotName)
 	if err != nil {
 		return
 	}
-	// // If storageLocations empty, automatically selects the closest one to the source
-	storageLocations := []string{}
-	// // If forceCreate is set to `true`, proceeds even if the disk is attached to
-	// // a running instance. This may compromise integrity of the image!
-	// forceCreate = false`
-	disk, err := c.getInstanceBootDisk(instanceName)
+	_, err = c.imageService.Insert(c.ctx, &computepb.InsertImageRequest{
+		Project: c.ProjectID,
+		ImageResource: &computepb.Image{
+			Name:           &imageName,
+			SourceSnapshot: snapshot.SelfLink,
+			Labels:         labels,
+		},
+	})
 	if err != nil {
-		return
+		err = liberr.Wrap(err, "image", imageName, "snapshot", snapshotName)
 	}
-	sourceReq := &computepb.GetDiskRequest{
-		Project: c.ProjectID,
-		Disk:    disk.GetDeviceName(),
-		Zone:    c.Zone,
+	return
+}
+
+// Create a snapshot of a persistent disk.
+// Compute Engine stores the snapshots of the same disk incrementally
+// but an image created from a snapshot always holds the whole disk.
+// The snapshot can be taken while the disk is attached to a running instance.
+// The operation is not awaited, the caller is expected to poll the snapshot status.
+func (c *Client) SnapshotCreate(zone, disk, snapshotName string, labels map[string]string) (err error) {
+	err = c.connectSnapshotServiceAPI()
+	if err != nil {
+		return
 	}
-	imageName = fmt.Sprintf("%s-%s", instanceName, "forklift")
-	sourceDisk, err := c.diskService.Get(c.ctx, sourceReq)
+	sourceDisk := &computepb.Disk{}
+	err = c.Get(sourceDisk, disk, zone)
 	if err != nil {
 		return
 	}
-	req := &computepb.InsertImageRequest{
+	_, err = c.snapshotService.Insert(c.ctx, &computepb.InsertSnapshotRequest{
 		Project: c.ProjectID,
-		ImageResource: &computepb.Image{
-			Name:             &imageName,
-			SourceDisk:       sourceDisk.SelfLink,
-			StorageLocations: storageLocations,
+		SnapshotResource: &computepb.Snapshot{
+			Name:       &snapshotName,
+			SourceDisk: sourceDisk.SelfLink,
+			Labels:     labels,
 		},
-		ForceCreate: &forceCreate,
+	})
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshotName, "disk", disk)
 	}
-	op, err := c.imageService.Insert(c.ctx, req)
+	return
+}
 
-	if err = op.Wait(c.ctx); err != nil {
-		return imageName, fmt.Errorf("unable to wait for the operation: %w", err)
+// Delete a snapshot.
+func (c *Client) SnapshotDelete(snapshotName string) (err error) {
+	snapshot := &computepb.Snapshot{Name: &snapshotName}
+	err = c.snapshotServiceAPI(snapshot, &DeleteOpts{})
+	if err != nil {
+		err = liberr.Wrap(err, "snapshot", snapshotName)
 	}
 	return
 }
 
-func (c *Client) VMStatus(instance string) (status string, err error) {
-	err = c.connectComputeServiceAPI()
+// Merge labels into the image labels.
+func (c *Client) ImageSetLabels(imageName string, labels map[string]string) (err error) {
+	image := &computepb.Image{}
+	err = c.Get(image, imageName)
 	if err != nil {
 		return
 	}
-	req := &computepb.GetInstanceRequest{
+	merged := map[string]string{}
+	for k, v := range image.Labels {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	_, err = c.imageService.SetLabels(c.ctx, &computepb.SetLabelsImageRequest{
 		Project:  c.ProjectID,
-		Instance: instance,
-		Zone:     c.Zone,
+		Resource: imageName,
+		GlobalSetLabelsRequestResource: &computepb.GlobalSetLabelsRequest{
+			Labels:           merged,
+			LabelFingerprint: image.LabelFingerprint,
+		},
+	})
+	if err != nil {
+		err = liberr.Wrap(err, "image", imageName)
 	}
-	instanceInfo, err := c.computeService.Get(c.ctx, req)
+	return
+}
+
+// Delete an image.
+func (c *Client) ImageDelete(imageName string) (err error) {
+	image := &computepb.Image{Name: &imageName}
+	err = c.imageServiceAPI(image, &DeleteOpts{})
 	if err != nil {
-		return
+		err = liberr.Wrap(err, "image", imageName)
 	}
-	status = instanceInfo.GetStatus()
 	return
 }
 
-func (c *Client) VMStart(instance string) (err error) {
-	err = c.connectComputeServiceAPI()
+// Export an image to the staging bucket as a compressed raw disk.
+// The export runs as a Cloud Build job tagged with the object name so that
+// repeated calls find the running build instead of submitting a new one.
+// Returns done=true once the object is in the bucket.
+func (c *Client) ImageExport(imageName, objectName string) (done bool, err error) {
+	err = c.ensureBucket()
 	if err != nil {
-		return err
+		return
 	}
-	req := &computepb.StartInstanceRequest{
-		Project:  c.ProjectID,
-		Instance: instance,
-		Zone:     c.Zone,
+	done, err = c.ObjectExists(c.BucketName, objectName)
+	if err != nil || done {
+		return
+	}
+	err = c.connectCloudBuildServiceAPI()
+	if err != nil {
+		return
 	}
-	op, err := c.computeService.Start(c.ctx, req)
+	tag := exportTag(objectName)
+	build, err := c.findBuild(tag)
 	if err != nil {
-		return err
+		return
+	}
+	if build != nil {
+		switch build.Status {
+		case BuildStatusQueued, BuildStatusPending, BuildStatusWorking:
+			return
+		case BuildStatusSuccess:
+			done, err = c.ObjectExists(c.BucketName, objectName)
+			return
+		default:
+			err = liberr.New(
+				"image export failed.",
+				"image",
+				imageName,
+				"status",
+				build.Status.String(),
+				"log",
+				build.LogUrl)
+			return
+		}
 	}
-	// Wait for the operation to complete.
-	if err = op.Wait(c.ctx); err != nil {
-		return fmt.Errorf("unable to wait for the operation: %w", err)
+	destinationURI := fmt.Sprintf("gs://%s/%s", c.BucketName, objectName)
+	_, err = c.cloudBuildService.CreateBuild(c.ctx, &cloudbuildpb.CreateBuildRequest{
+		ProjectId: c.ProjectID,
+		Build: &cloudbuildpb.Build{
+			Steps: []*cloudbuildpb.BuildStep{
+				{
+					Name: ExportBuilder,
+					Args: []string{
+						fmt.Sprintf("-timeout=%ds", int(ExportTimeout.Seconds())),
+						"-client_id=api",
+						"-source_image=" + imageName,
+						"-destination_uri=" + destinationURI,
+					},
+				},
+			},
+			Tags:    []string{tag},
+			Timeout: durationpb.New(ExportTimeout),
+		},
+	})
+	if err != nil {
+		err = liberr.Wrap(err, "image", imageName)
 	}
-	return nil
+	return
 }
 
-func (c *Client) VMStop(instance string) (err error) {
-	err = c.connectComputeServiceAPI()
+// Find the latest build with the tag.
+func (c *Client) findBuild(tag string) (build *cloudbuildpb.Build, err error) {
+	it := c.cloudBuildService.ListBuilds(c.ctx, &cloudbuildpb.ListBuildsRequest{
+		ProjectId: c.ProjectID,
+		Filter:    fmt.Sprintf("tags=\"%s\"", tag),
+	})
+	for {
+		found, nErr := it.Next()
+		if nErr == iterator.Done {
+			break
+		}
+		if nErr != nil {
+			err = liberr.Wrap(nErr)
+			return
+		}
+		if build == nil || found.GetCreateTime().AsTime().After(build.GetCreateTime().AsTime()) {
+			build = found
+		}
+	}
+	return
+}
+
+// Build tags only allow word characters, dots and dashes.
+func exportTag(objectName string) (tag string) {
+	tag = strings.TrimSuffix(objectName, ExportSuffix)
+	tag = strings.Map(
+		func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+				return r
+			}
+			return '-'
+		},
+		tag)
+	if len(tag) > 128 {
+		tag = tag[len(tag)-128:]
+	}
+	return
+}
 
+// Create the staging bucket when missing.
+func (c *Client) ensureBucket() (err error) {
+	err = c.connectStorageServiceAPI()
 	if err != nil {
-		return err
+		return
 	}
-	req := &computepb.StopInstanceRequest{
-		Project:  c.ProjectID,
-		Instance: instance,
-		Zone:     c.Zone,
+	bucket := c.storageService.Bucket(c.BucketName)
+	_, err = bucket.Attrs(c.ctx)
+	if err == nil || !c.IsNotFound(err) {
+		if err != nil {
+			err = liberr.Wrap(err, "bucket", c.BucketName)
+		}
+		return
+	}
+	err = bucket.Create(c.ctx, c.ProjectID, nil)
+	if err != nil {
+		err = liberr.Wrap(err, "bucket", c.BucketName)
+	}
+	return
+}
+
+// Check if the object exists in the bucket.
+func (c *Client) ObjectExists(bucketName string, objectName string) (exists bool, err error) {
+	err = c.connectStorageServiceAPI()
+	if err != nil {
+		return
 	}
-	op, err := c.computeService.Stop(c.ctx, req)
+	_, err = c.storageService.Bucket(bucketName).Object(objectName).Attrs(c.ctx)
 	if err != nil {
-		return err
+		if c.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err, "bucket", bucketName, "object", objectName)
+		}
+		return
 	}
-	// Wait for the operation to complete.
-	if err = op.Wait(c.ctx); err != nil {
-		return fmt.Errorf("unable to wait for the operation: %w", err)
+	exists = true
+	return
+}
+
+// Delete an object from the bucket.
+func (c *Client) ObjectDelete(bucketName string, objectName string) (err error) {
+	err = c.connectStorageServiceAPI()
+	if err != nil {
+		return
+	}
+	err = c.storageService.Bucket(bucketName).Object(objectName).Delete(c.ctx)
+	if err != nil {
+		if c.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err, "bucket", bucketName, "object", objectName)
+		}
 	}
 	return
 }
 
+// Open a reader on the object.
 func (c *Client) DownloadImageFromBucket(bucketName string, objectName string) (reader io.ReadCloser, err error) {
 	err = c.connectStorageServiceAPI()
 	if err != nil {
 		return
 	}
-	bucket := c.storageService.Bucket(bucketName)
-	object := bucket.Object(objectName)
-	reader, err = object.NewReader(c.ctx)
+	reader, err = c.storageService.Bucket(bucketName).Object(objectName).NewReader(c.ctx)
+	if err != nil {
+		err = liberr.Wrap(err, "bucket", bucketName, "object", objectName)
+	}
 	return
 }
 
@@ -697,5 +1232,3 @@ func (c *Client) unsupportedTypeError(object interface{}) (err error) {
 	err = liberr.New(fmt.Sprintf("unsupported type %T", object))
 	return
 }
-
-// buon qua di
@@ -1,11 +1,29 @@
 package gcp
 
-import "cloud.google.com/go/compute/apiv1/computepb"
+import (
+	"path"
+
+	"cloud.google.com/go/cloudbuild/apiv1/v2/cloudbuildpb"
+	"cloud.google.com/go/compute/apiv1/computepb"
+)
+
+// Secret keys.
+const (
+	// Service account key (JSON).
+	ServiceAccount = "serviceAccountKey"
+	// Project ID, defaults to the project of the service account.
+	Project = "projectID"
+	// Bucket used to stage the exported disk images.
+	Bucket = "bucketName"
+)
 
 // VM Status
 const (
-	VMStatusRunning = computepb.Instance_RUNNING
-	VMStatusStopped = computepb.Instance_STOPPED
+	VMStatusRunning    = computepb.Instance_RUNNING
+	VMStatusStopping   = computepb.Instance_STOPPING
+	VMStatusStopped    = computepb.Instance_STOPPED
+	VMStatusSuspended  = computepb.Instance_SUSPENDED
+	VMStatusTerminated = computepb.Instance_TERMINATED
 )
 
 // Image Status
@@ -16,6 +34,43 @@ const (
 	ImageStatusDeleting = computepb.Image_DELETING
 )
 
+// Snapshot Status
+const (
+	SnapshotStatusReady     = computepb.Snapshot_READY
+	SnapshotStatusFailed    = computepb.Snapshot_FAILED
+	SnapshotStatusCreating  = computepb.Snapshot_CREATING
+	SnapshotStatusUploading = computepb.Snapshot_UPLOADING
+	SnapshotStatusDeleting  = computepb.Snapshot_DELETING
+)
+
+// Build Status
+const (
+	BuildStatusQueued   = cloudbuildpb.Build_QUEUED
+	BuildStatusPending  = cloudbuildpb.Build_PENDING
+	BuildStatusWorking  = cloudbuildpb.Build_WORKING
+	BuildStatusSuccess  = cloudbuildpb.Build_SUCCESS
+	BuildStatusFailure  = cloudbuildpb.Build_FAILURE
+	BuildStatusTimeout  = cloudbuildpb.Build_TIMEOUT
+	BuildStatusCanceled = cloudbuildpb.Build_CANCELLED
+)
+
+// Attached disk types.
+const (
+	DiskTypePersistent = "PERSISTENT"
+	DiskTypeScratch    = "SCRATCH"
+)
+
+// Node affinity keys of sole-tenant placement.
+const (
+	NodeGroupAffinity = "compute.googleapis.com/node-group-name"
+	NodeAffinity      = "compute.googleapis.com/node-name"
+)
+
+// Guest OS features.
+const (
+	UEFICompatible = "UEFI_COMPATIBLE"
+)
+
 // Image Type
 const (
 	QCOW2 = "qcow2"
@@ -24,8 +79,12 @@ const (
 	VPC   = "vpc"
 )
 
+// The suffix of the compressed raw disk written by the image export.
+const ExportSuffix = ".tar.gz"
+
 type GetOpts struct {
-	ID string
+	ID   string
+	Zone string
 }
 
 type VMListOpts struct {
@@ -43,5 +102,32 @@ type NetworkListOpts struct {
 type NetworkCreateOpts struct {
 }
 
+type DiskTypeListOpts struct {
+}
+
+type DiskListOpts struct {
+}
+
+type ZoneListOpts struct {
+}
+
+type SubnetworkListOpts struct {
+}
+
+// Machine types are listed by zone.
+type MachineTypeListOpts struct {
+	Zone string
+}
+
 type DeleteOpts struct {
 }
+
+// Return the last segment of a resource URL.
+// Compute Engine references other resources (zones, machine types,
+// disks, networks) by their full URL.
+func ShortName(url string) string {
+	if url == "" {
+		return ""
+	}
+	return path.Base(url)
+}
@@ -0,0 +1,5 @@
+/*
+Provides the block comparison used to apply the content of a
+source disk onto a volume already populated by a previous transfer.
+*/
+package delta
@@ -0,0 +1,58 @@
+package delta
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// Block size.
+const BlockSize = 1024 * 1024
+
+// Volume written by blocks.
+type Volume interface {
+	io.ReaderAt
+	io.WriterAt
+	Sync() error
+}
+
+// Reports the number of bytes read from the source.
+type Progress func(offset int64)
+
+// Write the blocks of the source that differ from the volume content.
+// The whole source is read, only the writes of the unchanged blocks
+// are saved. Returns the number of bytes read and written.
+func Write(source io.Reader, volume Volume, progress Progress) (total, changed int64, err error) {
+	block := make([]byte, BlockSize)
+	current := make([]byte, BlockSize)
+	for {
+		n, rErr := io.ReadFull(source, block)
+		if n > 0 {
+			m, vErr := volume.ReadAt(current[:n], total)
+			if vErr != nil && !errors.Is(vErr, io.EOF) {
+				err = vErr
+				return
+			}
+			if m < n || !bytes.Equal(block[:n], current[:n]) {
+				_, err = volume.WriteAt(block[:n], total)
+				if err != nil {
+					return
+				}
+				changed += int64(n)
+			}
+			total += int64(n)
+			if progress != nil {
+				progress(total)
+			}
+		}
+		if errors.Is(rErr, io.EOF) || errors.Is(rErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if rErr != nil {
+			err = rErr
+			return
+		}
+	}
+	err = volume.Sync()
+	return
+}
@@ -0,0 +1,62 @@
+package delta
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+// Volume recording the offsets written.
+type volume struct {
+	*os.File
+	written []int64
+}
+
+func (r *volume) WriteAt(p []byte, offset int64) (int, error) {
+	r.written = append(r.written, offset)
+	return r.File.WriteAt(p, offset)
+}
+
+func TestWrite(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "disk.img"))
+	g.Expect(err).To(gomega.BeNil())
+	defer file.Close()
+	v := &volume{File: file}
+
+	// Populate the empty volume.
+	source := bytes.Repeat([]byte{1}, 2*BlockSize+10)
+	var offsets []int64
+	total, changed, err := Write(bytes.NewReader(source), v, func(offset int64) {
+		offsets = append(offsets, offset)
+	})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(total).To(gomega.Equal(int64(len(source))))
+	g.Expect(changed).To(gomega.Equal(total))
+	g.Expect(offsets).To(gomega.Equal([]int64{BlockSize, 2 * BlockSize, 2*BlockSize + 10}))
+
+	// Only the changed block is written.
+	v.written = nil
+	source[BlockSize+1] = 2
+	total, changed, err = Write(bytes.NewReader(source), v, nil)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(total).To(gomega.Equal(int64(len(source))))
+	g.Expect(changed).To(gomega.Equal(int64(BlockSize)))
+	g.Expect(v.written).To(gomega.Equal([]int64{BlockSize}))
+
+	// The grown source is appended.
+	v.written = nil
+	source = append(source, 3)
+	_, changed, err = Write(bytes.NewReader(source), v, nil)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(changed).To(gomega.Equal(int64(11)))
+	g.Expect(v.written).To(gomega.Equal([]int64{2 * BlockSize}))
+
+	content, err := os.ReadFile(file.Name())
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(content).To(gomega.Equal(source))
+}
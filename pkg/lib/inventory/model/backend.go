@@ -0,0 +1,142 @@
+package model
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Backend names.
+const (
+	// Embedded SQLite rebuilt each time the DB is opened.
+	SQLiteBackend = "sqlite"
+	// Embedded SQLite kept across restarts.
+	PersistentSQLiteBackend = "sqlite-persistent"
+)
+
+// Storage backend.
+// Provides the DB connections used by the session pool.
+type Backend interface {
+	// Open a DB connection.
+	Open() (*sql.DB, error)
+	// Delete the stored content.
+	Purge() error
+	// The stored content survives restarts.
+	Persistent() bool
+	// Description.
+	String() string
+}
+
+// Backend factory.
+// Builds a backend storing the content at the specified location.
+type BackendFactory func(location string) Backend
+
+// Registered backends.
+var backends = struct {
+	mutex   sync.RWMutex
+	factory map[string]BackendFactory
+}{
+	factory: map[string]BackendFactory{
+		SQLiteBackend: func(location string) Backend {
+			return &SQLite{Path: location}
+		},
+		PersistentSQLiteBackend: func(location string) Backend {
+			return &SQLite{Path: location, Keep: true}
+		},
+	},
+}
+
+// Register a backend.
+func Register(name string, factory BackendFactory) {
+	backends.mutex.Lock()
+	defer backends.mutex.Unlock()
+	backends.factory[name] = factory
+}
+
+// Build a registered backend.
+func NewBackend(name, location string) (backend Backend, err error) {
+	backends.mutex.RLock()
+	defer backends.mutex.RUnlock()
+	factory, found := backends.factory[name]
+	if !found {
+		names := []string{}
+		for n := range backends.factory {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		err = liberr.New(
+			fmt.Sprintf(
+				"backend '%s' not registered, expected one of: %v",
+				name,
+				names))
+		return
+	}
+	backend = factory(location)
+	return
+}
+
+// Embedded SQLite backend.
+type SQLite struct {
+	// DB file path.
+	Path string
+	// Keep the content across restarts.
+	Keep bool
+}
+
+// Open a DB connection.
+// For sqlite3:
+//
+//	foreign keys must be enabled on each connection.
+func (r *SQLite) Open() (db *sql.DB, err error) {
+	db, err = sql.Open("sqlite3", r.Path)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	pragma := []string{
+		"PRAGMA foreign_keys = ON",
+		"PRAGMA journal_mode = WAL",
+	}
+	for _, stmt := range pragma {
+		_, err = db.Exec(stmt)
+		if err != nil {
+			_ = db.Close()
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+// Delete the DB file.
+func (r *SQLite) Purge() (err error) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		err = os.Remove(r.Path + suffix)
+		if err != nil {
+			if os.IsNotExist(err) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+	}
+
+	return
+}
+
+// The content survives restarts.
+func (r *SQLite) Persistent() bool {
+	return r.Keep
+}
+
+// Description.
+func (r *SQLite) String() string {
+	return r.Path
+}
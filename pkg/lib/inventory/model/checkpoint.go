@@ -0,0 +1,60 @@
+package model
+
+import (
+	"errors"
+)
+
+// Checkpoint model.
+// Records the last revision collected so that a collector
+// may resume when the DB content has survived a restart.
+type Checkpoint struct {
+	// Collector (or component) name.
+	Name string `sql:"pk"`
+	// Last revision.
+	Revision string `sql:""`
+}
+
+func (m *Checkpoint) Pk() string {
+	return m.Name
+}
+
+func (m *Checkpoint) String() string {
+	return m.Name
+}
+
+// Get the revision recorded by the named checkpoint.
+// Returns found=false when not recorded.
+func GetCheckpoint(db DB, name string) (revision string, found bool, err error) {
+	m := &Checkpoint{Name: name}
+	err = db.Get(m)
+	if err != nil {
+		if errors.Is(err, NotFound) {
+			err = nil
+		}
+		return
+	}
+	revision = m.Revision
+	found = true
+	return
+}
+
+// Record the revision of the named checkpoint.
+func SetCheckpoint(db DB, name, revision string) (err error) {
+	err = db.With(func(tx *Tx) (err error) {
+		m := &Checkpoint{Name: name}
+		err = tx.Get(m)
+		if err != nil {
+			if errors.Is(err, NotFound) {
+				m.Revision = revision
+				err = tx.Insert(m)
+			}
+			return
+		}
+		if m.Revision != revision {
+			m.Revision = revision
+			err = tx.Update(m)
+		}
+		return
+	})
+	return
+}
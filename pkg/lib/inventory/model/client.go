@@ -1,8 +1,10 @@
 package model
 
 import (
+	"crypto/sha1"
 	"database/sql"
-	"os"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
@@ -42,10 +44,13 @@ type DB interface {
 	EndWatch(watch *Watch)
 }
 
+// Checkpoint recording the schema of a persistent DB.
+const SchemaCheckpoint = "schema"
+
 // Database client.
 type Client struct {
-	// file path.
-	path string
+	// Storage backend.
+	backend Backend
 	// Model
 	models []interface{}
 	// Overall data model.
@@ -60,13 +65,14 @@ type Client struct {
 
 // Create the database.
 // Build the schema to support the specified models.
+// The content of a persistent backend is kept unless `delete`
+// is specified or the schema has changed.
 // See: Pool.Open().
 func (r *Client) Open(delete bool) (err error) {
 	if delete {
-		_ = os.Remove(r.path)
-		r.log.V(3).Info("DB file deleted.")
+		r.purge()
 	}
-	err = r.pool.Open(1, 10, r.path, &r.journal)
+	err = r.pool.Open(1, 10, r.backend, &r.journal)
 	if err != nil {
 		r.log.V(3).Error(err, "open session pool failed.")
 		panic(err)
@@ -74,7 +80,7 @@ func (r *Client) Open(delete bool) (err error) {
 	defer func() {
 		if err != nil {
 			_ = r.pool.Close()
-			_ = os.Remove(r.path)
+			r.purge()
 		}
 	}()
 	err = r.build()
@@ -103,8 +109,7 @@ func (r *Client) Close(delete bool) (err error) {
 			"Error closing the session pool.")
 	}
 	if delete {
-		_ = os.Remove(r.path)
-		r.log.V(3).Info("DB file deleted.")
+		r.purge()
 	}
 
 	r.log.V(3).Info("DB closed.")
@@ -205,7 +210,7 @@ func (r *Client) Begin(labels ...string) (tx *Tx, error error) {
 		err = liberr.Wrap(
 			err,
 			"db",
-			r.path)
+			r.backend.String())
 		return
 	}
 	tx = &Tx{
@@ -351,7 +356,7 @@ func (r *Client) EndWatch(watch *Watch) {
 
 // Build the data model.
 func (r *Client) build() (err error) {
-	r.models = append(r.models, &Label{})
+	r.models = append(r.models, &Label{}, &Checkpoint{})
 	r.dm, err = NewModel(r.models)
 	if err != nil {
 		return err
@@ -360,11 +365,25 @@ func (r *Client) build() (err error) {
 	if err != nil {
 		return err
 	}
+	schema := r.schema(ddls)
+	if r.backend.Persistent() {
+		stored := &Checkpoint{Name: SchemaCheckpoint}
+		gErr := r.Get(stored)
+		if gErr != nil || stored.Revision != schema {
+			_ = r.pool.Close()
+			r.purge()
+			err = r.pool.Open(1, 10, r.backend, &r.journal)
+			if err != nil {
+				return err
+			}
+			r.log.Info("DB content discarded, the schema has changed.")
+		}
+	}
 	session := r.pool.Writer()
-	defer session.Return()
 	for _, ddl := range ddls {
 		_, err := session.db.Exec(ddl)
 		if err != nil {
+			session.Return()
 			return liberr.Wrap(
 				err,
 				"DDL failed.",
@@ -377,10 +396,32 @@ func (r *Client) build() (err error) {
 				ddl)
 		}
 	}
+	session.Return()
+	err = SetCheckpoint(r, SchemaCheckpoint, schema)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// Digest of the schema.
+func (r *Client) schema(ddls []string) string {
+	h := sha1.New()
+	_, _ = h.Write([]byte(strings.Join(ddls, ";")))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Delete the stored content.
+func (r *Client) purge() {
+	err := r.backend.Purge()
+	if err != nil {
+		r.log.Error(err, "DB content not deleted.")
+		return
+	}
+	r.log.V(3).Info("DB content deleted.")
+}
+
 // Database transaction.
 type Tx struct {
 	// DB session.
@@ -1,6 +1,10 @@
 // The `model` package essentially provides a lightweight object
 // relational model (ORM) based on sqlite3 intended to support the
 // needs of the `container` package.
+// The storage is provided by a `Backend`. The embedded SQLite
+// backend is the default. Backends are registered by name using
+// Register() and may keep the content across restarts so that
+// collectors can resume from the last `Checkpoint`.
 // Each entity (table) is modeled by a struct.  Each field (column)
 // is Described using tags:
 //
@@ -153,17 +157,23 @@ import (
 )
 
 // New database.
+// Stored in an (embedded) SQLite file.
 func New(path string, models ...interface{}) DB {
+	return NewWith(&SQLite{Path: path}, models...)
+}
+
+// New database stored by the specified backend.
+func NewWith(backend Backend, models ...interface{}) DB {
 	client := &Client{
-		path:   path,
-		models: models,
+		backend: backend,
+		models:  models,
 	}
 	client.log = logging.WithName("model|db").WithValues(
 		"path",
-		path)
+		backend.String())
 	client.journal.log = logging.WithName("db|journal").WithValues(
 		"db",
-		path)
+		backend.String())
 
 	return client
 }
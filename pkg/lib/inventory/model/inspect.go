@@ -4,6 +4,7 @@ import (
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -243,11 +244,16 @@ func (r *DataModel) Add(md *Definition) {
 	r.content[key] = md
 }
 
-// Definitions.
+// Definitions sorted by kind.
 func (r *DataModel) Definitions() (list Definitions) {
 	list = Definitions{}
-	for _, md := range r.content {
-		list = append(list, md)
+	keys := []string{}
+	for key := range r.content {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		list = append(list, r.content[key])
 	}
 
 	return
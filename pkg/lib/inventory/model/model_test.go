@@ -1188,6 +1188,49 @@ func TestCloseDB(t *testing.T) {
 	g.Expect(handler.done).To(gomega.BeTrue())
 }
 
+func TestPersistentBackend(t *testing.T) {
+	var err error
+	g := gomega.NewGomegaWithT(t)
+	backend, err := NewBackend(PersistentSQLiteBackend, "/tmp/test-persistent.db")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(backend.Persistent()).To(gomega.BeTrue())
+	_, err = NewBackend("unknown", "/tmp/test-unknown.db")
+	g.Expect(err).ToNot(gomega.BeNil())
+	// Content stored.
+	DB := NewWith(backend, &PlainObject{})
+	err = DB.Open(true)
+	g.Expect(err).To(gomega.BeNil())
+	err = DB.Insert(&PlainObject{ID: 1, Name: "Elmer"})
+	g.Expect(err).To(gomega.BeNil())
+	err = SetCheckpoint(DB, "collector", "42")
+	g.Expect(err).To(gomega.BeNil())
+	err = DB.Close(false)
+	g.Expect(err).To(gomega.BeNil())
+	// Content kept when reopened.
+	DB = NewWith(backend, &PlainObject{})
+	err = DB.Open(false)
+	g.Expect(err).To(gomega.BeNil())
+	err = DB.Get(&PlainObject{ID: 1})
+	g.Expect(err).To(gomega.BeNil())
+	revision, found, err := GetCheckpoint(DB, "collector")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(revision).To(gomega.Equal("42"))
+	err = DB.Close(false)
+	g.Expect(err).To(gomega.BeNil())
+	// Content discarded when the schema has changed.
+	DB = NewWith(backend, &PlainObject{}, &TestObject{})
+	err = DB.Open(false)
+	g.Expect(err).To(gomega.BeNil())
+	err = DB.Get(&PlainObject{ID: 1})
+	g.Expect(errors.Is(err, NotFound)).To(gomega.BeTrue())
+	_, found, err = GetCheckpoint(DB, "collector")
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(found).To(gomega.BeFalse())
+	err = DB.Close(true)
+	g.Expect(err).To(gomega.BeNil())
+}
+
 func TestMutatingWatch(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	DB := New("/tmp/test-mutating-watch.db", &TestObject{})
@@ -1543,6 +1586,29 @@ func TestDefinitions(t *testing.T) {
 		}))
 }
 
+func TestDDL(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	models := []interface{}{
+		&PlainObject{},
+		&TestObject{},
+		&Label{},
+		&Checkpoint{},
+	}
+	dm, err := NewModel(models)
+	g.Expect(err).To(gomega.BeNil())
+	ddl, err := dm.DDL()
+	g.Expect(err).To(gomega.BeNil())
+	// The schema digest of a persistent DB
+	// requires the DDL to be stable.
+	for i := 0; i < 20; i++ {
+		dm, err = NewModel(models)
+		g.Expect(err).To(gomega.BeNil())
+		next, err := dm.DDL()
+		g.Expect(err).To(gomega.BeNil())
+		g.Expect(next).To(gomega.Equal(ddl))
+	}
+}
+
 func fieldNames(fields []*Field) (names []string) {
 	for _, f := range fields {
 		names = append(names, f.Name)
@@ -2,8 +2,8 @@ package model
 
 import (
 	"database/sql"
+
 	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 // DB session.
@@ -76,37 +76,28 @@ type Pool struct {
 }
 
 // Open the pool.
-// Create sessions with DB connections.
+// Create sessions with DB connections provided by the backend.
 // For sqlite3:
 //
 //	Even with journal=WAL, nWriter must be (1) to
 //	prevent SQLITE_LOCKED error.
-func (p *Pool) Open(nWriter, nReader int, path string, journal *Journal) (err error) {
+func (p *Pool) Open(nWriter, nReader int, backend Backend, journal *Journal) (err error) {
 	defer func() {
 		if err != nil {
 			_ = p.Close()
 		}
 	}()
 	p.journal = journal
+	p.sessions = nil
 	total := nWriter + nReader
 	p.next.writer = make(chan *Session, nWriter)
 	p.next.reader = make(chan *Session, nReader)
 	for id := 0; id < total; id++ {
 		session := &Session{id: id}
-		session.db, err = sql.Open("sqlite3", path)
+		session.db, err = backend.Open()
 		if err != nil {
 			return
 		}
-		pragma := []string{
-			"PRAGMA foreign_keys = ON",
-			"PRAGMA journal_mode = WAL",
-		}
-		for _, stmt := range pragma {
-			_, err = session.db.Exec(stmt)
-			if err != nil {
-				return
-			}
-		}
 		p.sessions = append(
 			p.sessions,
 			session)
@@ -12,6 +12,7 @@ import (
 	fb "github.com/konveyor/forklift-controller/pkg/lib/filebacked"
 	"github.com/mattn/go-sqlite3"
 	"reflect"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -279,7 +280,13 @@ func (t Table) IndexDDL(md *Definition) (list []string, err error) {
 			index[group] = []*Field{fk.Owner}
 		}
 	}
-	for group, idxFields := range index {
+	groups := []string{}
+	for group := range index {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		idxFields := index[group]
 		tpl, err = tpl.Parse(IndexDDL)
 		if err != nil {
 			err = liberr.Wrap(err)
@@ -705,7 +712,13 @@ func (t Table) Constraints(md *Definition, dm *DataModel) (constraints []string,
 			}
 		}
 	}
-	for _, list := range unique {
+	names := []string{}
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		list := unique[name]
 		constraints = append(
 			constraints,
 			fmt.Sprintf(
@@ -15,6 +15,7 @@ const (
 const (
 	AllowedOrigins = "CORS_ALLOWED_ORIGINS"
 	WorkingDir     = "WORKING_DIR"
+	DBBackend      = "INVENTORY_DB_BACKEND"
 	AuthRequired   = "AUTH_REQUIRED"
 	Host           = "API_HOST"
 	Port           = "API_PORT"
@@ -35,6 +36,8 @@ type Inventory struct {
 	CORS CORS
 	// DB working directory.
 	WorkingDir string
+	// DB storage backend.
+	DBBackend string
 	// Authorization required.
 	AuthRequired bool
 	// Host.
@@ -72,6 +75,12 @@ func (r *Inventory) Load() error {
 	} else {
 		r.WorkingDir = os.TempDir()
 	}
+	// DBBackend
+	if s, found := os.LookupEnv(DBBackend); found {
+		r.DBBackend = s
+	} else {
+		r.DBBackend = "sqlite"
+	}
 	// Auth
 	r.AuthRequired = getEnvBool(AuthRequired, true)
 	// Host
@@ -10,6 +10,8 @@ import (
 // Environment variables.
 const (
 	MaxVmInFlight           = "MAX_VM_INFLIGHT"
+	MaxDiskInFlight         = "MAX_DISK_INFLIGHT"
+	MaxDiskSizeInFlight     = "MAX_DISK_SIZE_INFLIGHT"
 	HookRetry               = "HOOK_RETRY"
 	ImporterRetry           = "IMPORTER_RETRY"
 	VirtV2vImage            = "VIRT_V2V_IMAGE"
@@ -18,17 +20,32 @@ const (
 	SnapshotRemovalTimeout  = "SNAPSHOT_REMOVAL_TIMEOUT"
 	SnapshotStatusCheckRate = "SNAPSHOT_STATUS_CHECK_RATE"
 	CDIExportTokenTTL       = "CDI_EXPORT_TOKEN_TTL"
+	GcpPopulatorImage       = "GCP_POPULATOR_IMAGE"
+	OpenstackPopulatorImage = "OPENSTACK_POPULATOR_IMAGE"
+	OcpSyncImage            = "OCP_SYNC_IMAGE"
+	VerificationTimeout     = "VERIFICATION_TIMEOUT"
 )
 
 // Default virt-v2v image.
 const (
-	DefaultVirtV2vImage = "quay.io/kubev2v/forklift-virt-v2v:latest"
+	DefaultVirtV2vImage            = "quay.io/kubev2v/forklift-virt-v2v:latest"
+	DefaultGcpPopulatorImage       = "quay.io/kubev2v/gcp-populator:latest"
+	DefaultOpenstackPopulatorImage = "quay.io/kubev2v/openstack-populator:latest"
+	DefaultOcpSyncImage            = "quay.io/kubev2v/ocp-sync:latest"
 )
 
 // Migration settings
 type Migration struct {
-	// Max VMs in-flight.
+	// Max VMs in-flight per provider.
+	// Max disks in-flight per ESX host (vSphere).
 	MaxInFlight int
+	// Max disks in-flight per source bottleneck (oVirt
+	// storage domain, Cinder backend, OVA NFS export,
+	// OpenShift namespace). Not limited when zero.
+	MaxDiskInFlight int
+	// Max size (GiB) of the disks in-flight per source
+	// bottleneck. Not limited when zero.
+	MaxDiskSizeInFlight int
 	// Hook fail/retry limit.
 	HookRetry int
 	// Importer pod retry limit.
@@ -46,6 +63,14 @@ type Migration struct {
 	VirtV2vDontRequestKVM bool
 	// OCP Export token TTL minutes
 	CDIExportTokenTTL int
+	// GCP populator image for the warm migration precopies
+	GcpPopulatorImage string
+	// OpenStack populator image for the warm migration precopies
+	OpenstackPopulatorImage string
+	// OpenShift sync image for the warm migration precopies
+	OcpSyncImage string
+	// Post-migration verification timeout in minutes
+	VerificationTimeout int
 }
 
 // Load settings.
@@ -54,6 +79,14 @@ func (r *Migration) Load() (err error) {
 	if err != nil {
 		err = liberr.Wrap(err)
 	}
+	r.MaxDiskInFlight, err = getEnvLimit(MaxDiskInFlight, 0)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	r.MaxDiskSizeInFlight, err = getEnvLimit(MaxDiskSizeInFlight, 0)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
 	r.HookRetry, err = getEnvLimit(HookRetry, 3)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -92,6 +125,25 @@ func (r *Migration) Load() (err error) {
 	if err != nil {
 		err = liberr.Wrap(err)
 	}
+	r.VerificationTimeout, err = getEnvLimit(VerificationTimeout, 10)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	if gcpPopulatorImage, ok := os.LookupEnv(GcpPopulatorImage); ok {
+		r.GcpPopulatorImage = gcpPopulatorImage
+	} else {
+		r.GcpPopulatorImage = DefaultGcpPopulatorImage
+	}
+	if openstackPopulatorImage, ok := os.LookupEnv(OpenstackPopulatorImage); ok {
+		r.OpenstackPopulatorImage = openstackPopulatorImage
+	} else {
+		r.OpenstackPopulatorImage = DefaultOpenstackPopulatorImage
+	}
+	if ocpSyncImage, ok := os.LookupEnv(OcpSyncImage); ok {
+		r.OcpSyncImage = ocpSyncImage
+	} else {
+		r.OcpSyncImage = DefaultOcpSyncImage
+	}
 
 	return
 }
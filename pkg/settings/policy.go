@@ -1,21 +1,42 @@
 package settings
 
 import (
+	"fmt"
 	"os"
+
+	liberr "github.com/konveyor/forklift-controller/pkg/lib/error"
 )
 
 // Environment variables.
 const (
 	PolicyAgentURL            = "POLICY_AGENT_URL"
+	PolicyAgentMode           = "POLICY_AGENT_MODE"
+	PolicyAgentPolicies       = "POLICY_AGENT_POLICIES"
 	PolicyAgentCA             = "POLICY_AGENT_CA"
 	PolicyAgentWorkerLimit    = "POLICY_AGENT_WORKER_LIMIT"
 	PolicyAgentSearchInterval = "POLICY_AGENT_SEARCH_INTERVAL"
 )
 
+// Policy agent modes.
+const (
+	// Validation delegated to the validation service.
+	PolicyModeRemote = "remote"
+	// Validation evaluated in-process using the
+	// policies found in the policies directory.
+	PolicyModeEmbedded = "embedded"
+)
+
+// Default policies directory.
+const DefaultPolicies = "/usr/share/opa/policies"
+
 // Policy agent settings.
 type PolicyAgent struct {
+	// Mode (remote|embedded).
+	Mode string
 	// URL.
 	URL string
+	// Policies (directory) used in embedded mode.
+	Policies string
 	// TLS
 	TLS struct {
 		// CA path
@@ -35,6 +56,31 @@ func (r *PolicyAgent) Load() (err error) {
 	if s, found := os.LookupEnv(PolicyAgentURL); found {
 		r.URL = s
 	}
+	// Mode defaults to remote when the URL is
+	// specified and embedded otherwise.
+	if s, found := os.LookupEnv(PolicyAgentMode); found && s != "" {
+		r.Mode = s
+	} else if r.URL != "" {
+		r.Mode = PolicyModeRemote
+	} else {
+		r.Mode = PolicyModeEmbedded
+	}
+	switch r.Mode {
+	case PolicyModeRemote, PolicyModeEmbedded:
+	default:
+		err = liberr.New(
+			fmt.Sprintf(
+				"%s must be one of: %s, %s.",
+				PolicyAgentMode,
+				PolicyModeRemote,
+				PolicyModeEmbedded))
+		return
+	}
+	if s, found := os.LookupEnv(PolicyAgentPolicies); found {
+		r.Policies = s
+	} else {
+		r.Policies = DefaultPolicies
+	}
 	// TLS
 	if s, found := os.LookupEnv(PolicyAgentCA); found {
 		r.TLS.CA = s
@@ -55,5 +101,13 @@ func (r *PolicyAgent) Load() (err error) {
 
 // Enabled.
 func (r *PolicyAgent) Enabled() bool {
+	if r.Embedded() {
+		return r.Policies != ""
+	}
 	return r.URL != ""
 }
+
+// Policies evaluated in-process.
+func (r *PolicyAgent) Embedded() bool {
+	return r.Mode == PolicyModeEmbedded
+}
@@ -0,0 +1,5 @@
+package io.konveyor.forklift.gcp
+
+debug {
+	trace(sprintf("** debug ** vm name: %v", [input.name]))
+}
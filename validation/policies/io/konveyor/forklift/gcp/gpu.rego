@@ -0,0 +1,16 @@
+package io.konveyor.forklift.gcp
+
+import future.keywords.if
+
+default has_gpu = false
+
+has_gpu if count(input.accelerators) > 0
+
+concerns[flag] {
+	has_gpu
+	flag := {
+		"category": "Warning",
+		"label": "GPU detected",
+		"assessment": "The VM has GPUs attached. GPU passthrough is not configured by the migration. The VM can be migrated but it will not have any GPU attached to it in the target environment.",
+	}
+}
@@ -0,0 +1,19 @@
+package io.konveyor.forklift.gcp
+
+test_without_gpu {
+	mock_vm := {
+		"name": "test",
+		"accelerators": [],
+	}
+	results = concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_gpu {
+	mock_vm := {
+		"name": "test",
+		"accelerators": [{"type": "nvidia-tesla-t4", "count": 1}],
+	}
+	results = concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,20 @@
+package io.konveyor.forklift.gcp
+
+import future.keywords.if
+import future.keywords.in
+
+default has_local_ssd = false
+
+has_local_ssd if {
+	some disk in input.disks
+	disk.type == "SCRATCH"
+}
+
+concerns[flag] {
+	has_local_ssd
+	flag := {
+		"category": "Warning",
+		"label": "Local SSD detected",
+		"assessment": "The VM has local SSD disks attached. Local SSDs are ephemeral and cannot be exported, the data they hold will not be migrated.",
+	}
+}
@@ -0,0 +1,22 @@
+package io.konveyor.forklift.gcp
+
+test_without_local_ssd {
+	mock_vm := {
+		"name": "test",
+		"disks": [{"type": "PERSISTENT"}],
+	}
+	results = concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_local_ssd {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"type": "PERSISTENT"},
+			{"type": "SCRATCH"},
+		],
+	}
+	results = concerns with input as mock_vm
+	count(results) == 1
+}
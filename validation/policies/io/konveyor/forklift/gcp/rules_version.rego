@@ -0,0 +1,5 @@
+package io.konveyor.forklift.gcp
+
+RULES_VERSION := 2
+
+rules_version = {"rules_version": RULES_VERSION}
@@ -0,0 +1,20 @@
+package io.konveyor.forklift.gcp
+
+import future.keywords.if
+import future.keywords.in
+
+default has_shared_disk = false
+
+has_shared_disk if {
+	some disk in input.disks
+	disk.mode == "READ_ONLY"
+}
+
+concerns[flag] {
+	has_shared_disk
+	flag := {
+		"category": "Warning",
+		"label": "Shared disk detected",
+		"assessment": "The VM has a disk attached in read-only mode, which may be shared with other instances. Shared disks are only supported by certain OpenShift Virtualization storage configurations. Ensure that the correct storage is selected for the disk.",
+	}
+}
@@ -0,0 +1,22 @@
+package io.konveyor.forklift.gcp
+
+test_without_shared_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [{"type": "PERSISTENT", "mode": "READ_WRITE"}],
+	}
+	results = concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_shared_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"type": "PERSISTENT", "mode": "READ_WRITE"},
+			{"type": "PERSISTENT", "mode": "READ_ONLY"},
+		],
+	}
+	results = concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,16 @@
+package io.konveyor.forklift.gcp
+
+import future.keywords.if
+
+default sole_tenant = false
+
+sole_tenant if input.soleTenant == true
+
+concerns[flag] {
+	sole_tenant
+	flag := {
+		"category": "Information",
+		"label": "Sole-tenant placement detected",
+		"assessment": "The VM runs on sole-tenant nodes. Node affinity is not migrated, use a node selector on the target VM to keep it isolated.",
+	}
+}
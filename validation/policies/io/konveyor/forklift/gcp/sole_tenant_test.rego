@@ -0,0 +1,19 @@
+package io.konveyor.forklift.gcp
+
+test_without_sole_tenant {
+	mock_vm := {
+		"name": "test",
+		"soleTenant": false,
+	}
+	results = concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_sole_tenant {
+	mock_vm := {
+		"name": "test",
+		"soleTenant": true,
+	}
+	results = concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,16 @@
+package io.konveyor.forklift.gcp
+
+import future.keywords.if
+
+default has_vtpm = false
+
+has_vtpm if input.vtpm == true
+
+concerns[flag] {
+	has_vtpm
+	flag := {
+		"category": "Warning",
+		"label": "vTPM detected",
+		"assessment": "The VM is a shielded VM with a virtual TPM. The TPM state cannot be exported, the VM will be created with a new (empty) TPM on OpenShift Virtualization. Secrets sealed by the TPM, such as BitLocker keys, must be recovered before the migration.",
+	}
+}
@@ -0,0 +1,19 @@
+package io.konveyor.forklift.gcp
+
+test_without_vtpm {
+	mock_vm := {
+		"name": "test",
+		"vtpm": false,
+	}
+	results = concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_vtpm {
+	mock_vm := {
+		"name": "test",
+		"vtpm": true,
+	}
+	results = concerns with input as mock_vm
+	count(results) == 1
+}
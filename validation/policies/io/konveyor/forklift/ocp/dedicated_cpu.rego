@@ -0,0 +1,16 @@
+package io.konveyor.forklift.ocp
+
+import future.keywords.if
+
+default has_dedicated_cpu = false
+
+has_dedicated_cpu if domain.cpu.dedicatedCpuPlacement == true
+
+concerns[flag] {
+	has_dedicated_cpu
+	flag := {
+		"category": "Warning",
+		"label": "Dedicated CPU placement detected",
+		"assessment": "The VM requests dedicated CPUs. The target cluster must have nodes with the CPU manager enabled, otherwise the VM will not be scheduled.",
+	}
+}
@@ -0,0 +1,22 @@
+package io.konveyor.forklift.ocp
+
+test_without_dedicated_cpu {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"cpu": {"cores": 2}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_dedicated_cpu {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"cpu": {
+			"cores": 2,
+			"dedicatedCpuPlacement": true,
+		}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,3 @@
+package io.konveyor.forklift.ocp
+
+domain := input.object.spec.template.spec.domain
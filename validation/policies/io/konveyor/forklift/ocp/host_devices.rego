@@ -0,0 +1,18 @@
+package io.konveyor.forklift.ocp
+
+import future.keywords.if
+
+default has_host_devices = false
+
+has_host_devices if count(domain.devices.gpus) > 0
+
+has_host_devices if count(domain.devices.hostDevices) > 0
+
+concerns[flag] {
+	has_host_devices
+	flag := {
+		"category": "Warning",
+		"label": "Host devices detected",
+		"assessment": "The VM has GPUs or host devices assigned. The devices must be available on the target cluster nodes and permitted by the KubeVirt configuration, otherwise the VM will not be scheduled.",
+	}
+}
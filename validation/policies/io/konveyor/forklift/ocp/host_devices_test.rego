@@ -0,0 +1,32 @@
+package io.konveyor.forklift.ocp
+
+test_without_host_devices {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"devices": {}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_gpu {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"devices": {
+			"gpus": [{"name": "gpu1", "deviceName": "nvidia.com/TU104GL_Tesla_T4"}],
+		}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_host_device {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"devices": {
+			"hostDevices": [{"name": "dev1", "deviceName": "intel.com/qat"}],
+		}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
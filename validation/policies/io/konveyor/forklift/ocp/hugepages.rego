@@ -0,0 +1,16 @@
+package io.konveyor.forklift.ocp
+
+import future.keywords.if
+
+default has_hugepages = false
+
+has_hugepages if domain.memory.hugepages.pageSize
+
+concerns[flag] {
+	has_hugepages
+	flag := {
+		"category": "Warning",
+		"label": "Hugepages detected",
+		"assessment": sprintf("The VM memory is backed by %v hugepages. The target cluster must have nodes with hugepages of the same size allocated, otherwise the VM will not be scheduled.", [domain.memory.hugepages.pageSize]),
+	}
+}
@@ -0,0 +1,22 @@
+package io.konveyor.forklift.ocp
+
+test_without_hugepages {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"memory": {"guest": "1Gi"}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_hugepages {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"memory": {
+			"guest": "1Gi",
+			"hugepages": {"pageSize": "1Gi"},
+		}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
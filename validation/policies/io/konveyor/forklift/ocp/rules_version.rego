@@ -0,0 +1,5 @@
+package io.konveyor.forklift.ocp
+
+RULES_VERSION := 1
+
+rules_version = {"rules_version": RULES_VERSION}
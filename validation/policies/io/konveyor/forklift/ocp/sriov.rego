@@ -0,0 +1,20 @@
+package io.konveyor.forklift.ocp
+
+import future.keywords.if
+import future.keywords.in
+
+default has_sriov_interface = false
+
+has_sriov_interface if {
+	some iface in domain.devices.interfaces
+	iface.sriov
+}
+
+concerns[flag] {
+	has_sriov_interface
+	flag := {
+		"category": "Warning",
+		"label": "SR-IOV interface detected",
+		"assessment": "The VM has SR-IOV network interfaces. The SR-IOV network operator must be deployed on the target cluster and the interfaces must be mapped to SR-IOV networks, otherwise the VM will not be scheduled.",
+	}
+}
@@ -0,0 +1,26 @@
+package io.konveyor.forklift.ocp
+
+test_without_sriov_interface {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"devices": {
+			"interfaces": [{"name": "default", "masquerade": {}}],
+		}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_sriov_interface {
+	mock_vm := {
+		"name": "test",
+		"object": {"spec": {"template": {"spec": {"domain": {"devices": {
+			"interfaces": [
+				{"name": "default", "masquerade": {}},
+				{"name": "sriov", "sriov": {}},
+			],
+		}}}}}},
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
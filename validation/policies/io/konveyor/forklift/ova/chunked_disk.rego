@@ -0,0 +1,20 @@
+package io.konveyor.forklift.ova
+
+import future.keywords.if
+import future.keywords.in
+
+default has_chunked_disk = false
+
+has_chunked_disk if {
+	some disk in input.disks
+	disk.ChunkSize > 0
+}
+
+concerns[flag] {
+	has_chunked_disk
+	flag := {
+		"category": "Information",
+		"label": "Split disk detected",
+		"assessment": "The OVF references a disk split into chunks. The chunks must all be present in the same location as the OVF for the disk to be imported.",
+	}
+}
@@ -0,0 +1,25 @@
+package io.konveyor.forklift.ova
+
+test_without_chunked_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"Name": "disk1.vmdk", "ChunkSize": 0},
+			{"Name": "disk2.vmdk"},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_chunked_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"Name": "disk1.vmdk", "ChunkSize": 0},
+			{"Name": "disk2.vmdk", "ChunkSize": 2147483648},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
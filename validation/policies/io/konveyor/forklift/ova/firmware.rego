@@ -0,0 +1,36 @@
+package io.konveyor.forklift.ova
+
+import future.keywords.if
+import future.keywords.in
+
+supported_firmware := {"bios", "efi"}
+
+default has_unsupported_firmware = false
+
+has_unsupported_firmware if {
+	is_string(input.Firmware)
+	input.Firmware != ""
+	not lower(input.Firmware) in supported_firmware
+}
+
+concerns[flag] {
+	has_unsupported_firmware
+	flag := {
+		"category": "Critical",
+		"label": "Unsupported firmware detected",
+		"assessment": sprintf("The VM firmware '%v' is not supported by OpenShift Virtualization. Only BIOS and UEFI firmware are supported.", [input.Firmware]),
+	}
+}
+
+default has_uefi_firmware = false
+
+has_uefi_firmware if lower(input.Firmware) == "efi"
+
+concerns[flag] {
+	has_uefi_firmware
+	flag := {
+		"category": "Warning",
+		"label": "UEFI detected",
+		"assessment": "UEFI secure boot will be disabled on OpenShift Virtualization. If the VM was set with UEFI secure boot, manual steps within the guest would be needed for the guest operating system to boot.",
+	}
+}
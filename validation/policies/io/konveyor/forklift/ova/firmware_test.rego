@@ -0,0 +1,34 @@
+package io.konveyor.forklift.ova
+
+test_without_firmware {
+	mock_vm := {"name": "test"}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_bios_firmware {
+	mock_vm := {
+		"name": "test",
+		"Firmware": "bios",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_efi_firmware {
+	mock_vm := {
+		"name": "test",
+		"Firmware": "efi",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_unsupported_firmware {
+	mock_vm := {
+		"name": "test",
+		"Firmware": "openfirmware",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
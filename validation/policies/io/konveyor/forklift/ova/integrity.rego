@@ -0,0 +1,29 @@
+package io.konveyor.forklift.ova
+
+import future.keywords.if
+
+default has_missing_files = false
+
+has_missing_files if count(input.MissingFiles) > 0
+
+default has_corrupt_files = false
+
+has_corrupt_files if count(input.CorruptFiles) > 0
+
+concerns[flag] {
+	has_missing_files
+	flag := {
+		"category": "Critical",
+		"label": "Incomplete OVA",
+		"assessment": sprintf("Files referenced by the OVF or listed in the manifest were not found: %v. The VM cannot be migrated until the missing files are provided.", [concat(", ", input.MissingFiles)]),
+	}
+}
+
+concerns[flag] {
+	has_corrupt_files
+	flag := {
+		"category": "Critical",
+		"label": "Corrupt OVA",
+		"assessment": sprintf("Files do not match the checksum listed in the manifest: %v. The VM cannot be migrated until the files are replaced.", [concat(", ", input.CorruptFiles)]),
+	}
+}
@@ -0,0 +1,39 @@
+package io.konveyor.forklift.ova
+
+test_without_integrity {
+	mock_vm := {"name": "test"}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_verified_integrity {
+	mock_vm := {
+		"name": "test",
+		"IntegrityVerified": true,
+		"MissingFiles": null,
+		"CorruptFiles": [],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_missing_files {
+	mock_vm := {
+		"name": "test",
+		"IntegrityVerified": true,
+		"MissingFiles": ["disk1.vmdk.000000001"],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_corrupt_files {
+	mock_vm := {
+		"name": "test",
+		"IntegrityVerified": true,
+		"MissingFiles": ["disk2.vmdk"],
+		"CorruptFiles": ["disk1.vmdk"],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 2
+}
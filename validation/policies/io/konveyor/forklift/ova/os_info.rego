@@ -0,0 +1,16 @@
+package io.konveyor.forklift.ova
+
+import future.keywords.if
+
+default missing_os_info = false
+
+missing_os_info if input.OsType == ""
+
+concerns[flag] {
+	missing_os_info
+	flag := {
+		"category": "Warning",
+		"label": "Missing operating system information",
+		"assessment": "The OVF does not describe the guest operating system (OperatingSystemSection osType). The operating system will be detected during the guest conversion and the VM will be created with default settings.",
+	}
+}
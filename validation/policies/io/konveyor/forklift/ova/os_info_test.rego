@@ -0,0 +1,28 @@
+package io.konveyor.forklift.ova
+
+test_with_os_info {
+	mock_vm := {
+		"name": "test",
+		"OsType": "rhel8_64Guest",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_without_os_info {
+	mock_vm := {
+		"name": "test",
+		"OsType": "",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
+
+test_with_other_os_info {
+	mock_vm := {
+		"name": "test",
+		"OsType": "windows2019srv_64Guest",
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
@@ -0,0 +1,20 @@
+package io.konveyor.forklift.ova
+
+import future.keywords.if
+import future.keywords.in
+
+default has_passthrough_device = false
+
+has_passthrough_device if {
+	some device in input.Devices
+	regex.match(`(?i)^pci (device|passthrough)`, device.Kind)
+}
+
+concerns[flag] {
+	has_passthrough_device
+	flag := {
+		"category": "Critical",
+		"label": "Passthrough device detected",
+		"assessment": "SCSI or PCI passthrough devices are not currently supported by OpenShift Virtualization. The VM cannot be migrated unless the passthrough device is removed.",
+	}
+}
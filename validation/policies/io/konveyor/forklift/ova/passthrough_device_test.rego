@@ -0,0 +1,34 @@
+package io.konveyor.forklift.ova
+
+test_with_no_device {
+	mock_vm := {
+		"name": "test",
+		"Devices": [],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_other_device {
+	mock_vm := {
+		"name": "test",
+		"Devices": [
+			{"Kind": "SCSI controller"},
+			{"Kind": "Hard disk"},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_pci_passthrough_device {
+	mock_vm := {
+		"name": "test",
+		"Devices": [
+			{"Kind": "SCSI controller"},
+			{"Kind": "PCI device"},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
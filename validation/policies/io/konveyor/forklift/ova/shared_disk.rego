@@ -0,0 +1,20 @@
+package io.konveyor.forklift.ova
+
+import future.keywords.if
+import future.keywords.in
+
+default has_shared_disk = false
+
+has_shared_disk if {
+	some disk in input.disks
+	disk.Shared
+}
+
+concerns[flag] {
+	has_shared_disk
+	flag := {
+		"category": "Warning",
+		"label": "Shared disk detected",
+		"assessment": "The VM has a disk attached with multi-writer sharing. Shared disks are only supported by certain OpenShift Virtualization storage configurations. Ensure that the correct storage is selected for the disk.",
+	}
+}
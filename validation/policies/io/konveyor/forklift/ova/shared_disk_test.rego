@@ -0,0 +1,34 @@
+package io.konveyor.forklift.ova
+
+test_with_no_disks {
+	mock_vm := {
+		"name": "test",
+		"disks": [],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_without_shared_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"Name": "disk1.vmdk", "Shared": false},
+			{"Name": "disk2.vmdk"},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_shared_disk {
+	mock_vm := {
+		"name": "test",
+		"disks": [
+			{"Name": "disk1.vmdk", "Shared": false},
+			{"Name": "disk2.vmdk", "Shared": true},
+		],
+	}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}